@@ -0,0 +1,254 @@
+package luno
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// AddressError is returned by ValidateAddress for a malformed address.
+type AddressError struct {
+	Currency string
+	Address  string
+	Reason   string
+}
+
+func (e *AddressError) Error() string {
+	return fmt.Sprintf("luno: invalid %s address %q: %s",
+		e.Currency, e.Address, e.Reason)
+}
+
+// addressFormat describes the address formats of a Bitcoin-like currency.
+type addressFormat struct {
+	base58Versions []byte
+	bech32Prefix   string
+}
+
+var bitcoinLikeFormats = map[string]addressFormat{
+	"XBT": {base58Versions: []byte{0x00, 0x05}, bech32Prefix: "bc"},
+	"LTC": {base58Versions: []byte{0x30, 0x32, 0x05}, bech32Prefix: "ltc"},
+}
+
+// ValidateAddress checks the format and checksum of a mainnet address for
+// currency. Bitcoin (XBT) and Litecoin (LTC) addresses may be base58check or
+// bech32/bech32m segwit addresses. Ethereum (ETH) addresses must be 40 hex
+// digits with a 0x prefix and, if they are mixed case, a valid EIP-55
+// checksum. Addresses of other currencies are not validated and nil is
+// returned.
+//
+// A nil error only means that the address is well formed, not that it is
+// safe to send to.
+func ValidateAddress(currency, address string) error {
+	var reason error
+	if f, ok := bitcoinLikeFormats[currency]; ok {
+		reason = validateBitcoinLike(f, address)
+	} else if currency == "ETH" {
+		reason = validateEthereum(address)
+	} else {
+		return nil
+	}
+	if reason != nil {
+		return &AddressError{
+			Currency: currency,
+			Address:  address,
+			Reason:   reason.Error(),
+		}
+	}
+	return nil
+}
+
+// SetAddressValidation enables or disables validating addresses with
+// ValidateAddress before calling Send. It is disabled by default.
+func (cl *Client) SetAddressValidation(enabled bool) {
+	cl.validateAddresses = enabled
+}
+
+func validateBitcoinLike(f addressFormat, address string) error {
+	if strings.HasPrefix(strings.ToLower(address), f.bech32Prefix+"1") {
+		return validateSegwit(f.bech32Prefix, address)
+	}
+	b, err := decodeBase58Check(address)
+	if err != nil {
+		return err
+	}
+	if len(b) != 21 {
+		return fmt.Errorf("wrong length %d", len(b))
+	}
+	if bytes.IndexByte(f.base58Versions, b[0]) < 0 {
+		return fmt.Errorf("unknown version %d", b[0])
+	}
+	return nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// decodeBase58Check decodes s and verifies its checksum, returning the
+// payload without the checksum.
+func decodeBase58Check(s string) ([]byte, error) {
+	if s == "" {
+		return nil, errors.New("empty address")
+	}
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range s {
+		i := strings.IndexRune(base58Alphabet, c)
+		if i < 0 {
+			return nil, fmt.Errorf("invalid character %q", c)
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+	var zeros int
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+	b := append(make([]byte, zeros), n.Bytes()...)
+	if len(b) < 5 {
+		return nil, fmt.Errorf("wrong length %d", len(b))
+	}
+
+	payload, sum := b[:len(b)-4], b[len(b)-4:]
+	h := sha256.Sum256(payload)
+	h = sha256.Sum256(h[:])
+	if !bytes.Equal(h[:4], sum) {
+		return nil, errors.New("bad checksum")
+	}
+	return payload, nil
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+// validateSegwit checks a bech32 (BIP 173) or bech32m (BIP 350) segwit
+// address.
+func validateSegwit(prefix, address string) error {
+	if address != strings.ToLower(address) && address != strings.ToUpper(address) {
+		return errors.New("mixed case")
+	}
+	s := strings.ToLower(address)
+	if len(s) > 90 {
+		return fmt.Errorf("wrong length %d", len(s))
+	}
+	data := make([]byte, 0, len(s)-len(prefix)-1)
+	for _, c := range s[len(prefix)+1:] {
+		i := strings.IndexRune(bech32Charset, c)
+		if i < 0 {
+			return fmt.Errorf("invalid character %q", c)
+		}
+		data = append(data, byte(i))
+	}
+	if len(data) < 7 {
+		return fmt.Errorf("wrong length %d", len(s))
+	}
+
+	version := data[0]
+	c := bech32Polymod(append(bech32ExpandPrefix(prefix), data...))
+	switch {
+	case version == 0 && c != bech32Const, version > 0 && c != bech32mConst:
+		return errors.New("bad checksum")
+	case version > 16:
+		return fmt.Errorf("unknown witness version %d", version)
+	}
+
+	program, err := convertBits(data[1:len(data)-6], 5, 8)
+	if err != nil {
+		return err
+	}
+	if len(program) < 2 || len(program) > 40 ||
+		version == 0 && len(program) != 20 && len(program) != 32 {
+		return fmt.Errorf("wrong witness program length %d", len(program))
+	}
+	return nil
+}
+
+func bech32ExpandPrefix(prefix string) []byte {
+	b := make([]byte, 0, len(prefix)*2+1)
+	for i := 0; i < len(prefix); i++ {
+		b = append(b, prefix[i]>>5)
+	}
+	b = append(b, 0)
+	for i := 0; i < len(prefix); i++ {
+		b = append(b, prefix[i]&31)
+	}
+	return b
+}
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (b>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+// convertBits regroups data from groups of from bits into groups of to bits,
+// rejecting non-zero padding.
+func convertBits(data []byte, from, to uint) ([]byte, error) {
+	var (
+		acc  uint32
+		bits uint
+		out  []byte
+	)
+	for _, v := range data {
+		acc = acc<<from | uint32(v)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&(1<<to-1)))
+		}
+	}
+	if bits >= from || acc<<(to-bits)&(1<<to-1) != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return out, nil
+}
+
+// validateEthereum checks the format and, for mixed case addresses, the
+// EIP-55 checksum of an Ethereum address.
+func validateEthereum(address string) error {
+	if !strings.HasPrefix(address, "0x") {
+		return errors.New("missing 0x prefix")
+	}
+	digits := address[2:]
+	if len(digits) != 40 {
+		return fmt.Errorf("wrong length %d", len(digits))
+	}
+	if _, err := hex.DecodeString(digits); err != nil {
+		return errors.New("not hexadecimal")
+	}
+	if digits == strings.ToLower(digits) || digits == strings.ToUpper(digits) {
+		// Single case addresses don't carry a checksum.
+		return nil
+	}
+
+	h := keccak256([]byte(strings.ToLower(digits)))
+	for i := 0; i < len(digits); i++ {
+		c := digits[i]
+		if c < 'A' || c > 'z' {
+			continue
+		}
+		nibble := h[i/2] >> 4
+		if i%2 == 1 {
+			nibble = h[i/2] & 0xf
+		}
+		if (nibble >= 8) != (c >= 'A' && c <= 'F') {
+			return errors.New("bad checksum")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func TestValidateAddress(t *testing.T) {
+	testCases := []struct {
+		name     string
+		currency string
+		address  string
+		expErr   bool
+	}{
+		{name: "xbt p2pkh", currency: "XBT", address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"},
+		{name: "xbt p2sh", currency: "XBT", address: "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"},
+		{name: "xbt bech32", currency: "XBT", address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{name: "xbt bech32 upper", currency: "XBT", address: "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4"},
+		{name: "xbt bech32m", currency: "XBT", address: "bc1p5d7rjq7g6rdk2yhzks9smlaqtedr4dekq08ge8ztwac72sfr9rusxg3297"},
+		{name: "xbt bad checksum", currency: "XBT", address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3", expErr: true},
+		{name: "xbt bech32 bad checksum", currency: "XBT", address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", expErr: true},
+		{name: "xbt bech32 mixed case", currency: "XBT", address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8F3t4", expErr: true},
+		{name: "xbt wrong length", currency: "XBT", address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJa", expErr: true},
+		{name: "xbt bech32 wrong length", currency: "XBT", address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7k", expErr: true},
+		{name: "xbt litecoin address", currency: "XBT", address: "LVuDpNCSSj6pQ7t9Pv6d6sUkLKoqDEVUnJ", expErr: true},
+		{name: "xbt empty", currency: "XBT", address: "", expErr: true},
+
+		{name: "ltc p2pkh", currency: "LTC", address: "LVuDpNCSSj6pQ7t9Pv6d6sUkLKoqDEVUnJ"},
+		{name: "ltc p2sh", currency: "LTC", address: "MJaRnao1s62a2zAKSkmG582KbLKianqb7v"},
+		{name: "ltc bech32", currency: "LTC", address: "ltc1qw508d6qejxtdg4y5r3zarvary0c5xw7kgmn4n9"},
+		{name: "ltc bad checksum", currency: "LTC", address: "LVuDpNCSSj6pQ7t9Pv6d6sUkLKoqDEVUnK", expErr: true},
+		{name: "ltc bech32 bad checksum", currency: "LTC", address: "ltc1qw508d6qejxtdg4y5r3zarvary0c5xw7kgmn4n8", expErr: true},
+		{name: "ltc wrong length", currency: "LTC", address: "LVuDpNCSSj6pQ7t9Pv6d6sUk", expErr: true},
+		{name: "ltc bitcoin address", currency: "LTC", address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", expErr: true},
+
+		{name: "eth checksum", currency: "ETH", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{name: "eth checksum 2", currency: "ETH", address: "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"},
+		{name: "eth checksum 3", currency: "ETH", address: "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB"},
+		{name: "eth lower case", currency: "ETH", address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+		{name: "eth upper case", currency: "ETH", address: "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED"},
+		{name: "eth bad checksum", currency: "ETH", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", expErr: true},
+		{name: "eth wrong length", currency: "ETH", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", expErr: true},
+		{name: "eth no prefix", currency: "ETH", address: "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", expErr: true},
+		{name: "eth not hex", currency: "ETH", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg", expErr: true},
+
+		{name: "unknown currency", currency: "XRP", address: "anything"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := luno.ValidateAddress(tc.currency, tc.address)
+			if !tc.expErr {
+				if err != nil {
+					t.Errorf("Expected success, got %v", err)
+				}
+				return
+			}
+			var ae *luno.AddressError
+			if !errors.As(err, &ae) {
+				t.Fatalf("Expected *AddressError, got %v", err)
+			}
+			if ae.Currency != tc.currency || ae.Address != tc.address {
+				t.Errorf("Expected error for %s %q, got %s %q",
+					tc.currency, tc.address, ae.Currency, ae.Address)
+			}
+		})
+	}
+}
+
+func TestSendAddressValidation(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	req := &luno.SendRequest{
+		Amount:   decimal.NewFromInt64(1),
+		Currency: "XBT",
+		Address:  "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3",
+	}
+
+	if _, err := cl.Send(context.Background(), req); err != nil {
+		t.Fatalf("Expected success without validation, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("Expected 1 call, got %d", calls)
+	}
+
+	cl.SetAddressValidation(true)
+	_, err := cl.Send(context.Background(), req)
+	var ae *luno.AddressError
+	if !errors.As(err, &ae) {
+		t.Fatalf("Expected *AddressError, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected invalid send not to be made, got %d calls", calls)
+	}
+}
@@ -22,7 +22,7 @@ type CancelWithdrawalResponse struct {
 	ExternalId string          `json:"external_id"`
 	Fee        decimal.Decimal `json:"fee"`
 	Id         string          `json:"id"`
-	Status     string          `json:"status"`
+	Status     SendStatus      `json:"status"`
 	Type       string          `json:"type"`
 }
 
@@ -34,7 +34,7 @@ type CancelWithdrawalResponse struct {
 // Permissions required: <code>Perm_W_Withdrawals</code>
 func (cl *Client) CancelWithdrawal(ctx context.Context, req *CancelWithdrawalRequest) (*CancelWithdrawalResponse, error) {
 	var res CancelWithdrawalResponse
-	err := cl.do(ctx, "DELETE", "/api/1/withdrawals/{id}", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointCancelWithdrawal, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +74,58 @@ type CreateAccountResponse struct {
 // Permissions required: <code>Perm_W_Addresses</code>
 func (cl *Client) CreateAccount(ctx context.Context, req *CreateAccountRequest) (*CreateAccountResponse, error) {
 	var res CreateAccountResponse
-	err := cl.do(ctx, "POST", "/api/1/accounts", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointCreateAccount, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateBeneficiaryRequest is the request struct for CreateBeneficiary.
+type CreateBeneficiaryRequest struct {
+	// Bank account type, e.g. Current/Cheque, Savings or Transmission
+	//
+	// required: true
+	AccountType string `json:"account_type" url:"account_type"`
+
+	// Beneficiary bank account number
+	//
+	// required: true
+	BankAccountNumber string `json:"bank_account_number" url:"bank_account_number"`
+
+	// Bank SWIFT code
+	//
+	// required: true
+	BankName string `json:"bank_name" url:"bank_name"`
+
+	// The owner of the recipient account
+	//
+	// required: true
+	BankRecipient string `json:"bank_recipient" url:"bank_recipient"`
+}
+
+// CreateBeneficiaryResponse is the response struct for CreateBeneficiary.
+type CreateBeneficiaryResponse struct {
+	BankAccountBranch string `json:"bank_account_branch"`
+	BankAccountNumber string `json:"bank_account_number"`
+	BankAccountType   string `json:"bank_account_type"`
+	BankCountry       string `json:"bank_country"`
+	BankName          string `json:"bank_name"`
+	BankRecipient     string `json:"bank_recipient"`
+	CreatedAt         Time   `json:"created_at"`
+	Id                string `json:"id"`
+}
+
+// CreateBeneficiary makes a call to POST /api/1/beneficiaries.
+//
+// Create a new beneficiary, i.e. a bank account which withdrawals can be paid out to.
+//
+// The parameters are sent as a JSON request body.
+//
+// Permissions required: <code>Perm_W_Beneficiaries</code>
+func (cl *Client) CreateBeneficiary(ctx context.Context, req *CreateBeneficiaryRequest) (*CreateBeneficiaryResponse, error) {
+	var res CreateBeneficiaryResponse
+	err := cl.doEndpoint(ctx, endpointCreateBeneficiary, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -93,18 +144,7 @@ type CreateFundingAddressRequest struct {
 }
 
 // CreateFundingAddressResponse is the response struct for CreateFundingAddress.
-type CreateFundingAddressResponse struct {
-	AccountId        string          `json:"account_id"`
-	Address          string          `json:"address"`
-	AddressMeta      []AddressMeta   `json:"address_meta"`
-	Asset            string          `json:"asset"`
-	AssignedAt       Time            `json:"assigned_at"`
-	Name             string          `json:"name"`
-	QrCodeUri        string          `json:"qr_code_uri"`
-	ReceiveFee       decimal.Decimal `json:"receive_fee"`
-	TotalReceived    decimal.Decimal `json:"total_received"`
-	TotalUnconfirmed decimal.Decimal `json:"total_unconfirmed"`
-}
+type CreateFundingAddressResponse FundingAddress
 
 // CreateFundingAddress makes a call to POST /api/1/funding_address.
 //
@@ -115,7 +155,7 @@ type CreateFundingAddressResponse struct {
 // Permissions required: <code>Perm_W_Addresses</code>
 func (cl *Client) CreateFundingAddress(ctx context.Context, req *CreateFundingAddressRequest) (*CreateFundingAddressResponse, error) {
 	var res CreateFundingAddressResponse
-	err := cl.do(ctx, "POST", "/api/1/funding_address", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointCreateFundingAddress, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -138,7 +178,7 @@ type CreateQuoteRequest struct {
 	// <code>BUY</code> or <code>SELL</code>.
 	//
 	// required: true
-	Type string `json:"type" url:"type"`
+	Type OrderType `json:"type" url:"type"`
 
 	// Optional Account for the pair's base currency.
 	BaseAccountId int64 `json:"base_account_id" url:"base_account_id"`
@@ -148,17 +188,7 @@ type CreateQuoteRequest struct {
 }
 
 // CreateQuoteResponse is the response struct for CreateQuote.
-type CreateQuoteResponse struct {
-	BaseAmount    decimal.Decimal `json:"base_amount"`
-	CounterAmount decimal.Decimal `json:"counter_amount"`
-	CreatedAt     Time            `json:"created_at"`
-	Discarded     bool            `json:"discarded"`
-	Exercised     bool            `json:"exercised"`
-	ExpiresAt     Time            `json:"expires_at"`
-	Id            string          `json:"id"`
-	Pair          string          `json:"pair"`
-	Type          string          `json:"type"`
-}
+type CreateQuoteResponse Quote
 
 // CreateQuote makes a call to POST /api/1/quotes.
 //
@@ -177,7 +207,7 @@ type CreateQuoteResponse struct {
 // Permissions required: <code>Perm_W_Orders</code>
 func (cl *Client) CreateQuote(ctx context.Context, req *CreateQuoteRequest) (*CreateQuoteResponse, error) {
 	var res CreateQuoteResponse
-	err := cl.do(ctx, "POST", "/api/1/quotes", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointCreateQuote, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -206,6 +236,10 @@ type CreateWithdrawalRequest struct {
 	// This field supports all alphanumeric characters including "-" and "_".
 	ExternalId string `json:"external_id" url:"external_id"`
 
+	// If true, it will be a fast withdrawal if possible. Fast withdrawals come
+	// with a fee.
+	Fast bool `json:"fast" url:"fast,omitempty"`
+
 	// For internal use.
 	Reference string `json:"reference" url:"reference"`
 }
@@ -218,7 +252,7 @@ type CreateWithdrawalResponse struct {
 	ExternalId string          `json:"external_id"`
 	Fee        decimal.Decimal `json:"fee"`
 	Id         string          `json:"id"`
-	Status     string          `json:"status"`
+	Status     SendStatus      `json:"status"`
 	Type       string          `json:"type"`
 }
 
@@ -229,7 +263,33 @@ type CreateWithdrawalResponse struct {
 // Permissions required: <code>Perm_W_Withdrawals</code>
 func (cl *Client) CreateWithdrawal(ctx context.Context, req *CreateWithdrawalRequest) (*CreateWithdrawalResponse, error) {
 	var res CreateWithdrawalResponse
-	err := cl.do(ctx, "POST", "/api/1/withdrawals", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointCreateWithdrawal, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteBeneficiaryRequest is the request struct for DeleteBeneficiary.
+type DeleteBeneficiaryRequest struct {
+	// ID of the Beneficiary to delete.
+	//
+	// required: true
+	Id int64 `json:"id" url:"id"`
+}
+
+// DeleteBeneficiaryResponse is the response struct for DeleteBeneficiary.
+type DeleteBeneficiaryResponse struct {
+}
+
+// DeleteBeneficiary makes a call to DELETE /api/1/beneficiaries/{id}.
+//
+// Delete a beneficiary.
+//
+// Permissions required: <code>Perm_W_Beneficiaries</code>
+func (cl *Client) DeleteBeneficiary(ctx context.Context, req *DeleteBeneficiaryRequest) (*DeleteBeneficiaryResponse, error) {
+	var res DeleteBeneficiaryResponse
+	err := cl.doEndpoint(ctx, endpointDeleteBeneficiary, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -245,17 +305,7 @@ type DiscardQuoteRequest struct {
 }
 
 // DiscardQuoteResponse is the response struct for DiscardQuote.
-type DiscardQuoteResponse struct {
-	BaseAmount    decimal.Decimal `json:"base_amount"`
-	CounterAmount decimal.Decimal `json:"counter_amount"`
-	CreatedAt     Time            `json:"created_at"`
-	Discarded     bool            `json:"discarded"`
-	Exercised     bool            `json:"exercised"`
-	ExpiresAt     Time            `json:"expires_at"`
-	Id            string          `json:"id"`
-	Pair          string          `json:"pair"`
-	Type          string          `json:"type"`
-}
+type DiscardQuoteResponse Quote
 
 // DiscardQuote makes a call to DELETE /api/1/quotes/{id}.
 //
@@ -265,7 +315,7 @@ type DiscardQuoteResponse struct {
 // Permissions required: <code>Perm_W_Orders</code>
 func (cl *Client) DiscardQuote(ctx context.Context, req *DiscardQuoteRequest) (*DiscardQuoteResponse, error) {
 	var res DiscardQuoteResponse
-	err := cl.do(ctx, "DELETE", "/api/1/quotes/{id}", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointDiscardQuote, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -281,17 +331,7 @@ type ExerciseQuoteRequest struct {
 }
 
 // ExerciseQuoteResponse is the response struct for ExerciseQuote.
-type ExerciseQuoteResponse struct {
-	BaseAmount    decimal.Decimal `json:"base_amount"`
-	CounterAmount decimal.Decimal `json:"counter_amount"`
-	CreatedAt     Time            `json:"created_at"`
-	Discarded     bool            `json:"discarded"`
-	Exercised     bool            `json:"exercised"`
-	ExpiresAt     Time            `json:"expires_at"`
-	Id            string          `json:"id"`
-	Pair          string          `json:"pair"`
-	Type          string          `json:"type"`
-}
+type ExerciseQuoteResponse Quote
 
 // ExerciseQuote makes a call to PUT /api/1/quotes/{id}.
 //
@@ -304,7 +344,7 @@ type ExerciseQuoteResponse struct {
 // Permissions required: <code>Perm_W_Orders</code>
 func (cl *Client) ExerciseQuote(ctx context.Context, req *ExerciseQuoteRequest) (*ExerciseQuoteResponse, error) {
 	var res ExerciseQuoteResponse
-	err := cl.do(ctx, "PUT", "/api/1/quotes/{id}", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointExerciseQuote, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -330,7 +370,54 @@ type GetBalancesResponse struct {
 // Permissions required: <code>Perm_R_Balance</code>
 func (cl *Client) GetBalances(ctx context.Context, req *GetBalancesRequest) (*GetBalancesResponse, error) {
 	var res GetBalancesResponse
-	err := cl.do(ctx, "GET", "/api/1/balance", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointGetBalances, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetCandlesRequest is the request struct for GetCandles.
+type GetCandlesRequest struct {
+	// Candle duration in seconds.
+	// For example, 300 corresponds to 5m candles. Currently supported
+	// durations are: 60 (1m), 300 (5m), 900 (15m), 1800 (30m), 3600 (1h),
+	// 10800 (3h), 14400 (4h), 28800 (8h), 86400 (24h), 259200 (3d), 604800
+	// (7d).
+	//
+	// required: true
+	Duration int64 `json:"duration" url:"duration"`
+
+	// Currency pair
+	//
+	// required: true
+	Pair string `json:"pair" url:"pair"`
+
+	// Filter to candles starting on or after this timestamp (Unix
+	// milliseconds).
+	// Only up to 1000 of the earliest candles are returned.
+	//
+	// required: true
+	Since Time `json:"since" url:"since"`
+}
+
+// GetCandlesResponse is the response struct for GetCandles.
+type GetCandlesResponse struct {
+	Candles []Candle `json:"candles"`
+
+	// Duration in seconds
+	Duration int64  `json:"duration"`
+	Pair     string `json:"pair"`
+}
+
+// GetCandles makes a call to GET /api/exchange/1/candles.
+//
+// Get candlestick market data from the specified time until now, from the oldest to the most recent.
+//
+// Permissions required: <code>MP_None</code>
+func (cl *Client) GetCandles(ctx context.Context, req *GetCandlesRequest) (*GetCandlesResponse, error) {
+	var res GetCandlesResponse
+	err := cl.doEndpoint(ctx, endpointGetCandles, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -359,7 +446,7 @@ type GetFeeInfoResponse struct {
 // Permissions required: <code>Perm_R_Orders</code>
 func (cl *Client) GetFeeInfo(ctx context.Context, req *GetFeeInfoRequest) (*GetFeeInfoResponse, error) {
 	var res GetFeeInfoResponse
-	err := cl.do(ctx, "GET", "/api/1/fee_info", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointGetFeeInfo, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -379,18 +466,7 @@ type GetFundingAddressRequest struct {
 }
 
 // GetFundingAddressResponse is the response struct for GetFundingAddress.
-type GetFundingAddressResponse struct {
-	AccountId        string          `json:"account_id"`
-	Address          string          `json:"address"`
-	AddressMeta      []AddressMeta   `json:"address_meta"`
-	Asset            string          `json:"asset"`
-	AssignedAt       Time            `json:"assigned_at"`
-	Name             string          `json:"name"`
-	QrCodeUri        string          `json:"qr_code_uri"`
-	ReceiveFee       decimal.Decimal `json:"receive_fee"`
-	TotalReceived    decimal.Decimal `json:"total_received"`
-	TotalUnconfirmed decimal.Decimal `json:"total_unconfirmed"`
-}
+type GetFundingAddressResponse FundingAddress
 
 // GetFundingAddress makes a call to GET /api/1/funding_address.
 //
@@ -403,7 +479,35 @@ type GetFundingAddressResponse struct {
 // Permissions required: <code>Perm_R_Addresses</code>
 func (cl *Client) GetFundingAddress(ctx context.Context, req *GetFundingAddressRequest) (*GetFundingAddressResponse, error) {
 	var res GetFundingAddressResponse
-	err := cl.do(ctx, "GET", "/api/1/funding_address", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointGetFundingAddress, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetMoveRequest is the request struct for GetMove.
+type GetMoveRequest struct {
+	// Get by the user defined ID. This is mutually exclusive with Id and is
+	// required if Id is not provided.
+	ClientMoveId string `json:"client_move_id" url:"client_move_id,omitempty"`
+
+	// Get by the system ID. This is mutually exclusive with ClientMoveId
+	// and is required if ClientMoveId is not provided.
+	Id string `json:"id" url:"id,omitempty"`
+}
+
+// GetMoveResponse is the response struct for GetMove.
+type GetMoveResponse Move
+
+// GetMove makes a call to GET /api/exchange/1/move.
+//
+// Get a move funds instruction by its ID or the user defined ID.
+//
+// Permissions required: <code>Perm_R_Transfers</code>
+func (cl *Client) GetMove(ctx context.Context, req *GetMoveRequest) (*GetMoveResponse, error) {
+	var res GetMoveResponse
+	err := cl.doEndpoint(ctx, endpointGetMove, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -452,7 +556,7 @@ type GetOrderResponse struct {
 // Permissions required: <code>Perm_R_Orders</code>
 func (cl *Client) GetOrder(ctx context.Context, req *GetOrderRequest) (*GetOrderResponse, error) {
 	var res GetOrderResponse
-	err := cl.do(ctx, "GET", "/api/1/orders/{id}", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointGetOrder, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -465,13 +569,18 @@ type GetOrderBookRequest struct {
 	//
 	// required: true
 	Pair string `json:"pair" url:"pair"`
+
+	// Optional maximum number of bids and asks to return. The order book is
+	// truncated client-side after sorting since the API doesn't support a
+	// depth limit.
+	Depth int `json:"-" url:"-"`
 }
 
 // GetOrderBookResponse is the response struct for GetOrderBook.
 type GetOrderBookResponse struct {
 	Asks      []OrderBookEntry `json:"asks"`
 	Bids      []OrderBookEntry `json:"bids"`
-	Timestamp int64            `json:"timestamp"`
+	Timestamp Time             `json:"timestamp"`
 }
 
 // GetOrderBook makes a call to GET /api/1/orderbook_top.
@@ -483,12 +592,16 @@ type GetOrderBookResponse struct {
 // Bid Orders are sorted by price descending.
 //
 // Orders of the same price are aggregated.
+//
+// The client sorts the entries by exact price, ordering entries with the same
+// price by volume, so the order holds even if the server's doesn't.
 func (cl *Client) GetOrderBook(ctx context.Context, req *GetOrderBookRequest) (*GetOrderBookResponse, error) {
 	var res GetOrderBookResponse
-	err := cl.do(ctx, "GET", "/api/1/orderbook_top", req, &res, false)
+	err := cl.doEndpoint(ctx, endpointGetOrderBook, req, &res)
 	if err != nil {
 		return nil, err
 	}
+	res.Bids, res.Asks = truncateOrderBook(res.Bids, res.Asks, req.Depth)
 	return &res, nil
 }
 
@@ -498,13 +611,18 @@ type GetOrderBookFullRequest struct {
 	//
 	// required: true
 	Pair string `json:"pair" url:"pair"`
+
+	// Optional maximum number of bids and asks to return. The order book is
+	// truncated client-side after sorting since the API doesn't support a
+	// depth limit.
+	Depth int `json:"-" url:"-"`
 }
 
 // GetOrderBookFullResponse is the response struct for GetOrderBookFull.
 type GetOrderBookFullResponse struct {
 	Asks      []OrderBookEntry `json:"asks"`
 	Bids      []OrderBookEntry `json:"bids"`
-	Timestamp int64            `json:"timestamp"`
+	Timestamp Time             `json:"timestamp"`
 }
 
 // GetOrderBookFull makes a call to GET /api/1/orderbook.
@@ -520,12 +638,16 @@ type GetOrderBookFullResponse struct {
 // <b>Warning:</b> This may return a large amount of data.
 // Users are recommended to use the <a href="#operation/getOrderBook">top 100 bids and asks</a>
 // or the <a href="#tag/streaming-API-(beta)">Streaming API</a>.
+//
+// The client sorts the entries by exact price, ordering entries with the same
+// price by volume, so the order holds even if the server's doesn't.
 func (cl *Client) GetOrderBookFull(ctx context.Context, req *GetOrderBookFullRequest) (*GetOrderBookFullResponse, error) {
 	var res GetOrderBookFullResponse
-	err := cl.do(ctx, "GET", "/api/1/orderbook", req, &res, false)
+	err := cl.doEndpoint(ctx, endpointGetOrderBookFull, req, &res)
 	if err != nil {
 		return nil, err
 	}
+	res.Bids, res.Asks = truncateOrderBook(res.Bids, res.Asks, req.Depth)
 	return &res, nil
 }
 
@@ -604,7 +726,93 @@ type GetOrderV2Response struct {
 // Permissions required: <code>Perm_R_Orders</code>
 func (cl *Client) GetOrderV2(ctx context.Context, req *GetOrderV2Request) (*GetOrderV2Response, error) {
 	var res GetOrderV2Response
-	err := cl.do(ctx, "GET", "/api/exchange/2/orders/{id}", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointGetOrderV2, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetOrderV3Request is the request struct for GetOrderV3.
+type GetOrderV3Request struct {
+	// Order reference. Either Id or ClientOrderId must be set.
+	Id string `json:"id" url:"id,omitempty"`
+
+	// Client order ID given when the order was placed.
+	ClientOrderId string `json:"client_order_id" url:"client_order_id,omitempty"`
+}
+
+// GetOrderV3Response is the response struct for GetOrderV3.
+type GetOrderV3Response struct {
+	// Amount of base filled
+	Base decimal.Decimal `json:"base"`
+
+	// Time of order completion in milliseconds
+	CompletedTimestamp Time `json:"completed_timestamp"`
+
+	// Client order ID given when the order was placed, if any
+	ClientOrderId string `json:"client_order_id"`
+
+	// Amount of counter filled
+	Counter decimal.Decimal `json:"counter"`
+
+	// Time of order creation in milliseconds
+	CreationTimestamp Time `json:"creation_timestamp"`
+
+	// Time of order expiration in milliseconds
+	ExpirationTimestamp Time `json:"expiration_timestamp"`
+
+	// Base amount of fees to be charged
+	FeeBase decimal.Decimal `json:"fee_base"`
+
+	// Counter amount of fees to be charged
+	FeeCounter decimal.Decimal `json:"fee_counter"`
+
+	// Limit price to transact
+	LimitPrice decimal.Decimal `json:"limit_price"`
+
+	// Limit volume to transact
+	LimitVolume decimal.Decimal `json:"limit_volume"`
+
+	// The order reference
+	OrderId string `json:"order_id"`
+
+	// Specifies the market
+	Pair string `json:"pair"`
+
+	// The order intention
+	Side Side `json:"side"`
+
+	// The current state of the order
+	//
+	// Status meaning:<br>
+	// <code>AWAITING</code> The order is awaiting to enter the order book.<br>
+	// <code>PENDING</code> The order is in the order book. Some trades may
+	// have taken place but the order is not filled yet.<br>
+	// <code>COMPLETE</code> The order is no longer in the order book. It has
+	// been settled/filled or has been cancelled.
+	Status Status `json:"status"`
+
+	// Direction to trigger the order
+	StopDirection StopDirection `json:"stop_direction"`
+
+	// Price to trigger the order
+	StopPrice decimal.Decimal `json:"stop_price"`
+
+	// The order type
+	Type Type `json:"type"`
+}
+
+// GetOrderV3 makes a call to GET /api/exchange/3/order.
+//
+// Get the details for an order by its order ID or client order ID.<br>
+// This endpoint is in BETA, behaviour and specification may change without
+// any previous notice.
+//
+// Permissions required: <code>Perm_R_Orders</code>
+func (cl *Client) GetOrderV3(ctx context.Context, req *GetOrderV3Request) (*GetOrderV3Response, error) {
+	var res GetOrderV3Response
+	err := cl.doEndpoint(ctx, endpointGetOrderV3, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -620,17 +828,7 @@ type GetQuoteRequest struct {
 }
 
 // GetQuoteResponse is the response struct for GetQuote.
-type GetQuoteResponse struct {
-	BaseAmount    decimal.Decimal `json:"base_amount"`
-	CounterAmount decimal.Decimal `json:"counter_amount"`
-	CreatedAt     Time            `json:"created_at"`
-	Discarded     bool            `json:"discarded"`
-	Exercised     bool            `json:"exercised"`
-	ExpiresAt     Time            `json:"expires_at"`
-	Id            string          `json:"id"`
-	Pair          string          `json:"pair"`
-	Type          string          `json:"type"`
-}
+type GetQuoteResponse Quote
 
 // GetQuote makes a call to GET /api/1/quotes/{id}.
 //
@@ -639,7 +837,32 @@ type GetQuoteResponse struct {
 // Permissions required: <code>Perm_R_Orders</code>
 func (cl *Client) GetQuote(ctx context.Context, req *GetQuoteRequest) (*GetQuoteResponse, error) {
 	var res GetQuoteResponse
-	err := cl.do(ctx, "GET", "/api/1/quotes/{id}", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointGetQuote, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetServerTimeRequest is the request struct for GetServerTime.
+type GetServerTimeRequest struct {
+}
+
+// GetServerTimeResponse is the response struct for GetServerTime.
+type GetServerTimeResponse struct {
+	// Current server time as a Unix timestamp in milliseconds
+	Now int64 `json:"now"`
+
+	// Time zone of the server, e.g. UTC
+	Timezone string `json:"timezone"`
+}
+
+// GetServerTime makes a call to GET /api/1/time.
+//
+// Returns the current time of the server.
+func (cl *Client) GetServerTime(ctx context.Context, req *GetServerTimeRequest) (*GetServerTimeResponse, error) {
+	var res GetServerTimeResponse
+	err := cl.doEndpoint(ctx, endpointGetServerTime, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -677,11 +900,17 @@ type GetTickerResponse struct {
 //
 // Please see the <a href="#tag/currency ">Currency list</a> for the complete list of supported currency pairs.
 func (cl *Client) GetTicker(ctx context.Context, req *GetTickerRequest) (*GetTickerResponse, error) {
+	if res, ok := cl.cachedTicker(ctx, req.Pair); ok {
+		return res, nil
+	}
 	var res GetTickerResponse
-	err := cl.do(ctx, "GET", "/api/1/ticker", req, &res, false)
+	err := cl.doEndpoint(ctx, endpointGetTicker, req, &res)
 	if err != nil {
 		return nil, err
 	}
+	if err := cl.checkPair(req.Pair, res.Pair); err != nil {
+		return nil, err
+	}
 	return &res, nil
 }
 
@@ -703,7 +932,7 @@ type GetTickersResponse struct {
 // Please see the <a href="#tag/currency ">Currency list</a> for the complete list of supported currency pairs.
 func (cl *Client) GetTickers(ctx context.Context, req *GetTickersRequest) (*GetTickersResponse, error) {
 	var res GetTickersResponse
-	err := cl.do(ctx, "GET", "/api/1/tickers", req, &res, false)
+	err := cl.doEndpoint(ctx, endpointGetTickers, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -726,7 +955,7 @@ type GetWithdrawalResponse struct {
 	ExternalId string          `json:"external_id"`
 	Fee        decimal.Decimal `json:"fee"`
 	Id         string          `json:"id"`
-	Status     string          `json:"status"`
+	Status     SendStatus      `json:"status"`
 	Type       string          `json:"type"`
 }
 
@@ -737,7 +966,30 @@ type GetWithdrawalResponse struct {
 // Permissions required: <code>Perm_R_Withdrawals</code>
 func (cl *Client) GetWithdrawal(ctx context.Context, req *GetWithdrawalRequest) (*GetWithdrawalResponse, error) {
 	var res GetWithdrawalResponse
-	err := cl.do(ctx, "GET", "/api/1/withdrawals/{id}", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointGetWithdrawal, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListBeneficiariesRequest is the request struct for ListBeneficiaries.
+type ListBeneficiariesRequest struct {
+}
+
+// ListBeneficiariesResponse is the response struct for ListBeneficiaries.
+type ListBeneficiariesResponse struct {
+	Beneficiaries []Beneficiary `json:"beneficiaries"`
+}
+
+// ListBeneficiaries makes a call to GET /api/1/beneficiaries.
+//
+// Returns a list of bank beneficiaries.
+//
+// Permissions required: <code>Perm_R_Beneficiaries</code>
+func (cl *Client) ListBeneficiaries(ctx context.Context, req *ListBeneficiariesRequest) (*ListBeneficiariesResponse, error) {
+	var res ListBeneficiariesResponse
+	err := cl.doEndpoint(ctx, endpointListBeneficiaries, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -750,7 +1002,7 @@ type ListBeneficiariesResponseRequest struct {
 
 // ListBeneficiariesResponseResponse is the response struct for ListBeneficiariesResponse.
 type ListBeneficiariesResponseResponse struct {
-	Beneficiaries []beneficiary `json:"beneficiaries"`
+	Beneficiaries []Beneficiary `json:"beneficiaries"`
 }
 
 // ListBeneficiariesResponse makes a call to GET /api/1/beneficiaries.
@@ -758,9 +1010,40 @@ type ListBeneficiariesResponseResponse struct {
 // Returns a list of bank beneficiaries.
 //
 // Permissions required: <code>Perm_R_Beneficiaries</code>
+//
+// Deprecated: Use ListBeneficiaries.
 func (cl *Client) ListBeneficiariesResponse(ctx context.Context, req *ListBeneficiariesResponseRequest) (*ListBeneficiariesResponseResponse, error) {
 	var res ListBeneficiariesResponseResponse
-	err := cl.do(ctx, "GET", "/api/1/beneficiaries", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointListBeneficiariesResponse, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListMovesRequest is the request struct for ListMoves.
+type ListMovesRequest struct {
+	// Filter to moves requested before this timestamp (Unix milliseconds)
+	Before int64 `json:"before" url:"before,omitempty"`
+
+	// Limit to this many moves
+	Limit int64 `json:"limit" url:"limit,omitempty"`
+}
+
+// ListMovesResponse is the response struct for ListMoves.
+type ListMovesResponse struct {
+	Moves []Move `json:"moves"`
+}
+
+// ListMoves makes a call to GET /api/exchange/1/move/list_moves.
+//
+// Returns a list of the most recent moves ordered from newest to oldest.
+// This endpoint will list up to 100 most recent moves by default.
+//
+// Permissions required: <code>Perm_R_Transfers</code>
+func (cl *Client) ListMoves(ctx context.Context, req *ListMovesRequest) (*ListMovesResponse, error) {
+	var res ListMovesResponse
+	err := cl.doEndpoint(ctx, endpointListMoves, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -797,7 +1080,7 @@ type ListOrdersResponse struct {
 // Permissions required: <code>Perm_R_Orders</code>
 func (cl *Client) ListOrders(ctx context.Context, req *ListOrdersRequest) (*ListOrdersResponse, error) {
 	var res ListOrdersResponse
-	err := cl.do(ctx, "GET", "/api/1/listorders", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointListOrders, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -834,7 +1117,7 @@ type ListOrdersV2Response struct {
 // Permissions required: <Code>Perm_R_Orders</Code>
 func (cl *Client) ListOrdersV2(ctx context.Context, req *ListOrdersV2Request) (*ListOrdersV2Response, error) {
 	var res ListOrdersV2Response
-	err := cl.do(ctx, "GET", "/api/exchange/2/listorders", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointListOrdersV2, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -867,7 +1150,7 @@ type ListPendingTransactionsResponse struct {
 // Permissions required: <code>Perm_R_Transactions</code>
 func (cl *Client) ListPendingTransactions(ctx context.Context, req *ListPendingTransactionsRequest) (*ListPendingTransactionsResponse, error) {
 	var res ListPendingTransactionsResponse
-	err := cl.do(ctx, "GET", "/api/1/accounts/{id}/pending", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointListPendingTransactions, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -899,7 +1182,7 @@ type ListTradesResponse struct {
 // Please see the <a href="#tag/currency ">Currency list</a> for the complete list of supported currency pairs.
 func (cl *Client) ListTrades(ctx context.Context, req *ListTradesRequest) (*ListTradesResponse, error) {
 	var res ListTradesResponse
-	err := cl.do(ctx, "GET", "/api/1/trades", req, &res, false)
+	err := cl.doEndpoint(ctx, endpointListTrades, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -949,7 +1232,7 @@ type ListTransactionsResponse struct {
 // Permissions required: <code>Perm_R_Transactions</code>
 func (cl *Client) ListTransactions(ctx context.Context, req *ListTransactionsRequest) (*ListTransactionsResponse, error) {
 	var res ListTransactionsResponse
-	err := cl.do(ctx, "GET", "/api/1/accounts/{id}/transactions", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointListTransactions, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -1005,7 +1288,7 @@ type ListUserTradesResponse struct {
 // Permissions required: <code>Perm_R_Orders</code>
 func (cl *Client) ListUserTrades(ctx context.Context, req *ListUserTradesRequest) (*ListUserTradesResponse, error) {
 	var res ListUserTradesResponse
-	err := cl.do(ctx, "GET", "/api/1/listtrades", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointListUserTrades, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -1028,7 +1311,7 @@ type ListWithdrawalsResponse struct {
 // Permissions required: <code>Perm_R_Withdrawals</code>
 func (cl *Client) ListWithdrawals(ctx context.Context, req *ListWithdrawalsRequest) (*ListWithdrawalsResponse, error) {
 	var res ListWithdrawalsResponse
-	err := cl.do(ctx, "GET", "/api/1/withdrawals", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointListWithdrawals, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -1050,7 +1333,57 @@ type MarketsResponse struct {
 // max volumes and market ID.
 func (cl *Client) Markets(ctx context.Context, req *MarketsRequest) (*MarketsResponse, error) {
 	var res MarketsResponse
-	err := cl.do(ctx, "GET", "/api/exchange/1/markets", req, &res, false)
+	err := cl.doEndpoint(ctx, endpointMarkets, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// MoveRequest is the request struct for Move.
+type MoveRequest struct {
+	// Amount to transfer. Must be positive.
+	//
+	// required: true
+	Amount decimal.Decimal `json:"amount" url:"amount"`
+
+	// The account to credit the funds to.
+	//
+	// required: true
+	CreditAccountId int64 `json:"credit_account_id" url:"credit_account_id"`
+
+	// The account to debit the funds from.
+	//
+	// required: true
+	DebitAccountId int64 `json:"debit_account_id" url:"debit_account_id"`
+
+	// Client move ID.
+	// May only contain alphanumeric (0-9, a-z, or A-Z) and special characters (_ ; , . -). Maximum length: 255.
+	// It will be available in read endpoints, so you can use it to avoid duplicate moves between the same accounts.
+	// Values must be unique across all your successful calls of this endpoint; trying to create a move request
+	// with the same `client_move_id` as one of your past move requests will result in a HTTP 409 Conflict response.
+	ClientMoveId string `json:"client_move_id" url:"client_move_id,omitempty"`
+}
+
+// MoveResponse is the response struct for Move.
+type MoveResponse struct {
+	// Move unique identifier
+	Id string `json:"id"`
+
+	// The current state of the move.
+	Status MoveStatus `json:"status"`
+}
+
+// Move makes a call to POST /api/exchange/1/move.
+//
+// Move funds between two of your accounts with the same currency, such as
+// between your spot and savings accounts. The funds may not be moved by the
+// time the request returns. Use GetMove to check the status of the move.
+//
+// Permissions required: <code>Perm_W_Transfers</code>
+func (cl *Client) Move(ctx context.Context, req *MoveRequest) (*MoveResponse, error) {
+	var res MoveResponse
+	err := cl.doEndpoint(ctx, endpointMove, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -1083,6 +1416,11 @@ type PostLimitOrderRequest struct {
 	// The base currency Account to use in the trade.
 	BaseAccountId int64 `json:"base_account_id" url:"base_account_id"`
 
+	// Client order ID. May be used to identify the order, e.g. with
+	// GetOrderV3, and to avoid placing it twice: an order with the same
+	// client order ID as an existing order is rejected.
+	ClientOrderId string `json:"client_order_id" url:"client_order_id,omitempty"`
+
 	// The counter currency Account to use in the trade.
 	CounterAccountId int64 `json:"counter_account_id" url:"counter_account_id"`
 
@@ -1106,6 +1444,16 @@ type PostLimitOrderRequest struct {
 	// is set then this is treated as a Stop Limit Order and `stop_direction`
 	// is expected to be set too.
 	StopPrice decimal.Decimal `json:"stop_price" url:"stop_price"`
+
+	// <code>GTC</code> Good 'Til Cancelled. The order remains open until it
+	// is filled or cancelled by the user. This is the default.<br>
+	// <code>IOC</code> Immediate Or Cancel. The part of the order that cannot
+	// be filled immediately will be cancelled. Cannot be post-only.<br>
+	// <code>FOK</code> Fill Or Kill. If the order cannot be filled immediately
+	// and completely it will be cancelled. Cannot be post-only.
+	//
+	// Only supported by limit orders, not by stop limit orders.
+	TimeInForce TimeInForce `json:"time_in_force" url:"time_in_force,omitempty"`
 }
 
 // PostLimitOrderResponse is the response struct for PostLimitOrder.
@@ -1126,8 +1474,11 @@ type PostLimitOrderResponse struct {
 //
 // Permissions required: <code>Perm_W_Orders</code>
 func (cl *Client) PostLimitOrder(ctx context.Context, req *PostLimitOrderRequest) (*PostLimitOrderResponse, error) {
+	if err := cl.checkLimitOrderNotional(ctx, req); err != nil {
+		return nil, err
+	}
 	var res PostLimitOrderResponse
-	err := cl.do(ctx, "POST", "/api/1/postorder", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointPostLimitOrder, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -1153,6 +1504,11 @@ type PostMarketOrderRequest struct {
 	// For a <code>SELL</code> order: amount of the base currency to use (e.g. how much BTC to sell for EUR in the BTC/EUR market)
 	BaseVolume decimal.Decimal `json:"base_volume" url:"base_volume"`
 
+	// Client order ID. May be used to identify the order, e.g. with
+	// GetOrderV3, and to avoid placing it twice: an order with the same
+	// client order ID as an existing order is rejected.
+	ClientOrderId string `json:"client_order_id" url:"client_order_id,omitempty"`
+
 	// The counter currency account to use in the trade.
 	CounterAccountId int64 `json:"counter_account_id" url:"counter_account_id"`
 
@@ -1179,8 +1535,11 @@ type PostMarketOrderResponse struct {
 //
 // Permissions required: <code>Perm_W_Orders</code>
 func (cl *Client) PostMarketOrder(ctx context.Context, req *PostMarketOrderRequest) (*PostMarketOrderResponse, error) {
+	if err := cl.checkMarketOrderNotional(ctx, req); err != nil {
+		return nil, err
+	}
 	var res PostMarketOrderResponse
-	err := cl.do(ctx, "POST", "/api/1/marketorder", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointPostMarketOrder, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -1225,9 +1584,18 @@ type SendRequest struct {
 	// Optional boolean flag indicating that a XRP destination tag is provided (even if zero).
 	HasDestinationTag bool `json:"has_destination_tag" url:"has_destination_tag"`
 
+	// Optional memo for assets which identify the recipient of a send to a
+	// shared address by memo rather than destination tag, e.g. XLM.
+	Memo string `json:"memo" url:"memo,omitempty"`
+
 	// Message to send to the recipient.
 	// This is only relevant when sending to an email address.
 	Message string `json:"message" url:"message"`
+
+	// Optional network to send on, for assets which can be sent on more than
+	// one network, e.g. "ERC20" or "TRC20" for USDT. The default network of
+	// the asset is used if it is empty.
+	Network string `json:"network" url:"network,omitempty"`
 }
 
 // SendResponse is the response struct for Send.
@@ -1244,8 +1612,63 @@ type SendResponse struct {
 //
 // Permissions required: <code>Perm_W_Send</code>
 func (cl *Client) Send(ctx context.Context, req *SendRequest) (*SendResponse, error) {
+	if cl.validateAddresses {
+		if err := ValidateAddress(req.Currency, req.Address); err != nil {
+			return nil, err
+		}
+	}
 	var res SendResponse
-	err := cl.do(ctx, "POST", "/api/1/send", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointSend, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// SendFeeRequest is the request struct for SendFee.
+type SendFeeRequest struct {
+	// Destination address or email address.
+	//
+	// <b>Note</b>:
+	// <ul>
+	// <li>Ethereum addresses must be
+	// <a href="https://github.com/ethereum/EIPs/blob/master/EIPS/eip-55.md" target="_blank">checksummed</a>.</li>
+	// <li>Ethereum sends to email addresses are not supported.</li>
+	// </ul>
+	//
+	// required: true
+	Address string `json:"address" url:"address"`
+
+	// Amount to send as a decimal string.
+	//
+	// required: true
+	Amount decimal.Decimal `json:"amount" url:"amount"`
+
+	// Currency to send.
+	//
+	// required: true
+	Currency string `json:"currency" url:"currency"`
+
+	// Optional network to send on, as for SendRequest.
+	Network string `json:"network" url:"network,omitempty"`
+}
+
+// SendFeeResponse is the response struct for SendFee.
+type SendFeeResponse struct {
+	Currency string          `json:"currency"`
+	Fee      decimal.Decimal `json:"fee"`
+}
+
+// SendFee makes a call to GET /api/1/send_fee.
+//
+// Calculate fees involved with a crypto send request.
+//
+// Send address can be to a cryptocurrency receive address, or the email address of another Luno platform user.
+//
+// Permissions required: <code>Perm_W_Send</code>
+func (cl *Client) SendFee(ctx context.Context, req *SendFeeRequest) (*SendFeeResponse, error) {
+	var res SendFeeResponse
+	err := cl.doEndpoint(ctx, endpointSendFee, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -1275,7 +1698,7 @@ type StopOrderResponse struct {
 // Permissions required: <code>Perm_W_Orders</code>
 func (cl *Client) StopOrder(ctx context.Context, req *StopOrderRequest) (*StopOrderResponse, error) {
 	var res StopOrderResponse
-	err := cl.do(ctx, "POST", "/api/1/stoporder", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointStopOrder, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -1307,7 +1730,52 @@ type UpdateAccountNameResponse struct {
 // Permissions required: <code>Perm_W_Addresses</code>
 func (cl *Client) UpdateAccountName(ctx context.Context, req *UpdateAccountNameRequest) (*UpdateAccountNameResponse, error) {
 	var res UpdateAccountNameResponse
-	err := cl.do(ctx, "PUT", "/api/1/accounts/{id}/name", req, &res, true)
+	err := cl.doEndpoint(ctx, endpointUpdateAccountName, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ValidateRequest is the request struct for Validate.
+type ValidateRequest struct {
+	// Destination address or email address.
+	//
+	// required: true
+	Address string `json:"address" url:"address"`
+
+	// Currency is the currency associated with the address.
+	//
+	// required: true
+	Currency string `json:"currency" url:"currency"`
+
+	// Optional XRP destination tag. Note that HasDestinationTag must be true if this value is provided.
+	DestinationTag int64 `json:"destination_tag" url:"destination_tag,omitempty"`
+
+	// Optional boolean flag indicating that a XRP destination tag is provided (even if zero).
+	HasDestinationTag bool `json:"has_destination_tag" url:"has_destination_tag,omitempty"`
+
+	// Optional memo, as for SendRequest.
+	Memo string `json:"memo" url:"memo,omitempty"`
+
+	// Optional network the address is on, as for SendRequest.
+	Network string `json:"network" url:"network,omitempty"`
+}
+
+// ValidateResponse is the response struct for Validate.
+type ValidateResponse struct {
+	Success bool `json:"success"`
+}
+
+// Validate makes a call to POST /api/1/address/validate.
+//
+// Validate checks with Luno that an address, along with any destination tag,
+// memo or network, is a valid destination for a send of the currency.
+//
+// Permissions required: <code>Perm_W_Send</code>
+func (cl *Client) Validate(ctx context.Context, req *ValidateRequest) (*ValidateResponse, error) {
+	var res ValidateResponse
+	err := cl.doEndpoint(ctx, endpointValidate, req, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,73 @@
+package luno
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff determines how long to wait between retries.
+type Backoff interface {
+	// Next returns the delay before the retry with the given zero-based index.
+	Next(attempt int) time.Duration
+}
+
+// ExponentialBackoff is a Backoff which grows the delay by Multiplier for
+// every attempt, starting at Base and capped at Max.
+type ExponentialBackoff struct {
+	// Base is the delay before the first retry.
+	Base time.Duration
+
+	// Max caps the delay. No cap is applied if Max is zero.
+	Max time.Duration
+
+	// Multiplier is the factor by which the delay grows. It defaults to 2 if
+	// zero.
+	Multiplier float64
+
+	// Jitter randomises the delay by up to the given fraction in either
+	// direction, e.g. 0.1 for ±10%.
+	Jitter float64
+}
+
+// Next returns the delay before the retry with the given zero-based index.
+func (b ExponentialBackoff) Next(attempt int) time.Duration {
+	m := b.Multiplier
+	if m == 0 {
+		m = 2
+	}
+	d := float64(b.Base) * math.Pow(m, float64(attempt))
+	if math.IsNaN(d) {
+		// A zero Base times an infinite factor.
+		d = 0
+	}
+	// Clamp before applying jitter, since the delay overflows to +Inf for
+	// large attempts, and jitter would make that NaN.
+	max := float64(math.MaxInt64)
+	if b.Max > 0 {
+		max = float64(b.Max)
+	}
+	if d > max {
+		d = max
+	}
+	if b.Jitter > 0 {
+		d += d * b.Jitter * (2*rand.Float64() - 1)
+	}
+	if d >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(d)
+}
+
+// ConstantBackoff is a Backoff which always waits the same duration.
+type ConstantBackoff time.Duration
+
+// Next returns the constant delay.
+func (b ConstantBackoff) Next(attempt int) time.Duration {
+	return time.Duration(b)
+}
+
+var defaultBackoff Backoff = ExponentialBackoff{
+	Base: 100 * time.Millisecond,
+	Max:  10 * time.Second,
+}
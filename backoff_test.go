@@ -0,0 +1,72 @@
+package luno_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	b := luno.ExponentialBackoff{
+		Base:       time.Second,
+		Max:        10 * time.Second,
+		Multiplier: 3,
+	}
+	exp := []time.Duration{
+		time.Second,
+		3 * time.Second,
+		9 * time.Second,
+		10 * time.Second,
+		10 * time.Second,
+	}
+	for i, e := range exp {
+		if act := b.Next(i); act != e {
+			t.Errorf("Expected attempt %d to wait %s, got %s", i, e, act)
+		}
+	}
+}
+
+func TestExponentialBackoffOverflow(t *testing.T) {
+	b := luno.ExponentialBackoff{Base: time.Second, Jitter: 0.1}
+	for _, attempt := range []int{100, 10000, math.MaxInt32} {
+		for i := 0; i < 100; i++ {
+			// Jitter may shorten the maximum duration by up to 10%.
+			if act := b.Next(attempt); act < math.MaxInt64/10*9 {
+				t.Fatalf("Expected attempt %d to wait about the maximum duration, got %s", attempt, act)
+			}
+		}
+	}
+	b.Max = time.Minute
+	for i := 0; i < 100; i++ {
+		if act := b.Next(math.MaxInt32); act < 54*time.Second || act > 66*time.Second {
+			t.Fatalf("Expected delay within 10%% of 1m, got %s", act)
+		}
+	}
+	if act := (luno.ExponentialBackoff{}).Next(10000); act != 0 {
+		t.Errorf("Expected zero base to wait 0, got %s", act)
+	}
+}
+
+func TestExponentialBackoffJitter(t *testing.T) {
+	b := luno.ExponentialBackoff{
+		Base:   time.Second,
+		Jitter: 0.1,
+	}
+	for i := 0; i < 100; i++ {
+		act := b.Next(1)
+		if act < 1800*time.Millisecond || act > 2200*time.Millisecond {
+			t.Errorf("Expected delay within 10%% of 2s, got %s", act)
+		}
+	}
+}
+
+func TestConstantBackoff(t *testing.T) {
+	b := luno.ConstantBackoff(time.Second)
+	for i := 0; i < 5; i++ {
+		if act := b.Next(i); act != time.Second {
+			t.Errorf("Expected attempt %d to wait 1s, got %s", i, act)
+		}
+	}
+}
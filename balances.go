@@ -0,0 +1,147 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// Balances are the balances of a user's accounts.
+type Balances []AccountBalance
+
+// GetAllBalances returns the balances of all the user's accounts, including
+// sub-accounts, using a single request.
+func (cl *Client) GetAllBalances(ctx context.Context) (Balances, error) {
+	res, err := cl.GetBalances(ctx, &GetBalancesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return Balances(res.Balance), nil
+}
+
+// AssetBalance is the total balance of an asset across accounts.
+type AssetBalance struct {
+	Asset       string
+	Balance     decimal.Decimal
+	Reserved    decimal.Decimal
+	Unconfirmed decimal.Decimal
+
+	// Accounts is the number of accounts holding the asset.
+	Accounts int
+}
+
+// GroupByAsset sums the balances of accounts with the same asset, keyed by
+// asset.
+func (b Balances) GroupByAsset() map[string]AssetBalance {
+	m := make(map[string]AssetBalance)
+	for _, ab := range b {
+		sum, ok := m[ab.Asset]
+		if !ok {
+			sum = AssetBalance{
+				Asset:       ab.Asset,
+				Balance:     decimal.Zero(),
+				Reserved:    decimal.Zero(),
+				Unconfirmed: decimal.Zero(),
+			}
+		}
+		sum.Balance = sum.Balance.Add(ab.Balance)
+		sum.Reserved = sum.Reserved.Add(ab.Reserved)
+		sum.Unconfirmed = sum.Unconfirmed.Add(ab.Unconfirmed)
+		sum.Accounts++
+		m[ab.Asset] = sum
+	}
+	return m
+}
+
+// ErrNoAccount is returned by AccountIDForAsset when the user has no account
+// for the asset.
+var ErrNoAccount = errors.New("luno: no account for asset")
+
+// ErrAmbiguousAccount is returned by AccountIDForAsset when the user has more
+// than one account for the asset, so the account must be chosen explicitly.
+var ErrAmbiguousAccount = errors.New("luno: more than one account for asset")
+
+// accountIDCache holds the results of AccountIDForAsset, keyed by asset.
+type accountIDCache struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+// AccountIDForAsset returns the ID of the user's only account for asset, e.g.
+// "XBT". Since account IDs never change, the ID is cached for the lifetime of
+// the client once found. Errors aren't cached, so an account created later is
+// found by the next call.
+//
+// It returns an error wrapping ErrNoAccount if there is no account for asset,
+// and one wrapping ErrAmbiguousAccount if there are several.
+func (cl *Client) AccountIDForAsset(ctx context.Context, asset string) (string, error) {
+	c := &cl.accountIDs
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if id, ok := c.ids[asset]; ok {
+		return id, nil
+	}
+
+	res, err := cl.GetBalances(ctx, &GetBalancesRequest{Assets: []string{asset}})
+	if err != nil {
+		return "", err
+	}
+	var ids []string
+	for _, ab := range res.Balance {
+		if ab.Asset == asset {
+			ids = append(ids, ab.AccountId)
+		}
+	}
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("%w %s", ErrNoAccount, asset)
+	case 1:
+	default:
+		return "", fmt.Errorf("%w %s: %v", ErrAmbiguousAccount, asset, ids)
+	}
+
+	if c.ids == nil {
+		c.ids = make(map[string]string)
+	}
+	c.ids[asset] = ids[0]
+	return ids[0], nil
+}
+
+// GetAssetBalances returns the total balance of each of assets across the
+// user's accounts, keyed by asset. Balances of any number of assets are
+// fetched with a single request.
+//
+// If the user has no account for some of the assets, the balances of the
+// others are returned along with an error wrapping ErrNoAccount which names
+// the missing assets.
+func (cl *Client) GetAssetBalances(ctx context.Context, assets []string) (
+	map[string]AssetBalance, error) {
+
+	if len(assets) == 0 {
+		return map[string]AssetBalance{}, nil
+	}
+	res, err := cl.GetBalances(ctx, &GetBalancesRequest{Assets: assets})
+	if err != nil {
+		return nil, err
+	}
+	all := Balances(res.Balance).GroupByAsset()
+
+	m := make(map[string]AssetBalance, len(assets))
+	var missing []string
+	for _, asset := range assets {
+		if ab, ok := all[asset]; ok {
+			m[asset] = ab
+		} else {
+			missing = append(missing, asset)
+		}
+	}
+	if len(missing) > 0 {
+		return m, fmt.Errorf("%w %s", ErrNoAccount, strings.Join(missing, ", "))
+	}
+	return m, nil
+}
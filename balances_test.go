@@ -0,0 +1,151 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestGetAllBalances(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/api/1/balance" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		w.Write([]byte(`{"balance":[
+			{"account_id":"1","asset":"XBT","balance":"0.5","reserved":"0.1","unconfirmed":"0"},
+			{"account_id":"2","asset":"ZAR","balance":"1000","reserved":"0","unconfirmed":"0"},
+			{"account_id":"3","asset":"XBT","balance":"1.25","reserved":"0","unconfirmed":"0.01"},
+			{"account_id":"4","asset":"XBT","balance":"0.00000001","reserved":"0","unconfirmed":"0"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	bals, err := cl.GetAllBalances(context.Background())
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(bals) != 4 {
+		t.Fatalf("Expected 4 balances, got %d", len(bals))
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+
+	byAsset := bals.GroupByAsset()
+	if len(byAsset) != 2 {
+		t.Fatalf("Expected 2 assets, got %d", len(byAsset))
+	}
+	testCases := []struct {
+		asset       string
+		balance     string
+		reserved    string
+		unconfirmed string
+		accounts    int
+	}{
+		{asset: "XBT", balance: "1.75000001", reserved: "0.1", unconfirmed: "0.01", accounts: 3},
+		{asset: "ZAR", balance: "1000", reserved: "0", unconfirmed: "0", accounts: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.asset, func(t *testing.T) {
+			ab := byAsset[tc.asset]
+			if ab.Asset != tc.asset || ab.Accounts != tc.accounts {
+				t.Errorf("Expected %s with %d accounts, got %s with %d",
+					tc.asset, tc.accounts, ab.Asset, ab.Accounts)
+			}
+			if ab.Balance.Cmp(mustDecimal(t, tc.balance)) != 0 {
+				t.Errorf("Expected balance %s, got %s", tc.balance, ab.Balance)
+			}
+			if ab.Reserved.Cmp(mustDecimal(t, tc.reserved)) != 0 {
+				t.Errorf("Expected reserved %s, got %s", tc.reserved, ab.Reserved)
+			}
+			if ab.Unconfirmed.Cmp(mustDecimal(t, tc.unconfirmed)) != 0 {
+				t.Errorf("Expected unconfirmed %s, got %s", tc.unconfirmed, ab.Unconfirmed)
+			}
+		})
+	}
+}
+
+func TestAccountIDForAsset(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		accounts := map[string]string{
+			"XBT": `{"account_id":"1","asset":"XBT"}`,
+			"ETH": `{"account_id":"2","asset":"ETH"},{"account_id":"3","asset":"ETH"}`,
+		}
+		w.Write([]byte(`{"balance":[` + accounts[r.URL.Query().Get("assets")] + `]}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	ctx := context.Background()
+
+	t.Run("single account", func(t *testing.T) {
+		calls = 0
+		for i := 0; i < 2; i++ {
+			id, err := cl.AccountIDForAsset(ctx, "XBT")
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if id != "1" {
+				t.Errorf("Expected account 1, got %q", id)
+			}
+		}
+		if calls != 1 {
+			t.Errorf("Expected the account ID to be cached, got %d calls", calls)
+		}
+	})
+
+	t.Run("no account", func(t *testing.T) {
+		_, err := cl.AccountIDForAsset(ctx, "ZAR")
+		if !errors.Is(err, luno.ErrNoAccount) {
+			t.Errorf("Expected ErrNoAccount, got %v", err)
+		}
+	})
+
+	t.Run("multiple accounts", func(t *testing.T) {
+		_, err := cl.AccountIDForAsset(ctx, "ETH")
+		if !errors.Is(err, luno.ErrAmbiguousAccount) {
+			t.Errorf("Expected ErrAmbiguousAccount, got %v", err)
+		}
+	})
+}
+
+func TestGetAssetBalances(t *testing.T) {
+	var assets []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assets = r.URL.Query()["assets"]
+		w.Write([]byte(`{"balance":[
+			{"account_id":"1","asset":"XBT","balance":"0.5","reserved":"0.1","unconfirmed":"0"},
+			{"account_id":"2","asset":"ZAR","balance":"1000","reserved":"0","unconfirmed":"0"},
+			{"account_id":"3","asset":"XBT","balance":"1.25","reserved":"0","unconfirmed":"0"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL))
+	bals, err := cl.GetAssetBalances(context.Background(), []string{"XBT", "ETH", "ZAR", "USDC"})
+	if !errors.Is(err, luno.ErrNoAccount) {
+		t.Fatalf("Expected ErrNoAccount, got %v", err)
+	}
+	if exp := "luno: no account for asset ETH, USDC"; err.Error() != exp {
+		t.Errorf("Expected %q, got %q", exp, err.Error())
+	}
+	if len(assets) != 4 {
+		t.Errorf("Expected a single request for 4 assets, got %v", assets)
+	}
+	if len(bals) != 2 || bals["XBT"].Balance.String() != "1.75" ||
+		bals["ZAR"].Accounts != 1 {
+		t.Errorf("Unexpected balances %+v", bals)
+	}
+}
@@ -0,0 +1,104 @@
+package luno
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BatchError is returned by Batch if any of the calls failed.
+type BatchError struct {
+	// Errs holds the error of each call, in the order the calls were given,
+	// or nil for calls which succeeded.
+	Errs []error
+}
+
+func (e *BatchError) Error() string {
+	var n int
+	for _, err := range e.Errs {
+		if err != nil {
+			n++
+		}
+	}
+	return fmt.Sprintf("luno: %d of %d calls failed, first: %v",
+		n, len(e.Errs), e.Unwrap())
+}
+
+// Unwrap returns the error of the first call which failed.
+func (e *BatchError) Unwrap() error {
+	for _, err := range e.Errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Batch runs calls concurrently, at most limit at a time, and waits for them
+// to finish. Failed calls don't stop the others. If any call fails, a
+// *BatchError holding the error of every call is returned.
+//
+// The calls usually make requests with a shared Client, whose rate limiter
+// applies as usual, so limit only bounds how many requests are in flight.
+// Calls which haven't started when ctx is done fail with its error. A limit
+// of zero or less runs every call at once.
+//
+// Example:
+//
+//	var ticker *luno.GetTickerResponse
+//	var balances *luno.GetBalancesResponse
+//	err := luno.Batch(ctx, 4,
+//		func(ctx context.Context) (err error) {
+//			ticker, err = cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"})
+//			return err
+//		},
+//		func(ctx context.Context) (err error) {
+//			balances, err = cl.GetBalances(ctx, &luno.GetBalancesRequest{})
+//			return err
+//		},
+//	)
+func Batch(ctx context.Context, limit int, calls ...func(ctx context.Context) error) error {
+	if limit <= 0 || limit > len(calls) {
+		limit = len(calls)
+	}
+
+	var (
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, limit)
+		errs   = make([]error, len(calls))
+		failed bool
+		mu     sync.Mutex
+	)
+	fail := func(i int, err error) {
+		mu.Lock()
+		errs[i] = err
+		failed = true
+		mu.Unlock()
+	}
+	for i, call := range calls {
+		if ctx.Err() != nil {
+			fail(i, ctx.Err())
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			fail(i, ctx.Err())
+			continue
+		}
+		wg.Add(1)
+		go func(i int, call func(context.Context) error) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := call(ctx); err != nil {
+				fail(i, err)
+			}
+		}(i, call)
+	}
+	wg.Wait()
+
+	if !failed {
+		return nil
+	}
+	return &BatchError{Errs: errs}
+}
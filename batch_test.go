@@ -0,0 +1,99 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestBatchBounded(t *testing.T) {
+	var running, maxRunning, done int32
+	calls := make([]func(context.Context) error, 20)
+	for i := range calls {
+		calls[i] = func(ctx context.Context) error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&done, 1)
+			return nil
+		}
+	}
+
+	if err := luno.Batch(context.Background(), 3, calls...); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if done != 20 {
+		t.Errorf("Expected 20 calls, got %d", done)
+	}
+	if maxRunning > 3 {
+		t.Errorf("Expected at most 3 concurrent calls, got %d", maxRunning)
+	}
+}
+
+func TestBatchErrors(t *testing.T) {
+	var done int32
+	calls := make([]func(context.Context) error, 10)
+	for i := range calls {
+		i := i
+		calls[i] = func(ctx context.Context) error {
+			atomic.AddInt32(&done, 1)
+			if i%3 == 0 {
+				return fmt.Errorf("call %d failed", i)
+			}
+			return nil
+		}
+	}
+
+	err := luno.Batch(context.Background(), 2, calls...)
+	var be *luno.BatchError
+	if !errors.As(err, &be) {
+		t.Fatalf("Expected BatchError, got %v", err)
+	}
+	if done != 10 {
+		t.Errorf("Expected every call to run past failures, got %d", done)
+	}
+	for i, err := range be.Errs {
+		if failed := err != nil; failed != (i%3 == 0) {
+			t.Errorf("Unexpected error for call %d: %v", i, err)
+		}
+	}
+	if exp := "luno: 4 of 10 calls failed, first: call 0 failed"; err.Error() != exp {
+		t.Errorf("Expected %q, got %q", exp, err.Error())
+	}
+}
+
+func TestBatchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	calls := []func(context.Context) error{
+		func(ctx context.Context) error {
+			cancel()
+			<-release
+			return nil
+		},
+		func(ctx context.Context) error {
+			t.Errorf("Expected call not to start once cancelled")
+			return nil
+		},
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+
+	err := luno.Batch(ctx, 1, calls...)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
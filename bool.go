@@ -0,0 +1,44 @@
+package luno
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Bool is a boolean returned by the Luno API. Some endpoints return booleans
+// as strings, so it is decoded from a JSON boolean or from one of the strings
+// "true", "false", "1" and "0". It is always encoded as a JSON boolean.
+type Bool bool
+
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "true", `"true"`, `"1"`:
+		*b = true
+	case "false", `"false"`, `"0"`, "null":
+		*b = false
+	default:
+		return fmt.Errorf("luno: invalid boolean %s", data)
+	}
+	return nil
+}
+
+func (b Bool) MarshalJSON() ([]byte, error) {
+	if b {
+		return []byte("true"), nil
+	}
+	return []byte("false"), nil
+}
+
+// UnmarshalJSON decodes t, accepting is_buy as any of the forms of a Bool.
+func (t *Trade) UnmarshalJSON(data []byte) error {
+	type trade Trade
+	v := struct {
+		*trade
+		IsBuy Bool `json:"is_buy"`
+	}{trade: (*trade)(t)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	t.IsBuy = bool(v.IsBuy)
+	return nil
+}
@@ -0,0 +1,84 @@
+package luno_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestBoolUnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		in  string
+		exp luno.Bool
+		err bool
+	}{
+		{in: "true", exp: true},
+		{in: "false", exp: false},
+		{in: `"true"`, exp: true},
+		{in: `"false"`, exp: false},
+		{in: `"1"`, exp: true},
+		{in: `"0"`, exp: false},
+		{in: "null", exp: false},
+		{in: `""`, err: true},
+		{in: "1", err: true},
+		{in: `"yes"`, err: true},
+		{in: `"TRUE"`, err: true},
+		{in: "{}", err: true},
+	}
+	for _, test := range testCases {
+		act := luno.Bool(!test.exp)
+		err := act.UnmarshalJSON([]byte(test.in))
+		if test.err {
+			if err == nil {
+				t.Errorf("Expected unmarshalling %s to fail", test.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Expected %s to unmarshal, got %v", test.in, err)
+			continue
+		}
+		if act != test.exp {
+			t.Errorf("Expected %s to unmarshal as %v, got %v", test.in, test.exp, act)
+		}
+	}
+}
+
+func TestBoolTrade(t *testing.T) {
+	for _, in := range []string{`{"is_buy":true}`, `{"is_buy":"true"}`, `{"is_buy":"1"}`} {
+		var trade luno.Trade
+		if err := json.Unmarshal([]byte(in), &trade); err != nil {
+			t.Fatalf("Expected %s to unmarshal, got %v", in, err)
+		}
+		if !trade.IsBuy {
+			t.Errorf("Expected %s to be a buy", in)
+		}
+		b, err := json.Marshal(trade.IsBuy)
+		if err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		if string(b) != "true" {
+			t.Errorf("Expected true to marshal as true, got %s", b)
+		}
+	}
+}
+
+func TestTradeUnmarshalJSON(t *testing.T) {
+	for _, in := range []string{`false`, `"false"`, `"0"`} {
+		var trade luno.Trade
+		b := []byte(`{"order_id":"BXO1","price":"100.5","is_buy":` + in + `,"timestamp":1000}`)
+		if err := json.Unmarshal(b, &trade); err != nil {
+			t.Fatalf("Expected %s to unmarshal, got %v", b, err)
+		}
+		if trade.IsBuy || trade.OrderId != "BXO1" || trade.Price.String() != "100.5" ||
+			time.Time(trade.Timestamp).UnixNano() != 1e9 {
+			t.Errorf("Expected a sell trade with all fields decoded from %s, got %+v", b, trade)
+		}
+	}
+	var trade luno.Trade
+	if err := json.Unmarshal([]byte(`{"is_buy":"yes"}`), &trade); err == nil {
+		t.Errorf("Expected invalid is_buy to fail")
+	}
+}
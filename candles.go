@@ -0,0 +1,219 @@
+package luno
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// candleDurations are the candle durations supported by GetCandles, longest
+// first.
+var candleDurations = []time.Duration{
+	7 * 24 * time.Hour,
+	3 * 24 * time.Hour,
+	24 * time.Hour,
+	8 * time.Hour,
+	4 * time.Hour,
+	3 * time.Hour,
+	time.Hour,
+	30 * time.Minute,
+	15 * time.Minute,
+	5 * time.Minute,
+	time.Minute,
+}
+
+// candlesPageSize is the maximum number of candles returned by GetCandles.
+const candlesPageSize = 1000
+
+// publicTradesPageSize is the maximum number of trades returned by ListTrades.
+const publicTradesPageSize = 100
+
+// Candles returns the candles of duration d of pair from the one containing
+// since up to now, oldest first. Unlike GetCandles, any d which is a whole
+// number of seconds is supported and as many pages are fetched as needed.
+//
+// If d is a multiple of a duration supported by GetCandles the candles of
+// the longest such duration are fetched and aggregated with
+// AggregateCandles. Otherwise the candles are built from the public trades
+// of pair with CandlesFromTrades, which only covers the last 24 hours.
+func (cl *Client) Candles(ctx context.Context, pair string, since time.Time,
+	d time.Duration) ([]Candle, error) {
+
+	if d <= 0 || d%time.Second != 0 {
+		return nil, fmt.Errorf("luno: invalid candle duration %v", d)
+	}
+	var src time.Duration
+	for _, cd := range candleDurations {
+		if d%cd == 0 {
+			src = cd
+			break
+		}
+	}
+	if src == 0 {
+		return cl.CandlesFromTrades(ctx, pair, since, d)
+	}
+
+	// Candles are fetched from the start of the first candle of duration d,
+	// so that it isn't aggregated from only some of its source candles.
+	start := candleStart(since, d)
+	var candles []Candle
+	for {
+		res, err := cl.GetCandles(ctx, &GetCandlesRequest{
+			Pair:     pair,
+			Duration: int64(src / time.Second),
+			Since:    Time(start),
+		})
+		if err != nil {
+			return nil, err
+		}
+		candles = append(candles, res.Candles...)
+		if len(res.Candles) < candlesPageSize {
+			break
+		}
+		start = time.Time(res.Candles[len(res.Candles)-1].Timestamp).Add(src)
+	}
+	if src == d {
+		return candles, nil
+	}
+	return AggregateCandles(candles, d)
+}
+
+// CandlesFromTrades builds the candles of duration d of pair from the one
+// containing since up to now from its public trades, oldest first, using
+// ListTrades. It supports bar sizes which GetCandles doesn't, such as 10
+// seconds or 2 minutes, but ListTrades only returns trades of the last 24
+// hours, so earlier candles are missing. Periods without trades have no
+// candle.
+func (cl *Client) CandlesFromTrades(ctx context.Context, pair string,
+	since time.Time, d time.Duration) ([]Candle, error) {
+
+	if d <= 0 {
+		return nil, fmt.Errorf("luno: invalid candle duration %v", d)
+	}
+
+	start := candleStart(since, d)
+	seen := make(map[int64]bool)
+	var trades []Trade
+	for {
+		res, err := cl.ListTrades(ctx, &ListTradesRequest{Pair: pair, Since: Time(start)})
+		if err != nil {
+			return nil, err
+		}
+		var latest time.Time
+		for _, t := range res.Trades {
+			if ts := time.Time(t.Timestamp); ts.After(latest) {
+				latest = ts
+			}
+			// Trades at the start of a page may have been on the previous
+			// one.
+			if t.Sequence != 0 && seen[t.Sequence] {
+				continue
+			}
+			seen[t.Sequence] = true
+			trades = append(trades, t)
+		}
+		if len(res.Trades) < publicTradesPageSize || !latest.After(start) {
+			break
+		}
+		start = latest
+	}
+	return AggregateTrades(trades, d), nil
+}
+
+// AggregateTrades builds candles of duration d from trades, oldest first.
+// Candles start at multiples of d since the Unix epoch, so that for example
+// hourly candles start on the hour. Periods without trades have no candle.
+func AggregateTrades(trades []Trade, d time.Duration) []Candle {
+	trades = append([]Trade(nil), trades...)
+	sort.SliceStable(trades, func(i, j int) bool {
+		ti, tj := time.Time(trades[i].Timestamp), time.Time(trades[j].Timestamp)
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return trades[i].Sequence < trades[j].Sequence
+	})
+
+	var candles []Candle
+	for _, t := range trades {
+		start := candleStart(time.Time(t.Timestamp), d)
+		n := len(candles)
+		if n == 0 || !time.Time(candles[n-1].Timestamp).Equal(start) {
+			candles = append(candles, Candle{
+				Timestamp: Time(start),
+				Open:      t.Price,
+				High:      t.Price,
+				Low:       t.Price,
+				Close:     t.Price,
+				Volume:    decimal.Zero(),
+			})
+			n++
+		}
+		c := &candles[n-1]
+		if t.Price.Cmp(c.High) > 0 {
+			c.High = t.Price
+		}
+		if t.Price.Cmp(c.Low) < 0 {
+			c.Low = t.Price
+		}
+		c.Close = t.Price
+		c.Volume = c.Volume.Add(t.Volume)
+	}
+	return candles
+}
+
+// AggregateCandles combines candles, oldest first, into candles of duration
+// d, e.g. hourly candles into 2 hour ones. Like AggregateTrades, candles
+// start at multiples of d since the Unix epoch. d should be a multiple of the
+// duration of candles, otherwise candles straddling two periods are counted
+// in the first.
+func AggregateCandles(candles []Candle, d time.Duration) ([]Candle, error) {
+	if d <= 0 {
+		return nil, fmt.Errorf("luno: invalid candle duration %v", d)
+	}
+	var res []Candle
+	for i, c := range candles {
+		ts := time.Time(c.Timestamp)
+		if i > 0 {
+			prev := time.Time(candles[i-1].Timestamp)
+			if !ts.After(prev) {
+				return nil, fmt.Errorf("luno: candle at %v isn't after %v",
+					ts.Format(time.RFC3339), prev.Format(time.RFC3339))
+			}
+		}
+
+		start := candleStart(ts, d)
+		n := len(res)
+		if n == 0 || !time.Time(res[n-1].Timestamp).Equal(start) {
+			c.Timestamp = Time(start)
+			res = append(res, c)
+			continue
+		}
+		agg := &res[n-1]
+		if c.High.Cmp(agg.High) > 0 {
+			agg.High = c.High
+		}
+		if c.Low.Cmp(agg.Low) < 0 {
+			agg.Low = c.Low
+		}
+		agg.Close = c.Close
+		agg.Volume = agg.Volume.Add(c.Volume)
+	}
+	return res, nil
+}
+
+// candleStart returns the start of the candle of duration d containing t.
+func candleStart(t time.Time, d time.Duration) time.Time {
+	ms := t.UnixNano() / int64(time.Millisecond)
+	dms := int64(d / time.Millisecond)
+	if dms <= 0 {
+		return t
+	}
+	start := ms - ms%dms
+	if ms%dms < 0 {
+		start -= dms
+	}
+	return time.Unix(0, start*int64(time.Millisecond)).UTC()
+}
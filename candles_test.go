@@ -0,0 +1,151 @@
+package luno_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func formatCandles(candles []luno.Candle) []string {
+	var res []string
+	for _, c := range candles {
+		res = append(res, fmt.Sprintf("%s o=%s h=%s l=%s c=%s v=%s",
+			time.Time(c.Timestamp).UTC().Format("15:04:05"),
+			c.Open, c.High, c.Low, c.Close, c.Volume))
+	}
+	return res
+}
+
+func checkCandles(t *testing.T, got []luno.Candle, exp ...string) {
+	t.Helper()
+	s := formatCandles(got)
+	if len(s) != len(exp) {
+		t.Fatalf("Expected %d candles, got %v", len(exp), s)
+	}
+	for i := range exp {
+		if s[i] != exp[i] {
+			t.Errorf("Expected candle %d to be %q, got %q", i, exp[i], s[i])
+		}
+	}
+}
+
+func makeTrade(ts time.Time, seq int64, price, volume int64) luno.Trade {
+	return luno.Trade{
+		Timestamp: luno.Time(ts),
+		Sequence:  seq,
+		Price:     decimal.NewFromInt64(price),
+		Volume:    decimal.NewFromInt64(volume),
+	}
+}
+
+func TestAggregateTrades(t *testing.T) {
+	t0 := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	trades := []luno.Trade{
+		makeTrade(t0.Add(25*time.Second), 5, 102, 1),
+		makeTrade(t0.Add(2*time.Second), 1, 100, 1),
+		makeTrade(t0.Add(5*time.Second), 2, 105, 2),
+		makeTrade(t0.Add(9*time.Second), 3, 98, 1),
+		makeTrade(t0.Add(9*time.Second), 4, 99, 3),
+	}
+	checkCandles(t, luno.AggregateTrades(trades, 10*time.Second),
+		"10:00:00 o=100 h=105 l=98 c=99 v=7",
+		"10:00:20 o=102 h=102 l=102 c=102 v=1")
+}
+
+func TestAggregateCandles(t *testing.T) {
+	t0 := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	var candles []luno.Candle
+	for i, ohlc := range [][4]int64{
+		{100, 110, 95, 105},
+		{105, 120, 100, 115},
+		{115, 116, 90, 92},
+	} {
+		candles = append(candles, luno.Candle{
+			Timestamp: luno.Time(t0.Add(time.Duration(i) * time.Hour)),
+			Open:      decimal.NewFromInt64(ohlc[0]),
+			High:      decimal.NewFromInt64(ohlc[1]),
+			Low:       decimal.NewFromInt64(ohlc[2]),
+			Close:     decimal.NewFromInt64(ohlc[3]),
+			Volume:    decimal.NewFromInt64(int64(i + 1)),
+		})
+	}
+
+	got, err := luno.AggregateCandles(candles, 2*time.Hour)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	checkCandles(t, got,
+		"10:00:00 o=100 h=120 l=95 c=115 v=3",
+		"12:00:00 o=115 h=116 l=90 c=92 v=3")
+
+	candles[1], candles[2] = candles[2], candles[1]
+	if _, err := luno.AggregateCandles(candles, 2*time.Hour); err == nil {
+		t.Errorf("Expected error for unordered candles, got nil")
+	}
+}
+
+func TestCandles(t *testing.T) {
+	t0 := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	var durations []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/exchange/1/candles" {
+			t.Errorf("Unexpected request for %s", r.URL.Path)
+		}
+		durations = append(durations, r.URL.Query().Get("duration"))
+		since, _ := strconv.ParseInt(r.URL.Query().Get("since"), 10, 64)
+		if exp := t0.UnixNano() / 1e6; since != exp {
+			t.Errorf("Expected since %d, got %d", exp, since)
+		}
+		fmt.Fprintf(w, `{"candles":[
+			{"timestamp":%d,"open":"1","high":"3","low":"1","close":"2","volume":"1"},
+			{"timestamp":%d,"open":"2","high":"4","low":"2","close":"4","volume":"1"}]}`,
+			t0.UnixNano()/1e6, t0.Add(time.Hour).UnixNano()/1e6)
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL))
+	got, err := cl.Candles(context.Background(), "XBTZAR", t0.Add(30*time.Minute), 2*time.Hour)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	checkCandles(t, got, "10:00:00 o=1 h=4 l=1 c=4 v=2")
+	if len(durations) != 1 || durations[0] != "3600" {
+		t.Errorf("Expected hourly candles to be fetched, got durations %v", durations)
+	}
+
+	if _, err := cl.Candles(context.Background(), "XBTZAR", t0, 1500*time.Millisecond); err == nil {
+		t.Errorf("Expected error for fractional duration, got nil")
+	}
+}
+
+func TestCandlesFromTrades(t *testing.T) {
+	t0 := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/1/trades" {
+			t.Errorf("Unexpected request for %s", r.URL.Path)
+		}
+		ms := t0.UnixNano() / 1e6
+		fmt.Fprintf(w, `{"trades":[
+			{"timestamp":%d,"sequence":1,"price":"100","volume":"1"},
+			{"timestamp":%d,"sequence":2,"price":"103","volume":"2"},
+			{"timestamp":%d,"sequence":3,"price":"101","volume":"1"}]}`,
+			ms+1000, ms+6000, ms+8000)
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL))
+	got, err := cl.CandlesFromTrades(context.Background(), "XBTZAR", t0, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	checkCandles(t, got,
+		"10:00:00 o=100 h=100 l=100 c=100 v=1",
+		"10:00:05 o=103 h=103 l=101 c=101 v=3")
+}
@@ -0,0 +1,140 @@
+// Package chaos provides an HTTP transport which injects artificial latency
+// and failures into requests, to test how code using the Luno client behaves
+// on a slow or unreliable network.
+//
+// Install it on a client used in tests:
+//
+//	tr := chaos.New(nil, chaos.Config{
+//		Latency:     200 * time.Millisecond,
+//		Jitter:      100 * time.Millisecond,
+//		FailureRate: 0.1,
+//		Seed:        1,
+//	})
+//	cl := luno.NewClient(luno.WithHTTPClient(&http.Client{Transport: tr}))
+//
+// With the same seed and sequence of requests, the same delays and failures
+// are injected on every run.
+package chaos
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInjected is the error returned for injected network failures.
+var ErrInjected = errors.New("chaos: injected failure")
+
+// Config configures the latency and failures injected by a Transport.
+type Config struct {
+	// Latency is added before every request.
+	Latency time.Duration
+
+	// Jitter adds a random delay of up to Jitter on top of Latency.
+	Jitter time.Duration
+
+	// FailureRate is the probability, between 0 and 1, that a request fails
+	// instead of being sent.
+	FailureRate float64
+
+	// FailureStatus is the HTTP status code of the response returned for
+	// failed requests, e.g. http.StatusServiceUnavailable. If it is zero,
+	// failed requests return ErrInjected as a network error instead.
+	FailureStatus int
+
+	// Seed seeds the random source, so that runs can be reproduced.
+	Seed int64
+}
+
+// Transport is an http.RoundTripper which delays requests, and fails some of
+// them, before delegating to another transport. It is safe for concurrent
+// use, but the delays and failures are only reproducible if requests are made
+// in the same order.
+type Transport struct {
+	cfg       Config
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	requests int
+	failures int
+}
+
+// New returns a Transport which injects latency and failures as configured
+// by cfg before sending requests with transport. http.DefaultTransport is
+// used if transport is nil.
+func New(transport http.RoundTripper, cfg Config) *Transport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Transport{
+		cfg:       cfg,
+		transport: transport,
+		rand:      rand.New(rand.NewSource(cfg.Seed)),
+	}
+}
+
+// Requests returns the number of requests received by the transport,
+// including failed ones.
+func (t *Transport) Requests() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.requests
+}
+
+// Failures returns the number of injected failures.
+func (t *Transport) Failures() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.failures
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.requests++
+	delay := t.cfg.Latency
+	if t.cfg.Jitter > 0 {
+		delay += time.Duration(t.rand.Int63n(int64(t.cfg.Jitter)))
+	}
+	fail := t.cfg.FailureRate > 0 && t.rand.Float64() < t.cfg.FailureRate
+	if fail {
+		t.failures++
+	}
+	t.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+
+	if !fail {
+		return t.transport.RoundTrip(req)
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	if t.cfg.FailureStatus == 0 {
+		return nil, ErrInjected
+	}
+	return &http.Response{
+		Status:     http.StatusText(t.cfg.FailureStatus),
+		StatusCode: t.cfg.FailureStatus,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body: ioutil.NopCloser(strings.NewReader(
+			`{"error":"Injected failure","error_code":"ErrInjected"}`)),
+		Request: req,
+	}, nil
+}
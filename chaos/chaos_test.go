@@ -0,0 +1,119 @@
+package chaos_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/chaos"
+)
+
+func newTickerServer(hits *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		w.Write([]byte(`{"pair":"XBTZAR","last_trade":"100"}`))
+	}))
+}
+
+func newClient(srv *httptest.Server, tr *chaos.Transport) *luno.Client {
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetHTTPClient(&http.Client{Transport: tr})
+	cl.SetMaxRetries(50)
+	cl.SetRetryBackoff(luno.ConstantBackoff(time.Millisecond))
+	return cl
+}
+
+func TestLatency(t *testing.T) {
+	var hits int32
+	srv := newTickerServer(&hits)
+	defer srv.Close()
+
+	tr := chaos.New(nil, chaos.Config{Latency: 50 * time.Millisecond})
+	cl := newClient(srv, tr)
+
+	start := time.Now()
+	if _, err := cl.GetTicker(context.Background(),
+		&luno.GetTickerRequest{Pair: "XBTZAR"}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Errorf("Expected at least 50ms of latency, got %s", d)
+	}
+
+	// The injected latency counts towards the request timeout.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := cl.GetTicker(ctx,
+		&luno.GetTickerRequest{Pair: "XBTZAR"}); err == nil {
+		t.Errorf("Expected timeout")
+	}
+	if hits != 1 {
+		t.Errorf("Expected 1 request to reach the server, got %d", hits)
+	}
+}
+
+func TestFailuresRetried(t *testing.T) {
+	for _, status := range []int{0, http.StatusServiceUnavailable} {
+		var hits int32
+		srv := newTickerServer(&hits)
+
+		tr := chaos.New(nil, chaos.Config{
+			FailureRate:   0.5,
+			FailureStatus: status,
+			Seed:          42,
+		})
+		cl := newClient(srv, tr)
+
+		for i := 0; i < 20; i++ {
+			if _, err := cl.GetTicker(context.Background(),
+				&luno.GetTickerRequest{Pair: "XBTZAR"}); err != nil {
+				t.Fatalf("Expected failures to be retried, got %v", err)
+			}
+		}
+		srv.Close()
+
+		if tr.Failures() == 0 {
+			t.Errorf("Expected injected failures with status %d", status)
+		}
+		if hits != 20 {
+			t.Errorf("Expected 20 requests to reach the server, got %d", hits)
+		}
+		if tr.Requests() != 20+tr.Failures() {
+			t.Errorf("Expected every failure to be retried, got %d requests "+
+				"and %d failures", tr.Requests(), tr.Failures())
+		}
+	}
+}
+
+func TestSeedReproducible(t *testing.T) {
+	failures := func() []bool {
+		var hits int32
+		srv := newTickerServer(&hits)
+		defer srv.Close()
+
+		tr := chaos.New(nil, chaos.Config{FailureRate: 0.5, Seed: 7})
+		cl := newClient(srv, tr)
+		cl.SetMaxRetries(0)
+
+		var res []bool
+		for i := 0; i < 20; i++ {
+			_, err := cl.GetTicker(context.Background(),
+				&luno.GetTickerRequest{Pair: "XBTZAR"})
+			res = append(res, err != nil)
+		}
+		return res
+	}
+
+	a, b := failures(), failures()
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("Expected the same failures with the same seed, got %v and %v",
+				a, b)
+		}
+	}
+}
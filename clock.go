@@ -0,0 +1,59 @@
+package luno
+
+import "time"
+
+// Clock is the source of time for the client, used for retries, rate
+// limiting, caches and the checks of timestamps such as quote expiry. It can
+// be replaced with SetClock, e.g. by a fake in tests so that time-dependent
+// logic can be tested deterministically.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	Sleep(d time.Duration)
+}
+
+// realClock is a clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// SetClock sets the clock used by the client. A nil clock restores the system
+// clock, which is the default.
+//
+// Rate limiters and hosts already set up with SetRateLimit, SetAuthRateLimit
+// and SetHosts switch to the new clock too, including those shared with
+// clones of the client, see Clone.
+func (cl *Client) SetClock(c Clock) {
+	if c == nil {
+		c = realClock{}
+	}
+	cl.clock = c
+	if cl.limiter != nil {
+		cl.limiter.setClock(c)
+	}
+	if cl.authLimiter != nil {
+		cl.authLimiter.setClock(c)
+	}
+	if cl.hosts != nil {
+		cl.hosts.setClock(c)
+	}
+}
+
+// ServerNow returns the current time of the exchange, i.e. the time of the
+// client's clock corrected for the clock skew observed in responses, see
+// ServerClockSkew. It is the client's clock time if no skew has been
+// observed yet.
+func (cl *Client) ServerNow() time.Time {
+	skew, _ := cl.skew.get()
+	return cl.clock.Now().Add(skew)
+}
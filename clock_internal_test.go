@@ -0,0 +1,183 @@
+package luno
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock which only moves when advanced.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(1600000000, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// Advance moves the clock forward by d, firing any waiters which are due.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	var pending []fakeWaiter
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// BlockUntil waits until n waiters are pending and returns the durations
+// until they are due.
+func (c *fakeClock) BlockUntil(n int) []time.Duration {
+	for {
+		c.mu.Lock()
+		if len(c.waiters) >= n {
+			var ds []time.Duration
+			for _, w := range c.waiters {
+				ds = append(ds, w.at.Sub(c.now))
+			}
+			c.mu.Unlock()
+			return ds
+		}
+		c.mu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDoBackoffSchedule(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 4 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	clk := newFakeClock()
+	cl := NewClient()
+	cl.clock = clk
+	cl.SetBaseURL(srv.URL)
+	cl.SetMaxRetries(3)
+	cl.SetRetryBackoff(ExponentialBackoff{Base: time.Second, Max: 3 * time.Second})
+
+	done := make(chan error)
+	go func() {
+		var res interface{}
+		done <- cl.do(context.Background(), "GET", "/", nil, &res, false)
+	}()
+
+	start := clk.Now()
+	for _, exp := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+		ds := clk.BlockUntil(1)
+		if len(ds) != 1 || ds[0] != exp {
+			t.Fatalf("Expected a single backoff of %s, got %v", exp, ds)
+		}
+		clk.Advance(exp)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+	if attempts != 4 {
+		t.Errorf("Expected 4 attempts, got %d", attempts)
+	}
+	if elapsed := clk.Now().Sub(start); elapsed != 6*time.Second {
+		t.Errorf("Expected 6s of backoff, got %s", elapsed)
+	}
+}
+
+func TestSetClockAfterLimits(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetRateLimitPolicy(RateLimitReject)
+	cl.SetRateLimit(60, 1)
+	cl.SetAuthRateLimit(60, 1)
+	if err := cl.SetHosts([]string{srv.URL, srv.URL + "/"}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	clk := newFakeClock()
+	cl.SetClock(clk)
+	if cl.authLimiter.clock != clk || cl.hosts.clock != clk {
+		t.Errorf("Expected the rate limiters and hosts to use the new clock")
+	}
+
+	var res interface{}
+	if err := cl.do(context.Background(), "GET", "/", nil, &res, false); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if err := cl.do(context.Background(), "GET", "/", nil, &res, false); err == nil {
+		t.Fatalf("Expected rate limit error, got nil")
+	}
+	clk.Advance(time.Second)
+	if err := cl.do(context.Background(), "GET", "/", nil, &res, false); err != nil {
+		t.Errorf("Expected success after the fake clock refilled the bucket, got %v", err)
+	}
+}
+
+func TestBudgetUsesClock(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	clk := &fakeClock{now: time.Now().Add(time.Hour)}
+	var budget time.Duration
+	cl := NewClient(WithBaseURL(srv.URL), WithClock(clk), WithHooks(Hooks{
+		BeforeRequest: func(ctx context.Context, info RequestInfo) {
+			budget = info.Budget
+		},
+	}))
+
+	ctx, cancel := context.WithDeadline(context.Background(), clk.Now().Add(time.Minute))
+	defer cancel()
+	var res interface{}
+	if err := cl.do(ctx, "GET", "/", nil, &res, false); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if budget != time.Minute {
+		t.Errorf("Expected a budget of 1m by the client's clock, got %s", budget)
+	}
+}
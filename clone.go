@@ -0,0 +1,105 @@
+package luno
+
+import "github.com/luno/luno-go/decimal"
+
+// Clone returns a new client with the same configuration as cl, such as the
+// credentials, base URL, retry policy and hooks, with opts applied on top.
+// The clone can be configured independently afterwards, without affecting
+// cl.
+//
+// The clone shares cl's HTTP transport, and so its connection pool, as well
+// as its rate limiters, since the API limits requests per key, unless the
+// clone is given its own with WithRateLimit, SetRateLimit or
+// SetAuthRateLimit. Caches, such as those of market info and fees, and
+// conditional request state start empty.
+//
+// Example:
+//
+//	orders := cl.Clone(luno.WithTimeout(time.Second), luno.WithHooks(orderHooks))
+func (cl *Client) Clone(opts ...Option) *Client {
+	hc := *cl.httpClient
+
+	cl.authMu.RLock()
+	creds := cl.creds
+	cl.authMu.RUnlock()
+
+	c := &Client{
+		httpClient:     &hc,
+		baseURL:        cl.baseURL,
+		debug:          cl.debug,
+		maxRetries:     cl.maxRetries,
+		backoff:        cl.backoff,
+		maxRetryDelay:  cl.maxRetryDelay,
+		clock:          cl.clock,
+		redirectPolicy: cl.redirectPolicy,
+		checksRedirect: cl.checksRedirect,
+
+		readTimeout:    cl.readTimeout,
+		writeTimeout:   cl.writeTimeout,
+		staleThreshold: cl.staleThreshold,
+		retryPredicate: cl.retryPredicate,
+
+		retryRateLimitOnly: cl.retryRateLimitOnly,
+
+		creds:  creds,
+		signer: cl.signer,
+
+		maxResponseBytes:    cl.maxResponseBytes,
+		hooks:               cl.hooks,
+		correlationIDHeader: cl.correlationIDHeader,
+		userAgentSuffix:     cl.userAgentSuffix,
+		newEncoder:          cl.newEncoder,
+		newDecoder:          cl.newDecoder,
+
+		limiter:         cl.limiter,
+		authLimiter:     cl.authLimiter,
+		rateLimitPolicy: cl.rateLimitPolicy,
+		metrics:         cl.metrics,
+		logger:          cl.logger,
+		hosts:           cl.hosts,
+
+		validateAddresses: cl.validateAddresses,
+		normalizePairs:    cl.normalizePairs,
+		validatePairs:     cl.validatePairs,
+		metadataPolicy:    cl.metadataPolicy,
+		strictDecoding:    cl.strictDecoding,
+	}
+	if cl.checksRedirect {
+		// Otherwise the clone would follow cl's redirect policy.
+		c.httpClient.CheckRedirect = c.checkRedirect
+	}
+
+	c.interceptors = append([]Interceptor(nil), cl.interceptors...)
+
+	c.redactedParams = make(map[string]bool, len(cl.redactedParams))
+	for k, v := range cl.redactedParams {
+		c.redactedParams[k] = v
+	}
+	if cl.maxNotional != nil {
+		c.maxNotional = make(map[string]decimal.Decimal, len(cl.maxNotional))
+		for k, v := range cl.maxNotional {
+			c.maxNotional[k] = v
+		}
+	}
+	if cl.etags != nil {
+		c.SetConditionalRequests(true)
+	}
+	if cl.dedup != nil {
+		c.SetRequestDedup(true)
+	}
+
+	cl.tickerCache.mu.Lock()
+	c.tickerCache.ttl = cl.tickerCache.ttl
+	cl.tickerCache.mu.Unlock()
+
+	cl.authFailure.mu.Lock()
+	c.authFailure.fn = cl.authFailure.fn
+	cl.authFailure.mu.Unlock()
+
+	c.skew.setThreshold(cl.skew.getThreshold())
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
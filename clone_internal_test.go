@@ -0,0 +1,105 @@
+package luno
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+func TestClone(t *testing.T) {
+	var auth []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	if err := cl.SetAuth("key", "secret"); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	cl.SetRateLimit(60, 10)
+	cl.SetMaxOrderNotional("ZAR", decimal.NewFromInt64(1000))
+
+	var origHooks, cloneHooks int
+	cl.SetHooks(Hooks{BeforeRequest: func(context.Context, RequestInfo) {
+		origHooks++
+	}})
+
+	c := cl.Clone(WithTimeout(time.Second), WithHooks(Hooks{
+		BeforeRequest: func(context.Context, RequestInfo) { cloneHooks++ },
+	}))
+
+	if c.httpClient == cl.httpClient {
+		t.Fatalf("Expected the clone to have its own HTTP client")
+	}
+	if c.httpClient.Transport != cl.httpClient.Transport {
+		t.Errorf("Expected the transport to be shared")
+	}
+	if c.httpClient.Timeout != time.Second {
+		t.Errorf("Expected clone timeout 1s, got %s", c.httpClient.Timeout)
+	}
+	if cl.httpClient.Timeout != defaultTimeout {
+		t.Errorf("Expected original timeout %s, got %s", defaultTimeout,
+			cl.httpClient.Timeout)
+	}
+	if c.limiter != cl.limiter {
+		t.Errorf("Expected the rate limiter to be shared by default")
+	}
+
+	var res interface{}
+	for _, x := range []*Client{cl, c} {
+		if err := x.do(context.Background(), "GET", "/", nil, &res, true); err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+	}
+	if origHooks != 1 || cloneHooks != 1 {
+		t.Errorf("Expected one hook call each, got %d and %d", origHooks, cloneHooks)
+	}
+	if len(auth) != 2 || auth[0] == "" || auth[0] != auth[1] {
+		t.Errorf("Expected both clients to use the same credentials, got %q", auth)
+	}
+
+	// Changes to the clone don't affect the original.
+	c.SetMaxOrderNotional("ZAR", decimal.NewFromInt64(1))
+	c.SetMaxOrderNotional("NGN", decimal.NewFromInt64(1))
+	if max := cl.maxNotional["ZAR"]; max.Cmp(decimal.NewFromInt64(1000)) != 0 {
+		t.Errorf("Expected original notional limit 1000, got %s", max)
+	}
+	if _, ok := cl.maxNotional["NGN"]; ok {
+		t.Errorf("Expected no NGN limit on the original")
+	}
+	c.SetTimeout(2 * time.Second)
+	c.SetBaseURL("http://example.com")
+	if cl.httpClient.Timeout != defaultTimeout || cl.baseURL != srv.URL {
+		t.Errorf("Expected the original to be unchanged")
+	}
+	c.SetRedirectPolicy(RedirectNone)
+	if cl.redirectPolicy != RedirectSameHost {
+		t.Errorf("Expected original redirect policy to be unchanged")
+	}
+
+	// A clone can have its own rate limiter.
+	c = cl.Clone(WithRateLimit(120, 1))
+	if c.limiter == cl.limiter || c.limiter == nil {
+		t.Errorf("Expected the clone to have its own rate limiter")
+	}
+}
+
+func TestCloneRedirectPolicy(t *testing.T) {
+	cl := NewClient()
+	c := cl.Clone()
+
+	// The clone's redirects follow its own policy.
+	cl.SetRedirectPolicy(RedirectNone)
+	req, _ := http.NewRequest("GET", "https://api.luno.com/b", nil)
+	via, _ := http.NewRequest("GET", "https://api.luno.com/a", nil)
+	if err := c.httpClient.CheckRedirect(req, []*http.Request{via}); err != nil {
+		t.Errorf("Expected the clone to follow same host redirects, got %v", err)
+	}
+}
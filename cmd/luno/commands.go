@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+// requestTimeout limits each command's requests, including retries.
+const requestTimeout = 30 * time.Second
+
+func (e *env) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), requestTimeout)
+}
+
+func (e *env) table() *tabwriter.Writer {
+	return tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+}
+
+func (e *env) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	return fs
+}
+
+// decimalFlag is a flag holding a decimal, which is unset if it is zero.
+type decimalFlag struct {
+	d decimal.Decimal
+}
+
+func (f *decimalFlag) String() string {
+	return f.d.String()
+}
+
+func (f *decimalFlag) Set(s string) error {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return err
+	}
+	f.d = d
+	return nil
+}
+
+func (f *decimalFlag) isSet() bool {
+	return f.d.Sign() != 0
+}
+
+func (e *env) ticker(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: luno ticker PAIR")
+	}
+	ctx, cancel := e.context()
+	defer cancel()
+
+	res, err := e.cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: strings.ToUpper(args[0])})
+	if err != nil {
+		return err
+	}
+	w := e.table()
+	fmt.Fprintf(w, "PAIR\tBID\tASK\tLAST\t24H VOLUME\tSTATUS\n")
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", res.Pair, res.Bid, res.Ask,
+		res.LastTrade, res.Rolling24HourVolume, res.Status)
+	return w.Flush()
+}
+
+func (e *env) balance(args []string) error {
+	ctx, cancel := e.context()
+	defer cancel()
+
+	req := &luno.GetBalancesRequest{}
+	for _, a := range args {
+		req.Assets = append(req.Assets, strings.ToUpper(a))
+	}
+	res, err := e.cl.GetBalances(ctx, req)
+	if err != nil {
+		return err
+	}
+	w := e.table()
+	fmt.Fprintf(w, "ACCOUNT\tASSET\tBALANCE\tRESERVED\tUNCONFIRMED\tNAME\n")
+	for _, b := range res.Balance {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", b.AccountId, b.Asset,
+			b.Balance, b.Reserved, b.Unconfirmed, b.Name)
+	}
+	return w.Flush()
+}
+
+func (e *env) orders(args []string) error {
+	if len(args) == 0 || args[0] != "list" {
+		return errors.New("usage: luno orders list [-pair PAIR] [-state PENDING|COMPLETE]")
+	}
+	fs := e.flags("orders list")
+	pair := fs.String("pair", "", "only list orders of this market")
+	state := fs.String("state", "", "only list orders in this state, PENDING or COMPLETE")
+	limit := fs.Int64("limit", 0, "maximum number of orders to list")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	req := &luno.ListOrdersRequest{Pair: strings.ToUpper(*pair), Limit: *limit}
+	if *state != "" {
+		s, err := luno.ParseOrderState(*state)
+		if err != nil {
+			return err
+		}
+		req.State = s
+	}
+	ctx, cancel := e.context()
+	defer cancel()
+
+	res, err := e.cl.ListOrders(ctx, req)
+	if err != nil {
+		return err
+	}
+	w := e.table()
+	fmt.Fprintf(w, "ID\tPAIR\tTYPE\tSTATE\tPRICE\tVOLUME\tFILLED\tCREATED\n")
+	for _, o := range res.Orders {
+		printOrder(w, o)
+	}
+	return w.Flush()
+}
+
+func printOrder(w *tabwriter.Writer, o luno.Order) {
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", o.OrderId, o.Pair, o.Type,
+		o.State, o.LimitPrice, o.LimitVolume, o.Base,
+		time.Time(o.CreationTimestamp).Format(time.RFC3339))
+}
+
+func (e *env) order(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: luno order get|place|stop")
+	}
+	switch args[0] {
+	case "get":
+		return e.orderGet(args[1:])
+	case "place":
+		return e.orderPlace(args[1:])
+	case "stop":
+		return e.orderStop(args[1:])
+	}
+	return fmt.Errorf("unknown order command %q", args[0])
+}
+
+func (e *env) orderGet(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: luno order get ID")
+	}
+	ctx, cancel := e.context()
+	defer cancel()
+
+	res, err := e.cl.GetOrder(ctx, &luno.GetOrderRequest{Id: args[0]})
+	if err != nil {
+		return err
+	}
+	w := e.table()
+	fmt.Fprintf(w, "ID\tPAIR\tTYPE\tSTATE\tPRICE\tVOLUME\tFILLED\tCREATED\n")
+	printOrder(w, luno.Order(*res))
+	return w.Flush()
+}
+
+func (e *env) orderPlace(args []string) error {
+	fs := e.flags("order place")
+	typ := fs.String("type", "limit", "order type, limit or market")
+	pair := fs.String("pair", "", "market to trade, e.g. XBTZAR")
+	side := fs.String("side", "", "BID or ASK for limit orders, BUY or SELL for market orders")
+	var price, volume, base, counter decimalFlag
+	fs.Var(&price, "price", "limit price")
+	fs.Var(&volume, "volume", "limit order volume in the base currency")
+	fs.Var(&base, "base", "market SELL volume in the base currency")
+	fs.Var(&counter, "counter", "market BUY volume in the counter currency")
+	postOnly := fs.Bool("post-only", false, "cancel the limit order if it would trade immediately")
+	clientID := fs.String("client-id", "", "client order ID, to make the request idempotent")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *pair == "" || *side == "" {
+		return errors.New("-pair and -side are required")
+	}
+	orderType, err := luno.ParseOrderType(*side)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := e.context()
+	defer cancel()
+
+	var id string
+	switch *typ {
+	case "limit":
+		if orderType != luno.OrderTypeBid && orderType != luno.OrderTypeAsk {
+			return errors.New("-side must be BID or ASK for limit orders")
+		}
+		if !price.isSet() || !volume.isSet() {
+			return errors.New("-price and -volume are required for limit orders")
+		}
+		res, err := e.cl.PostLimitOrder(ctx, &luno.PostLimitOrderRequest{
+			Pair:          strings.ToUpper(*pair),
+			Type:          orderType,
+			Price:         price.d,
+			Volume:        volume.d,
+			PostOnly:      *postOnly,
+			ClientOrderId: *clientID,
+		})
+		if err != nil {
+			return err
+		}
+		id = res.OrderId
+	case "market":
+		switch orderType {
+		case luno.OrderTypeBuy:
+			if !counter.isSet() || base.isSet() {
+				return errors.New("market BUY orders take -counter, not -base")
+			}
+		case luno.OrderTypeSell:
+			if !base.isSet() || counter.isSet() {
+				return errors.New("market SELL orders take -base, not -counter")
+			}
+		default:
+			return errors.New("-side must be BUY or SELL for market orders")
+		}
+		res, err := e.cl.PostMarketOrder(ctx, &luno.PostMarketOrderRequest{
+			Pair:          strings.ToUpper(*pair),
+			Type:          orderType,
+			BaseVolume:    base.d,
+			CounterVolume: counter.d,
+			ClientOrderId: *clientID,
+		})
+		if err != nil {
+			return err
+		}
+		id = res.OrderId
+	default:
+		return fmt.Errorf("unknown order type %q", *typ)
+	}
+	fmt.Fprintln(e.stdout, id)
+	return nil
+}
+
+func (e *env) orderStop(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: luno order stop ID")
+	}
+	ctx, cancel := e.context()
+	defer cancel()
+
+	res, err := e.cl.StopOrder(ctx, &luno.StopOrderRequest{OrderId: args[0]})
+	if err != nil {
+		return err
+	}
+	if !res.Success {
+		return fmt.Errorf("order %s was not stopped", args[0])
+	}
+	fmt.Fprintf(e.stdout, "stopped %s\n", args[0])
+	return nil
+}
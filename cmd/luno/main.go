@@ -0,0 +1,156 @@
+// Command luno is a command line client for the Luno API, built on the SDK.
+//
+// Usage:
+//
+//	luno [flags] ticker PAIR
+//	luno [flags] balance [ASSET...]
+//	luno [flags] orders list [-pair PAIR] [-state PENDING|COMPLETE]
+//	luno [flags] order get ID
+//	luno [flags] order place -type limit -pair PAIR -side BID|ASK -price PRICE -volume VOLUME [-post-only]
+//	luno [flags] order place -type market -pair PAIR -side BUY -counter VOLUME
+//	luno [flags] order place -type market -pair PAIR -side SELL -base VOLUME
+//	luno [flags] order stop ID
+//	luno [flags] stream orderbook PAIR
+//
+// The API key is read from the LUNO_API_KEY_ID and LUNO_API_KEY_SECRET
+// environment variables, or else from the JSON config file given with
+// -config, by default ~/.config/luno/config.json:
+//
+//	{"api_key_id": "...", "api_key_secret": "..."}
+//
+// LUNO_BASE_URL, or "base_url" in the config file, overrides the API base
+// URL.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	luno "github.com/luno/luno-go"
+)
+
+const usage = `Usage: luno [flags] COMMAND [ARGS]
+
+Commands:
+  ticker PAIR              Show the ticker of a market
+  balance [ASSET...]       Show account balances
+  orders list              List orders
+  order get ID             Show an order
+  order place              Place a limit or market order
+  order stop ID            Stop an order
+  stream orderbook PAIR    Stream the top of the order book
+
+Flags:
+`
+
+// config holds the settings read from the config file.
+type config struct {
+	APIKeyID     string `json:"api_key_id"`
+	APIKeySecret string `json:"api_key_secret"`
+
+	// BaseURL overrides the API base URL, e.g. for a staging environment.
+	BaseURL string `json:"base_url"`
+}
+
+// env holds what a command needs to run, so that it can be replaced in
+// tests.
+type env struct {
+	cl     *luno.Client
+	cfg    config
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr, os.Getenv); err != nil {
+		fmt.Fprintln(os.Stderr, "luno:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer, getenv func(string) string) error {
+	fs := flag.NewFlagSet("luno", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", defaultConfigPath(getenv), "path of the JSON config file")
+	debug := fs.Bool("debug", false, "log requests and responses")
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no command given")
+	}
+
+	cfg, err := loadConfig(*configPath, getenv)
+	if err != nil {
+		return err
+	}
+	opts := []luno.Option{luno.WithUserAgentSuffix("luno-cli")}
+	if cfg.APIKeyID != "" || cfg.APIKeySecret != "" {
+		opts = append(opts, luno.WithAuth(cfg.APIKeyID, cfg.APIKeySecret))
+	}
+	if cfg.BaseURL != "" {
+		opts = append(opts, luno.WithBaseURL(cfg.BaseURL))
+	}
+	cl := luno.NewClient(opts...)
+	cl.SetDebug(*debug)
+
+	e := &env{cl: cl, cfg: cfg, stdout: stdout, stderr: stderr}
+	cmd, args := fs.Arg(0), fs.Args()[1:]
+	switch cmd {
+	case "ticker":
+		return e.ticker(args)
+	case "balance", "balances":
+		return e.balance(args)
+	case "orders":
+		return e.orders(args)
+	case "order":
+		return e.order(args)
+	case "stream":
+		return e.stream(args)
+	}
+	return fmt.Errorf("unknown command %q", cmd)
+}
+
+func defaultConfigPath(getenv func(string) string) string {
+	home := getenv("HOME")
+	if home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".config", "luno", "config.json")
+}
+
+// loadConfig reads the config file at path, if it exists, and overrides it
+// with the environment.
+func loadConfig(path string, getenv func(string) string) (config, error) {
+	var cfg config
+	if path != "" {
+		b, err := ioutil.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return config{}, err
+		}
+		if err == nil {
+			if err := json.Unmarshal(b, &cfg); err != nil {
+				return config{}, fmt.Errorf("invalid config file %s: %v", path, err)
+			}
+		}
+	}
+	if id := getenv("LUNO_API_KEY_ID"); id != "" {
+		cfg.APIKeyID = id
+		cfg.APIKeySecret = getenv("LUNO_API_KEY_SECRET")
+	}
+	if u := getenv("LUNO_BASE_URL"); u != "" {
+		cfg.BaseURL = u
+	}
+	return cfg, nil
+}
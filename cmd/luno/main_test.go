@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testServer(reqs *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		id, _, _ := r.BasicAuth()
+		*reqs = append(*reqs, r.Method+" "+r.URL.Path+" "+id+" "+r.Form.Encode())
+		switch r.URL.Path {
+		case "/api/1/ticker":
+			w.Write([]byte(`{"pair":"XBTZAR","bid":"99","ask":"100","last_trade":"99.5",` +
+				`"rolling_24_hour_volume":"12.5","status":"ACTIVE"}`))
+		case "/api/1/balance":
+			w.Write([]byte(`{"balance":[{"account_id":"1","asset":"XBT","balance":"1.5",` +
+				`"reserved":"0.5","unconfirmed":"0","name":"Main"}]}`))
+		case "/api/1/postorder":
+			w.Write([]byte(`{"order_id":"BX1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Not found","error_code":"ErrNotFound"}`))
+		}
+	}))
+}
+
+func runCLI(t *testing.T, srvURL string, args ...string) (string, error) {
+	t.Helper()
+	env := map[string]string{
+		"LUNO_API_KEY_ID":     "key",
+		"LUNO_API_KEY_SECRET": "secret",
+		"LUNO_BASE_URL":       srvURL,
+	}
+	var stdout, stderr bytes.Buffer
+	err := run(append([]string{"-config", ""}, args...), &stdout, &stderr,
+		func(k string) string { return env[k] })
+	return stdout.String(), err
+}
+
+func TestTicker(t *testing.T) {
+	var reqs []string
+	srv := testServer(&reqs)
+	defer srv.Close()
+
+	out, err := runCLI(t, srv.URL, "ticker", "xbtzar")
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	exp := "PAIR    BID  ASK  LAST  24H VOLUME  STATUS\n" +
+		"XBTZAR  99   100  99.5  12.5        ACTIVE\n"
+	if out != exp {
+		t.Errorf("Expected %q, got %q", exp, out)
+	}
+	if len(reqs) != 1 || reqs[0] != "GET /api/1/ticker  pair=XBTZAR" {
+		t.Errorf("Expected public ticker request, got %v", reqs)
+	}
+}
+
+func TestBalance(t *testing.T) {
+	var reqs []string
+	srv := testServer(&reqs)
+	defer srv.Close()
+
+	out, err := runCLI(t, srv.URL, "balance", "xbt")
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if !strings.Contains(out, "1        XBT    1.5      0.5       0            Main") {
+		t.Errorf("Expected XBT balance, got %q", out)
+	}
+	if len(reqs) != 1 || reqs[0] != "GET /api/1/balance key assets=XBT" {
+		t.Errorf("Expected authenticated balance request, got %v", reqs)
+	}
+}
+
+func TestOrderPlace(t *testing.T) {
+	var reqs []string
+	srv := testServer(&reqs)
+	defer srv.Close()
+
+	out, err := runCLI(t, srv.URL, "order", "place", "-pair", "XBTZAR", "-side", "bid",
+		"-price", "100", "-volume", "0.01", "-post-only")
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if out != "BX1\n" {
+		t.Errorf("Expected order ID, got %q", out)
+	}
+	if len(reqs) != 1 || !strings.Contains(reqs[0], "post_only=true") ||
+		!strings.Contains(reqs[0], "price=100") || !strings.Contains(reqs[0], "type=BID") {
+		t.Errorf("Expected post-only bid, got %v", reqs)
+	}
+
+	testCases := []struct {
+		name   string
+		args   []string
+		expErr string
+	}{
+		{name: "limit buy", args: []string{"-side", "BUY", "-price", "100", "-volume", "1"},
+			expErr: "BID or ASK"},
+		{name: "market bid", args: []string{"-type", "market", "-side", "BID", "-counter", "100"},
+			expErr: "BUY or SELL"},
+		{name: "market buy without counter", args: []string{"-type", "market", "-side", "BUY"},
+			expErr: "take -counter"},
+		{name: "market buy with base", args: []string{"-type", "market", "-side", "BUY", "-base", "1"},
+			expErr: "take -counter"},
+		{name: "market sell with counter", args: []string{"-type", "market", "-side", "SELL",
+			"-base", "1", "-counter", "100"}, expErr: "take -base"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := len(reqs)
+			args := append([]string{"order", "place", "-pair", "XBTZAR"}, tc.args...)
+			_, err := runCLI(t, srv.URL, args...)
+			if err == nil || !strings.Contains(err.Error(), tc.expErr) {
+				t.Errorf("Expected error containing %q, got %v", tc.expErr, err)
+			}
+			if len(reqs) != n {
+				t.Errorf("Expected no request, got %v", reqs[n:])
+			}
+		})
+	}
+}
+
+func TestOrderNotFound(t *testing.T) {
+	var reqs []string
+	srv := testServer(&reqs)
+	defer srv.Close()
+
+	if _, err := runCLI(t, srv.URL, "order", "get", "BX404"); err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+	if _, err := runCLI(t, srv.URL, "nope"); err == nil {
+		t.Errorf("Expected unknown command error, got nil")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "luno")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	err = ioutil.WriteFile(path, []byte(`{"api_key_id":"file","api_key_secret":"s"}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadConfig(path, func(string) string { return "" })
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if cfg.APIKeyID != "file" || cfg.APIKeySecret != "s" {
+		t.Errorf("Expected key from file, got %+v", cfg)
+	}
+
+	env := map[string]string{"LUNO_API_KEY_ID": "env", "LUNO_API_KEY_SECRET": "e"}
+	cfg, err = loadConfig(path, func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if cfg.APIKeyID != "env" || cfg.APIKeySecret != "e" {
+		t.Errorf("Expected key from environment, got %+v", cfg)
+	}
+
+	if _, err := loadConfig(filepath.Join(dir, "missing.json"), func(string) string { return "" }); err != nil {
+		t.Errorf("Expected missing config file to be ignored, got %v", err)
+	}
+}
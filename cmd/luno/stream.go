@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/streaming"
+)
+
+func (e *env) stream(args []string) error {
+	if len(args) != 2 || args[0] != "orderbook" {
+		return errors.New("usage: luno stream orderbook PAIR")
+	}
+	if e.cfg.APIKeyID == "" {
+		return errors.New("streaming requires an API key")
+	}
+
+	// The connect callback runs on the connection's goroutine, also on
+	// reconnects, while updates are printed from this one.
+	var (
+		mu   sync.Mutex
+		last string
+	)
+	printTop := func(c *streaming.Conn) {
+		ss := c.Snapshot()
+		line := fmt.Sprintf("%s\tbid %s\task %s", ss.Status, top(ss.Bids), top(ss.Asks))
+		mu.Lock()
+		defer mu.Unlock()
+		// Only print changes of the top of the book.
+		if line != last {
+			fmt.Fprintf(e.stdout, "%s\t%s\n", ss.Timestamp.Format(time.RFC3339), line)
+			last = line
+		}
+	}
+
+	updates := make(chan struct{}, 1)
+	c, err := streaming.Dial(e.cfg.APIKeyID, e.cfg.APIKeySecret, strings.ToUpper(args[1]),
+		streaming.WithConnectCallback(printTop),
+		streaming.WithUpdateCallback(func(streaming.Update) {
+			select {
+			case updates <- struct{}{}:
+			default:
+			}
+		}))
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	for {
+		select {
+		case <-updates:
+			printTop(c)
+		case <-interrupt:
+			return nil
+		}
+	}
+}
+
+// top formats the best entry of one side of the order book.
+func top(entries []luno.OrderBookEntry) string {
+	if len(entries) == 0 {
+		return "-"
+	}
+	return entries[0].Volume.String() + " @ " + entries[0].Price.String()
+}
@@ -0,0 +1,107 @@
+package luno
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Encoder encodes values as JSON, like json.Encoder.
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+// Decoder decodes JSON values, like json.Decoder.
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+// SetJSONCodec sets the constructors of the JSON encoder and decoder used by
+// the client, e.g. to use a faster JSON library. The decoder is used for all
+// response bodies and the encoder for the request bodies of endpoints which
+// take JSON, such as CreateBeneficiary; other requests are form-encoded. By
+// default the encoding/json package is used.
+func (cl *Client) SetJSONCodec(enc func(io.Writer) Encoder,
+	dec func(io.Reader) Decoder) {
+
+	cl.newEncoder = enc
+	cl.newDecoder = dec
+}
+
+func newJSONEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
+
+func newJSONDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+func (cl *Client) decodeJSON(b []byte, v interface{}) error {
+	return cl.newDecoder(bytes.NewReader(b)).Decode(v)
+}
+
+func (cl *Client) encodeJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := cl.newEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
+
+// encodeJSONBody encodes req as the JSON body of a request, without the path
+// parameters in omit. If pair is set it replaces the pair field, so that
+// pairs are normalised as for form bodies, see SetPairNormalization. The body
+// is also returned with the values of redacted parameters replaced.
+func (cl *Client) encodeJSONBody(req interface{}, omit []string, pair string) (
+	body []byte, redactedBody string, err error) {
+
+	b, err := cl.encodeJSON(req)
+	if err != nil {
+		return nil, "", err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, "", fmt.Errorf("luno: request isn't a JSON object: %w", err)
+	}
+	for _, k := range jsonKeys(req, omit) {
+		delete(fields, k)
+	}
+	if _, ok := fields["pair"]; ok && pair != "" {
+		fields["pair"], _ = json.Marshal(pair)
+	}
+	body, err = cl.encodeJSON(fields)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, cl.redactJSON(fields), nil
+}
+
+// jsonKeys returns the json names of the fields of req whose url names are
+// in params, since the two tags of a field may differ. The parameters of a
+// map request have the same name in both.
+func jsonKeys(req interface{}, params []string) []string {
+	t := reflect.TypeOf(req)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return params
+	}
+	keys := make([]string, 0, len(params))
+	for _, p := range params {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if name, _ := parseURLTag(f.Tag.Get("url")); name != p {
+				continue
+			}
+			key, _ := parseURLTag(f.Tag.Get("json"))
+			if key == "" {
+				key = f.Name
+			}
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,112 @@
+package luno_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+type countingDecoder struct {
+	dec   *json.Decoder
+	calls *int
+}
+
+func (d countingDecoder) Decode(v interface{}) error {
+	*d.calls++
+	return d.dec.Decode(v)
+}
+
+func TestSetJSONCodec(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/1/ticker" {
+			w.Write([]byte(`{"pair":"XBTZAR"}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"bad","error_code":"ErrBad"}`))
+	}))
+	defer srv.Close()
+
+	var calls int
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetJSONCodec(
+		func(w io.Writer) luno.Encoder { return json.NewEncoder(w) },
+		func(r io.Reader) luno.Decoder {
+			return countingDecoder{dec: json.NewDecoder(r), calls: &calls}
+		},
+	)
+
+	res, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if res.Pair != "XBTZAR" {
+		t.Errorf("Expected pair XBTZAR, got %q", res.Pair)
+	}
+	if calls != 1 {
+		t.Errorf("Expected decoder to be used for response, got %d calls", calls)
+	}
+
+	_, err = cl.GetTickers(context.Background(), &luno.GetTickersRequest{})
+	if !luno.IsErrorCode(err, "ErrBad") {
+		t.Errorf("Expected ErrBad, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected decoder to be used for error, got %d calls", calls)
+	}
+}
+
+type countingEncoder struct {
+	enc   *json.Encoder
+	calls *int
+}
+
+func (e countingEncoder) Encode(v interface{}) error {
+	*e.calls++
+	return e.enc.Encode(v)
+}
+
+func TestSetJSONCodecEncoder(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"id":"8"}`))
+	}))
+	defer srv.Close()
+
+	var encodes, decodes int
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL), luno.WithAuth("key", "secret"))
+	cl.SetJSONCodec(
+		func(w io.Writer) luno.Encoder {
+			return countingEncoder{enc: json.NewEncoder(w), calls: &encodes}
+		},
+		func(r io.Reader) luno.Decoder {
+			return countingDecoder{dec: json.NewDecoder(r), calls: &decodes}
+		},
+	)
+
+	_, err := cl.CreateBeneficiary(context.Background(), &luno.CreateBeneficiaryRequest{
+		BankName: "ABSA",
+	})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if encodes == 0 {
+		t.Errorf("Expected encoder to be used for the request body")
+	}
+	if decodes != 1 {
+		t.Errorf("Expected decoder to be used for the response, got %d calls", decodes)
+	}
+	if exp := `"bank_name":"ABSA"`; !strings.Contains(body, exp) {
+		t.Errorf("Expected body with %s, got %s", exp, body)
+	}
+}
@@ -0,0 +1,184 @@
+package luno
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RequestOptions are per-request overrides which can be attached to the
+// context passed to any Client method.
+type RequestOptions struct {
+	// DisableRetry makes exactly one attempt of the request, for callers
+	// with their own retry loop. Rate limited requests aren't retried either,
+	// and the request isn't shared with an identical one in flight, see
+	// SetRequestDedup, since that may be retried. The client's rate limiter
+	// still applies before the attempt.
+	DisableRetry bool
+
+	// ExtraHeaders are added to the HTTP request.
+	ExtraHeaders http.Header
+
+	// Timeout limits the duration of the request, including any retries. It
+	// applies in addition to the timeout of the HTTP client.
+	Timeout time.Duration
+}
+
+type requestOptionsKey struct{}
+
+// WithRequestOptions returns a copy of ctx carrying the given request options.
+func WithRequestOptions(ctx context.Context, opts RequestOptions) context.Context {
+	return context.WithValue(ctx, requestOptionsKey{}, opts)
+}
+
+func requestOptionsFromContext(ctx context.Context) RequestOptions {
+	opts, _ := ctx.Value(requestOptionsKey{}).(RequestOptions)
+	return opts
+}
+
+// WithRequestTimeout returns a copy of ctx which limits requests made with it
+// to d, including any retries, keeping any other request options carried by
+// ctx. See RequestOptions.Timeout.
+func WithRequestTimeout(ctx context.Context, d time.Duration) context.Context {
+	opts := requestOptionsFromContext(ctx)
+	opts.Timeout = d
+	return WithRequestOptions(ctx, opts)
+}
+
+// WithNoRetry returns a copy of ctx which makes exactly one attempt of
+// requests made with it, keeping any other request options carried by ctx.
+// See RequestOptions.DisableRetry.
+func WithNoRetry(ctx context.Context) context.Context {
+	opts := requestOptionsFromContext(ctx)
+	opts.DisableRetry = true
+	return WithRequestOptions(ctx, opts)
+}
+
+// WithHeader returns a copy of ctx which adds a header to requests made with
+// it, keeping any other request options and headers carried by ctx. See
+// RequestOptions.ExtraHeaders.
+//
+// Example:
+//
+//	ctx = luno.WithRequestTimeout(ctx, 2*time.Second)
+//	ctx = luno.WithHeader(ctx, "X-Trace", id)
+//	res, err := cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"})
+func WithHeader(ctx context.Context, key, value string) context.Context {
+	opts := requestOptionsFromContext(ctx)
+	// Copy the headers so that ctx's options aren't modified.
+	h := make(http.Header, len(opts.ExtraHeaders)+1)
+	for k, vv := range opts.ExtraHeaders {
+		h[k] = append([]string(nil), vv...)
+	}
+	h.Add(key, value)
+	opts.ExtraHeaders = h
+	return WithRequestOptions(ctx, opts)
+}
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a copy of ctx carrying a correlation ID. The ID is
+// passed to hooks and, if a header has been configured using
+// Client.SetCorrelationIDHeader, sent with every request made with ctx.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID carried by ctx, if any.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+type priorityKey struct{}
+
+// WithPriority returns a copy of ctx which gives requests made with it the
+// given priority when waiting on the client's rate limiter.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+func priorityFromContext(ctx context.Context) Priority {
+	p, _ := ctx.Value(priorityKey{}).(Priority)
+	return p
+}
+
+type baseURLKey struct{}
+
+// WithBaseURLOverride returns a copy of ctx which sends requests made with it
+// to baseURL instead of the client's base URL or hosts, for example to try a
+// canary deployment. Unlike SetBaseURL, the client isn't modified, so
+// concurrent requests are unaffected. Requests fail without being sent if
+// baseURL isn't an absolute http or https URL.
+func WithBaseURLOverride(ctx context.Context, baseURL string) context.Context {
+	return context.WithValue(ctx, baseURLKey{}, baseURL)
+}
+
+// baseURLOverrideFromContext returns the validated base URL set with
+// WithBaseURLOverride, or "" if there is none.
+func baseURLOverrideFromContext(ctx context.Context) (string, error) {
+	s, ok := ctx.Value(baseURLKey{}).(string)
+	if !ok {
+		return "", nil
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("luno: invalid base URL override %q", s)
+	}
+	return strings.TrimRight(s, "/"), nil
+}
+
+// WithSingleAttempt returns a copy of ctx which makes exactly one attempt of
+// requests made with it, like WithNoRetry.
+//
+// Deprecated: Use WithNoRetry.
+func WithSingleAttempt(ctx context.Context) context.Context {
+	return WithNoRetry(ctx)
+}
+
+type retrySafeKey struct{}
+
+// WithRetrySafe returns a copy of ctx which marks requests made with it as
+// safe to retry after server and network errors, even if they aren't GET
+// requests. Only use it for requests which can't take effect twice, for
+// example because they carry a client order ID or other idempotency key.
+// Rate limited requests are always retried since they weren't processed.
+func WithRetrySafe(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retrySafeKey{}, true)
+}
+
+func retrySafeFromContext(ctx context.Context) bool {
+	safe, _ := ctx.Value(retrySafeKey{}).(bool)
+	return safe
+}
+
+// CallStats records statistics about a call made with a context from
+// WithCallStats.
+type CallStats struct {
+	// Attempts is the number of HTTP requests made, including retries.
+	Attempts int
+
+	// Throttles lists the delays caused by rate limiting, in order.
+	Throttles []ThrottleInfo
+
+	// Warnings lists problems detected in a successful response.
+	Warnings []Warning
+}
+
+type callStatsKey struct{}
+
+// WithCallStats returns a copy of ctx which records statistics about requests
+// made with it in s. s is updated as the request progresses, so it should
+// only be read once the call has returned and should not be shared by
+// concurrent calls.
+func WithCallStats(ctx context.Context, s *CallStats) context.Context {
+	return context.WithValue(ctx, callStatsKey{}, s)
+}
+
+func callStatsFromContext(ctx context.Context) *CallStats {
+	s, _ := ctx.Value(callStatsKey{}).(*CallStats)
+	return s
+}
@@ -0,0 +1,113 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// CostEstimate is the estimated execution cost of a market order, including
+// the price impact of walking the order book and the account's fees.
+type CostEstimate struct {
+	Side Side
+
+	// Base is the base volume traded.
+	Base decimal.Decimal
+
+	// Counter is the counter amount traded, excluding fees.
+	Counter decimal.Decimal
+
+	// BestPrice is the price of the best level the order trades against,
+	// i.e. the best ask for a buy and the best bid for a sell.
+	BestPrice    decimal.Decimal
+	AveragePrice decimal.Decimal
+
+	// Slippage is how much worse the average price is than the best price,
+	// as a fraction of the best price, e.g. 0.002 for 0.2%. It is never
+	// negative.
+	Slippage decimal.Decimal
+
+	// MakerFee and TakerFee are the account's fee rates for the pair. Market
+	// orders take liquidity, so Fee is based on TakerFee.
+	MakerFee decimal.Decimal
+	TakerFee decimal.Decimal
+
+	// Fee is the estimated fee in the counter currency.
+	Fee decimal.Decimal
+
+	// Total is the counter amount including fees: the amount paid for a buy,
+	// or the amount received for a sell.
+	Total decimal.Decimal
+}
+
+// EstimateCost estimates the execution cost of a market order to buy or sell
+// baseVolume on pair, like EstimateMarketOrder, adding the slippage from the
+// best price, the account's maker fee for pair and the total including fees.
+// An error is returned if the book is not deep enough.
+//
+// The estimate may differ from the outcome of an order since the book and
+// the account's fee tier may change before the order is placed.
+func (cl *Client) EstimateCost(ctx context.Context, pair string, side Side,
+	baseVolume decimal.Decimal) (*CostEstimate, error) {
+
+	est, err := cl.EstimateMarketOrder(ctx, pair, side, baseVolume)
+	if err != nil {
+		return nil, err
+	}
+	maker, err := cl.MakerFee(ctx, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	slippage := est.AveragePrice.Sub(est.BestPrice)
+	total := est.Counter.Add(est.EstimatedFee)
+	if side == SideSell {
+		slippage = slippage.Neg()
+		total = est.Counter.Sub(est.EstimatedFee)
+	}
+
+	return &CostEstimate{
+		Side:         side,
+		Base:         est.Base,
+		Counter:      est.Counter,
+		BestPrice:    est.BestPrice,
+		AveragePrice: est.AveragePrice,
+		Slippage:     slippage.Div(est.BestPrice, averagePriceScale),
+		MakerFee:     maker,
+		TakerFee:     est.TakerFee,
+		Fee:          est.EstimatedFee,
+		Total:        total,
+	}, nil
+}
+
+// MinProfitablePrice returns the lowest price at which base currency bought
+// at entryPrice can be sold without a loss, after paying the fee rate
+// entryFee on the purchase and exitFee on the sale, e.g. the taker fee and
+// the maker fee of a pair. The price is rounded up to scale decimal places,
+// e.g. the price scale of the market, see MarketInfo.
+//
+// Fees are charged on the counter amount of each trade, so the price is
+// entryPrice * (1 + entryFee) / (1 - exitFee).
+func MinProfitablePrice(entryPrice, entryFee, exitFee decimal.Decimal,
+	scale int) (decimal.Decimal, error) {
+
+	if entryPrice.Sign() <= 0 {
+		return decimal.Decimal{}, fmt.Errorf("luno: invalid entry price %s", entryPrice)
+	}
+	one := decimal.NewFromInt64(1)
+	keep := one.Sub(exitFee)
+	if keep.Sign() <= 0 {
+		return decimal.Decimal{}, errors.New("luno: exit fee must be less than 100%")
+	}
+
+	cost := entryPrice.Mul(one.Add(entryFee))
+	price := cost.Div(keep, scale)
+	if price.Mul(keep).Cmp(cost) < 0 {
+		// Div truncates, so round up by one unit of the last place.
+		price = price.Add(decimal.New(big.NewInt(1), scale))
+	}
+	return price, nil
+}
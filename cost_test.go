@@ -0,0 +1,126 @@
+package luno_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestEstimateCost(t *testing.T) {
+	var feeCalls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/1/fee_info":
+			feeCalls++
+			w.Write([]byte(`{"maker_fee":"0.0005","taker_fee":"0.001","thirty_day_volume":"10"}`))
+		case "/api/1/orderbook":
+			w.Write([]byte(`{
+				"bids":[{"price":"98","volume":"1"},{"price":"100","volume":"1"}],
+				"asks":[{"price":"102","volume":"2"},{"price":"100","volume":"2"}]
+			}`))
+		default:
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	testCases := []struct {
+		name        string
+		side        luno.Side
+		expBest     string
+		expAvg      string
+		expSlippage string
+		expFee      string
+		expTotal    string
+	}{
+		// 2*100 + 2*102 = 404, avg 101, fee 0.404
+		{name: "buy", side: luno.SideBuy, expBest: "100", expAvg: "101",
+			expSlippage: "0.01", expFee: "0.404", expTotal: "404.404"},
+		// 1*100 + 1*98 = 198, avg 99, fee 0.198
+		{name: "sell", side: luno.SideSell, expBest: "100", expAvg: "99",
+			expSlippage: "0.01", expFee: "0.198", expTotal: "197.802"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vol := "4"
+			if tc.side == luno.SideSell {
+				vol = "2"
+			}
+			est, err := cl.EstimateCost(context.Background(), "XBTZAR", tc.side,
+				mustDecimal(t, vol))
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			checks := []struct{ name, exp, got string }{
+				{"best price", tc.expBest, est.BestPrice.String()},
+				{"average price", tc.expAvg, est.AveragePrice.String()},
+				{"slippage", tc.expSlippage, est.Slippage.String()},
+				{"fee", tc.expFee, est.Fee.String()},
+				{"total", tc.expTotal, est.Total.String()},
+			}
+			for _, c := range checks {
+				if mustDecimal(t, c.got).Cmp(mustDecimal(t, c.exp)) != 0 {
+					t.Errorf("Expected %s %s, got %s", c.name, c.exp, c.got)
+				}
+			}
+			if est.MakerFee.Cmp(mustDecimal(t, "0.0005")) != 0 {
+				t.Errorf("Expected maker fee 0.0005, got %s", est.MakerFee)
+			}
+		})
+	}
+	if feeCalls != 1 {
+		t.Errorf("Expected fee info to be cached, got %d calls", feeCalls)
+	}
+
+	_, err := cl.EstimateCost(context.Background(), "XBTZAR", luno.SideSell,
+		mustDecimal(t, "3"))
+	if err == nil {
+		t.Errorf("Expected error for insufficient depth, got nil")
+	}
+	_, err = cl.EstimateCost(context.Background(), "XBTZAR", luno.Side("BID"),
+		mustDecimal(t, "1"))
+	if err == nil {
+		t.Errorf("Expected error for invalid side, got nil")
+	}
+}
+
+func TestMinProfitablePrice(t *testing.T) {
+	testCases := []struct {
+		name     string
+		price    string
+		entryFee string
+		exitFee  string
+		scale    int
+		exp      string
+	}{
+		{name: "no fees", price: "100", entryFee: "0", exitFee: "0", scale: 2, exp: "100"},
+		// 100 * 1.001 / 0.999 = 100.2002...
+		{name: "rounded up", price: "100", entryFee: "0.001", exitFee: "0.001", scale: 2, exp: "100.21"},
+		// 100 * 1.001 / 1.001 = 100
+		{name: "rebate", price: "100", entryFee: "0.001", exitFee: "-0.001", scale: 0, exp: "100"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := luno.MinProfitablePrice(mustDecimal(t, tc.price),
+				mustDecimal(t, tc.entryFee), mustDecimal(t, tc.exitFee), tc.scale)
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if got.Cmp(mustDecimal(t, tc.exp)) != 0 {
+				t.Errorf("Expected %s, got %s", tc.exp, got)
+			}
+		})
+	}
+
+	_, err := luno.MinProfitablePrice(mustDecimal(t, "100"), mustDecimal(t, "0"),
+		mustDecimal(t, "1"), 2)
+	if err == nil {
+		t.Errorf("Expected error for 100%% exit fee, got nil")
+	}
+}
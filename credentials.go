@@ -0,0 +1,102 @@
+package luno
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// CredentialProvider supplies the API key used to authenticate requests. It
+// is called for every authenticated request, so that credentials can be
+// rotated, for example by fetching them from a secrets manager. Any caching
+// is up to the provider. It must be safe for concurrent use.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (keyID, keySecret string, err error)
+}
+
+// CredentialProviderFunc adapts a function to a CredentialProvider.
+type CredentialProviderFunc func(ctx context.Context) (keyID, keySecret string, err error)
+
+// Credentials calls f.
+func (f CredentialProviderFunc) Credentials(ctx context.Context) (string, string, error) {
+	return f(ctx)
+}
+
+// SetCredentialProvider sets the provider of the API key used to authenticate
+// requests, replacing any key set with SetAuth. If the provider returns an
+// error, the request fails without being sent. It is safe to call while
+// requests are in flight.
+func (cl *Client) SetCredentialProvider(p CredentialProvider) {
+	cl.authMu.Lock()
+	defer cl.authMu.Unlock()
+	cl.creds = p
+}
+
+// staticCredentials is the provider set by SetAuth.
+type staticCredentials struct {
+	keyID     string
+	keySecret string
+}
+
+func (c staticCredentials) Credentials(context.Context) (string, string, error) {
+	return c.keyID, c.keySecret, nil
+}
+
+// credentials returns the current API key pair, which is empty if no
+// credentials have been provided.
+func (cl *Client) credentials(ctx context.Context) (string, string, error) {
+	cl.authMu.RLock()
+	p := cl.creds
+	cl.authMu.RUnlock()
+	if p == nil {
+		return "", "", nil
+	}
+	keyID, keySecret, err := p.Credentials(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("luno: error getting credentials: %w", err)
+	}
+	return keyID, keySecret, nil
+}
+
+// authFailureNotifier calls the callback set with SetOnAuthFailure once per
+// run of rejected authenticated requests.
+type authFailureNotifier struct {
+	mu    sync.Mutex
+	fn    func()
+	fired bool
+}
+
+// SetOnAuthFailure sets a callback which is called the first time an
+// authenticated request is rejected with 401 Unauthorized or 403 Forbidden,
+// for example because the API key was revoked, so that the service can alert
+// and pause. It isn't called again for further rejections until an
+// authenticated request succeeds. fn is called synchronously from the request
+// which was rejected, so it shouldn't block. Setting nil removes the callback.
+func (cl *Client) SetOnAuthFailure(fn func()) {
+	n := &cl.authFailure
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.fn = fn
+	n.fired = false
+}
+
+// observe records the status code of a response to an authenticated request.
+func (n *authFailureNotifier) observe(statusCode int) {
+	n.mu.Lock()
+	var fn func()
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		if !n.fired {
+			n.fired = true
+			fn = n.fn
+		}
+	case statusCode < http.StatusBadRequest:
+		n.fired = false
+	}
+	n.mu.Unlock()
+
+	if fn != nil {
+		fn()
+	}
+}
@@ -0,0 +1,137 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+// rotatingProvider returns a new key pair on every call.
+type rotatingProvider struct {
+	mu    sync.Mutex
+	calls int
+	err   error
+}
+
+func (p *rotatingProvider) Credentials(ctx context.Context) (string, string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return "", "", p.err
+	}
+	p.calls++
+	n := strconv.Itoa(p.calls)
+	return "key" + n, "secret" + n, nil
+}
+
+func TestCredentialProvider(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		keys = append(keys, id+":"+secret)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	p := &rotatingProvider{}
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetCredentialProvider(p)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := cl.GetBalances(ctx, &luno.GetBalancesRequest{}); err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+	}
+	// Public endpoints don't need credentials.
+	if _, err := cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	exp := []string{"key1:secret1", "key2:secret2", "key3:secret3", ":"}
+	if !reflect.DeepEqual(keys, exp) {
+		t.Errorf("Expected keys %v, got %v", exp, keys)
+	}
+
+	p.err = errors.New("vault sealed")
+	_, err := cl.GetBalances(ctx, &luno.GetBalancesRequest{})
+	if !errors.Is(err, p.err) {
+		t.Errorf("Expected provider error, got %v", err)
+	}
+	if len(keys) != len(exp) {
+		t.Errorf("Expected request not to be sent, got %d requests", len(keys))
+	}
+
+	// SetAuth replaces the provider.
+	if err := cl.SetAuth("static", "secret"); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if _, err := cl.GetBalances(ctx, &luno.GetBalancesRequest{}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if act := keys[len(keys)-1]; act != "static:secret" {
+		t.Errorf("Expected static key, got %s", act)
+	}
+}
+
+func TestOnAuthFailure(t *testing.T) {
+	status := http.StatusUnauthorized
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/1/ticker" {
+			// Unauthenticated calls don't affect the callback.
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"Forbidden","error_code":"ErrForbidden"}`))
+			return
+		}
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			w.Write([]byte(`{"balance":[]}`))
+			return
+		}
+		w.Write([]byte(`{"error":"Unauthorized","error_code":"ErrUnauthorised"}`))
+	}))
+	defer srv.Close()
+
+	var fired int
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetAuth("key", "secret")
+	cl.SetOnAuthFailure(func() { fired++ })
+	ctx := context.Background()
+
+	balances := func() {
+		t.Helper()
+		_, err := cl.GetBalances(ctx, &luno.GetBalancesRequest{})
+		if (status == http.StatusOK) != (err == nil) {
+			t.Fatalf("Unexpected error for status %d: %v", status, err)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		balances()
+	}
+	if fired != 1 {
+		t.Errorf("Expected 1 callback for consecutive rejections, got %d", fired)
+	}
+
+	// A success re-arms the callback.
+	status = http.StatusOK
+	balances()
+	cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"})
+	if fired != 1 {
+		t.Errorf("Expected no callback for an unauthenticated call, got %d", fired)
+	}
+	status = http.StatusForbidden
+	balances()
+	balances()
+	if fired != 2 {
+		t.Errorf("Expected 2 callbacks after a success, got %d", fired)
+	}
+}
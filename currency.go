@@ -0,0 +1,33 @@
+package luno
+
+// Currency is a currency or asset code, e.g. XBT. Asset and currency fields
+// of API types are plain strings so that codes of assets added by Luno after
+// this package was released are kept as is. Convert them to use IsKnown, e.g.
+// luno.Currency(balance.Asset).IsKnown().
+type Currency string
+
+// Codes of common currencies.
+const (
+	CurrencyXBT  Currency = "XBT"
+	CurrencyETH  Currency = "ETH"
+	CurrencyLTC  Currency = "LTC"
+	CurrencyXRP  Currency = "XRP"
+	CurrencyBCH  Currency = "BCH"
+	CurrencyUSDC Currency = "USDC"
+	CurrencyUSDT Currency = "USDT"
+
+	CurrencyEUR Currency = "EUR"
+	CurrencyGBP Currency = "GBP"
+	CurrencyIDR Currency = "IDR"
+	CurrencyMYR Currency = "MYR"
+	CurrencyNGN Currency = "NGN"
+	CurrencyUGX Currency = "UGX"
+	CurrencyZAR Currency = "ZAR"
+)
+
+// IsKnown returns whether c is a currency code known to this package, which
+// are the codes recognised by NormalizePair. Unknown codes may still be valid
+// if Luno has added the asset since.
+func (c Currency) IsKnown() bool {
+	return knownCurrencies[string(c)]
+}
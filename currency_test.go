@@ -0,0 +1,65 @@
+package luno_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestUnknownCurrencyRoundTrip(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"balance":[{"account_id":"1","asset":"NEWCOIN","balance":"1.5","reserved":"0","unconfirmed":"0"}]}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	res, err := cl.GetBalances(context.Background(), &luno.GetBalancesRequest{})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(res.Balance) != 1 || res.Balance[0].Asset != "NEWCOIN" {
+		t.Fatalf("Expected NEWCOIN balance, got %+v", res.Balance)
+	}
+	if luno.Currency(res.Balance[0].Asset).IsKnown() {
+		t.Errorf("Expected NEWCOIN not to be known")
+	}
+
+	b, err := json.Marshal(res.Balance[0])
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	var got luno.AccountBalance
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if got.Asset != "NEWCOIN" || got.Balance.String() != "1.5" {
+		t.Errorf("Expected balance to round trip, got %+v", got)
+	}
+
+	var tx luno.Transaction
+	if err := json.Unmarshal([]byte(`{"currency":"NEWCOIN"}`), &tx); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if tx.Currency != "NEWCOIN" {
+		t.Errorf("Expected transaction currency NEWCOIN, got %q", tx.Currency)
+	}
+}
+
+func TestCurrencyIsKnown(t *testing.T) {
+	for _, c := range []luno.Currency{luno.CurrencyXBT, luno.CurrencyZAR, "USDC"} {
+		if !c.IsKnown() {
+			t.Errorf("Expected %s to be known", c)
+		}
+	}
+	for _, c := range []luno.Currency{"", "xbt", "NEWCOIN"} {
+		if c.IsKnown() {
+			t.Errorf("Expected %q not to be known", c)
+		}
+	}
+}
@@ -1,3 +1,29 @@
+/*
+Package decimal implements the arbitrary-precision decimal type used for all
+prices, volumes and balances in the Luno API.
+
+Amounts are exchanged with the API as decimal strings and are never converted
+to binary floating point, so no rounding errors are introduced. Decimals
+marshal to and from JSON strings and are encoded in query strings and forms
+in the same way.
+
+Code which holds amounts as float64 can migrate by parsing amounts with
+NewFromString, or NewFromInt64, instead of converting floats, and using Cmp,
+Add, Sub, Mul and MulPrice instead of float arithmetic:
+
+	price, err := decimal.NewFromString("1234567.89")
+	if err != nil {
+		return err
+	}
+	volume, err := decimal.NewFromString("0.0015")
+	if err != nil {
+		return err
+	}
+	counter := volume.MulPrice(price, 2) // 1851.85
+
+NewFromFloat64 and Float64 are lossy and should only be used at the edges,
+e.g. for display or plotting.
+*/
 package decimal
 
 import (
@@ -127,6 +153,31 @@ func (d Decimal) ToScale(scale int) Decimal {
 	return Decimal{i: o, scale: scale}
 }
 
+// StringFixed returns d formatted with exactly places decimal places, padding
+// with trailing zeros or rounding half away from zero as needed. Negative
+// places are treated as zero. Scientific notation and thousands separators
+// are never used.
+func (d Decimal) StringFixed(places int) string {
+	if places < 0 {
+		places = 0
+	}
+	if places >= d.scale {
+		return d.ToScale(places).String()
+	}
+
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.scale-places)), nil)
+	di := bigIntDefault(d.i)
+	q, r := new(big.Int).QuoRem(di, unit, new(big.Int))
+	if r.Abs(r).Lsh(r, 1).Cmp(unit) >= 0 {
+		if di.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return Decimal{i: q, scale: places}.String()
+}
+
 // Sign returns -1 if d is negative, 1 if d is positive and 0 if d is zero.
 func (d Decimal) Sign() int {
 	return bigIntDefault(d.i).Sign()
@@ -184,6 +235,13 @@ func (d Decimal) Mul(y Decimal) Decimal {
 	return New(new(big.Int).Mul(di, yi), d.scale+y.scale)
 }
 
+// MulPrice returns the counter amount of the base volume d at price,
+// truncated towards 0 to the given scale, e.g. the number of decimal places
+// of the counter currency. d is left unchanged.
+func (d Decimal) MulPrice(price Decimal, scale int) Decimal {
+	return d.Mul(price).ToScale(scale)
+}
+
 // Div divides d by y and returns the result in the provided scale. If the
 // provided scale is too small for the result of d/y, the result is truncated
 // towards 0. d is left unchanged.
@@ -201,6 +201,37 @@ func TestDecimalString(t *testing.T) {
 	}
 }
 
+func TestDecimalStringFixed(t *testing.T) {
+	testCases := []struct {
+		d      decimal.Decimal
+		places int
+		exp    string
+	}{
+		{d: decimal.New(big.NewInt(0), 0), places: 2, exp: "0.00"},
+		{d: decimal.New(big.NewInt(12), 1), places: 4, exp: "1.2000"},
+		{d: decimal.New(big.NewInt(1200), 3), places: 1, exp: "1.2"},
+		{d: decimal.New(big.NewInt(1200), 3), places: 0, exp: "1"},
+		{d: decimal.New(big.NewInt(-12), 1), places: 3, exp: "-1.200"},
+		{d: decimal.New(big.NewInt(12345), 4), places: 3, exp: "1.235"},
+		{d: decimal.New(big.NewInt(12344), 4), places: 3, exp: "1.234"},
+		{d: decimal.New(big.NewInt(-12345), 4), places: 3, exp: "-1.235"},
+		{d: decimal.New(big.NewInt(-4), 1), places: 0, exp: "0"},
+		{d: decimal.New(big.NewInt(-5), 1), places: 0, exp: "-1"},
+		{d: decimal.New(big.NewInt(99995), 4), places: 3, exp: "10.000"},
+		{d: decimal.New(big.NewInt(1), 12), places: 8, exp: "0.00000000"},
+		{d: decimal.New(big.NewInt(15), 1), places: -1, exp: "2"},
+		{d: decimal.New(big.NewInt(123), -3), places: 1, exp: "123000.0"},
+		{d: decimal.Decimal{}, places: 1, exp: "0.0"},
+	}
+	for _, test := range testCases {
+		act := test.d.StringFixed(test.places)
+		if act != test.exp {
+			t.Errorf("Expected %v to %d places to be %q, not %q",
+				test.d, test.places, test.exp, act)
+		}
+	}
+}
+
 func TestDecimalSign(t *testing.T) {
 	type testCase struct {
 		d   decimal.Decimal
@@ -599,6 +630,50 @@ func TestDecimalMul(t *testing.T) {
 	}
 }
 
+func TestDecimalMulPrice(t *testing.T) {
+	type testCase struct {
+		d     decimal.Decimal
+		price decimal.Decimal
+		scale int
+		exp   string
+	}
+
+	testCases := []testCase{
+		testCase{
+			d:     decimal.Decimal{},
+			price: decimal.Decimal{},
+			scale: 2,
+			exp:   "0.00",
+		},
+		testCase{
+			d:     decimal.New(big.NewInt(15), 4),
+			price: decimal.New(big.NewInt(123456789), 2),
+			scale: 2,
+			exp:   "1851.85",
+		},
+		testCase{
+			d:     decimal.New(big.NewInt(-15), 4),
+			price: decimal.New(big.NewInt(123456789), 2),
+			scale: 2,
+			exp:   "-1851.85",
+		},
+		testCase{
+			d:     decimal.New(big.NewInt(2), 0),
+			price: decimal.New(big.NewInt(5), 1),
+			scale: 3,
+			exp:   "1.000",
+		},
+	}
+
+	for _, test := range testCases {
+		act := test.d.MulPrice(test.price, test.scale).String()
+		if act != test.exp {
+			t.Errorf("Expected %s at %s to be %q, got %q",
+				test.d, test.price, test.exp, act)
+		}
+	}
+}
+
 func TestDecimalDivNoPanic(t *testing.T) {
 	type testCase struct {
 		d     decimal.Decimal
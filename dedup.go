@@ -0,0 +1,93 @@
+package luno
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// SetRequestDedup enables or disables sharing the response of identical GET
+// requests which are in flight at the same time. Concurrent requests with the
+// same URL and authentication make a single upstream call whose response, or
+// error, is returned to all of them. Other methods are never deduplicated.
+//
+// The shared call is made with the context of the first request, so
+// cancelling it fails the others too, and only its call stats are updated.
+func (cl *Client) SetRequestDedup(enabled bool) {
+	if !enabled {
+		cl.dedup = nil
+		return
+	}
+	if cl.dedup == nil {
+		cl.dedup = &flightGroup{}
+	}
+}
+
+// doShared makes the GET request c, or waits for an identical request which
+// is already in flight, and decodes the shared response into c.res, see
+// decodeResponse.
+func (cl *Client) doShared(ctx context.Context, c *call, maxRetries int) error {
+	key := " " + c.host + c.url
+	if c.auth {
+		keyID, _, err := cl.credentials(ctx)
+		if err != nil {
+			return err
+		}
+		key = keyID + key
+	}
+	b, err := cl.dedup.do(key, func() ([]byte, error) {
+		var raw json.RawMessage
+		shared := *c
+		shared.res = &raw
+		err := cl.doCall(ctx, &shared, maxRetries)
+		return raw, err
+	})
+	if err != nil {
+		return err
+	}
+	// The shared call decodes into a raw message, so strict decoding of the
+	// response applies here.
+	return cl.decodeResponse(c, b)
+}
+
+// flightGroup deduplicates concurrent calls with the same key, like
+// golang.org/x/sync/singleflight.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	wg   sync.WaitGroup
+	val  []byte
+	err  error
+	dups int
+}
+
+// do calls fn and returns its result, unless a call with the same key is in
+// flight, in which case it waits for that call and returns its result.
+func (g *flightGroup) do(key string, fn func() ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.val, c.err
+	}
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	c := &flightCall{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+	c.val, c.err = fn()
+	return c.val, c.err
+}
@@ -0,0 +1,115 @@
+package luno
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// dupsOf returns the number of requests waiting on the in-flight call with
+// key, or -1 if there is none.
+func dupsOf(g *flightGroup, key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	c, ok := g.calls[key]
+	if !ok {
+		return -1
+	}
+	return c.dups
+}
+
+func TestRequestDedup(t *testing.T) {
+	const n = 10
+
+	var calls int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		w.Write([]byte(`{"pair":"XBTZAR","last_trade":"100"}`))
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetRequestDedup(true)
+
+	var wg sync.WaitGroup
+	res := make([]*GetTickerResponse, n)
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res[i], errs[i] = cl.GetTicker(context.Background(), &GetTickerRequest{Pair: "XBTZAR"})
+		}(i)
+	}
+
+	key := " /api/1/ticker?pair=XBTZAR"
+	deadline := time.Now().Add(5 * time.Second)
+	for dupsOf(cl.dedup, key) < n-1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d requests to wait, got %d", n-1, dupsOf(cl.dedup, key))
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", n)
+	}
+	for i := 0; i < n; i++ {
+		if errs[i] != nil {
+			t.Fatalf("Expected success, got %v", errs[i])
+		}
+		if res[i].Pair != "XBTZAR" || res[i].LastTrade.String() != "100" {
+			t.Errorf("Unexpected response %+v", res[i])
+		}
+	}
+	if res[0] == res[1] {
+		t.Errorf("Expected each caller to get its own response")
+	}
+
+	// Once the shared call has completed, the next request is made anew.
+	if _, err := cl.GetTicker(context.Background(), &GetTickerRequest{Pair: "XBTZAR"}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("Expected a new upstream call, got %d calls", n)
+	}
+}
+
+func TestRequestDedupNotGet(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetRequestDedup(true)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var res interface{}
+			if err := cl.do(context.Background(), "POST", "/api/1/postorder", nil, &res, true); err != nil {
+				t.Errorf("Expected success, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Errorf("Expected every POST to be sent, got %d calls", n)
+	}
+}
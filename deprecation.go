@@ -0,0 +1,80 @@
+package luno
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DeprecationNotice describes a deprecation signalled by the API in the
+// Warning or Sunset header of a response.
+type DeprecationNotice struct {
+	Method string
+
+	// Path is the endpoint path template, e.g. /api/1/orders/{id}.
+	Path string
+
+	// Warning is the value of the Warning header, if any.
+	Warning string
+
+	// Sunset is the time after which the endpoint may be removed, from the
+	// Sunset header. It is zero if the header is missing or invalid.
+	Sunset time.Time
+}
+
+// deprecations tracks the endpoints which have been reported as deprecated.
+type deprecations struct {
+	mu   sync.Mutex
+	seen map[string]bool
+	last *DeprecationNotice
+}
+
+// checkDeprecation reports a deprecation notice in header to the Deprecation
+// hook, once per endpoint.
+func (cl *Client) checkDeprecation(ctx context.Context, info RequestInfo,
+	header http.Header) {
+
+	warning := header.Get("Warning")
+	sunset := header.Get("Sunset")
+	if warning == "" && sunset == "" {
+		return
+	}
+	n := DeprecationNotice{
+		Method:  info.Method,
+		Path:    info.Path,
+		Warning: warning,
+	}
+	if t, err := http.ParseTime(sunset); err == nil {
+		n.Sunset = t
+	}
+
+	d := &cl.deprecations
+	d.mu.Lock()
+	d.last = &n
+	key := n.Method + " " + n.Path
+	first := !d.seen[key]
+	if first {
+		if d.seen == nil {
+			d.seen = make(map[string]bool)
+		}
+		d.seen[key] = true
+	}
+	d.mu.Unlock()
+
+	if first {
+		cl.hooks.deprecation(ctx, n)
+	}
+}
+
+// LastDeprecation returns the most recent deprecation notice received from
+// the API, if any.
+func (cl *Client) LastDeprecation() (DeprecationNotice, bool) {
+	d := &cl.deprecations
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.last == nil {
+		return DeprecationNotice{}, false
+	}
+	return *d.last, true
+}
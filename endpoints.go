@@ -0,0 +1,124 @@
+package luno
+
+import "net/http"
+
+// endpoint describes how an API method is called: its HTTP method, its path,
+// in which each "{param}" is replaced by the request parameter of that name,
+// whether it must be authenticated and how request bodies are encoded.
+type endpoint struct {
+	method   string
+	path     string
+	auth     bool
+	encoding bodyEncoding
+}
+
+// bodyEncoding is the encoding of the parameters of a request which aren't
+// path parameters. It only applies to requests with a body; the parameters
+// of GET requests are always sent in the query.
+type bodyEncoding int
+
+const (
+	// bodyForm sends parameters form-encoded, as expected by most of the
+	// API.
+	bodyForm bodyEncoding = iota
+
+	// bodyJSON sends parameters as a JSON object, encoded with the
+	// client's JSON encoder, using the json tags of the request.
+	bodyJSON
+)
+
+// The endpoints of the API methods, named after the methods.
+var (
+	endpointCancelWithdrawal          = endpoint{http.MethodDelete, "/api/1/withdrawals/{id}", true, bodyForm}
+	endpointCreateAccount             = endpoint{http.MethodPost, "/api/1/accounts", true, bodyForm}
+	endpointCreateBeneficiary         = endpoint{http.MethodPost, "/api/1/beneficiaries", true, bodyJSON}
+	endpointCreateFundingAddress      = endpoint{http.MethodPost, "/api/1/funding_address", true, bodyForm}
+	endpointCreateQuote               = endpoint{http.MethodPost, "/api/1/quotes", true, bodyForm}
+	endpointCreateWithdrawal          = endpoint{http.MethodPost, "/api/1/withdrawals", true, bodyForm}
+	endpointDeleteBeneficiary         = endpoint{http.MethodDelete, "/api/1/beneficiaries/{id}", true, bodyForm}
+	endpointDiscardQuote              = endpoint{http.MethodDelete, "/api/1/quotes/{id}", true, bodyForm}
+	endpointExerciseQuote             = endpoint{http.MethodPut, "/api/1/quotes/{id}", true, bodyForm}
+	endpointGetBalances               = endpoint{http.MethodGet, "/api/1/balance", true, bodyForm}
+	endpointGetCandles                = endpoint{http.MethodGet, "/api/exchange/1/candles", true, bodyForm}
+	endpointGetFeeInfo                = endpoint{http.MethodGet, "/api/1/fee_info", true, bodyForm}
+	endpointGetFundingAddress         = endpoint{http.MethodGet, "/api/1/funding_address", true, bodyForm}
+	endpointGetMove                   = endpoint{http.MethodGet, "/api/exchange/1/move", true, bodyForm}
+	endpointGetOrder                  = endpoint{http.MethodGet, "/api/1/orders/{id}", true, bodyForm}
+	endpointGetOrderBook              = endpoint{http.MethodGet, "/api/1/orderbook_top", false, bodyForm}
+	endpointGetOrderBookFull          = endpoint{http.MethodGet, "/api/1/orderbook", false, bodyForm}
+	endpointGetOrderV2                = endpoint{http.MethodGet, "/api/exchange/2/orders/{id}", true, bodyForm}
+	endpointGetOrderV3                = endpoint{http.MethodGet, "/api/exchange/3/order", true, bodyForm}
+	endpointGetQuote                  = endpoint{http.MethodGet, "/api/1/quotes/{id}", true, bodyForm}
+	endpointGetServerTime             = endpoint{http.MethodGet, "/api/1/time", false, bodyForm}
+	endpointGetTicker                 = endpoint{http.MethodGet, "/api/1/ticker", false, bodyForm}
+	endpointGetTickers                = endpoint{http.MethodGet, "/api/1/tickers", false, bodyForm}
+	endpointGetWithdrawal             = endpoint{http.MethodGet, "/api/1/withdrawals/{id}", true, bodyForm}
+	endpointListBeneficiaries         = endpoint{http.MethodGet, "/api/1/beneficiaries", true, bodyForm}
+	endpointListBeneficiariesResponse = endpoint{http.MethodGet, "/api/1/beneficiaries", true, bodyForm}
+	endpointListMoves                 = endpoint{http.MethodGet, "/api/exchange/1/move/list_moves", true, bodyForm}
+	endpointListOrders                = endpoint{http.MethodGet, "/api/1/listorders", true, bodyForm}
+	endpointListOrdersV2              = endpoint{http.MethodGet, "/api/exchange/2/listorders", true, bodyForm}
+	endpointListPendingTransactions   = endpoint{http.MethodGet, "/api/1/accounts/{id}/pending", true, bodyForm}
+	endpointListTrades                = endpoint{http.MethodGet, "/api/1/trades", false, bodyForm}
+	endpointListTransactions          = endpoint{http.MethodGet, "/api/1/accounts/{id}/transactions", true, bodyForm}
+	endpointListUserTrades            = endpoint{http.MethodGet, "/api/1/listtrades", true, bodyForm}
+	endpointListWithdrawals           = endpoint{http.MethodGet, "/api/1/withdrawals", true, bodyForm}
+	endpointMarkets                   = endpoint{http.MethodGet, "/api/exchange/1/markets", false, bodyForm}
+	endpointMove                      = endpoint{http.MethodPost, "/api/exchange/1/move", true, bodyForm}
+	endpointPostLimitOrder            = endpoint{http.MethodPost, "/api/1/postorder", true, bodyForm}
+	endpointPostMarketOrder           = endpoint{http.MethodPost, "/api/1/marketorder", true, bodyForm}
+	endpointSend                      = endpoint{http.MethodPost, "/api/1/send", true, bodyForm}
+	endpointSendFee                   = endpoint{http.MethodGet, "/api/1/send_fee", true, bodyForm}
+	endpointStopOrder                 = endpoint{http.MethodPost, "/api/1/stoporder", true, bodyForm}
+	endpointUpdateAccountName         = endpoint{http.MethodPut, "/api/1/accounts/{id}/name", true, bodyForm}
+	endpointValidate                  = endpoint{http.MethodPost, "/api/1/address/validate", true, bodyForm}
+)
+
+// endpoints maps the name of each API method to its endpoint, so that tests
+// can check that every method is wired to the endpoint it is documented to
+// call.
+var endpoints = map[string]endpoint{
+	"CancelWithdrawal":          endpointCancelWithdrawal,
+	"CreateAccount":             endpointCreateAccount,
+	"CreateBeneficiary":         endpointCreateBeneficiary,
+	"CreateFundingAddress":      endpointCreateFundingAddress,
+	"CreateQuote":               endpointCreateQuote,
+	"CreateWithdrawal":          endpointCreateWithdrawal,
+	"DeleteBeneficiary":         endpointDeleteBeneficiary,
+	"DiscardQuote":              endpointDiscardQuote,
+	"ExerciseQuote":             endpointExerciseQuote,
+	"GetBalances":               endpointGetBalances,
+	"GetCandles":                endpointGetCandles,
+	"GetFeeInfo":                endpointGetFeeInfo,
+	"GetFundingAddress":         endpointGetFundingAddress,
+	"GetMove":                   endpointGetMove,
+	"GetOrder":                  endpointGetOrder,
+	"GetOrderBook":              endpointGetOrderBook,
+	"GetOrderBookFull":          endpointGetOrderBookFull,
+	"GetOrderV2":                endpointGetOrderV2,
+	"GetOrderV3":                endpointGetOrderV3,
+	"GetQuote":                  endpointGetQuote,
+	"GetServerTime":             endpointGetServerTime,
+	"GetTicker":                 endpointGetTicker,
+	"GetTickers":                endpointGetTickers,
+	"GetWithdrawal":             endpointGetWithdrawal,
+	"ListBeneficiaries":         endpointListBeneficiaries,
+	"ListBeneficiariesResponse": endpointListBeneficiariesResponse,
+	"ListMoves":                 endpointListMoves,
+	"ListOrders":                endpointListOrders,
+	"ListOrdersV2":              endpointListOrdersV2,
+	"ListPendingTransactions":   endpointListPendingTransactions,
+	"ListTrades":                endpointListTrades,
+	"ListTransactions":          endpointListTransactions,
+	"ListUserTrades":            endpointListUserTrades,
+	"ListWithdrawals":           endpointListWithdrawals,
+	"Markets":                   endpointMarkets,
+	"Move":                      endpointMove,
+	"PostLimitOrder":            endpointPostLimitOrder,
+	"PostMarketOrder":           endpointPostMarketOrder,
+	"Send":                      endpointSend,
+	"SendFee":                   endpointSendFee,
+	"StopOrder":                 endpointStopOrder,
+	"UpdateAccountName":         endpointUpdateAccountName,
+	"Validate":                  endpointValidate,
+}
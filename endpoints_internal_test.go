@@ -0,0 +1,202 @@
+package luno
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// apiMethods returns the API methods of Client by name, i.e. the methods
+// taking a context and a *<Name>Request.
+func apiMethods() map[string]reflect.Method {
+	ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
+	errType := reflect.TypeOf((*error)(nil)).Elem()
+
+	methods := make(map[string]reflect.Method)
+	t := reflect.TypeOf(&Client{})
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		mt := m.Type
+		if mt.NumIn() != 3 || mt.NumOut() != 2 ||
+			mt.In(1) != ctxType || mt.Out(1) != errType ||
+			mt.In(2).Kind() != reflect.Ptr ||
+			mt.In(2).Elem().Name() != m.Name+"Request" {
+			continue
+		}
+		methods[m.Name] = m
+	}
+	return methods
+}
+
+// documentedEndpoints returns the HTTP method and path of each API method
+// from its "makes a call to" doc comment in api.go.
+func documentedEndpoints(t *testing.T) map[string][2]string {
+	f, err := parser.ParseFile(token.NewFileSet(), "api.go", nil, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	re := regexp.MustCompile(`^\w+ makes a call to (\w+) (\S+)\.`)
+	docs := make(map[string][2]string)
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || fn.Doc == nil {
+			continue
+		}
+		if m := re.FindStringSubmatch(fn.Doc.Text()); m != nil {
+			docs[fn.Name.Name] = [2]string{m[1], m[2]}
+		}
+	}
+	return docs
+}
+
+func TestEndpointsDocumented(t *testing.T) {
+	docs := documentedEndpoints(t)
+	for name, doc := range docs {
+		e, ok := endpoints[name]
+		if !ok {
+			t.Errorf("Expected an endpoint for %s", name)
+			continue
+		}
+		if e.method != doc[0] || e.path != doc[1] {
+			t.Errorf("Expected %s to call %s %s, got %s %s",
+				name, doc[0], doc[1], e.method, e.path)
+		}
+	}
+	for name := range endpoints {
+		if _, ok := docs[name]; !ok {
+			t.Errorf("Expected %s to be documented", name)
+		}
+	}
+}
+
+func TestEndpointsWired(t *testing.T) {
+	var method, path, auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, auth = r.Method, r.URL.Path, r.Header.Get("Authorization")
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	if err := cl.SetAuth("key", "secret"); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	methods := apiMethods()
+	for name := range endpoints {
+		if _, ok := methods[name]; !ok {
+			t.Errorf("Expected a method for endpoint %s", name)
+		}
+	}
+	for name, m := range methods {
+		t.Run(name, func(t *testing.T) {
+			e, ok := endpoints[name]
+			if !ok {
+				t.Fatalf("Expected an endpoint for %s", name)
+			}
+
+			method, path, auth = "", "", ""
+			out := m.Func.Call([]reflect.Value{
+				reflect.ValueOf(cl),
+				reflect.ValueOf(context.Background()),
+				reflect.New(m.Type.In(2).Elem()),
+			})
+			if err, _ := out[1].Interface().(error); err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+
+			if method != e.method {
+				t.Errorf("Expected %s, got %s", e.method, method)
+			}
+			pathRE := "^" + strings.Replace(regexp.QuoteMeta(e.path),
+				regexp.QuoteMeta("{id}"), "[^/]*", -1) + "$"
+			if !regexp.MustCompile(pathRE).MatchString(path) {
+				t.Errorf("Expected path %s, got %s", e.path, path)
+			}
+			if authed := auth != ""; authed != e.auth {
+				t.Errorf("Expected authenticated %v, got %v", e.auth, authed)
+			}
+		})
+	}
+}
+
+func TestDoEndpointBodies(t *testing.T) {
+	type request struct {
+		AccountID string `json:"account_id" url:"account_id"`
+		ID        string `json:"address_id" url:"id"`
+		Pair      string `json:"pair" url:"pair"`
+		Address   string `json:"address" url:"address"`
+		Amount    int64  `json:"amount" url:"amount"`
+	}
+	req := &request{AccountID: "12", ID: "a/b", Pair: "xbt_zar", Address: "abc", Amount: 5}
+
+	testCases := []struct {
+		name        string
+		encoding    bodyEncoding
+		expBody     string
+		expType     string
+		expRedacted string
+	}{
+		{
+			name:        "form",
+			encoding:    bodyForm,
+			expBody:     "address=abc&amount=5&pair=XBTZAR",
+			expType:     "application/x-www-form-urlencoded",
+			expRedacted: "address=REDACTED&amount=5&pair=XBTZAR",
+		},
+		{
+			name:        "json",
+			encoding:    bodyJSON,
+			expBody:     `{"address":"abc","amount":5,"pair":"XBTZAR"}`,
+			expType:     "application/json",
+			expRedacted: `{"address":"REDACTED","amount":5,"pair":"XBTZAR"}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var path, body, contentType string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.EscapedPath()
+				contentType = r.Header.Get("Content-Type")
+				b, _ := ioutil.ReadAll(r.Body)
+				body = string(b)
+				w.Write([]byte(`{}`))
+			}))
+			defer srv.Close()
+
+			var info RequestInfo
+			cl := NewClient(WithBaseURL(srv.URL), WithHooks(Hooks{
+				BeforeRequest: func(ctx context.Context, i RequestInfo) { info = i },
+			}))
+			cl.SetPairNormalization(true)
+
+			e := endpoint{http.MethodPost, "/api/2/accounts/{account_id}/addresses/{id}",
+				false, tc.encoding}
+			var res struct{}
+			if err := cl.doEndpoint(context.Background(), e, req, &res); err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if exp := "/api/2/accounts/12/addresses/a%2Fb"; path != exp {
+				t.Errorf("Expected path %s, got %s", exp, path)
+			}
+			if body != tc.expBody {
+				t.Errorf("Expected body %s, got %s", tc.expBody, body)
+			}
+			if contentType != tc.expType {
+				t.Errorf("Expected content type %s, got %s", tc.expType, contentType)
+			}
+			if info.Body != tc.expRedacted {
+				t.Errorf("Expected redacted body %s, got %s", tc.expRedacted, info.Body)
+			}
+		})
+	}
+}
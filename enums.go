@@ -0,0 +1,120 @@
+package luno
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The enum types are strings, so they are encoded and decoded as the API's
+// values by encoding/json and in request forms. Values which the API adds
+// later are decoded as is, so IsValid reports false for them rather than
+// decoding failing.
+
+var (
+	kinds          = []string{string(KindExchange), string(KindFee), string(KindInterest), string(KindTransfer)}
+	moveStatuses   = []string{string(MoveStatusCreated), string(MoveStatusMoving), string(MoveStatusSuccessful), string(MoveStatusFailed)}
+	orderStates    = []string{string(OrderStateComplete), string(OrderStatePending)}
+	orderTypes     = []string{string(OrderTypeAsk), string(OrderTypeBid), string(OrderTypeBuy), string(OrderTypeSell)}
+	orderV2Types   = []string{string(TypeLimit), string(TypeMarket), string(TypeStop_limit)}
+	sendStatuses   = []string{string(SendStatusPending), string(SendStatusProcessing), string(SendStatusCompleted), string(SendStatusCancelled)}
+	sides          = []string{string(SideBuy), string(SideSell)}
+	statuses       = []string{string(StatusActive), string(StatusAwaiting), string(StatusComplete), string(StatusDisabled), string(StatusPending), string(StatusPostonly)}
+	stopDirections = []string{string(StopDirectionAbove), string(StopDirectionBelow), string(StopDirectionRelative_last_trade)}
+	timesInForce   = []string{string(TimeInForceFok), string(TimeInForceGtc), string(TimeInForceIoc)}
+	tradingStates  = []string{string(TradingStatusPost_only), string(TradingStatusActive), string(TradingStatusSuspended)}
+)
+
+func isOneOf(s string, values []string) bool {
+	for _, v := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// parseEnum returns the value of values which equals s, ignoring case.
+func parseEnum(name, s string, values []string) (string, error) {
+	for _, v := range values {
+		if strings.EqualFold(s, v) {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("luno: invalid %s %q, expected one of %s",
+		name, s, strings.Join(values, ", "))
+}
+
+// IsValid returns whether k is one of the Kind constants.
+func (k Kind) IsValid() bool { return isOneOf(string(k), kinds) }
+
+// IsValid returns whether s is one of the MoveStatus constants.
+func (s MoveStatus) IsValid() bool { return isOneOf(string(s), moveStatuses) }
+
+// IsValid returns whether s is one of the OrderState constants.
+func (s OrderState) IsValid() bool { return isOneOf(string(s), orderStates) }
+
+// IsValid returns whether t is one of the OrderType constants.
+func (t OrderType) IsValid() bool { return isOneOf(string(t), orderTypes) }
+
+// IsValid returns whether s is one of the SendStatus constants.
+func (s SendStatus) IsValid() bool { return isOneOf(string(s), sendStatuses) }
+
+// IsValid returns whether s is one of the Side constants.
+func (s Side) IsValid() bool { return isOneOf(string(s), sides) }
+
+// IsValid returns whether s is one of the Status constants.
+func (s Status) IsValid() bool { return isOneOf(string(s), statuses) }
+
+// IsValid returns whether d is one of the StopDirection constants.
+func (d StopDirection) IsValid() bool { return isOneOf(string(d), stopDirections) }
+
+// IsValid returns whether t is one of the TimeInForce constants.
+func (t TimeInForce) IsValid() bool { return isOneOf(string(t), timesInForce) }
+
+// IsValid returns whether s is one of the TradingStatus constants.
+func (s TradingStatus) IsValid() bool { return isOneOf(string(s), tradingStates) }
+
+// IsValid returns whether t is one of the Type constants.
+func (t Type) IsValid() bool { return isOneOf(string(t), orderV2Types) }
+
+// ParseOrderState returns the OrderState named by s, ignoring case, e.g.
+// OrderStatePending for "pending".
+func ParseOrderState(s string) (OrderState, error) {
+	v, err := parseEnum("order state", s, orderStates)
+	return OrderState(v), err
+}
+
+// ParseOrderType returns the OrderType named by s, ignoring case, e.g.
+// OrderTypeBid for "bid".
+func ParseOrderType(s string) (OrderType, error) {
+	v, err := parseEnum("order type", s, orderTypes)
+	return OrderType(v), err
+}
+
+// ParseSide returns the Side named by s, ignoring case, e.g. SideBuy for
+// "buy".
+func ParseSide(s string) (Side, error) {
+	v, err := parseEnum("side", s, sides)
+	return Side(v), err
+}
+
+// ParseStopDirection returns the StopDirection named by s, ignoring case,
+// e.g. StopDirectionAbove for "above".
+func ParseStopDirection(s string) (StopDirection, error) {
+	v, err := parseEnum("stop direction", s, stopDirections)
+	return StopDirection(v), err
+}
+
+// ParseTimeInForce returns the TimeInForce named by s, ignoring case, e.g.
+// TimeInForceIoc for "ioc".
+func ParseTimeInForce(s string) (TimeInForce, error) {
+	v, err := parseEnum("time in force", s, timesInForce)
+	return TimeInForce(v), err
+}
+
+// ParseType returns the Type named by s, ignoring case, e.g. TypeLimit for
+// "limit".
+func ParseType(s string) (Type, error) {
+	v, err := parseEnum("order type", s, orderV2Types)
+	return Type(v), err
+}
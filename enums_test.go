@@ -0,0 +1,61 @@
+package luno_test
+
+import (
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestEnumIsValid(t *testing.T) {
+	testCases := []struct {
+		name  string
+		valid bool
+		exp   bool
+	}{
+		{name: "order type", valid: luno.OrderTypeBid.IsValid(), exp: true},
+		{name: "lower case order type", valid: luno.OrderType("bid").IsValid(), exp: false},
+		{name: "order state", valid: luno.OrderStateComplete.IsValid(), exp: true},
+		{name: "unknown order state", valid: luno.OrderState("OPEN").IsValid(), exp: false},
+		{name: "send status", valid: luno.SendStatusProcessing.IsValid(), exp: true},
+		{name: "empty status", valid: luno.Status("").IsValid(), exp: false},
+		{name: "trading status", valid: luno.TradingStatusPost_only.IsValid(), exp: true},
+		{name: "kind", valid: luno.KindInterest.IsValid(), exp: true},
+	}
+	for _, tc := range testCases {
+		if tc.valid != tc.exp {
+			t.Errorf("Expected %s to be valid: %v", tc.name, tc.exp)
+		}
+	}
+}
+
+func TestParseOrderType(t *testing.T) {
+	for s, exp := range map[string]luno.OrderType{
+		"bid":  luno.OrderTypeBid,
+		"ASK":  luno.OrderTypeAsk,
+		"Sell": luno.OrderTypeSell,
+	} {
+		act, err := luno.ParseOrderType(s)
+		if err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		if act != exp {
+			t.Errorf("Expected %s, got %s", exp, act)
+		}
+	}
+
+	_, err := luno.ParseOrderType("BIDS")
+	exp := `luno: invalid order type "BIDS", expected one of ASK, BID, BUY, SELL`
+	if err == nil || err.Error() != exp {
+		t.Errorf("Expected %q, got %v", exp, err)
+	}
+}
+
+func TestParseTimeInForce(t *testing.T) {
+	act, err := luno.ParseTimeInForce("ioc")
+	if err != nil || act != luno.TimeInForceIoc {
+		t.Errorf("Expected IOC, got %s: %v", act, err)
+	}
+	if _, err := luno.ParseTimeInForce("day"); err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
@@ -1,8 +1,12 @@
 package luno
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+
+	"github.com/luno/luno-go/decimal"
 )
 
 // Error is a Luno API error.
@@ -13,8 +17,23 @@ type Error struct {
 
 	// Message may be localised for authenticated API calls.
 	Message string `json:"error"`
+
+	// RequestID is the ID which the server assigned to the request, if any.
+	// Quote it when contacting Luno support about the error.
+	RequestID string `json:"-"`
+
+	// StatusCode is the HTTP status code of the response, or zero for errors
+	// which weren't received from the server.
+	StatusCode int `json:"-"`
+
+	// raw is the error body as received, truncated to maxRawErrorBytes. It's
+	// a string so that Error stays comparable.
+	raw string
 }
 
+// maxRawErrorBytes caps the size of the error body kept by Error.
+const maxRawErrorBytes = 4096
+
 func (e Error) Error() string {
 	return fmt.Sprintf("%s (%s)", e.Message, e.Code)
 }
@@ -23,6 +42,36 @@ func (e Error) ErrCode() string {
 	return e.Code
 }
 
+// Is reports whether e matches one of the sentinel errors below, so that
+// errors.Is(err, ErrOrderNotFound) works for errors returned by Client.
+func (e Error) Is(target error) bool {
+	switch target {
+	case ErrInsufficientBalance:
+		return e.Code == ErrCodeInsufficientBalance
+	case ErrOrderNotFound:
+		return e.Code == ErrCodeOrderNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests ||
+			e.Code == ErrCodeTooManyRequests
+	case ErrAuth:
+		return e.StatusCode == http.StatusUnauthorized ||
+			e.StatusCode == http.StatusForbidden ||
+			e.Code == ErrCodeInsufficientPermissions
+	}
+	return false
+}
+
+// Raw returns the JSON error body as received from the server, which may
+// include fields not decoded into Error. Bodies longer than 4 KiB are
+// truncated. It returns nil for errors which weren't received from the
+// server.
+func (e Error) Raw() []byte {
+	if e.raw == "" {
+		return nil
+	}
+	return []byte(e.raw)
+}
+
 // IsErrorCode returns whether an error is identifiable by a given code. This can be used to handle luno.Client errors.
 // Any other errors will cause this to return false.
 func IsErrorCode(err error, code string) bool {
@@ -31,9 +80,112 @@ func IsErrorCode(err error, code string) bool {
 	}
 
 	var lErr Error
-	if errors.Is(err, lErr) {
+	if errors.As(err, &lErr) {
 		return lErr.ErrCode() == code
 	}
 
 	return false
 }
+
+// Error codes returned by the API.
+const (
+	// ErrCodeInsufficientBalance is returned when an account doesn't have
+	// enough available balance for an order or send.
+	ErrCodeInsufficientBalance = "ErrInsufficientBalance"
+
+	// ErrCodeOrderNotFound is returned when an order doesn't exist or
+	// belongs to another user.
+	ErrCodeOrderNotFound = "ErrOrderNotFound"
+
+	// ErrCodeTooManyRequests is returned when a request is rate limited.
+	ErrCodeTooManyRequests = "ErrTooManyRequests"
+)
+
+// Sentinel errors for classes of API errors. Use errors.Is to check for them:
+// Error matches them by code or HTTP status, and the typed errors wrapping
+// Error match them too.
+var (
+	ErrInsufficientBalance = errors.New("luno: insufficient balance")
+	ErrOrderNotFound       = errors.New("luno: order not found")
+
+	// ErrRateLimited is returned for requests rejected with 429 Too Many
+	// Requests.
+	ErrRateLimited = errors.New("luno: too many requests")
+
+	// ErrAuth matches errors for missing or invalid credentials and for API
+	// keys without the required permissions.
+	ErrAuth = errors.New("luno: not authorised")
+)
+
+// InsufficientBalanceError is returned when a request is rejected for
+// insufficient funds. The balance detail is only populated if Luno includes
+// it in the error response; otherwise the amounts are zero.
+type InsufficientBalanceError struct {
+	Err Error
+
+	Asset     string
+	Available decimal.Decimal
+	Required  decimal.Decimal
+}
+
+func (e *InsufficientBalanceError) Error() string {
+	if e.Asset == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s available, %s required",
+		e.Err.Error(), e.Available, e.Required)
+}
+
+// Unwrap returns the underlying API error.
+func (e *InsufficientBalanceError) Unwrap() error {
+	return e.Err
+}
+
+// Shortfall returns how much more balance is required. It is zero if the
+// balance detail is unknown.
+func (e *InsufficientBalanceError) Shortfall() decimal.Decimal {
+	return e.Required.Sub(e.Available)
+}
+
+// ErrCodePostOnlyRejected is the error code returned when a post-only order
+// is rejected because it would have traded immediately.
+const ErrCodePostOnlyRejected = "ErrPostOnlyOrderWouldTrade"
+
+// PostOnlyRejectedError is returned when a post-only limit order is rejected
+// because its price would have crossed the book. The order can be repriced
+// and placed again.
+type PostOnlyRejectedError struct {
+	Err Error
+}
+
+func (e *PostOnlyRejectedError) Error() string {
+	return "luno: post-only order would have crossed the book: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying API error.
+func (e *PostOnlyRejectedError) Unwrap() error {
+	return e.Err
+}
+
+// makeAPIError returns the typed error for the API error e decoded from the
+// response body b.
+func makeAPIError(b []byte, e Error) error {
+	switch e.Code {
+	case ErrCodeInsufficientBalance:
+		var detail struct {
+			Asset     string          `json:"asset"`
+			Available decimal.Decimal `json:"available"`
+			Required  decimal.Decimal `json:"required"`
+		}
+		_ = json.Unmarshal(b, &detail)
+		return &InsufficientBalanceError{
+			Err:       e,
+			Asset:     detail.Asset,
+			Available: detail.Available,
+			Required:  detail.Required,
+		}
+	case ErrCodePostOnlyRejected:
+		return &PostOnlyRejectedError{Err: e}
+	}
+	return e
+}
@@ -1,7 +1,13 @@
 package luno
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -67,3 +73,167 @@ func TestIsErrorCode(t *testing.T) {
 		})
 	}
 }
+
+func TestMakeAPIErrorInsufficientBalance(t *testing.T) {
+	testCases := []struct {
+		name         string
+		body         string
+		expAsset     string
+		expShortfall string
+	}{
+		{
+			name:         "without detail",
+			body:         `{"error":"Insufficient balance","error_code":"ErrInsufficientBalance"}`,
+			expShortfall: "0",
+		},
+		{
+			name:         "with detail",
+			body:         `{"error":"Insufficient balance","error_code":"ErrInsufficientBalance","asset":"XBT","available":"0.5","required":"0.75"}`,
+			expAsset:     "XBT",
+			expShortfall: "0.25",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var e Error
+			if err := json.Unmarshal([]byte(tc.body), &e); err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			err := makeAPIError([]byte(tc.body), e)
+
+			var ibErr *InsufficientBalanceError
+			if !errors.As(err, &ibErr) {
+				t.Fatalf("Expected InsufficientBalanceError, got %T", err)
+			}
+			if ibErr.Asset != tc.expAsset {
+				t.Errorf("Expected asset %q, got %q", tc.expAsset, ibErr.Asset)
+			}
+			if act := ibErr.Shortfall().String(); act != tc.expShortfall {
+				t.Errorf("Expected shortfall %s, got %s", tc.expShortfall, act)
+			}
+			if !IsErrorCode(err, ErrCodeInsufficientBalance) {
+				t.Errorf("Expected error to match code %s", ErrCodeInsufficientBalance)
+			}
+		})
+	}
+}
+
+func TestErrorRaw(t *testing.T) {
+	body := `{"error":"Not found","error_code":"ErrNotFound","detail":"extra"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/1/ticker" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(body))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"error":"%s","error_code":"ErrLong"}`,
+			strings.Repeat("x", 2*maxRawErrorBytes))
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	_, err := cl.GetTicker(context.Background(), &GetTickerRequest{Pair: "XBTZAR"})
+	var e Error
+	if !errors.As(err, &e) {
+		t.Fatalf("Expected Error, got %v", err)
+	}
+	if string(e.Raw()) != body {
+		t.Errorf("Expected raw body %s, got %s", body, e.Raw())
+	}
+
+	_, err = cl.GetTickers(context.Background(), &GetTickersRequest{})
+	if !errors.As(err, &e) {
+		t.Fatalf("Expected Error, got %v", err)
+	}
+	if len(e.Raw()) != maxRawErrorBytes {
+		t.Errorf("Expected raw body capped at %d bytes, got %d",
+			maxRawErrorBytes, len(e.Raw()))
+	}
+
+	if (Error{Code: "ErrFoo"}).Raw() != nil {
+		t.Errorf("Expected no raw body for a constructed error")
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		exp  error
+	}{
+		{
+			name: "insufficient balance",
+			err: &InsufficientBalanceError{
+				Err: Error{Code: ErrCodeInsufficientBalance, StatusCode: 400},
+			},
+			exp: ErrInsufficientBalance,
+		},
+		{
+			name: "order not found",
+			err:  Error{Code: ErrCodeOrderNotFound, StatusCode: 404},
+			exp:  ErrOrderNotFound,
+		},
+		{
+			name: "rate limited by code",
+			err:  Error{Code: ErrCodeTooManyRequests},
+			exp:  ErrRateLimited,
+		},
+		{
+			name: "unauthorised",
+			err:  Error{Code: "ErrUnauthorised", StatusCode: 401},
+			exp:  ErrAuth,
+		},
+		{
+			name: "insufficient permissions",
+			err:  fmt.Errorf("wrapped: %w", Error{Code: ErrCodeInsufficientPermissions}),
+			exp:  ErrAuth,
+		},
+	}
+
+	sentinels := []error{ErrInsufficientBalance, ErrOrderNotFound, ErrRateLimited, ErrAuth}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, s := range sentinels {
+				if act := errors.Is(tc.err, s); act != (s == tc.exp) {
+					t.Errorf("Expected errors.Is(%v, %v) to be %v, got %v",
+						tc.err, s, s == tc.exp, act)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorStatusCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/1/ticker" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Order not found","error_code":"ErrOrderNotFound"}`))
+			return
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	cl := NewClient(WithBaseURL(srv.URL))
+	ctx := WithSingleAttempt(context.Background())
+
+	_, err := cl.GetTicker(ctx, &GetTickerRequest{Pair: "XBTZAR"})
+	var e Error
+	if !errors.As(err, &e) {
+		t.Fatalf("Expected Error, got %v", err)
+	}
+	if e.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status code 404, got %d", e.StatusCode)
+	}
+	if !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("Expected ErrOrderNotFound, got %v", err)
+	}
+
+	_, err = cl.GetTickers(ctx, &GetTickersRequest{})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+}
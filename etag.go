@@ -0,0 +1,42 @@
+package luno
+
+import "sync"
+
+// etagCache stores the ETag and body of GET responses by URL so that repeated
+// requests can be made conditional.
+type etagCache struct {
+	mu      sync.Mutex
+	entries map[string]etagEntry
+}
+
+type etagEntry struct {
+	etag string
+	body []byte
+}
+
+func (c *etagCache) get(url string) (etagEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[url]
+	return e, ok
+}
+
+func (c *etagCache) set(url string, e etagEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[url] = e
+}
+
+// SetConditionalRequests enables or disables conditional GET requests. When
+// enabled, the ETag of each GET response is stored and sent back in the
+// If-None-Match header of the next identical request. A 304 Not Modified
+// response is then decoded from the stored body. Only endpoints which return
+// an ETag benefit from this. One response is kept per distinct URL so this is
+// intended for polling a small set of slow-moving endpoints, such as Markets.
+func (cl *Client) SetConditionalRequests(enabled bool) {
+	if !enabled {
+		cl.etags = nil
+		return
+	}
+	cl.etags = &etagCache{entries: make(map[string]etagEntry)}
+}
@@ -0,0 +1,65 @@
+package luno_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	var full, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"markets":[{"market_id":"XBTZAR","price_scale":0}]}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetConditionalRequests(true)
+
+	for i := 0; i < 3; i++ {
+		res, err := cl.Markets(context.Background(), &luno.MarketsRequest{})
+		if err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		if len(res.Markets) != 1 || res.Markets[0].MarketId != "XBTZAR" {
+			t.Errorf("Expected cached markets, got %+v", res.Markets)
+		}
+	}
+	if full != 1 || notModified != 2 {
+		t.Errorf("Expected 1 full and 2 not modified responses, got %d and %d",
+			full, notModified)
+	}
+
+	cl.SetConditionalRequests(false)
+	if _, err := cl.Markets(context.Background(), &luno.MarketsRequest{}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if full != 2 {
+		t.Errorf("Expected unconditional request when disabled, got %d full", full)
+	}
+}
+
+func TestNotModifiedWithoutCache(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	if _, err := cl.Markets(context.Background(), &luno.MarketsRequest{}); err == nil {
+		t.Errorf("Expected error for unexpected 304, got nil")
+	}
+}
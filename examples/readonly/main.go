@@ -19,9 +19,8 @@ var (
 func main() {
 	flag.Parse()
 
-	cl := luno.NewClient()
+	cl := luno.NewClient(luno.WithAuth(*apiKeyID, *apiKeySecret))
 	cl.SetDebug(*debug)
-	cl.SetAuth(*apiKeyID, *apiKeySecret)
 
 	ctx := context.Background()
 
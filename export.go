@@ -0,0 +1,41 @@
+package luno
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvTimeFormat is the timestamp format used in CSV exports.
+const csvTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+var transactionCSVHeader = []string{
+	"row_index", "timestamp", "currency", "balance", "balance_delta",
+	"description",
+}
+
+// WriteCSV writes the transactions in r as CSV to w, starting with a header
+// row. Timestamps are written in UTC and amounts are written as plain
+// decimals.
+func (r ListTransactionsResponse) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(transactionCSVHeader); err != nil {
+		return err
+	}
+	for _, tx := range r.Transactions {
+		err := cw.Write([]string{
+			strconv.FormatInt(tx.RowIndex, 10),
+			time.Time(tx.Timestamp).UTC().Format(csvTimeFormat),
+			tx.Currency,
+			tx.Balance.String(),
+			tx.BalanceDelta.String(),
+			tx.Description,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
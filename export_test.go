@@ -0,0 +1,40 @@
+package luno_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func TestListTransactionsResponseWriteCSV(t *testing.T) {
+	delta, err := decimal.NewFromString("0.00000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := luno.ListTransactionsResponse{
+		Transactions: []luno.Transaction{
+			{
+				RowIndex:     1,
+				Timestamp:    luno.Time(time.Date(2021, 2, 3, 4, 5, 6, 7e6, time.UTC)),
+				Currency:     "XBT",
+				Balance:      delta,
+				BalanceDelta: delta,
+				Description:  "Received, with thanks",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := res.WriteCSV(&buf); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	exp := "row_index,timestamp,currency,balance,balance_delta,description\n" +
+		"1,2021-02-03T04:05:06.007Z,XBT,0.00000001,0.00000001,\"Received, with thanks\"\n"
+	if buf.String() != exp {
+		t.Errorf("Expected %q, got %q", exp, buf.String())
+	}
+}
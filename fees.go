@@ -0,0 +1,122 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// feeInfoCacheTTL is how long the fee info of a pair is reused by TakerFee.
+// Fee tiers are based on 30 day trading volume so they change slowly.
+const feeInfoCacheTTL = time.Hour
+
+// feeInfoCache holds the results of GetFeeInfo, keyed by pair.
+type feeInfoCache struct {
+	mu   sync.Mutex
+	fees map[string]cachedFeeInfo
+}
+
+type cachedFeeInfo struct {
+	info    GetFeeInfoResponse
+	fetched time.Time
+}
+
+// FeeInfo returns the account's fee info for pair. It is cached for an hour.
+func (cl *Client) FeeInfo(ctx context.Context, pair string) (GetFeeInfoResponse, error) {
+	c := &cl.feeInfoCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if f, ok := c.fees[pair]; ok && cl.clock.Now().Sub(f.fetched) <= feeInfoCacheTTL {
+		return f.info, nil
+	}
+
+	res, err := cl.GetFeeInfo(ctx, &GetFeeInfoRequest{Pair: pair})
+	if err != nil {
+		return GetFeeInfoResponse{}, err
+	}
+	if c.fees == nil {
+		c.fees = make(map[string]cachedFeeInfo)
+	}
+	c.fees[pair] = cachedFeeInfo{info: *res, fetched: cl.clock.Now()}
+	return *res, nil
+}
+
+// TakerFee returns the account's current taker fee rate for pair, e.g. 0.001
+// for 0.1%, using the cached fee info.
+func (cl *Client) TakerFee(ctx context.Context, pair string) (decimal.Decimal, error) {
+	info, err := cl.FeeInfo(ctx, pair)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	return parseFee("taker", info.TakerFee)
+}
+
+// MakerFee returns the account's current maker fee rate for pair, using the
+// cached fee info. Maker fees can be zero or negative, i.e. a rebate.
+func (cl *Client) MakerFee(ctx context.Context, pair string) (decimal.Decimal, error) {
+	info, err := cl.FeeInfo(ctx, pair)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	return parseFee("maker", info.MakerFee)
+}
+
+func parseFee(kind, s string) (decimal.Decimal, error) {
+	fee, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("luno: invalid %s fee %q: %w",
+			kind, s, err)
+	}
+	return fee, nil
+}
+
+// FeeTier is a tier of a fee schedule.
+type FeeTier struct {
+	// MinVolume is the 30 day trading volume from which the tier applies.
+	MinVolume decimal.Decimal
+
+	// Fee rates of the tier, e.g. 0.001 for 0.1%.
+	Maker decimal.Decimal
+	Taker decimal.Decimal
+}
+
+// FeeSchedule lists the fee tiers of a market, e.g. to project trading costs
+// as volume grows. Luno's API only reports the current tier, see GetFeeInfo,
+// so the schedule has to be built from Luno's published fees.
+type FeeSchedule struct {
+	// Tiers are sorted by MinVolume, starting from zero.
+	Tiers []FeeTier
+}
+
+// NewFeeSchedule returns the fee schedule with the given tiers. The tiers
+// must be sorted by strictly increasing MinVolume, starting from zero.
+func NewFeeSchedule(tiers ...FeeTier) (*FeeSchedule, error) {
+	if len(tiers) == 0 || tiers[0].MinVolume.Sign() != 0 {
+		return nil, errors.New("luno: fee schedule must start at zero volume")
+	}
+	for i := 1; i < len(tiers); i++ {
+		if tiers[i].MinVolume.Cmp(tiers[i-1].MinVolume) <= 0 {
+			return nil, fmt.Errorf("luno: fee tier %d volume %s doesn't "+
+				"exceed %s", i, tiers[i].MinVolume, tiers[i-1].MinVolume)
+		}
+	}
+	return &FeeSchedule{Tiers: tiers}, nil
+}
+
+// FeeAt returns the maker and taker fee rates of the tier for a 30 day
+// trading volume. A volume equal to the MinVolume of a tier is in that tier.
+func (s *FeeSchedule) FeeAt(volume decimal.Decimal) (maker, taker decimal.Decimal) {
+	var t FeeTier
+	for _, tier := range s.Tiers {
+		if volume.Cmp(tier.MinVolume) < 0 {
+			break
+		}
+		t = tier
+	}
+	return t.Maker, t.Taker
+}
@@ -0,0 +1,73 @@
+package luno_test
+
+import (
+	"testing"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func dec(t *testing.T, s string) decimal.Decimal {
+	t.Helper()
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestFeeScheduleFeeAt(t *testing.T) {
+	s, err := luno.NewFeeSchedule(
+		luno.FeeTier{MinVolume: dec(t, "0"), Maker: dec(t, "0"), Taker: dec(t, "0.001")},
+		luno.FeeTier{MinVolume: dec(t, "10"), Maker: dec(t, "0"), Taker: dec(t, "0.0008")},
+		luno.FeeTier{MinVolume: dec(t, "100"), Maker: dec(t, "-0.0001"), Taker: dec(t, "0.0005")},
+	)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	testCases := []struct {
+		volume string
+		maker  string
+		taker  string
+	}{
+		{"0", "0", "0.001"},
+		{"9.99999999", "0", "0.001"},
+		{"10", "0", "0.0008"},
+		{"10.00000001", "0", "0.0008"},
+		{"99.99999999", "0", "0.0008"},
+		{"100", "-0.0001", "0.0005"},
+		{"1000000", "-0.0001", "0.0005"},
+	}
+	for _, tc := range testCases {
+		maker, taker := s.FeeAt(dec(t, tc.volume))
+		if maker.String() != tc.maker || taker.String() != tc.taker {
+			t.Errorf("Expected fees %s/%s at volume %s, got %s/%s",
+				tc.maker, tc.taker, tc.volume, maker, taker)
+		}
+	}
+}
+
+func TestNewFeeScheduleInvalid(t *testing.T) {
+	testCases := []struct {
+		name  string
+		tiers []luno.FeeTier
+	}{
+		{"empty", nil},
+		{"nonzero start", []luno.FeeTier{{MinVolume: dec(t, "1")}}},
+		{"unsorted", []luno.FeeTier{
+			{MinVolume: dec(t, "0")},
+			{MinVolume: dec(t, "100")},
+			{MinVolume: dec(t, "10")},
+		}},
+		{"duplicate", []luno.FeeTier{
+			{MinVolume: dec(t, "0")},
+			{MinVolume: dec(t, "0")},
+		}},
+	}
+	for _, tc := range testCases {
+		if _, err := luno.NewFeeSchedule(tc.tiers...); err == nil {
+			t.Errorf("%s: Expected error", tc.name)
+		}
+	}
+}
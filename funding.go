@@ -0,0 +1,118 @@
+package luno
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// paymentURISchemes are the URI schemes of the currencies supported by
+// PaymentURI.
+var paymentURISchemes = map[string]string{
+	"BCH": "bitcoincash",
+	"ETH": "ethereum",
+	"LTC": "litecoin",
+	"XBT": "bitcoin",
+}
+
+var weiPerEther = decimal.New(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil), 0)
+
+// FundingAddresses returns the receive addresses of asset with the given
+// addresses, or the default receive address if none are given, including
+// the confirmed and unconfirmed amounts received by each, see
+// GetFundingAddress. The Luno API can't list all of an account's receive
+// addresses, so addresses returned by CreateFundingAddress need to be kept
+// by the caller.
+func (cl *Client) FundingAddresses(ctx context.Context, asset string,
+	addresses ...string) ([]FundingAddress, error) {
+
+	if len(addresses) == 0 {
+		addresses = []string{""}
+	}
+	res := make([]FundingAddress, 0, len(addresses))
+	for _, a := range addresses {
+		r, err := cl.GetFundingAddress(ctx, &GetFundingAddressRequest{
+			Asset:   asset,
+			Address: a,
+		})
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, FundingAddress(*r))
+	}
+	return res, nil
+}
+
+// TotalBalance returns the total amount received by a, including
+// unconfirmed transactions.
+func (a FundingAddress) TotalBalance() decimal.Decimal {
+	return a.TotalReceived.Add(a.TotalUnconfirmed)
+}
+
+// PaymentURI returns a payment URI requesting amount to be sent to a, see
+// the PaymentURI function.
+func (a FundingAddress) PaymentURI(amount decimal.Decimal) (string, error) {
+	return PaymentURI(a.Asset, a.Address, amount, "")
+}
+
+// PaymentURI returns a BIP-21 style payment URI for sending amount of
+// currency to address, e.g. to show as a link or QR code. The amount and
+// label are left out if they are zero or empty. The address is validated
+// with ValidateAddress.
+//
+// Bitcoin (XBT), Litecoin (LTC) and Bitcoin Cash (BCH) URIs have the amount
+// in whole coins, e.g. "bitcoin:1BvB...?amount=0.01". Ethereum (ETH) URIs
+// follow EIP-681 and have the amount in wei, so it can't have more than 18
+// decimal places. An error is returned for other currencies.
+//
+// Receive addresses from the API also have a QrCodeUri, without an amount.
+func PaymentURI(currency, address string, amount decimal.Decimal,
+	label string) (string, error) {
+
+	scheme, ok := paymentURISchemes[currency]
+	if !ok {
+		return "", fmt.Errorf("luno: payment URIs are not supported for %s", currency)
+	}
+	// Bitcoin Cash addresses may already include the scheme.
+	address = strings.TrimPrefix(address, scheme+":")
+	if err := ValidateAddress(currency, address); err != nil {
+		return "", err
+	}
+	if amount.Sign() < 0 {
+		return "", fmt.Errorf("luno: invalid payment amount %s", amount)
+	}
+
+	q := make([]string, 0, 2)
+	if amount.Sign() > 0 {
+		if currency == "ETH" {
+			wei := amount.Mul(weiPerEther)
+			whole := wei.ToScale(0)
+			if whole.Cmp(wei) != 0 {
+				return "", fmt.Errorf("luno: payment amount %s has more "+
+					"than 18 decimal places", amount)
+			}
+			q = append(q, "value="+whole.String())
+		} else {
+			q = append(q, "amount="+amount.String())
+		}
+	}
+	if label != "" {
+		q = append(q, "label="+uriEscape(label))
+	}
+
+	uri := scheme + ":" + address
+	if len(q) > 0 {
+		uri += "?" + strings.Join(q, "&")
+	}
+	return uri, nil
+}
+
+// uriEscape escapes s for a URI query, with spaces as %20 as BIP-21 expects
+// rather than +.
+func uriEscape(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
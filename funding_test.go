@@ -0,0 +1,139 @@
+package luno_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func TestFundingAddresses(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			r.ParseForm()
+			queries = append(queries, "POST "+r.Form.Encode())
+			w.Write([]byte(`{"asset":"XBT","address":"new","name":"Shop"}`))
+			return
+		}
+		queries = append(queries, "GET "+r.URL.RawQuery)
+		addr := r.URL.Query().Get("address")
+		if addr == "" {
+			addr = "default"
+		}
+		w.Write([]byte(`{"asset":"XBT","address":"` + addr + `",` +
+			`"total_received":"1.5","total_unconfirmed":"0.25"}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	ctx := context.Background()
+
+	created, err := cl.CreateFundingAddress(ctx, &luno.CreateFundingAddressRequest{
+		Asset: "XBT",
+		Name:  "Shop",
+	})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if created.Address != "new" || created.Name != "Shop" {
+		t.Errorf("Expected created address, got %+v", created)
+	}
+
+	addrs, err := cl.FundingAddresses(ctx, "XBT")
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(addrs) != 1 || addrs[0].Address != "default" {
+		t.Errorf("Expected default address, got %+v", addrs)
+	}
+
+	addrs, err = cl.FundingAddresses(ctx, "XBT", "a", "b")
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(addrs) != 2 || addrs[0].Address != "a" || addrs[1].Address != "b" {
+		t.Fatalf("Expected addresses a and b, got %+v", addrs)
+	}
+	if addrs[1].TotalUnconfirmed.Cmp(mustDecimal(t, "0.25")) != 0 {
+		t.Errorf("Expected unconfirmed 0.25, got %s", addrs[1].TotalUnconfirmed)
+	}
+	if addrs[1].TotalBalance().Cmp(mustDecimal(t, "1.75")) != 0 {
+		t.Errorf("Expected total balance 1.75, got %s", addrs[1].TotalBalance())
+	}
+
+	exp := []string{
+		"POST asset=XBT&name=Shop",
+		"GET address=&asset=XBT",
+		"GET address=a&asset=XBT",
+		"GET address=b&asset=XBT",
+	}
+	if len(queries) != len(exp) {
+		t.Fatalf("Expected %d requests, got %v", len(exp), queries)
+	}
+	for i := range exp {
+		if queries[i] != exp[i] {
+			t.Errorf("Expected request %q, got %q", exp[i], queries[i])
+		}
+	}
+}
+
+func TestPaymentURI(t *testing.T) {
+	testCases := []struct {
+		name     string
+		currency string
+		address  string
+		amount   string
+		label    string
+		exp      string
+		expErr   bool
+	}{
+		{name: "xbt", currency: "XBT", address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+			amount: "0.01", label: "Order 42",
+			exp: "bitcoin:1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2?amount=0.01&label=Order%2042"},
+		{name: "no amount", currency: "LTC", address: "LVuDpNCSSj6pQ7t9Pv6d6sUkLKoqDEVUnJ",
+			amount: "0", exp: "litecoin:LVuDpNCSSj6pQ7t9Pv6d6sUkLKoqDEVUnJ"},
+		{name: "bch with scheme", currency: "BCH",
+			address: "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", amount: "1.5",
+			exp: "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a?amount=1.5"},
+		{name: "eth in wei", currency: "ETH", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			amount: "0.25", exp: "ethereum:0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed?value=250000000000000000"},
+		{name: "eth too precise", currency: "ETH", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			amount: "0.0000000000000000001", expErr: true},
+		{name: "invalid address", currency: "XBT", address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3",
+			amount: "1", expErr: true},
+		{name: "negative amount", currency: "XBT", address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+			amount: "-1", expErr: true},
+		{name: "unsupported", currency: "ZAR", address: "x", amount: "1", expErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uri, err := luno.PaymentURI(tc.currency, tc.address, mustDecimal(t, tc.amount), tc.label)
+			if tc.expErr {
+				if err == nil {
+					t.Errorf("Expected error, got %q", uri)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if uri != tc.exp {
+				t.Errorf("Expected %q, got %q", tc.exp, uri)
+			}
+		})
+	}
+
+	a := luno.FundingAddress{Asset: "XBT", Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"}
+	uri, err := a.PaymentURI(decimal.NewFromInt64(2))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if exp := "bitcoin:1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2?amount=2"; uri != exp {
+		t.Errorf("Expected %q, got %q", exp, uri)
+	}
+}
@@ -0,0 +1,234 @@
+// Package history downloads the public trade history of a market page by
+// page, delivering each trade once and in order, with checkpoints from which
+// an interrupted download can be resumed.
+//
+// Example:
+//
+//	cp, err := history.ReadCheckpoint("trades.checkpoint")
+//	...
+//	err = history.DownloadTrades(ctx, cl, "XBTZAR", from, time.Time{},
+//		history.SinkFunc(func(ctx context.Context, trades []luno.Trade,
+//			cp history.Checkpoint) error {
+//
+//			if err := store(trades); err != nil {
+//				return err
+//			}
+//			return history.WriteCheckpoint("trades.checkpoint", cp)
+//		}),
+//		history.WithCheckpoint(cp))
+//
+// How far back trades can be downloaded is limited by ListTrades, which
+// currently only returns trades of the last 24 hours.
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/luno/luno-go"
+)
+
+// pageSize is the maximum number of trades returned by ListTrades.
+const pageSize = 100
+
+// Checkpoint is the position of a download after the last trade delivered.
+type Checkpoint struct {
+	Pair string `json:"pair"`
+
+	// Timestamp and Sequence are those of the last trade delivered.
+	Timestamp time.Time `json:"timestamp"`
+	Sequence  int64     `json:"sequence"`
+}
+
+// IsZero returns whether cp is the zero Checkpoint, i.e. no trades have been
+// delivered yet.
+func (cp Checkpoint) IsZero() bool {
+	return cp == Checkpoint{}
+}
+
+// Sink receives downloaded trades.
+type Sink interface {
+	// WriteTrades is called with each page of new trades, oldest first, and
+	// the checkpoint to resume from once they have been stored. Storing the
+	// trades and the checkpoint together makes sure that no trade is lost or
+	// stored twice. If WriteTrades returns an error the download stops.
+	WriteTrades(ctx context.Context, trades []luno.Trade, cp Checkpoint) error
+}
+
+// SinkFunc is a function which implements Sink.
+type SinkFunc func(ctx context.Context, trades []luno.Trade, cp Checkpoint) error
+
+// WriteTrades calls f.
+func (f SinkFunc) WriteTrades(ctx context.Context, trades []luno.Trade, cp Checkpoint) error {
+	return f(ctx, trades, cp)
+}
+
+type options struct {
+	checkpoint   Checkpoint
+	pageInterval time.Duration
+}
+
+// Option configures DownloadTrades.
+type Option func(*options)
+
+// WithCheckpoint returns an option which resumes a download after the last
+// trade delivered before cp was made. A zero cp starts from the beginning.
+func WithCheckpoint(cp Checkpoint) Option {
+	return func(o *options) {
+		o.checkpoint = cp
+	}
+}
+
+// WithPageInterval returns an option which waits at least d between
+// requests, on top of the client's own rate limit, to leave room for other
+// requests made with the same API key.
+func WithPageInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.pageInterval = d
+	}
+}
+
+// DownloadTrades downloads the trades of pair from from up to, but not
+// including, to using ListTrades and passes them to sink. A zero to
+// downloads up to now. Duplicate trades at page boundaries are dropped.
+//
+// Requests are made with luno.PriorityLow, so that if a rate limit is set on
+// the client with SetRateLimit the download waits for other requests.
+func DownloadTrades(ctx context.Context, cl luno.API, pair string, from, to time.Time,
+	sink Sink, opts ...Option) error {
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	last := Checkpoint{Pair: pair}
+	since := from
+	if !o.checkpoint.IsZero() {
+		if o.checkpoint.Pair != pair {
+			return fmt.Errorf("history: checkpoint is for %s, not %s",
+				o.checkpoint.Pair, pair)
+		}
+		last = o.checkpoint
+		if last.Timestamp.After(since) {
+			since = last.Timestamp
+		}
+	}
+	reqCtx := luno.WithPriority(ctx, luno.PriorityLow)
+
+	for {
+		res, err := cl.ListTrades(reqCtx, &luno.ListTradesRequest{
+			Pair:  pair,
+			Since: luno.Time(since),
+		})
+		if err != nil {
+			return err
+		}
+		trades := append([]luno.Trade(nil), res.Trades...)
+		sort.SliceStable(trades, func(i, j int) bool {
+			return before(trades[i], trades[j])
+		})
+
+		done := len(trades) < pageSize
+		var page []luno.Trade
+		for _, t := range trades {
+			ts := time.Time(t.Timestamp)
+			if !to.IsZero() && !ts.Before(to) {
+				done = true
+				break
+			}
+			if ts.Before(from) || !after(t, last) {
+				continue
+			}
+			page = append(page, t)
+			last.Timestamp, last.Sequence = ts, t.Sequence
+		}
+		if len(page) > 0 {
+			if err := sink.WriteTrades(ctx, page, last); err != nil {
+				return fmt.Errorf("history: writing trades: %w", err)
+			}
+		}
+		if done {
+			return nil
+		}
+
+		if last.Timestamp.After(since) {
+			since = last.Timestamp
+		} else {
+			// A whole page of trades in the same millisecond has already been
+			// delivered, so move on to avoid fetching it forever.
+			since = since.Add(time.Millisecond)
+		}
+
+		if o.pageInterval > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(o.pageInterval):
+			}
+		}
+	}
+}
+
+// before returns whether a was executed before b.
+func before(a, b luno.Trade) bool {
+	ta, tb := time.Time(a.Timestamp), time.Time(b.Timestamp)
+	if !ta.Equal(tb) {
+		return ta.Before(tb)
+	}
+	return a.Sequence < b.Sequence
+}
+
+// after returns whether t was executed after the last trade of cp.
+func after(t luno.Trade, cp Checkpoint) bool {
+	ts := time.Time(t.Timestamp)
+	if !ts.Equal(cp.Timestamp) {
+		return ts.After(cp.Timestamp)
+	}
+	return t.Sequence > cp.Sequence
+}
+
+// ReadCheckpoint reads a checkpoint written by WriteCheckpoint. It returns
+// the zero Checkpoint if the file doesn't exist.
+func ReadCheckpoint(path string) (Checkpoint, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Checkpoint{}, nil
+	} else if err != nil {
+		return Checkpoint{}, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return Checkpoint{}, fmt.Errorf("history: invalid checkpoint %s: %w", path, err)
+	}
+	return cp, nil
+}
+
+// WriteCheckpoint writes cp to the file at path. The file is replaced
+// atomically, so that an interrupted write leaves the previous checkpoint.
+func WriteCheckpoint(path string, cp Checkpoint) error {
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
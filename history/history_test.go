@@ -0,0 +1,158 @@
+package history_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-go/history"
+	"github.com/luno/luno-go/lunotest"
+)
+
+var t0 = time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+
+// newFake returns a fake whose ListTrades returns up to 100 of n trades at or
+// after since, oldest first. Every pair of trades shares a timestamp.
+func newFake(n int) *lunotest.Fake {
+	var trades []luno.Trade
+	for i := 0; i < n; i++ {
+		trades = append(trades, luno.Trade{
+			Sequence:  int64(i + 1),
+			Timestamp: luno.Time(t0.Add(time.Duration(i/2) * time.Second)),
+			Price:     decimal.NewFromInt64(100),
+			Volume:    decimal.NewFromInt64(1),
+		})
+	}
+	f := lunotest.NewFake()
+	f.Handle("ListTrades", func(ctx context.Context, req interface{}) (interface{}, error) {
+		since := time.Time(req.(*luno.ListTradesRequest).Since)
+		var res luno.ListTradesResponse
+		for _, t := range trades {
+			if !time.Time(t.Timestamp).Before(since) && len(res.Trades) < 100 {
+				res.Trades = append(res.Trades, t)
+			}
+		}
+		return &res, nil
+	})
+	return f
+}
+
+func collect(seqs *[]int64) history.Sink {
+	return history.SinkFunc(func(ctx context.Context, trades []luno.Trade, cp history.Checkpoint) error {
+		for _, t := range trades {
+			*seqs = append(*seqs, t.Sequence)
+		}
+		return nil
+	})
+}
+
+func checkSequences(t *testing.T, seqs []int64, from, to int64) {
+	t.Helper()
+	if len(seqs) != int(to-from+1) {
+		t.Fatalf("Expected %d trades, got %d", to-from+1, len(seqs))
+	}
+	for i, s := range seqs {
+		if s != from+int64(i) {
+			t.Fatalf("Expected trade %d to have sequence %d, got %d", i, from+int64(i), s)
+		}
+	}
+}
+
+func TestDownloadTrades(t *testing.T) {
+	f := newFake(250)
+	var seqs []int64
+	err := history.DownloadTrades(context.Background(), f, "XBTZAR", t0, time.Time{}, collect(&seqs))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	checkSequences(t, seqs, 1, 250)
+	if n := len(f.CallsTo("ListTrades")); n != 3 {
+		t.Errorf("Expected 3 pages, got %d", n)
+	}
+}
+
+func TestDownloadTradesRange(t *testing.T) {
+	var seqs []int64
+	err := history.DownloadTrades(context.Background(), newFake(250), "XBTZAR",
+		t0.Add(10*time.Second), t0.Add(60*time.Second), collect(&seqs))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	checkSequences(t, seqs, 21, 120)
+}
+
+func TestDownloadTradesResume(t *testing.T) {
+	f := newFake(250)
+	errFull := errors.New("disk full")
+	fail := true
+	var seqs []int64
+	var saved history.Checkpoint
+	sink := history.SinkFunc(func(ctx context.Context, trades []luno.Trade, cp history.Checkpoint) error {
+		if fail && len(seqs) >= 100 {
+			return errFull
+		}
+		for _, tr := range trades {
+			seqs = append(seqs, tr.Sequence)
+		}
+		saved = cp
+		return nil
+	})
+
+	err := history.DownloadTrades(context.Background(), f, "XBTZAR", t0, time.Time{}, sink)
+	if !errors.Is(err, errFull) {
+		t.Fatalf("Expected sink error, got %v", err)
+	}
+	exp := history.Checkpoint{Pair: "XBTZAR", Timestamp: t0.Add(49 * time.Second), Sequence: 100}
+	if saved != exp {
+		t.Errorf("Expected checkpoint %+v, got %+v", exp, saved)
+	}
+
+	fail = false
+	err = history.DownloadTrades(context.Background(), f, "XBTZAR", t0, time.Time{}, sink,
+		history.WithCheckpoint(saved))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	checkSequences(t, seqs, 1, 250)
+
+	err = history.DownloadTrades(context.Background(), f, "ETHZAR", t0, time.Time{}, sink,
+		history.WithCheckpoint(saved))
+	if err == nil {
+		t.Errorf("Expected error for checkpoint of another pair, got nil")
+	}
+}
+
+func TestCheckpointFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint")
+
+	cp, err := history.ReadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if !cp.IsZero() {
+		t.Errorf("Expected zero checkpoint for missing file, got %+v", cp)
+	}
+
+	exp := history.Checkpoint{Pair: "XBTZAR", Timestamp: t0, Sequence: 42}
+	if err := history.WriteCheckpoint(path, exp); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	cp, err = history.ReadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if !cp.Timestamp.Equal(exp.Timestamp) || cp.Pair != exp.Pair || cp.Sequence != exp.Sequence {
+		t.Errorf("Expected %+v, got %+v", exp, cp)
+	}
+}
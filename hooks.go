@@ -0,0 +1,108 @@
+package luno
+
+import (
+	"context"
+	"time"
+)
+
+// RequestInfo describes an attempt of an API request.
+type RequestInfo struct {
+	Method string
+
+	// Path is the endpoint path template, e.g. /api/1/orders/{id}.
+	Path string
+
+	// Attempt is the zero-based attempt number. It is greater than zero for
+	// retries.
+	Attempt int
+
+	// CorrelationID is the ID set using WithCorrelationID, if any.
+	CorrelationID string
+
+	// Body is the form-encoded or JSON body of the request, if any, with
+	// the values of sensitive parameters redacted.
+	Body string
+
+	// Budget is the time which remained before the deadline of the request
+	// context when the attempt started, or 0 if the context has no deadline.
+	Budget time.Duration
+}
+
+// ResponseInfo describes the outcome of an attempt of an API request.
+type ResponseInfo struct {
+	RequestInfo
+
+	// StatusCode is the HTTP status code, or 0 if no response was received.
+	StatusCode int
+
+	// RequestID is the ID which the server assigned to the request, if any.
+	RequestID string
+
+	Duration time.Duration
+	Err      error
+}
+
+// BudgetUsed returns the fraction of the time budget which the attempt took,
+// i.e. Duration divided by Budget. It returns 0 if the request context has no
+// deadline.
+func (i ResponseInfo) BudgetUsed() float64 {
+	if i.Budget <= 0 {
+		return 0
+	}
+	return float64(i.Duration) / float64(i.Budget)
+}
+
+// Hooks are callbacks which are invoked for every attempt of an API request.
+// Any callback may be nil.
+type Hooks struct {
+	BeforeRequest func(ctx context.Context, info RequestInfo)
+	AfterResponse func(ctx context.Context, info ResponseInfo)
+
+	// Deprecation is called the first time a response for an endpoint
+	// signals that it is deprecated.
+	Deprecation func(ctx context.Context, n DeprecationNotice)
+
+	// Throttled is called when an attempt of a request was delayed by the
+	// client's rate limiter, or is about to be delayed before a retry
+	// because the server responded with 429 Too Many Requests.
+	Throttled func(ctx context.Context, info ThrottleInfo)
+
+	// Warning is called for each problem detected in a successful response,
+	// such as a stale ticker, and when a local check of a request is skipped.
+	Warning func(ctx context.Context, w Warning)
+}
+
+// SetHooks sets the callbacks invoked around every API request.
+func (cl *Client) SetHooks(h Hooks) {
+	cl.hooks = h
+}
+
+func (h Hooks) beforeRequest(ctx context.Context, info RequestInfo) {
+	if h.BeforeRequest != nil {
+		h.BeforeRequest(ctx, info)
+	}
+}
+
+func (h Hooks) afterResponse(ctx context.Context, info ResponseInfo) {
+	if h.AfterResponse != nil {
+		h.AfterResponse(ctx, info)
+	}
+}
+
+func (h Hooks) deprecation(ctx context.Context, n DeprecationNotice) {
+	if h.Deprecation != nil {
+		h.Deprecation(ctx, n)
+	}
+}
+
+func (h Hooks) throttled(ctx context.Context, info ThrottleInfo) {
+	if h.Throttled != nil {
+		h.Throttled(ctx, info)
+	}
+}
+
+func (h Hooks) warning(ctx context.Context, w Warning) {
+	if h.Warning != nil {
+		h.Warning(ctx, w)
+	}
+}
@@ -0,0 +1,262 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestHooksCorrelationID(t *testing.T) {
+	var header string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Correlation-Id")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var before []luno.RequestInfo
+	var after []luno.ResponseInfo
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetCorrelationIDHeader("X-Correlation-Id")
+	cl.SetHooks(luno.Hooks{
+		BeforeRequest: func(ctx context.Context, info luno.RequestInfo) {
+			before = append(before, info)
+		},
+		AfterResponse: func(ctx context.Context, info luno.ResponseInfo) {
+			after = append(after, info)
+		},
+	})
+
+	ctx := luno.WithCorrelationID(context.Background(), "abc123")
+	_, err := cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	if len(before) != 1 || len(after) != 1 {
+		t.Fatalf("Expected hooks to be called once, got %d and %d",
+			len(before), len(after))
+	}
+	if before[0].CorrelationID != "abc123" {
+		t.Errorf("Expected correlation ID %q, got %q", "abc123", before[0].CorrelationID)
+	}
+	if after[0].CorrelationID != "abc123" {
+		t.Errorf("Expected correlation ID %q, got %q", "abc123", after[0].CorrelationID)
+	}
+	if after[0].Path != "/api/1/ticker" || after[0].StatusCode != http.StatusOK {
+		t.Errorf("Unexpected response info %+v", after[0])
+	}
+	if header != "abc123" {
+		t.Errorf("Expected correlation ID header %q, got %q", "abc123", header)
+	}
+}
+
+func TestHooksDeprecation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/1/ticker" {
+			w.Header().Set("Sunset", "Sat, 31 Dec 2022 23:59:59 GMT")
+			w.Header().Set("Warning", `299 - "Deprecated API"`)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var notices []luno.DeprecationNotice
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetHooks(luno.Hooks{
+		Deprecation: func(ctx context.Context, n luno.DeprecationNotice) {
+			notices = append(notices, n)
+		},
+	})
+
+	if _, ok := cl.LastDeprecation(); ok {
+		t.Errorf("Expected no deprecation notice before any request")
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		_, err := cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"})
+		if err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+	}
+	if _, err := cl.GetTickers(ctx, &luno.GetTickersRequest{}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	if len(notices) != 1 {
+		t.Fatalf("Expected 1 deprecation notice, got %d", len(notices))
+	}
+	n := notices[0]
+	if n.Method != http.MethodGet || n.Path != "/api/1/ticker" {
+		t.Errorf("Expected GET /api/1/ticker, got %s %s", n.Method, n.Path)
+	}
+	if n.Warning != `299 - "Deprecated API"` {
+		t.Errorf("Unexpected warning %q", n.Warning)
+	}
+	expSunset := time.Date(2022, 12, 31, 23, 59, 59, 0, time.UTC)
+	if !n.Sunset.Equal(expSunset) {
+		t.Errorf("Expected sunset %s, got %s", expSunset, n.Sunset)
+	}
+
+	last, ok := cl.LastDeprecation()
+	if !ok || last.Path != "/api/1/ticker" {
+		t.Errorf("Expected last deprecation for /api/1/ticker, got %+v", last)
+	}
+}
+
+func TestHooksDeadline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var (
+		deadline time.Time
+		before   luno.RequestInfo
+		after    luno.ResponseInfo
+	)
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetHooks(luno.Hooks{
+		BeforeRequest: func(ctx context.Context, info luno.RequestInfo) {
+			deadline, _ = ctx.Deadline()
+			before = info
+		},
+		AfterResponse: func(ctx context.Context, info luno.ResponseInfo) {
+			after = info
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	exp, _ := ctx.Deadline()
+	if _, err := cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if !deadline.Equal(exp) {
+		t.Errorf("Expected deadline %v, got %v", exp, deadline)
+	}
+	if before.Budget <= 59*time.Second || before.Budget > time.Minute {
+		t.Errorf("Expected a budget of about a minute, got %v", before.Budget)
+	}
+	if after.Budget != before.Budget {
+		t.Errorf("Expected budget %v after the response, got %v", before.Budget, after.Budget)
+	}
+	if used := after.BudgetUsed(); used <= 0 || used >= 1 {
+		t.Errorf("Expected a fraction of the budget to be used, got %v", used)
+	}
+
+	// Without a deadline there's no budget.
+	if _, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if !deadline.IsZero() || after.Budget != 0 || after.BudgetUsed() != 0 {
+		t.Errorf("Expected no deadline or budget, got %v, %v and %v",
+			deadline, after.Budget, after.BudgetUsed())
+	}
+}
+
+func TestHooksStaleTicker(t *testing.T) {
+	var ts int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"pair":"XBTZAR","timestamp":` + strconv.FormatInt(ts, 10) + `}`))
+	}))
+	defer srv.Close()
+
+	var warnings []luno.Warning
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetStaleThreshold(time.Minute)
+	cl.SetHooks(luno.Hooks{
+		Warning: func(ctx context.Context, w luno.Warning) {
+			warnings = append(warnings, w)
+		},
+	})
+
+	get := func() *luno.CallStats {
+		var stats luno.CallStats
+		ctx := luno.WithCallStats(context.Background(), &stats)
+		if _, err := cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"}); err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		return &stats
+	}
+
+	ts = time.Now().Add(-time.Second).UnixNano() / 1e6
+	if stats := get(); len(stats.Warnings) != 0 || len(warnings) != 0 {
+		t.Errorf("Expected no warnings for a fresh ticker, got %v", warnings)
+	}
+
+	ts = time.Now().Add(-time.Hour).UnixNano() / 1e6
+	stats := get()
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %v", warnings)
+	}
+	w := warnings[0]
+	if w.Kind != luno.WarningStale || w.Path != "/api/1/ticker" {
+		t.Errorf("Expected a stale warning for /api/1/ticker, got %+v", w)
+	}
+	if len(stats.Warnings) != 1 || stats.Warnings[0] != w {
+		t.Errorf("Expected the warning in the call stats, got %v", stats.Warnings)
+	}
+
+	ts = 0
+	warnings = nil
+	get()
+	if len(warnings) != 1 || warnings[0].Kind != luno.WarningMissingField {
+		t.Errorf("Expected a missing field warning, got %v", warnings)
+	}
+}
+
+func TestHooksRequestID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-"+r.URL.Path)
+		if r.URL.Path == "/api/1/ticker" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"Not found","error_code":"ErrNotFound"}`))
+	}))
+	defer srv.Close()
+
+	var after []luno.ResponseInfo
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetHooks(luno.Hooks{
+		AfterResponse: func(ctx context.Context, info luno.ResponseInfo) {
+			after = append(after, info)
+		},
+	})
+
+	ctx := context.Background()
+	if _, err := cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	_, err := cl.GetWithdrawal(ctx, &luno.GetWithdrawalRequest{Id: 1})
+	var lErr luno.Error
+	if !errors.As(err, &lErr) {
+		t.Fatalf("Expected luno.Error, got %v", err)
+	}
+	if lErr.RequestID != "req-/api/1/withdrawals/1" {
+		t.Errorf("Expected the request ID on the error, got %q", lErr.RequestID)
+	}
+
+	if len(after) != 2 {
+		t.Fatalf("Expected 2 responses, got %d", len(after))
+	}
+	for i, exp := range []string{"req-/api/1/ticker", "req-/api/1/withdrawals/1"} {
+		if after[i].RequestID != exp {
+			t.Errorf("Expected request ID %q, got %q", exp, after[i].RequestID)
+		}
+	}
+}
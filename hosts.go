@@ -0,0 +1,113 @@
+package luno
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hostFailureThreshold is the number of consecutive failures after which a
+// host is considered down.
+const hostFailureThreshold = 3
+
+// hostCooldown is how long a host which is down is avoided for.
+const hostCooldown = time.Minute
+
+// SetHosts sets the base URLs used for requests in order of preference. The
+// first is the primary and the others are fallbacks. A host which fails
+// three consecutive requests with a network error or a 5xx response is
+// avoided for a minute, during which requests, including retries, go to the
+// next host which is up. If all hosts are down, the one which will recover
+// first is used.
+//
+// SetBaseURL replaces the hosts set by SetHosts.
+func (cl *Client) SetHosts(hosts []string) error {
+	if len(hosts) == 0 {
+		return errors.New("luno: no hosts provided")
+	}
+	trimmed := make([]string, len(hosts))
+	for i, h := range hosts {
+		if h == "" {
+			return errors.New("luno: empty host")
+		}
+		trimmed[i] = strings.TrimRight(h, "/")
+	}
+	cl.baseURL = trimmed[0]
+	cl.hosts = nil
+	if len(trimmed) > 1 {
+		cl.hosts = &hostPool{
+			clock:     cl.clock,
+			hosts:     trimmed,
+			failures:  make([]int, len(trimmed)),
+			downUntil: make([]time.Time, len(trimmed)),
+		}
+	}
+	return nil
+}
+
+// hostPool tracks the health of a set of hosts.
+type hostPool struct {
+	mu        sync.Mutex
+	clock     Clock
+	hosts     []string
+	failures  []int
+	downUntil []time.Time
+}
+
+// pick returns the most preferred host which is up.
+func (p *hostPool) pick() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.clock.Now()
+	best := 0
+	for i := range p.hosts {
+		if !now.Before(p.downUntil[i]) {
+			return p.hosts[i]
+		}
+		if p.downUntil[i].Before(p.downUntil[best]) {
+			best = i
+		}
+	}
+	return p.hosts[best]
+}
+
+// report records the outcome of a request to host.
+func (p *hostPool) report(host string, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, h := range p.hosts {
+		if h != host {
+			continue
+		}
+		if ok {
+			p.failures[i] = 0
+			return
+		}
+		p.failures[i]++
+		if p.failures[i] >= hostFailureThreshold {
+			p.failures[i] = 0
+			p.downUntil[i] = p.clock.Now().Add(hostCooldown)
+		}
+		return
+	}
+}
+
+// setClock switches the pool to clk, keeping the remaining cooldown of hosts
+// which are down.
+func (p *hostPool) setClock(clk Clock) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	prev, now := p.clock.Now(), clk.Now()
+	for i, t := range p.downUntil {
+		if t.After(prev) {
+			p.downUntil[i] = now.Add(t.Sub(prev))
+		} else {
+			p.downUntil[i] = time.Time{}
+		}
+	}
+	p.clock = clk
+}
@@ -0,0 +1,117 @@
+package luno
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSetHostsFailover(t *testing.T) {
+	var primaryDown int32 = 1
+	var primaryHits, fallbackHits int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&primaryHits, 1)
+		if atomic.LoadInt32(&primaryDown) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer primary.Close()
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fallbackHits, 1)
+		w.Write([]byte("{}"))
+	}))
+	defer fallback.Close()
+
+	clk := newFakeClock()
+	cl := NewClient()
+	cl.clock = clk
+	if err := cl.SetHosts([]string{primary.URL, fallback.URL + "/"}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	get := func() error {
+		var res interface{}
+		return cl.do(context.Background(), "GET", "/api/1/tickers", nil, &res, false)
+	}
+	expectHits := func(p, f int32) {
+		t.Helper()
+		if got := atomic.LoadInt32(&primaryHits); got != p {
+			t.Errorf("Expected %d primary requests, got %d", p, got)
+		}
+		if got := atomic.LoadInt32(&fallbackHits); got != f {
+			t.Errorf("Expected %d fallback requests, got %d", f, got)
+		}
+	}
+
+	// The primary fails until it is marked down.
+	for i := 0; i < hostFailureThreshold; i++ {
+		if err := get(); err == nil {
+			t.Errorf("Expected error from primary, got nil")
+		}
+	}
+	expectHits(hostFailureThreshold, 0)
+
+	// Traffic shifts to the fallback during the cool-down, even once the
+	// primary has recovered.
+	atomic.StoreInt32(&primaryDown, 0)
+	for i := 0; i < 2; i++ {
+		if err := get(); err != nil {
+			t.Errorf("Expected success, got %v", err)
+		}
+	}
+	clk.Advance(hostCooldown - time.Second)
+	if err := get(); err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+	expectHits(hostFailureThreshold, 3)
+
+	// After the cool-down traffic returns to the primary.
+	clk.Advance(time.Second)
+	if err := get(); err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+	expectHits(hostFailureThreshold+1, 3)
+}
+
+func TestSetHostsFailoverOnRetry(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer primary.Close()
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"value":"fallback"}`))
+	}))
+	defer fallback.Close()
+
+	cl := NewClient()
+	cl.SetMaxRetries(hostFailureThreshold)
+	cl.SetRetryBackoff(ConstantBackoff(time.Millisecond))
+	if err := cl.SetHosts([]string{primary.URL, fallback.URL}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	var res struct {
+		Value string `json:"value"`
+	}
+	if err := cl.do(context.Background(), "GET", "/", nil, &res, false); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if res.Value != "fallback" {
+		t.Errorf("Expected response from fallback, got %q", res.Value)
+	}
+}
+
+func TestSetHostsInvalid(t *testing.T) {
+	cl := NewClient()
+	if err := cl.SetHosts(nil); err == nil {
+		t.Errorf("Expected error for no hosts, got nil")
+	}
+	if err := cl.SetHosts([]string{"https://api.luno.com", ""}); err == nil {
+		t.Errorf("Expected error for empty host, got nil")
+	}
+}
@@ -0,0 +1,125 @@
+package luno
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// ErrCodeDuplicateClientOrderID is the error code returned when an order is
+// placed with the client order ID of an existing order.
+const ErrCodeDuplicateClientOrderID = "ErrDuplicateClientOrderID"
+
+// maxIdempotentOrderAttempts is the maximum number of times
+// PlaceOrderIdempotent submits an order.
+const maxIdempotentOrderAttempts = 3
+
+// NewClientOrderID returns a random client order ID, in the form of a
+// version 4 UUID.
+func NewClientOrderID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("luno: reading random bytes: " + err.Error())
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// PlaceOrderIdempotent submits an order built by OrderBuilder, like
+// PlaceOrder, but makes sure that the order is placed at most once even if
+// requests fail in flight. It returns the ID of the order and the client
+// order ID it was placed with, which is generated if req doesn't have one.
+//
+// If placing the order fails in flight, with a network error, a timeout or a
+// 5xx, the order may or may not have reached the exchange. The
+// order is then looked up by its client order ID with GetOrderV3 and only
+// submitted again, with the same client order ID, if it wasn't found. At
+// most 3 submissions are made. Errors for which the server rejected the
+// order, or for which it wasn't sent, e.g. ErrMaxOrderNotional, are returned
+// without a lookup.
+//
+// If an order with the client order ID already exists, e.g. because req is
+// submitted again after an earlier call failed, its ID is returned rather
+// than placing another order.
+func (cl *Client) PlaceOrderIdempotent(ctx context.Context, req OrderRequest) (
+	orderID, clientOrderID string, err error) {
+
+	// Copy the request so that the caller's isn't modified.
+	switch {
+	case req.Limit != nil && req.Market == nil:
+		r := *req.Limit
+		if r.ClientOrderId == "" {
+			r.ClientOrderId = NewClientOrderID()
+		}
+		req.Limit, clientOrderID = &r, r.ClientOrderId
+	case req.Market != nil && req.Limit == nil:
+		r := *req.Market
+		if r.ClientOrderId == "" {
+			r.ClientOrderId = NewClientOrderID()
+		}
+		req.Market, clientOrderID = &r, r.ClientOrderId
+	default:
+		return "", "", errors.New("luno: order request must be either limit or market")
+	}
+
+	for attempt := 0; ; attempt++ {
+		orderID, err = cl.PlaceOrder(ctx, req)
+		if err == nil {
+			return orderID, clientOrderID, nil
+		}
+		if IsErrorCode(err, ErrCodeDuplicateClientOrderID) {
+			// An earlier submission reached the exchange after all.
+		} else if !orderOutcomeUnknown(err) || ctx.Err() != nil {
+			return "", clientOrderID, err
+		}
+
+		res, lookupErr := cl.GetOrderV3(ctx, &GetOrderV3Request{ClientOrderId: clientOrderID})
+		if lookupErr == nil {
+			return res.OrderId, clientOrderID, nil
+		}
+		if !errors.Is(lookupErr, ErrOrderNotFound) {
+			return "", clientOrderID, fmt.Errorf(
+				"luno: order %s may have been placed: %w (looking it up: %v)",
+				clientOrderID, err, lookupErr)
+		}
+		if attempt+1 >= maxIdempotentOrderAttempts {
+			return "", clientOrderID, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", clientOrderID, ctx.Err()
+		case <-cl.clock.After(cl.backoff.Next(attempt)):
+		}
+	}
+}
+
+// orderOutcomeUnknown returns whether err, returned for placing an order,
+// leaves it unknown whether the order was placed: network errors and server
+// errors. Errors from checks made before sending the order, e.g.
+// ErrMaxOrderNotional, and rejections by the server are known not to have
+// placed it.
+func orderOutcomeUnknown(err error) bool {
+	var rl *RateLimitError
+	if errors.Is(err, ErrRateLimited) || errors.As(err, &rl) {
+		return false
+	}
+	var e Error
+	if errors.As(err, &e) {
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	var se statusError
+	if errors.As(err, &se) {
+		return se.code >= http.StatusInternalServerError
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
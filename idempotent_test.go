@@ -0,0 +1,179 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func TestNewClientOrderID(t *testing.T) {
+	re := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	a, b := luno.NewClientOrderID(), luno.NewClientOrderID()
+	if !re.MatchString(a) {
+		t.Errorf("Expected a UUID, got %q", a)
+	}
+	if a == b {
+		t.Errorf("Expected random IDs, got %q twice", a)
+	}
+}
+
+func TestPlaceOrderIdempotent(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		// posts are the responses to placing the order in turn, and lookups
+		// the responses to looking it up.
+		posts   []int
+		lookups []int
+
+		expID    string
+		expErr   error
+		expPosts int
+	}{
+		{
+			name:     "placed",
+			posts:    []int{http.StatusOK},
+			expID:    "BXPOST",
+			expPosts: 1,
+		},
+		{
+			name:     "response lost after placing",
+			posts:    []int{http.StatusBadGateway},
+			lookups:  []int{http.StatusOK},
+			expID:    "BXLOOKUP",
+			expPosts: 1,
+		},
+		{
+			name:     "lost before placing",
+			posts:    []int{http.StatusBadGateway, http.StatusOK},
+			lookups:  []int{http.StatusNotFound},
+			expID:    "BXPOST",
+			expPosts: 2,
+		},
+		{
+			name:     "duplicate",
+			posts:    []int{http.StatusConflict},
+			lookups:  []int{http.StatusOK},
+			expID:    "BXLOOKUP",
+			expPosts: 1,
+		},
+		{
+			name:     "rejected",
+			posts:    []int{http.StatusBadRequest},
+			expErr:   luno.ErrInsufficientBalance,
+			expPosts: 1,
+		},
+		{
+			name:     "never placed",
+			posts:    []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			lookups:  []int{http.StatusNotFound, http.StatusNotFound, http.StatusNotFound},
+			expPosts: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var posts, lookups int
+			var clientIDs []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				var status int
+				switch r.URL.Path {
+				case "/api/1/postorder":
+					clientIDs = append(clientIDs, r.Form.Get("client_order_id"))
+					status = tc.posts[posts]
+					posts++
+				case "/api/exchange/3/order":
+					if id := r.Form.Get("client_order_id"); id != clientIDs[0] {
+						t.Errorf("Expected lookup by %q, got %q", clientIDs[0], id)
+					}
+					status = tc.lookups[lookups]
+					lookups++
+				}
+				w.WriteHeader(status)
+				switch status {
+				case http.StatusOK:
+					if r.URL.Path == "/api/1/postorder" {
+						w.Write([]byte(`{"order_id":"BXPOST"}`))
+					} else {
+						w.Write([]byte(`{"order_id":"BXLOOKUP"}`))
+					}
+				case http.StatusBadGateway:
+					w.Write([]byte(`<html>Bad Gateway</html>`))
+				case http.StatusNotFound:
+					w.Write([]byte(`{"error":"Not found","error_code":"ErrOrderNotFound"}`))
+				case http.StatusConflict:
+					w.Write([]byte(`{"error":"Duplicate","error_code":"ErrDuplicateClientOrderID"}`))
+				case http.StatusBadRequest:
+					w.Write([]byte(`{"error":"No funds","error_code":"ErrInsufficientBalance"}`))
+				}
+			}))
+			defer srv.Close()
+
+			cl := luno.NewClient(luno.WithBaseURL(srv.URL))
+			cl.SetRetryBackoff(luno.ConstantBackoff(time.Millisecond))
+
+			req := luno.OrderRequest{Limit: &luno.PostLimitOrderRequest{Pair: "XBTZAR"}}
+			id, clientID, err := cl.PlaceOrderIdempotent(context.Background(), req)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Errorf("Expected %v, got %v", tc.expErr, err)
+				}
+			} else if tc.expID == "" {
+				if err == nil {
+					t.Errorf("Expected error")
+				}
+			} else if err != nil || id != tc.expID {
+				t.Errorf("Expected order %s, got %q, %v", tc.expID, id, err)
+			}
+
+			if posts != tc.expPosts {
+				t.Errorf("Expected %d submissions, got %d", tc.expPosts, posts)
+			}
+			for _, c := range clientIDs {
+				if c != clientID || c == "" {
+					t.Errorf("Expected every submission with client order ID %q, got %q",
+						clientID, c)
+				}
+			}
+			if req.Limit.ClientOrderId != "" {
+				t.Errorf("Expected request not to be modified")
+			}
+		})
+	}
+}
+
+func TestPlaceOrderIdempotentNotSent(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		w.Write([]byte(`{"markets":[
+			{"market_id":"XBTZAR","base_currency":"XBT","counter_currency":"ZAR"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL))
+	cl.SetMaxOrderNotional("ZAR", decimal.NewFromInt64(100))
+
+	req := luno.OrderRequest{Limit: &luno.PostLimitOrderRequest{
+		Pair:   "XBTZAR",
+		Type:   luno.OrderTypeBid,
+		Price:  decimal.NewFromInt64(1000),
+		Volume: decimal.NewFromInt64(1),
+	}}
+	_, _, err := cl.PlaceOrderIdempotent(context.Background(), req)
+	if !errors.Is(err, luno.ErrMaxOrderNotional) {
+		t.Errorf("Expected ErrMaxOrderNotional, got %v", err)
+	}
+	if len(requests) != 1 || requests[0] != "/api/exchange/1/markets" {
+		t.Errorf("Expected no order to be sent or looked up, got %v", requests)
+	}
+}
@@ -0,0 +1,139 @@
+package luno
+
+import "context"
+
+// API is the set of Luno API calls made by Client, one method per endpoint.
+// Code which depends on API rather than *Client can be tested with a fake
+// implementation, such as the one in the lunotest package.
+type API interface {
+	// CancelWithdrawal makes a call to DELETE /api/1/withdrawals/{id}.
+	CancelWithdrawal(ctx context.Context, req *CancelWithdrawalRequest) (*CancelWithdrawalResponse, error)
+
+	// CreateAccount makes a call to POST /api/1/accounts.
+	CreateAccount(ctx context.Context, req *CreateAccountRequest) (*CreateAccountResponse, error)
+
+	// CreateBeneficiary makes a call to POST /api/1/beneficiaries.
+	CreateBeneficiary(ctx context.Context, req *CreateBeneficiaryRequest) (*CreateBeneficiaryResponse, error)
+
+	// CreateFundingAddress makes a call to POST /api/1/funding_address.
+	CreateFundingAddress(ctx context.Context, req *CreateFundingAddressRequest) (*CreateFundingAddressResponse, error)
+
+	// CreateQuote makes a call to POST /api/1/quotes.
+	CreateQuote(ctx context.Context, req *CreateQuoteRequest) (*CreateQuoteResponse, error)
+
+	// CreateWithdrawal makes a call to POST /api/1/withdrawals.
+	CreateWithdrawal(ctx context.Context, req *CreateWithdrawalRequest) (*CreateWithdrawalResponse, error)
+
+	// DeleteBeneficiary makes a call to DELETE /api/1/beneficiaries/{id}.
+	DeleteBeneficiary(ctx context.Context, req *DeleteBeneficiaryRequest) (*DeleteBeneficiaryResponse, error)
+
+	// DiscardQuote makes a call to DELETE /api/1/quotes/{id}.
+	DiscardQuote(ctx context.Context, req *DiscardQuoteRequest) (*DiscardQuoteResponse, error)
+
+	// ExerciseQuote makes a call to PUT /api/1/quotes/{id}.
+	ExerciseQuote(ctx context.Context, req *ExerciseQuoteRequest) (*ExerciseQuoteResponse, error)
+
+	// GetBalances makes a call to GET /api/1/balance.
+	GetBalances(ctx context.Context, req *GetBalancesRequest) (*GetBalancesResponse, error)
+
+	// GetCandles makes a call to GET /api/exchange/1/candles.
+	GetCandles(ctx context.Context, req *GetCandlesRequest) (*GetCandlesResponse, error)
+
+	// GetFeeInfo makes a call to GET /api/1/fee_info.
+	GetFeeInfo(ctx context.Context, req *GetFeeInfoRequest) (*GetFeeInfoResponse, error)
+
+	// GetFundingAddress makes a call to GET /api/1/funding_address.
+	GetFundingAddress(ctx context.Context, req *GetFundingAddressRequest) (*GetFundingAddressResponse, error)
+
+	// GetMove makes a call to GET /api/exchange/1/move.
+	GetMove(ctx context.Context, req *GetMoveRequest) (*GetMoveResponse, error)
+
+	// GetOrder makes a call to GET /api/1/orders/{id}.
+	GetOrder(ctx context.Context, req *GetOrderRequest) (*GetOrderResponse, error)
+
+	// GetOrderBook makes a call to GET /api/1/orderbook_top.
+	GetOrderBook(ctx context.Context, req *GetOrderBookRequest) (*GetOrderBookResponse, error)
+
+	// GetOrderBookFull makes a call to GET /api/1/orderbook.
+	GetOrderBookFull(ctx context.Context, req *GetOrderBookFullRequest) (*GetOrderBookFullResponse, error)
+
+	// GetOrderV2 makes a call to GET /api/exchange/2/orders/{id}.
+	GetOrderV2(ctx context.Context, req *GetOrderV2Request) (*GetOrderV2Response, error)
+
+	// GetOrderV3 makes a call to GET /api/exchange/3/order.
+	GetOrderV3(ctx context.Context, req *GetOrderV3Request) (*GetOrderV3Response, error)
+
+	// GetQuote makes a call to GET /api/1/quotes/{id}.
+	GetQuote(ctx context.Context, req *GetQuoteRequest) (*GetQuoteResponse, error)
+
+	// GetServerTime makes a call to GET /api/1/time.
+	GetServerTime(ctx context.Context, req *GetServerTimeRequest) (*GetServerTimeResponse, error)
+
+	// GetTicker makes a call to GET /api/1/ticker.
+	GetTicker(ctx context.Context, req *GetTickerRequest) (*GetTickerResponse, error)
+
+	// GetTickers makes a call to GET /api/1/tickers.
+	GetTickers(ctx context.Context, req *GetTickersRequest) (*GetTickersResponse, error)
+
+	// GetWithdrawal makes a call to GET /api/1/withdrawals/{id}.
+	GetWithdrawal(ctx context.Context, req *GetWithdrawalRequest) (*GetWithdrawalResponse, error)
+
+	// ListBeneficiaries makes a call to GET /api/1/beneficiaries.
+	ListBeneficiaries(ctx context.Context, req *ListBeneficiariesRequest) (*ListBeneficiariesResponse, error)
+
+	// ListBeneficiariesResponse makes a call to GET /api/1/beneficiaries.
+	ListBeneficiariesResponse(ctx context.Context, req *ListBeneficiariesResponseRequest) (*ListBeneficiariesResponseResponse, error)
+
+	// ListMoves makes a call to GET /api/exchange/1/move/list_moves.
+	ListMoves(ctx context.Context, req *ListMovesRequest) (*ListMovesResponse, error)
+
+	// ListOrders makes a call to GET /api/1/listorders.
+	ListOrders(ctx context.Context, req *ListOrdersRequest) (*ListOrdersResponse, error)
+
+	// ListOrdersV2 makes a call to GET /api/exchange/2/listorders.
+	ListOrdersV2(ctx context.Context, req *ListOrdersV2Request) (*ListOrdersV2Response, error)
+
+	// ListPendingTransactions makes a call to GET /api/1/accounts/{id}/pending.
+	ListPendingTransactions(ctx context.Context, req *ListPendingTransactionsRequest) (*ListPendingTransactionsResponse, error)
+
+	// ListTrades makes a call to GET /api/1/trades.
+	ListTrades(ctx context.Context, req *ListTradesRequest) (*ListTradesResponse, error)
+
+	// ListTransactions makes a call to GET /api/1/accounts/{id}/transactions.
+	ListTransactions(ctx context.Context, req *ListTransactionsRequest) (*ListTransactionsResponse, error)
+
+	// ListUserTrades makes a call to GET /api/1/listtrades.
+	ListUserTrades(ctx context.Context, req *ListUserTradesRequest) (*ListUserTradesResponse, error)
+
+	// ListWithdrawals makes a call to GET /api/1/withdrawals.
+	ListWithdrawals(ctx context.Context, req *ListWithdrawalsRequest) (*ListWithdrawalsResponse, error)
+
+	// Markets makes a call to GET /api/exchange/1/markets.
+	Markets(ctx context.Context, req *MarketsRequest) (*MarketsResponse, error)
+
+	// Move makes a call to POST /api/exchange/1/move.
+	Move(ctx context.Context, req *MoveRequest) (*MoveResponse, error)
+
+	// PostLimitOrder makes a call to POST /api/1/postorder.
+	PostLimitOrder(ctx context.Context, req *PostLimitOrderRequest) (*PostLimitOrderResponse, error)
+
+	// PostMarketOrder makes a call to POST /api/1/marketorder.
+	PostMarketOrder(ctx context.Context, req *PostMarketOrderRequest) (*PostMarketOrderResponse, error)
+
+	// Send makes a call to POST /api/1/send.
+	Send(ctx context.Context, req *SendRequest) (*SendResponse, error)
+
+	// SendFee makes a call to GET /api/1/send_fee.
+	SendFee(ctx context.Context, req *SendFeeRequest) (*SendFeeResponse, error)
+
+	// StopOrder makes a call to POST /api/1/stoporder.
+	StopOrder(ctx context.Context, req *StopOrderRequest) (*StopOrderResponse, error)
+
+	// UpdateAccountName makes a call to PUT /api/1/accounts/{id}/name.
+	UpdateAccountName(ctx context.Context, req *UpdateAccountNameRequest) (*UpdateAccountNameResponse, error)
+
+	// Validate makes a call to POST /api/1/address/validate.
+	Validate(ctx context.Context, req *ValidateRequest) (*ValidateResponse, error)
+}
+
+var _ API = (*Client)(nil)
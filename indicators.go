@@ -0,0 +1,114 @@
+package luno
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// indicatorScale is the number of decimal places of indicator values.
+// Intermediate results are truncated to this scale.
+const indicatorScale = 8
+
+// ErrInsufficientData is returned by indicators when there are too few
+// candles for the requested period.
+var ErrInsufficientData = errors.New("luno: insufficient data for indicator")
+
+// SMA returns the simple moving average of the closing prices of candles over
+// period candles. The result is aligned to candles: the value at index i
+// covers the period ending at candles[i], and the first period-1 values are
+// nil.
+func SMA(candles []Candle, period int) ([]*decimal.Decimal, error) {
+	if err := checkIndicatorInput(candles, period, period); err != nil {
+		return nil, err
+	}
+	res := make([]*decimal.Decimal, len(candles))
+	sum := decimal.Zero()
+	for i, c := range candles {
+		sum = sum.Add(c.Close)
+		if i >= period {
+			sum = sum.Sub(candles[i-period].Close)
+		}
+		if i >= period-1 {
+			v := sum.Div(decimal.NewFromInt64(int64(period)), indicatorScale)
+			res[i] = &v
+		}
+	}
+	return res, nil
+}
+
+// EMA returns the exponential moving average of the closing prices of candles
+// with a smoothing factor of 2/(period+1). It is seeded with the simple moving
+// average of the first period candles, so the first period-1 values are nil.
+func EMA(candles []Candle, period int) ([]*decimal.Decimal, error) {
+	if err := checkIndicatorInput(candles, period, period); err != nil {
+		return nil, err
+	}
+	sma, err := SMA(candles[:period], period)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*decimal.Decimal, len(candles))
+	res[period-1] = sma[period-1]
+	prev := *sma[period-1]
+	for i := period; i < len(candles); i++ {
+		v := prev.Add(candles[i].Close.Sub(prev).MulInt64(2).Div(
+			decimal.NewFromInt64(int64(period+1)), indicatorScale))
+		res[i] = &v
+		prev = v
+	}
+	return res, nil
+}
+
+// RSI returns the relative strength index of the closing prices of candles
+// using Wilder's smoothing over period price changes. Values range from 0 to
+// 100. A period without any price change has an RSI of 50. The first period
+// values are nil since period+1 candles are needed for period changes.
+func RSI(candles []Candle, period int) ([]*decimal.Decimal, error) {
+	if err := checkIndicatorInput(candles, period, period+1); err != nil {
+		return nil, err
+	}
+	res := make([]*decimal.Decimal, len(candles))
+	n := decimal.NewFromInt64(int64(period))
+	gain, loss := decimal.Zero(), decimal.Zero()
+	for i := 1; i < len(candles); i++ {
+		change := candles[i].Close.Sub(candles[i-1].Close)
+		g, l := decimal.Zero(), decimal.Zero()
+		if change.Sign() > 0 {
+			g = change
+		} else {
+			l = change.Neg()
+		}
+
+		if i <= period {
+			gain, loss = gain.Add(g), loss.Add(l)
+			if i < period {
+				continue
+			}
+			gain = gain.Div(n, indicatorScale)
+			loss = loss.Div(n, indicatorScale)
+		} else {
+			gain = gain.MulInt64(int64(period-1)).Add(g).Div(n, indicatorScale)
+			loss = loss.MulInt64(int64(period-1)).Add(l).Div(n, indicatorScale)
+		}
+
+		v := decimal.NewFromInt64(50)
+		if total := gain.Add(loss); total.Sign() != 0 {
+			v = gain.MulInt64(100).Div(total, indicatorScale)
+		}
+		res[i] = &v
+	}
+	return res, nil
+}
+
+func checkIndicatorInput(candles []Candle, period, required int) error {
+	if period <= 0 {
+		return fmt.Errorf("luno: invalid indicator period %d", period)
+	}
+	if len(candles) < required {
+		return fmt.Errorf("%w: %d candles given, %d required",
+			ErrInsufficientData, len(candles), required)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package luno_test
+
+import (
+	"errors"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func makeCandles(closes ...int64) []luno.Candle {
+	var candles []luno.Candle
+	for _, c := range closes {
+		candles = append(candles, luno.Candle{Close: decimal.NewFromInt64(c)})
+	}
+	return candles
+}
+
+func TestIndicators(t *testing.T) {
+	candles := makeCandles(44, 47, 45, 48, 50, 49, 52, 51, 53, 50)
+	testCases := []struct {
+		name string
+		fn   func([]luno.Candle, int) ([]*decimal.Decimal, error)
+		exp  []string
+	}{
+		{
+			name: "SMA",
+			fn:   luno.SMA,
+			exp: []string{"", "", "45.333333", "46.666667", "47.666667", "49",
+				"50.333333", "50.666667", "52", "51.333333"},
+		},
+		{
+			name: "EMA",
+			fn:   luno.EMA,
+			exp: []string{"", "", "45.333333", "46.666667", "48.333333", "48.666667",
+				"50.333333", "50.666667", "51.833333", "50.916667"},
+		},
+		{
+			name: "RSI",
+			fn:   luno.RSI,
+			exp: []string{"", "", "", "75", "81.818182", "67.924528",
+				"81.818182", "67.252747", "78.653295", "44.105242"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.fn(candles, 3)
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if len(res) != len(tc.exp) {
+				t.Fatalf("Expected %d values, got %d", len(tc.exp), len(res))
+			}
+			for i, exp := range tc.exp {
+				if exp == "" {
+					if res[i] != nil {
+						t.Errorf("Expected nil at %d, got %s", i, res[i])
+					}
+					continue
+				}
+				if res[i] == nil {
+					t.Errorf("Expected %s at %d, got nil", exp, i)
+					continue
+				}
+				if !closeTo(t, *res[i], exp) {
+					t.Errorf("Expected %s at %d, got %s", exp, i, res[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIndicatorsInsufficientData(t *testing.T) {
+	candles := makeCandles(1, 2, 3)
+	testCases := []struct {
+		name   string
+		fn     func([]luno.Candle, int) ([]*decimal.Decimal, error)
+		period int
+	}{
+		{name: "SMA", fn: luno.SMA, period: 4},
+		{name: "EMA", fn: luno.EMA, period: 4},
+		{name: "RSI", fn: luno.RSI, period: 3},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.fn(candles, tc.period)
+			if !errors.Is(err, luno.ErrInsufficientData) {
+				t.Errorf("Expected ErrInsufficientData, got %v", err)
+			}
+			if _, err := tc.fn(candles, 0); err == nil {
+				t.Errorf("Expected error for zero period, got nil")
+			}
+		})
+	}
+}
+
+// closeTo returns whether d is within 0.000001 of the decimal string exp.
+func closeTo(t *testing.T, d decimal.Decimal, exp string) bool {
+	e, err := decimal.NewFromString(exp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := d.Sub(e)
+	if diff.Sign() < 0 {
+		diff = diff.Neg()
+	}
+	return diff.Cmp(decimal.NewFromFloat64(0.000001, 6)) <= 0
+}
@@ -0,0 +1,99 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+var errNoResponse = errors.New("luno: interceptor returned no response")
+
+// Request is an attempt of an API request as seen by interceptors.
+type Request struct {
+	Info RequestInfo
+
+	// HTTP is the request about to be sent. It has already been signed, so
+	// interceptors may add headers but shouldn't change the body or URL of
+	// authenticated requests.
+	HTTP *http.Request
+}
+
+// Response is the response to an attempt of an API request as seen by
+// interceptors.
+type Response struct {
+	// HTTP is the response received. Its body is decoded by the client once
+	// the interceptors have returned, so they mustn't consume it.
+	HTTP *http.Response
+}
+
+// Invoker sends an attempt of an API request, using the remaining
+// interceptors of the chain.
+type Invoker func(ctx context.Context, req *Request) (*Response, error)
+
+// Interceptor wraps every attempt of an API request. It calls next to send
+// the request, and may modify the request beforehand, inspect the response
+// or error afterwards, or return without calling next to fail or answer the
+// attempt itself, in which case the response must have a body. Errors
+// returned are handled as transport errors, so they may be retried.
+//
+// Example:
+//
+//	cl.AddInterceptor(func(ctx context.Context, req *luno.Request,
+//		next luno.Invoker) (*luno.Response, error) {
+//
+//		req.HTTP.Header.Set("Traceparent", traceparent(ctx))
+//		return next(ctx, req)
+//	})
+type Interceptor func(ctx context.Context, req *Request, next Invoker) (*Response, error)
+
+// AddInterceptor appends i to the client's interceptor chain. Interceptors
+// are called in the order they were added, so the first one added sees the
+// request first and the response last. Interceptors run after the request
+// has been rate limited and signed, and before Hooks.AfterResponse is
+// called.
+func (cl *Client) AddInterceptor(i Interceptor) {
+	cl.interceptors = append(cl.interceptors, i)
+}
+
+// WithInterceptor returns an option which adds i to the client's
+// interceptor chain, as for AddInterceptor.
+func WithInterceptor(i Interceptor) Option {
+	return func(cl *Client) {
+		cl.AddInterceptor(i)
+	}
+}
+
+// send sends httpReq through the interceptor chain.
+func (cl *Client) send(ctx context.Context, info RequestInfo,
+	httpReq *http.Request) (*http.Response, error) {
+
+	if len(cl.interceptors) == 0 {
+		return cl.httpClient.Do(httpReq)
+	}
+
+	invoke := func(ctx context.Context, req *Request) (*Response, error) {
+		httpRes, err := cl.httpClient.Do(req.HTTP.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		return &Response{HTTP: httpRes}, nil
+	}
+	for i := len(cl.interceptors) - 1; i >= 0; i-- {
+		ic, next := cl.interceptors[i], invoke
+		invoke = func(ctx context.Context, req *Request) (*Response, error) {
+			return ic(ctx, req, next)
+		}
+	}
+
+	res, err := invoke(ctx, &Request{Info: info, HTTP: httpReq})
+	if err != nil {
+		if res != nil && res.HTTP != nil && res.HTTP.Body != nil {
+			res.HTTP.Body.Close()
+		}
+		return nil, err
+	}
+	if res == nil || res.HTTP == nil || res.HTTP.Body == nil {
+		return nil, errNoResponse
+	}
+	return res.HTTP, nil
+}
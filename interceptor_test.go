@@ -0,0 +1,93 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestInterceptorOrder(t *testing.T) {
+	var header string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Trace")
+		w.Write([]byte(`{"pair":"XBTZAR"}`))
+	}))
+	defer srv.Close()
+
+	var calls []string
+	trace := func(name string) luno.Interceptor {
+		return func(ctx context.Context, req *luno.Request,
+			next luno.Invoker) (*luno.Response, error) {
+
+			calls = append(calls, name+" "+req.Info.Path)
+			req.HTTP.Header.Add("X-Trace", name)
+			res, err := next(ctx, req)
+			if err == nil {
+				calls = append(calls, name+" "+res.HTTP.Status)
+			}
+			return res, err
+		}
+	}
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL), luno.WithInterceptor(trace("a")))
+	cl.AddInterceptor(trace("b"))
+
+	res, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if res.Pair != "XBTZAR" {
+		t.Errorf("Expected response to be decoded, got %+v", res)
+	}
+	exp := []string{"a /api/1/ticker", "b /api/1/ticker", "b 200 OK", "a 200 OK"}
+	if strings.Join(calls, ", ") != strings.Join(exp, ", ") {
+		t.Errorf("Expected calls %v, got %v", exp, calls)
+	}
+	if header != "a" {
+		t.Errorf("Expected header from first interceptor, got %q", header)
+	}
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected request not to be sent")
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL))
+	cl.AddInterceptor(func(ctx context.Context, req *luno.Request,
+		next luno.Invoker) (*luno.Response, error) {
+
+		return &luno.Response{HTTP: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       ioutil.NopCloser(strings.NewReader(`{"pair":"ETHZAR"}`)),
+		}}, nil
+	})
+
+	res, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "ETHZAR"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if res.Pair != "ETHZAR" {
+		t.Errorf("Expected canned response, got %+v", res)
+	}
+
+	errDenied := errors.New("denied")
+	cl = luno.NewClient(luno.WithBaseURL(srv.URL), luno.WithInterceptor(
+		func(ctx context.Context, req *luno.Request,
+			next luno.Invoker) (*luno.Response, error) {
+
+			return nil, errDenied
+		}))
+	ctx := luno.WithSingleAttempt(context.Background())
+	_, err = cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "ETHZAR"})
+	if !errors.Is(err, errDenied) {
+		t.Errorf("Expected interceptor error, got %v", err)
+	}
+}
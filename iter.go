@@ -0,0 +1,299 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultListTradesLimit is the number of trades ListUserTrades returns if no
+// limit is specified.
+const defaultListTradesLimit = 100
+
+// Iterator pages through the results of a list endpoint. Each iterator holds
+// its own state, so several iterators, e.g. over trades and transactions, can
+// be used concurrently from separate goroutines with the same Client. A
+// single iterator must not be used concurrently.
+//
+// Example:
+//
+//	it := cl.IterUserTrades(&luno.ListUserTradesRequest{Pair: "XBTZAR"})
+//	for it.Next(ctx) {
+//		fmt.Println(it.Trade().Price)
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type Iterator interface {
+	// Next advances to the next value, fetching another page if needed. It
+	// returns false once all values have been returned or an error occurred.
+	Next(ctx context.Context) bool
+
+	// Value returns the current value.
+	Value() interface{}
+
+	// Err returns the error which stopped the iterator, if any.
+	Err() error
+}
+
+// TradeIterator iterates over the user's trades on a pair, oldest first.
+type TradeIterator struct {
+	cl   *Client
+	req  ListUserTradesRequest
+	page []Trade
+	cur  Trade
+	done bool
+	err  error
+}
+
+// IterUserTrades returns an iterator over the trades matching req, oldest
+// first, using ListUserTrades. req.Limit sets the page size, up to 1000, and
+// req.SortDesc and req.BeforeSeq are ignored.
+func (cl *Client) IterUserTrades(req *ListUserTradesRequest) *TradeIterator {
+	r := *req
+	r.Limit = clampLimit(r.Limit, defaultListTradesLimit, tradesPageSize)
+	r.SortDesc = false
+	r.BeforeSeq = 0
+	return &TradeIterator{cl: cl, req: r}
+}
+
+func (it *TradeIterator) Next(ctx context.Context) bool {
+	if len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		res, err := it.cl.ListUserTrades(ctx, &it.req)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = res.Trades
+		it.done = !hasMoreTrades(res, it.req.Limit)
+		if len(it.page) == 0 {
+			return false
+		}
+		it.req.AfterSeq = it.page[len(it.page)-1].Sequence + 1
+	}
+	it.cur, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Trade returns the current trade.
+func (it *TradeIterator) Trade() Trade {
+	return it.cur
+}
+
+func (it *TradeIterator) Value() interface{} {
+	return it.cur
+}
+
+func (it *TradeIterator) Err() error {
+	return it.err
+}
+
+// TransactionIterator iterates over the transactions of an account, oldest
+// first.
+type TransactionIterator struct {
+	cl     *Client
+	id     int64
+	minRow int64
+	page   []Transaction
+	cur    Transaction
+	done   bool
+	err    error
+}
+
+// IterTransactions returns an iterator over all the transactions of an
+// account, oldest first, using ListTransactions.
+func (cl *Client) IterTransactions(accountID string) *TransactionIterator {
+	it := &TransactionIterator{cl: cl, minRow: 1}
+	id, err := strconv.ParseInt(accountID, 10, 64)
+	if err != nil {
+		it.err = fmt.Errorf("luno: invalid account id %q: %w", accountID, err)
+	}
+	it.id = id
+	return it
+}
+
+func (it *TransactionIterator) Next(ctx context.Context) bool {
+	if len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		req := &ListTransactionsRequest{
+			Id:     it.id,
+			MinRow: it.minRow,
+			MaxRow: it.minRow + ledgerPageSize,
+		}
+		res, err := it.cl.ListTransactions(ctx, req)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		txs := res.Transactions
+		sort.Slice(txs, func(i, j int) bool {
+			return txs[i].RowIndex < txs[j].RowIndex
+		})
+		it.page = txs
+		it.minRow = req.MaxRow
+		it.done = !hasMoreTransactions(res, req.MinRow, req.MaxRow)
+		if len(txs) == 0 {
+			return false
+		}
+	}
+	it.cur, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Transaction returns the current transaction.
+func (it *TransactionIterator) Transaction() Transaction {
+	return it.cur
+}
+
+func (it *TransactionIterator) Value() interface{} {
+	return it.cur
+}
+
+func (it *TransactionIterator) Err() error {
+	return it.err
+}
+
+// OrderIterator iterates over the user's orders, newest first.
+type OrderIterator struct {
+	cl   *Client
+	req  ListOrdersV2Request
+	page []OrderV2
+	cur  OrderV2
+	seen map[string]bool
+	done bool
+	err  error
+}
+
+// IterOrdersV2 returns an iterator over the orders matching req, newest
+// first, using ListOrdersV2. req.Limit sets the page size, up to 1000.
+//
+// Luno pages orders by creation timestamp rather than by an opaque cursor.
+// Since many orders may share a millisecond, each page after the first starts
+// at the millisecond of the oldest order seen so far and orders which have
+// already been returned are skipped.
+func (cl *Client) IterOrdersV2(req *ListOrdersV2Request) *OrderIterator {
+	r := *req
+	r.Limit = clampLimit(r.Limit, defaultListOrdersLimit, maxListOrdersLimit)
+	return &OrderIterator{cl: cl, req: r, seen: make(map[string]bool)}
+}
+
+func (it *OrderIterator) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		res, err := it.cl.ListOrdersV2(ctx, &it.req)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		var oldest time.Time
+		for _, o := range res.Orders {
+			ts := time.Time(o.CreationTimestamp)
+			if oldest.IsZero() || ts.Before(oldest) {
+				oldest = ts
+			}
+			if it.seen[o.OrderId] {
+				continue
+			}
+			it.seen[o.OrderId] = true
+			it.page = append(it.page, o)
+		}
+
+		it.done = !hasMoreOrders(res, it.req.Limit)
+		if !it.done && len(it.page) == 0 {
+			it.err = errors.New("luno: more orders share a timestamp " +
+				"than can be returned in a single page")
+			return false
+		}
+		it.req.CreatedBefore = oldest.UnixNano()/1e6 + 1
+	}
+	it.cur, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Order returns the current order.
+func (it *OrderIterator) Order() OrderV2 {
+	return it.cur
+}
+
+func (it *OrderIterator) Value() interface{} {
+	return it.cur
+}
+
+func (it *OrderIterator) Err() error {
+	return it.err
+}
+
+// FanValue is a value sent by Fan.
+type FanValue struct {
+	// Source is the index of the iterator which returned the value.
+	Source int
+	Value  interface{}
+}
+
+// Fan runs each iterator in its own goroutine and merges their values into
+// the returned channel. The values of each iterator are sent in order, but
+// values from different iterators are interleaved.
+//
+// The channel is closed once every iterator has finished. If an iterator
+// fails, or ctx is cancelled, the others are stopped. The returned function
+// waits for the channel to be closed and returns the first error. Callers
+// which stop reading early must cancel ctx so that the goroutines exit.
+func Fan(ctx context.Context, its ...Iterator) (<-chan FanValue, func() error) {
+	ctx, cancel := context.WithCancel(ctx)
+	ch := make(chan FanValue)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for i, it := range its {
+		wg.Add(1)
+		go func(i int, it Iterator) {
+			defer wg.Done()
+			for it.Next(ctx) {
+				select {
+				case ch <- FanValue{Source: i, Value: it.Value()}:
+				case <-ctx.Done():
+					fail(ctx.Err())
+					return
+				}
+			}
+			if err := it.Err(); err != nil {
+				fail(err)
+			}
+		}(i, it)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		cancel()
+		close(ch)
+		close(done)
+	}()
+
+	return ch, func() error {
+		<-done
+		return firstErr
+	}
+}
@@ -0,0 +1,225 @@
+package luno_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+// newListServer serves numTrades trades and numTxs transactions, paged like
+// the API.
+func newListServer(numTrades, numTxs int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/1/listtrades":
+			after, _ := strconv.ParseInt(q.Get("after_seq"), 10, 64)
+			limit, _ := strconv.ParseInt(q.Get("limit"), 10, 64)
+			if limit > 1000 {
+				// The API caps the page size.
+				limit = 1000
+			}
+			trades := []map[string]interface{}{}
+			for seq := after; seq <= numTrades && int64(len(trades)) < limit; seq++ {
+				if seq == 0 {
+					continue
+				}
+				trades = append(trades, map[string]interface{}{"sequence": seq})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"trades": trades})
+		case "/api/1/accounts/1/transactions":
+			min, _ := strconv.ParseInt(q.Get("min_row"), 10, 64)
+			max, _ := strconv.ParseInt(q.Get("max_row"), 10, 64)
+			txs := []map[string]interface{}{}
+			// Rows are returned newest first.
+			for row := max - 1; row >= min; row-- {
+				if row <= numTxs {
+					txs = append(txs, map[string]interface{}{"row_index": row})
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"transactions": txs})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Not found","error_code":"ErrNotFound"}`))
+		}
+	}))
+}
+
+func TestIteratorsConcurrent(t *testing.T) {
+	srv := newListServer(250, 2500)
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	ctx := context.Background()
+
+	var (
+		wg     sync.WaitGroup
+		trades []int64
+		txs    []int64
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		it := cl.IterUserTrades(&luno.ListUserTradesRequest{Pair: "XBTZAR"})
+		for it.Next(ctx) {
+			trades = append(trades, it.Trade().Sequence)
+		}
+		if err := it.Err(); err != nil {
+			t.Errorf("Expected success, got %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		it := cl.IterTransactions("1")
+		for it.Next(ctx) {
+			txs = append(txs, it.Transaction().RowIndex)
+		}
+		if err := it.Err(); err != nil {
+			t.Errorf("Expected success, got %v", err)
+		}
+	}()
+	wg.Wait()
+
+	checkSequential := func(name string, vals []int64, n int64) {
+		if int64(len(vals)) != n {
+			t.Errorf("Expected %d %s, got %d", n, name, len(vals))
+			return
+		}
+		for i, v := range vals {
+			if v != int64(i)+1 {
+				t.Errorf("Expected %s %d to be %d, got %d", name, i, i+1, v)
+				return
+			}
+		}
+	}
+	checkSequential("trades", trades, 250)
+	checkSequential("transactions", txs, 2500)
+}
+
+func TestFan(t *testing.T) {
+	srv := newListServer(250, 2500)
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	ch, wait := luno.Fan(context.Background(),
+		cl.IterUserTrades(&luno.ListUserTradesRequest{Pair: "XBTZAR"}),
+		cl.IterTransactions("1"),
+	)
+	counts := make(map[int]int)
+	last := make(map[int]int64)
+	for v := range ch {
+		counts[v.Source]++
+		var n int64
+		switch val := v.Value.(type) {
+		case luno.Trade:
+			n = val.Sequence
+		case luno.Transaction:
+			n = val.RowIndex
+		}
+		if n != last[v.Source]+1 {
+			t.Fatalf("Expected source %d values in order, got %d after %d",
+				v.Source, n, last[v.Source])
+		}
+		last[v.Source] = n
+	}
+	if err := wait(); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if counts[0] != 250 || counts[1] != 2500 {
+		t.Errorf("Expected 250 trades and 2500 transactions, got %v", counts)
+	}
+}
+
+func TestFanError(t *testing.T) {
+	srv := newListServer(250, 1000000)
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	// The missing account fails, which stops the transactions.
+	ch, wait := luno.Fan(context.Background(),
+		cl.IterTransactions("1"),
+		cl.IterTransactions("2"),
+	)
+	for range ch {
+	}
+	if err := wait(); !luno.IsErrorCode(err, "ErrNotFound") {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFanCancel(t *testing.T) {
+	srv := newListServer(250, 1000000)
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, wait := luno.Fan(ctx, cl.IterTransactions("1"))
+	<-ch
+	cancel()
+	for range ch {
+	}
+	if err := wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestIterOrdersV2(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// Every order shares a millisecond, so paging can't make progress
+		// past the first full page.
+		var orders []map[string]interface{}
+		for i := 0; i < 2; i++ {
+			orders = append(orders, map[string]interface{}{
+				"order_id":           strconv.Itoa(i),
+				"creation_timestamp": 100,
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"orders": orders})
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	ctx := context.Background()
+	it := cl.IterOrdersV2(&luno.ListOrdersV2Request{Limit: 2})
+	var ids []string
+	for it.Next(ctx) {
+		ids = append(ids, it.Order().OrderId)
+	}
+	if len(ids) != 2 || ids[0] != "0" || ids[1] != "1" {
+		t.Errorf("Expected orders 0 and 1, got %v", ids)
+	}
+	if it.Err() == nil {
+		t.Errorf("Expected error for orders sharing a timestamp")
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+
+	// Pages are only fetched as needed.
+	calls = 0
+	it = cl.IterOrdersV2(&luno.ListOrdersV2Request{Limit: 2})
+	if !it.Next(ctx) || !it.Next(ctx) {
+		t.Fatalf("Expected two orders, got error %v", it.Err())
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
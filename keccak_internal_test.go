@@ -0,0 +1,26 @@
+package luno
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestKeccak256(t *testing.T) {
+	testCases := []struct {
+		in  string
+		exp string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"abc", "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		// Longer than one block.
+		{strings.Repeat("a", 200), "96ea54061def936c4be90b518992fdc6f12f535068a256229aca54267b4d084d"},
+	}
+	for _, tc := range testCases {
+		h := keccak256([]byte(tc.in))
+		got := hex.EncodeToString(h[:])
+		if got != tc.exp {
+			t.Errorf("Expected keccak256(%q) = %s, got %s", tc.in, tc.exp, got)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// ledgerPageSize is the maximum number of rows ListTransactions returns per
+// call.
+const ledgerPageSize = 1000
+
+// LedgerEntry is a single account transaction normalised for bookkeeping.
+type LedgerEntry struct {
+	// Row number of the transaction within the account, starting from 1.
+	RowIndex  int64
+	Timestamp time.Time
+	Kind      Kind
+	Currency  string
+
+	// Human-readable description of the transaction.
+	Description string
+
+	// Signed change in balance caused by this transaction.
+	BalanceDelta decimal.Decimal
+
+	// Signed change in available balance caused by this transaction.
+	AvailableDelta decimal.Decimal
+
+	// Running balances after this transaction.
+	Balance   decimal.Decimal
+	Available decimal.Decimal
+}
+
+// GetLedger returns the transactions of an account with timestamps in the
+// range [from, to) as ledger entries. A zero to time means no upper bound.
+//
+// The running balance is verified while paging through the account: every
+// row must follow on from the previous one and its balance must equal the
+// previous balance plus its delta. An error is returned if the data is
+// inconsistent, which would indicate missing rows.
+func (cl *Client) GetLedger(ctx context.Context, accountID string,
+	from, to time.Time) ([]LedgerEntry, error) {
+
+	txs, err := cl.ledgerTransactions(ctx, accountID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var entries []LedgerEntry
+	for _, tx := range txs {
+		entries = append(entries, makeLedgerEntry(tx))
+	}
+	return entries, nil
+}
+
+// InterestHistory returns the interest paid into an account, such as a
+// savings account, with timestamps in the range [from, to), oldest first. The
+// BalanceDelta of each entry is the amount of interest paid. A zero to time
+// means no upper bound.
+//
+// Luno doesn't list interest payments separately, so every transaction of the
+// account in the range is fetched and verified as for GetLedger.
+func (cl *Client) InterestHistory(ctx context.Context, accountID string,
+	from, to time.Time) ([]LedgerEntry, error) {
+
+	txs, err := cl.ledgerTransactions(ctx, accountID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var entries []LedgerEntry
+	for _, tx := range txs {
+		if tx.Kind == KindInterest {
+			entries = append(entries, makeLedgerEntry(tx))
+		}
+	}
+	return entries, nil
+}
+
+// ledgerTransactions returns the transactions of an account with timestamps
+// in the range [from, to), verifying the running balance as for GetLedger.
+func (cl *Client) ledgerTransactions(ctx context.Context, accountID string,
+	from, to time.Time) ([]Transaction, error) {
+
+	id, err := strconv.ParseInt(accountID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("luno: invalid account id %q: %w", accountID, err)
+	}
+
+	var (
+		res         []Transaction
+		prevRow     int64
+		prevBalance = decimal.Zero()
+	)
+	for minRow := int64(1); ; minRow += ledgerPageSize {
+		maxRow := minRow + ledgerPageSize
+		page, err := cl.ListTransactions(ctx, &ListTransactionsRequest{
+			Id:     id,
+			MinRow: minRow,
+			MaxRow: maxRow,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		txs := page.Transactions
+		sort.Slice(txs, func(i, j int) bool {
+			return txs[i].RowIndex < txs[j].RowIndex
+		})
+
+		for _, tx := range txs {
+			if tx.RowIndex != prevRow+1 {
+				return nil, fmt.Errorf("luno: ledger gap between rows %d and %d",
+					prevRow, tx.RowIndex)
+			}
+			exp := prevBalance.Add(tx.BalanceDelta)
+			if tx.Balance.Cmp(exp) != 0 {
+				return nil, fmt.Errorf("luno: inconsistent ledger at row %d: "+
+					"balance %s, expected %s", tx.RowIndex, tx.Balance, exp)
+			}
+			prevRow, prevBalance = tx.RowIndex, tx.Balance
+
+			ts := time.Time(tx.Timestamp)
+			if !to.IsZero() && !ts.Before(to) {
+				return res, nil
+			}
+			if ts.Before(from) {
+				continue
+			}
+			res = append(res, tx)
+		}
+
+		if !hasMoreTransactions(page, minRow, maxRow) {
+			return res, nil
+		}
+	}
+}
+
+// ErrTransactionNotFound is returned by GetTransaction if the account has no
+// transaction with the given row index.
+var ErrTransactionNotFound = errors.New("luno: transaction not found")
+
+// GetTransaction returns the transaction of an account with the given row
+// index. Row indexes start from 1.
+func (cl *Client) GetTransaction(ctx context.Context, accountID string,
+	rowIndex int64) (*Transaction, error) {
+
+	id, err := strconv.ParseInt(accountID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("luno: invalid account id %q: %w", accountID, err)
+	}
+	if rowIndex < 1 {
+		return nil, fmt.Errorf("luno: invalid row index %d", rowIndex)
+	}
+
+	res, err := cl.ListTransactions(ctx, &ListTransactionsRequest{
+		Id:     id,
+		MinRow: rowIndex,
+		MaxRow: rowIndex + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range res.Transactions {
+		if tx.RowIndex == rowIndex {
+			return &tx, nil
+		}
+	}
+	return nil, ErrTransactionNotFound
+}
+
+func makeLedgerEntry(tx Transaction) LedgerEntry {
+	return LedgerEntry{
+		RowIndex:       tx.RowIndex,
+		Timestamp:      time.Time(tx.Timestamp),
+		Kind:           tx.Kind,
+		Currency:       tx.Currency,
+		Description:    tx.Description,
+		BalanceDelta:   tx.BalanceDelta,
+		AvailableDelta: tx.AvailableDelta,
+		Balance:        tx.Balance,
+		Available:      tx.Available,
+	}
+}
@@ -0,0 +1,158 @@
+package luno_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+)
+
+func newLedgerServer(t *testing.T, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/1/accounts/123/transactions" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		if r.FormValue("min_row") != "1" {
+			w.Write([]byte(`{"transactions":[]}`))
+			return
+		}
+		w.Write([]byte(body))
+	}))
+}
+
+func TestGetLedger(t *testing.T) {
+	srv := newLedgerServer(t, `{"transactions":[
+		{"row_index":3,"timestamp":3000,"balance":"1.5","balance_delta":"-0.5","available":"1.5","available_delta":"-0.5","currency":"XBT","kind":"TRANSFER"},
+		{"row_index":2,"timestamp":2000,"balance":"2","balance_delta":"1","available":"2","available_delta":"1","currency":"XBT","kind":"TRANSFER"},
+		{"row_index":1,"timestamp":1000,"balance":"1","balance_delta":"1","available":"1","available_delta":"1","currency":"XBT","kind":"TRANSFER"}
+	]}`)
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	entries, err := cl.GetLedger(context.Background(), "123",
+		time.Unix(2, 0), time.Time{})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].RowIndex != 2 || entries[1].RowIndex != 3 {
+		t.Errorf("Expected rows 2 and 3, got %d and %d",
+			entries[0].RowIndex, entries[1].RowIndex)
+	}
+	if entries[1].Balance.String() != "1.5" {
+		t.Errorf("Expected balance 1.5, got %s", entries[1].Balance)
+	}
+
+	entries, err = cl.GetLedger(context.Background(), "123",
+		time.Time{}, time.Unix(2, 0))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(entries) != 1 || entries[0].RowIndex != 1 {
+		t.Errorf("Expected only row 1, got %+v", entries)
+	}
+}
+
+func TestGetLedgerInconsistent(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "wrong balance",
+			body: `{"transactions":[
+				{"row_index":1,"timestamp":1000,"balance":"1","balance_delta":"1"},
+				{"row_index":2,"timestamp":2000,"balance":"3","balance_delta":"1"}
+			]}`,
+		},
+		{
+			name: "missing row",
+			body: `{"transactions":[
+				{"row_index":1,"timestamp":1000,"balance":"1","balance_delta":"1"},
+				{"row_index":3,"timestamp":3000,"balance":"2","balance_delta":"1"}
+			]}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newLedgerServer(t, tc.body)
+			defer srv.Close()
+
+			cl := luno.NewClient()
+			cl.SetBaseURL(srv.URL)
+
+			_, err := cl.GetLedger(context.Background(), "123",
+				time.Time{}, time.Time{})
+			if err == nil {
+				t.Errorf("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestInterestHistory(t *testing.T) {
+	srv := newLedgerServer(t, `{"transactions":[
+		{"row_index":1,"timestamp":1000,"balance":"100","balance_delta":"100","currency":"USDC","kind":"TRANSFER"},
+		{"row_index":2,"timestamp":2000,"balance":"100.01","balance_delta":"0.01","currency":"USDC","kind":"INTEREST"},
+		{"row_index":3,"timestamp":3000,"balance":"100.03","balance_delta":"0.02","currency":"USDC","kind":"INTEREST"}
+	]}`)
+	defer srv.Close()
+
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL))
+	entries, err := cl.InterestHistory(context.Background(), "123", time.Time{}, time.Unix(3, 0))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(entries) != 1 || entries[0].RowIndex != 2 || entries[0].BalanceDelta.String() != "0.01" {
+		t.Errorf("Expected interest of 0.01 in row 2, got %+v", entries)
+	}
+}
+
+func TestGetTransaction(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/1/accounts/123/transactions" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		if r.FormValue("min_row") != "2" || r.FormValue("max_row") != "3" {
+			w.Write([]byte(`{"transactions":[]}`))
+			return
+		}
+		w.Write([]byte(`{"transactions":[
+			{"row_index":2,"timestamp":2000,"balance":"2","balance_delta":"1","currency":"XBT","description":"Bought BTC"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	ctx := context.Background()
+
+	tx, err := cl.GetTransaction(ctx, "123", 2)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if tx.RowIndex != 2 || tx.Description != "Bought BTC" {
+		t.Errorf("Expected row 2, got %+v", tx)
+	}
+
+	_, err = cl.GetTransaction(ctx, "123", 5)
+	if err != luno.ErrTransactionNotFound {
+		t.Errorf("Expected ErrTransactionNotFound, got %v", err)
+	}
+
+	for _, row := range []int64{0, -1} {
+		if _, err := cl.GetTransaction(ctx, "123", row); err == nil {
+			t.Errorf("Expected error for row %d, got nil", row)
+		}
+	}
+	if _, err := cl.GetTransaction(ctx, "abc", 1); err == nil {
+		t.Errorf("Expected error for invalid account id, got nil")
+	}
+}
@@ -0,0 +1,152 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrClientClosed is returned for requests made after Close, and for
+// requests whose retry or rate limit wait was cut short by Close.
+var ErrClientClosed = errors.New("luno: client closed")
+
+// lifecycle tracks the requests in flight so that Close can interrupt their
+// waits and wait for their attempts to finish.
+type lifecycle struct {
+	mu      sync.Mutex
+	closed  bool
+	next    uint64
+	cancels map[uint64]context.CancelFunc
+	onClose []func()
+
+	inflight sync.WaitGroup
+}
+
+// begin registers a request. Its waits should use the returned context,
+// which is cancelled by Close, and end must be called once the request is
+// done.
+func (l *lifecycle) begin(ctx context.Context) (context.Context, func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, nil, ErrClientClosed
+	}
+
+	waitCtx, cancel := context.WithCancel(ctx)
+	id := l.next
+	l.next++
+	if l.cancels == nil {
+		l.cancels = make(map[uint64]context.CancelFunc)
+	}
+	l.cancels[id] = cancel
+	l.inflight.Add(1)
+
+	end := func() {
+		l.mu.Lock()
+		delete(l.cancels, id)
+		l.mu.Unlock()
+		cancel()
+		l.inflight.Done()
+	}
+	return waitCtx, end, nil
+}
+
+// close marks the client closed and cancels the waits of the requests in
+// flight. It returns the functions registered with OnClose, and false if the
+// client was already closed.
+func (l *lifecycle) close() ([]func(), bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, false
+	}
+	l.closed = true
+	for _, cancel := range l.cancels {
+		cancel()
+	}
+	fns := l.onClose
+	l.onClose = nil
+	return fns, true
+}
+
+// waitErr returns the error of a wait on waitCtx, a context returned by
+// begin for ctx, which ended with err: ErrClientClosed if the wait was
+// interrupted by Close, or else err.
+func waitErr(ctx, waitCtx context.Context, err error) error {
+	if ctx.Err() == nil && waitCtx.Err() != nil {
+		return ErrClientClosed
+	}
+	return err
+}
+
+// Close shuts the client down, e.g. when a service stops. Requests made
+// after Close fail with ErrClientClosed, as do requests waiting to be
+// retried or for the rate limiter. Close then waits for the attempts in
+// flight to finish, which is bounded by the HTTP client's timeout and the
+// requests' contexts, closes the idle connections of the HTTP client and
+// finally calls the functions registered with OnClose.
+//
+// Clones of the client, see Clone, aren't closed, but share its HTTP
+// transport, so their idle connections are closed too. Calling Close more
+// than once has no effect.
+func (cl *Client) Close() error {
+	fns, ok := cl.life.close()
+	if !ok {
+		return nil
+	}
+	cl.life.inflight.Wait()
+	cl.httpClient.CloseIdleConnections()
+	for _, fn := range fns {
+		fn()
+	}
+	return nil
+}
+
+// OnClose registers fn to be called by Close, e.g. to close a streaming
+// connection used along with the client:
+//
+//	conn, err := streaming.Dial(keyID, keySecret, "XBTZAR")
+//	...
+//	cl.OnClose(conn.Close)
+//
+// Functions are called in the order they were registered. If the client is
+// already closed, fn is called right away.
+func (cl *Client) OnClose(fn func()) {
+	l := &cl.life
+	l.mu.Lock()
+	if !l.closed {
+		l.onClose = append(l.onClose, fn)
+		l.mu.Unlock()
+		return
+	}
+	l.mu.Unlock()
+	fn()
+}
+
+// Healthcheck reports whether the API is reachable and, if the client has
+// credentials, whether the API key is accepted, e.g. for a readiness probe.
+// It makes a single attempt of each request, see WithNoRetry: a call
+// to GetServerTime, then one to GetBalances. An API key without the
+// Perm_R_Balance permission is still accepted.
+//
+// The returned error wraps the error of the failed request, so
+// errors.Is(err, ErrAuth) reports whether the API key was rejected.
+func (cl *Client) Healthcheck(ctx context.Context) error {
+	ctx = WithNoRetry(ctx)
+	if _, err := cl.GetServerTime(ctx, &GetServerTimeRequest{}); err != nil {
+		return fmt.Errorf("luno: API unreachable: %w", err)
+	}
+
+	cl.authMu.RLock()
+	hasCreds := cl.creds != nil
+	cl.authMu.RUnlock()
+	if !hasCreds {
+		return nil
+	}
+	_, err := cl.GetBalances(ctx, &GetBalancesRequest{})
+	if err != nil && !IsErrorCode(err, ErrCodeInsufficientPermissions) {
+		return fmt.Errorf("luno: API key check failed: %w", err)
+	}
+	return nil
+}
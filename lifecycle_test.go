@@ -0,0 +1,167 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestCloseInterruptsRetry(t *testing.T) {
+	attempts := make(chan struct{}, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts <- struct{}{}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL))
+	cl.SetMaxRetries(3)
+	cl.SetRetryBackoff(luno.ConstantBackoff(time.Hour))
+	var closed []string
+	cl.OnClose(func() { closed = append(closed, "a") })
+	cl.OnClose(func() { closed = append(closed, "b") })
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+		errc <- err
+	}()
+	<-attempts
+
+	if err := cl.Close(); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	select {
+	case err := <-errc:
+		if !errors.Is(err, luno.ErrClientClosed) {
+			t.Errorf("Expected ErrClientClosed, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected retry wait to be interrupted")
+	}
+	if len(closed) != 2 || closed[0] != "a" || closed[1] != "b" {
+		t.Errorf("Expected OnClose functions in order, got %v", closed)
+	}
+
+	_, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+	if !errors.Is(err, luno.ErrClientClosed) {
+		t.Errorf("Expected ErrClientClosed after Close, got %v", err)
+	}
+	if len(attempts) != 0 {
+		t.Errorf("Expected no attempts after Close, got %d", len(attempts))
+	}
+
+	var late bool
+	cl.OnClose(func() { late = true })
+	if !late {
+		t.Errorf("Expected OnClose after Close to call fn right away")
+	}
+	if err := cl.Close(); err != nil {
+		t.Errorf("Expected second Close to succeed, got %v", err)
+	}
+}
+
+func TestCloseWaitsForAttempts(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte(`{"pair":"XBTZAR"}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL))
+	errc := make(chan error, 1)
+	go func() {
+		_, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+		errc <- err
+	}()
+	<-started
+
+	closed := make(chan struct{})
+	go func() {
+		cl.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("Expected Close to wait for the attempt in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-errc; err != nil {
+		t.Errorf("Expected attempt in flight to succeed, got %v", err)
+	}
+	<-closed
+}
+
+func TestHealthcheck(t *testing.T) {
+	var balanceStatus int
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/api/1/time":
+			w.Write([]byte(`{"now":1600000000000,"timezone":"UTC"}`))
+		case "/api/1/balance":
+			switch balanceStatus {
+			case http.StatusUnauthorized:
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":"Unauthorized","error_code":"ErrUnauthorised"}`))
+			case http.StatusForbidden:
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"error":"Insufficient permissions","error_code":"ErrInsufficientPerms"}`))
+			default:
+				w.Write([]byte(`{"balance":[]}`))
+			}
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL))
+	if err := cl.Healthcheck(ctx); err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+	if len(paths) != 1 {
+		t.Errorf("Expected only a public request without credentials, got %v", paths)
+	}
+
+	cl = luno.NewClient(luno.WithBaseURL(srv.URL), luno.WithAuth("key", "secret"))
+	testCases := []struct {
+		name    string
+		status  int
+		expAuth bool
+	}{
+		{name: "valid", status: http.StatusOK},
+		{name: "missing permission", status: http.StatusForbidden},
+		{name: "rejected", status: http.StatusUnauthorized, expAuth: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			balanceStatus = tc.status
+			err := cl.Healthcheck(ctx)
+			if tc.expAuth {
+				if !errors.Is(err, luno.ErrAuth) {
+					t.Errorf("Expected ErrAuth, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected success, got %v", err)
+			}
+		})
+	}
+
+	cl.Close()
+	if err := cl.Healthcheck(ctx); !errors.Is(err, luno.ErrClientClosed) {
+		t.Errorf("Expected ErrClientClosed after Close, got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package luno
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultRedactedParams are the request parameters redacted by default.
+var defaultRedactedParams = []string{
+	"address",
+	"destination_tag",
+	"account_number",
+	"api_key_secret",
+	"secret",
+	"password",
+}
+
+const redacted = "REDACTED"
+
+// SetRedactedParams sets the names of request parameters whose values are
+// replaced by REDACTED in RequestInfo.Body and debug logs. It replaces the
+// default set, which contains parameters such as address and secret. Names
+// are case insensitive.
+func (cl *Client) SetRedactedParams(names ...string) {
+	cl.redactedParams = makeRedactedParams(names)
+}
+
+func makeRedactedParams(names []string) map[string]bool {
+	m := make(map[string]bool, len(names))
+	for _, n := range names {
+		m[strings.ToLower(n)] = true
+	}
+	return m
+}
+
+// redactValues encodes values with the values of redacted parameters
+// replaced.
+func (cl *Client) redactValues(values url.Values) string {
+	r := make(url.Values, len(values))
+	for k, vv := range values {
+		if cl.redactedParams[strings.ToLower(k)] {
+			r[k] = []string{redacted}
+			continue
+		}
+		r[k] = vv
+	}
+	return r.Encode()
+}
+
+// redactJSON encodes the fields of a JSON object with the values of redacted
+// parameters replaced.
+func (cl *Client) redactJSON(fields map[string]json.RawMessage) string {
+	r := make(map[string]json.RawMessage, len(fields))
+	for k, v := range fields {
+		if cl.redactedParams[strings.ToLower(k)] {
+			v = json.RawMessage(`"` + redacted + `"`)
+		}
+		r[k] = v
+	}
+	b, _ := json.Marshal(r)
+	return string(b)
+}
+
+// requestLogger receives the events logged by the client, see SetLogger.
+type requestLogger interface {
+	logRequest(ctx context.Context, info RequestInfo, url string)
+	logResponse(ctx context.Context, info ResponseInfo)
+	logRetry(ctx context.Context, info ResponseInfo, delay time.Duration)
+	logThrottle(ctx context.Context, info ThrottleInfo)
+}
+
+// LoggingHooks returns hooks which log every attempt of an API request and
+// its outcome to l. Request bodies are logged with sensitive parameters
+// redacted, see Client.SetRedactedParams. Headers, including the
+// Authorization header, are never logged.
+func LoggingHooks(l *log.Logger) Hooks {
+	return Hooks{
+		BeforeRequest: func(ctx context.Context, info RequestInfo) {
+			l.Printf("luno: %s %s attempt=%d body=%q",
+				info.Method, info.Path, info.Attempt, info.Body)
+		},
+		AfterResponse: func(ctx context.Context, info ResponseInfo) {
+			l.Printf("luno: %s %s attempt=%d status=%d request_id=%q duration=%s err=%v",
+				info.Method, info.Path, info.Attempt, info.StatusCode,
+				info.RequestID, info.Duration, info.Err)
+		},
+		Deprecation: func(ctx context.Context, n DeprecationNotice) {
+			sunset := "none"
+			if !n.Sunset.IsZero() {
+				sunset = n.Sunset.Format(time.RFC3339)
+			}
+			l.Printf("luno: %s %s is deprecated warning=%q sunset=%s",
+				n.Method, n.Path, n.Warning, sunset)
+		},
+		Warning: func(ctx context.Context, w Warning) {
+			l.Printf("luno: %s %s kind=%s warning=%q",
+				w.Method, w.Path, w.Kind, w.Message)
+		},
+	}
+}
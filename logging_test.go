@@ -0,0 +1,76 @@
+package luno_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func TestLoggingHooksRedaction(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	send := &luno.SendRequest{
+		Address:  "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+		Amount:   decimal.NewFromFloat64(0.1, 1),
+		Currency: "XBT",
+	}
+
+	testCases := []struct {
+		name      string
+		redacted  []string
+		expShown  []string
+		expHidden []string
+	}{
+		{
+			name:      "default",
+			expShown:  []string{"address=REDACTED", "amount=0.1", "currency=XBT"},
+			expHidden: []string{send.Address},
+		},
+		{
+			name:      "custom",
+			redacted:  []string{"Currency"},
+			expShown:  []string{"address=" + send.Address, "currency=REDACTED"},
+			expHidden: []string{"currency=XBT"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			cl := luno.NewClient()
+			cl.SetBaseURL(srv.URL)
+			if err := cl.SetAuth("keyid", "topsecret"); err != nil {
+				t.Fatal(err)
+			}
+			cl.SetHooks(luno.LoggingHooks(log.New(&buf, "", 0)))
+			if tc.redacted != nil {
+				cl.SetRedactedParams(tc.redacted...)
+			}
+
+			if _, err := cl.Send(context.Background(), send); err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+
+			out := buf.String()
+			for _, s := range tc.expShown {
+				if !strings.Contains(out, s) {
+					t.Errorf("Expected %q in log, got %q", s, out)
+				}
+			}
+			for _, s := range append(tc.expHidden, "topsecret", "Authorization") {
+				if strings.Contains(out, s) {
+					t.Errorf("Expected %q not in log, got %q", s, out)
+				}
+			}
+		})
+	}
+}
@@ -2,9 +2,7 @@
 package luno
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -13,55 +11,184 @@ import (
 	"net/http"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/luno/luno-go/decimal"
 )
 
 // Client is a Luno API client.
 type Client struct {
-	httpClient   *http.Client
-	baseURL      string
-	apiKeyID     string
-	apiKeySecret string
-	debug        bool
+	httpClient *http.Client
+	baseURL    string
+	debug      bool
+	maxRetries int
+	backoff    Backoff
+
+	// maxRetryDelay caps the delay asked for by a Retry-After header.
+	maxRetryDelay time.Duration
+
+	clock Clock
+
+	redirectPolicy RedirectPolicy
+
+	// checksRedirect is true if the CheckRedirect function of httpClient is
+	// cl.checkRedirect, rather than one set by the caller.
+	checksRedirect bool
+
+	readTimeout    time.Duration
+	writeTimeout   time.Duration
+	staleThreshold time.Duration
+
+	retryPredicate     func(attempt int, resp *http.Response, err error) bool
+	retryRateLimitOnly bool
+
+	// authMu guards the credential provider so that it can be replaced while
+	// requests are in flight.
+	authMu sync.RWMutex
+	creds  CredentialProvider
+
+	authFailure authFailureNotifier
+
+	// signer authenticates requests. Basic authentication is used if nil.
+	signer signer
+
+	maxResponseBytes int64
+
+	hooks               Hooks
+	interceptors        []Interceptor
+	correlationIDHeader string
+	userAgentSuffix     string
+
+	newEncoder func(io.Writer) Encoder
+	newDecoder func(io.Reader) Decoder
+
+	limiter         *rateLimiter
+	authLimiter     *rateLimiter
+	rateLimitPolicy RateLimitPolicy
+
+	metrics MetricsCollector
+	logger  requestLogger
+	etags   *etagCache
+	hosts   *hostPool
+
+	redactedParams map[string]bool
+
+	validateAddresses bool
+	normalizePairs    bool
+	validatePairs     bool
+	maxNotional       map[string]decimal.Decimal
+	metadataPolicy    MetadataPolicy
+	strictDecoding    bool
+	dedup             *flightGroup
+
+	marketsCache marketsCache
+	feeInfoCache feeInfoCache
+	tickerCache  tickerCache
+	accountIDs   accountIDCache
+	deprecations deprecations
+	skew         skewTracker
+	life         lifecycle
 }
 
 const defaultBaseURL = "https://api.luno.com"
 
 const defaultTimeout = 10 * time.Second
 
-// NewClient creates a new Luno API client with the default base URL.
-func NewClient() *Client {
-	return &Client{
+const defaultMaxResponseBytes = 32 << 20
+
+// requestIDHeader is the response header carrying the ID which the server
+// assigned to a request.
+const requestIDHeader = "X-Request-Id"
+
+// NewClient creates a new Luno API client with the default base URL, and
+// applies opts to it.
+//
+// Example:
+//
+//	cl := luno.NewClient(
+//		luno.WithAuth(keyID, keySecret),
+//		luno.WithTimeout(5*time.Second),
+//	)
+func NewClient(opts ...Option) *Client {
+	cl := &Client{
 		httpClient: &http.Client{Timeout: defaultTimeout},
 		baseURL:    defaultBaseURL,
+		backoff:    defaultBackoff,
+		clock:      realClock{},
+
+		maxRetryDelay: defaultMaxRetryDelay,
+
+		maxResponseBytes: defaultMaxResponseBytes,
+
+		newEncoder: newJSONEncoder,
+		newDecoder: newJSONDecoder,
+
+		redactedParams: makeRedactedParams(defaultRedactedParams),
 	}
+	cl.httpClient.CheckRedirect = cl.checkRedirect
+	cl.checksRedirect = true
+	for _, opt := range opts {
+		opt(cl)
+	}
+	return cl
 }
 
-// SetAuth provides the client with an API key and secret.
+// SetAuth provides the client with an API key and secret. It is safe to call
+// while requests are in flight, for example to rotate keys. Each request uses
+// either the old or the new key pair, never a mix of both. SetAuth replaces
+// any provider set with SetCredentialProvider.
+//
+// Deprecated: Use NewClient with WithAuth, or SetCredentialProvider to rotate
+// keys.
 func (cl *Client) SetAuth(apiKeyID, apiKeySecret string) error {
 	if apiKeyID == "" || apiKeySecret == "" {
 		return errors.New("luno: no credentials provided")
 	}
-	cl.apiKeyID = apiKeyID
-	cl.apiKeySecret = apiKeySecret
+	cl.SetCredentialProvider(staticCredentials{
+		keyID:     apiKeyID,
+		keySecret: apiKeySecret,
+	})
 	return nil
 }
 
-// SetHTTPClient sets the HTTP client that will be used for API calls.
+// SetHTTPClient sets the HTTP client that will be used for API calls. Its
+// CheckRedirect function is used as is, see SetRedirectPolicy.
+//
+// Deprecated: Use NewClient with WithHTTPClient.
 func (cl *Client) SetHTTPClient(httpClient *http.Client) {
-	cl.httpClient = httpClient
+	WithHTTPClient(httpClient)(cl)
 }
 
 // SetTimeout sets the timeout for requests made by this client. Note: if you
 // set a timeout and then call .SetHTTPClient(), the timeout in the new HTTP
 // client will be used.
+//
+// Deprecated: Use NewClient with WithTimeout.
 func (cl *Client) SetTimeout(timeout time.Duration) {
-	cl.httpClient.Timeout = timeout
+	WithTimeout(timeout)(cl)
+}
+
+// SetReadTimeout sets a default timeout for GET requests, such as market data
+// queries. It applies in addition to the timeout of the HTTP client and covers
+// any retries, but is ignored when the context already has a deadline or a
+// Timeout in its RequestOptions. Zero disables it.
+func (cl *Client) SetReadTimeout(timeout time.Duration) {
+	cl.readTimeout = timeout
+}
+
+// SetWriteTimeout sets a default timeout for requests which change state, such
+// as placing orders or sending funds. It applies in the same way as
+// SetReadTimeout.
+func (cl *Client) SetWriteTimeout(timeout time.Duration) {
+	cl.writeTimeout = timeout
 }
 
 // SetBaseURL overrides the default base URL. For internal use.
+//
+// Deprecated: Use NewClient with WithBaseURL.
 func (cl *Client) SetBaseURL(baseURL string) {
-	cl.baseURL = strings.TrimRight(baseURL, "/")
+	WithBaseURL(baseURL)(cl)
 }
 
 // SetDebug enables or disables debug mode. In debug mode, HTTP requests and
@@ -70,85 +197,470 @@ func (cl *Client) SetDebug(debug bool) {
 	cl.debug = debug
 }
 
+// SetMaxResponseBytes sets the maximum size of a response body. Larger
+// responses result in an error.
+func (cl *Client) SetMaxResponseBytes(n int64) {
+	cl.maxResponseBytes = n
+}
+
+// SetMaxRetries sets the maximum number of times a failed request is retried.
+// Rate limited requests are always retried, while server and network errors
+// are only retried for GET requests and requests made with a context from
+// WithRetrySafe. The default is 0, i.e. no retries.
+func (cl *Client) SetMaxRetries(n int) {
+	cl.maxRetries = n
+}
+
+// SetRetryPredicate replaces the built-in decision of whether a failed
+// attempt is retried. fn is called with the zero-based attempt number, the
+// HTTP response, or nil if none was received, and the error, which can be
+// inspected with IsErrorCode for API errors. The response body has already
+// been consumed. fn is not called for successful attempts or once the context
+// is done, and attempts are still limited by SetMaxRetries. Setting nil
+// restores the built-in logic.
+func (cl *Client) SetRetryPredicate(fn func(attempt int, resp *http.Response, err error) bool) {
+	cl.retryPredicate = fn
+}
+
+// SetRetryBackoff sets the policy used to wait between retries.
+func (cl *Client) SetRetryBackoff(b Backoff) {
+	cl.backoff = b
+}
+
+// SetCorrelationIDHeader sets the name of an HTTP header used to send the
+// correlation ID of requests made with a context from WithCorrelationID. No
+// header is sent by default.
+func (cl *Client) SetCorrelationIDHeader(name string) {
+	cl.correlationIDHeader = name
+}
+
 func (cl *Client) do(ctx context.Context, method, path string,
 	req, res interface{}, auth bool) error {
 
-	url := cl.baseURL + "/" + strings.TrimLeft(path, "/")
+	return cl.doEndpoint(ctx, endpoint{method, path, auth, bodyForm}, req, res)
+}
+
+// doEndpoint calls the API method described by e, encoding req as e
+// specifies and decoding the response into res.
+func (cl *Client) doEndpoint(ctx context.Context, e endpoint,
+	req, res interface{}) error {
+
+	method, path, auth := e.method, e.path, e.auth
+	opts := requestOptionsFromContext(ctx)
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	} else if _, ok := ctx.Deadline(); !ok {
+		timeout := cl.writeTimeout
+		if method == http.MethodGet {
+			timeout = cl.readTimeout
+		}
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+	}
+
+	host, err := baseURLOverrideFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	url := "/" + strings.TrimLeft(path, "/")
 
 	if cl.debug {
 		log.Printf("luno: Call: %s %s", method, path)
-		log.Printf("luno: Request: %#v", req)
 	}
 
 	var contentType string
-	var body io.Reader
+	var body, redactedBody string
+	logURL := url
 	if req != nil {
-		values := makeURLValues(req)
-		if strings.Contains(path, "{id}") {
-			url = strings.Replace(url, "{id}", values.Get("id"), -1)
-			values.Del("id")
+		values, err := makeURLValues(req)
+		if err != nil {
+			return err
 		}
-		if method == http.MethodGet {
+		if cl.normalizePairs && values.Get("pair") != "" {
+			pair, err := NormalizePair(values.Get("pair"))
+			if err != nil {
+				return err
+			}
+			values.Set("pair", pair)
+		}
+		var params []string
+		url, params = expandPath(url, values)
+		logURL = url
+		switch {
+		case method == http.MethodGet:
 			url = url + "?" + values.Encode()
-		} else {
-			body = strings.NewReader(values.Encode())
+			if cl.logger != nil {
+				logURL = logURL + "?" + cl.redactValues(values)
+			}
+		case e.encoding == bodyJSON:
+			b, redactedJSON, err := cl.encodeJSONBody(req, params, values.Get("pair"))
+			if err != nil {
+				return err
+			}
+			body, redactedBody = string(b), redactedJSON
+			contentType = "application/json"
+		default:
+			body = values.Encode()
+			redactedBody = cl.redactValues(values)
 			contentType = "application/x-www-form-urlencoded"
 		}
+		if cl.debug {
+			log.Printf("luno: Request: %s", cl.redactValues(values))
+		}
 	}
 
-	httpReq, err := http.NewRequest(method, url, body)
+	maxRetries := cl.maxRetries
+	single := opts.DisableRetry
+	if single {
+		maxRetries = 0
+	}
+
+	c := &call{
+		method:      method,
+		host:        host,
+		url:         url,
+		logURL:      logURL,
+		contentType: contentType,
+		body:        body,
+		res:         res,
+		auth:        auth,
+		opts:        opts,
+		info: RequestInfo{
+			Method:        method,
+			Path:          path,
+			CorrelationID: CorrelationIDFromContext(ctx),
+			Body:          redactedBody,
+		},
+	}
+
+	if cl.dedup != nil && method == http.MethodGet && len(opts.ExtraHeaders) == 0 && !single {
+		err = cl.doShared(ctx, c, maxRetries)
+	} else {
+		err = cl.doCall(ctx, c, maxRetries)
+	}
+	if err != nil {
+		return err
+	}
+	cl.reportWarnings(ctx, c.info, res)
+	return nil
+}
+
+// doCall makes the attempts of a request until one succeeds, it fails with an
+// error which isn't retried, or maxRetries retries have been made.
+func (cl *Client) doCall(ctx context.Context, c *call, maxRetries int) error {
+	stats := callStatsFromContext(ctx)
+	if stats != nil {
+		stats.Attempts = 0
+		stats.Throttles = nil
+		stats.Warnings = nil
+	}
+
+	// Close interrupts the waits between attempts, not the attempts.
+	waitCtx, end, err := cl.life.begin(ctx)
 	if err != nil {
 		return err
 	}
+	defer end()
+
+	for attempt := 0; ; attempt++ {
+		c.info.Attempt = attempt
+		if err := waitErr(ctx, waitCtx, nil); err != nil {
+			return err
+		}
+		if err := cl.waitRateLimit(waitCtx, c); err != nil {
+			return waitErr(ctx, waitCtx, err)
+		}
+
+		c.info.Budget = 0
+		if deadline, ok := ctx.Deadline(); ok {
+			c.info.Budget = deadline.Sub(cl.clock.Now())
+		}
+		cl.hooks.beforeRequest(ctx, c.info)
+		if cl.logger != nil {
+			cl.logger.logRequest(ctx, c.info, c.logURL)
+		}
+		t0 := cl.clock.Now()
+		httpRes, err := cl.doAttempt(ctx, c)
+		var statusCode int
+		var requestID string
+		if httpRes != nil {
+			statusCode = httpRes.StatusCode
+			requestID = httpRes.Header.Get(requestIDHeader)
+		}
+		if stats != nil {
+			stats.Attempts = attempt + 1
+		}
+		resInfo := ResponseInfo{
+			RequestInfo: c.info,
+			StatusCode:  statusCode,
+			RequestID:   requestID,
+			Duration:    cl.clock.Now().Sub(t0),
+			Err:         err,
+		}
+		cl.hooks.afterResponse(ctx, resInfo)
+		if cl.metrics != nil {
+			cl.metrics.ObserveRequest(resInfo)
+		}
+		if cl.logger != nil {
+			cl.logger.logResponse(ctx, resInfo)
+		}
+		if attempt >= maxRetries || !cl.shouldRetry(ctx, c.method, attempt, httpRes, err) {
+			return err
+		}
+
+		delay := cl.backoff.Next(attempt)
+		if d := retryAfter(httpRes, cl.clock.Now()); d > delay {
+			delay = d
+			if delay > cl.maxRetryDelay {
+				delay = cl.maxRetryDelay
+			}
+		}
+		if statusCode == http.StatusTooManyRequests {
+			cl.reportThrottle(ctx, ThrottleInfo{
+				RequestInfo: c.info,
+				Reason:      ThrottleServer,
+				Wait:        delay,
+			})
+		} else if cl.logger != nil {
+			cl.logger.logRetry(ctx, resInfo, delay)
+		}
+		select {
+		case <-waitCtx.Done():
+			return waitErr(ctx, waitCtx, ctx.Err())
+		case <-cl.clock.After(delay):
+		}
+	}
+}
+
+// call holds the parameters of an API request shared by all its attempts.
+type call struct {
+	method string
+
+	// host overrides the host chosen for each attempt if set.
+	host string
+
+	// url is relative to the host.
+	url string
+
+	// logURL is url as logged, with the values of redacted query parameters
+	// replaced. The query is only included if a logger is set.
+	logURL string
+
+	contentType string
+	body        string
+	res         interface{}
+	auth        bool
+	opts        RequestOptions
+	info        RequestInfo
+}
+
+// doAttempt makes a single HTTP request and decodes the response into c.res.
+// The HTTP response, whose body has already been consumed, is returned
+// alongside any error, or nil if no complete response was received.
+func (cl *Client) doAttempt(ctx context.Context, c *call) (*http.Response, error) {
+	var body io.Reader
+	if c.body != "" {
+		body = strings.NewReader(c.body)
+	}
+
+	host := c.host
+	pooled := host == "" && cl.hosts != nil
+	if pooled {
+		host = cl.hosts.pick()
+	} else if host == "" {
+		host = cl.baseURL
+	}
+
+	httpReq, err := http.NewRequest(c.method, host+c.url, body)
+	if err != nil {
+		return nil, err
+	}
 	httpReq = httpReq.WithContext(ctx)
-	httpReq.Header.Set("User-Agent", makeUserAgent())
-	if contentType != "" {
-		httpReq.Header.Set("Content-Type", contentType)
+	httpReq.Header.Set("User-Agent", cl.userAgent())
+	if c.contentType != "" {
+		httpReq.Header.Set("Content-Type", c.contentType)
+	}
+
+	if c.auth {
+		var s signer = basicAuthSigner{cl: cl}
+		if cl.signer != nil {
+			s = cl.signer
+		}
+		if err := s.Sign(httpReq, []byte(c.body)); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.method != http.MethodGet && c.contentType == "" {
+		httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	}
 
-	if auth {
-		httpReq.SetBasicAuth(cl.apiKeyID, cl.apiKeySecret)
+	if cl.correlationIDHeader != "" && c.info.CorrelationID != "" {
+		httpReq.Header.Set(cl.correlationIDHeader, c.info.CorrelationID)
+	}
+
+	for k, vv := range c.opts.ExtraHeaders {
+		for _, v := range vv {
+			httpReq.Header.Add(k, v)
+		}
+	}
+	if httpReq.Header.Get("Accept-Encoding") == "" {
+		httpReq.Header.Set("Accept-Encoding", "gzip")
 	}
 
-	if method != http.MethodGet {
-		httpReq.Header.Set("content-type", "application/x-www-form-urlencoded")
+	var cached etagEntry
+	var isCached bool
+	if cl.etags != nil && c.method == http.MethodGet {
+		cached, isCached = cl.etags.get(c.host + c.url)
+		if isCached {
+			httpReq.Header.Set("If-None-Match", cached.etag)
+		}
 	}
 
-	httpRes, err := cl.httpClient.Do(httpReq)
+	sent := cl.clock.Now()
+	httpRes, err := cl.send(ctx, c.info, httpReq)
+	if pooled && ctx.Err() == nil {
+		cl.hosts.report(host, err == nil &&
+			httpRes.StatusCode < http.StatusInternalServerError)
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer httpRes.Body.Close()
+	cl.checkDeprecation(ctx, c.info, httpRes.Header)
+	cl.observeSkew(ctx, c.info, httpRes.Header, sent, cl.clock.Now())
+	if c.auth {
+		cl.authFailure.observe(httpRes.StatusCode)
+	}
+	defer func() {
+		// Drain any unread bytes so that the connection can be reused.
+		_, _ = io.Copy(ioutil.Discard, httpRes.Body)
+		_ = httpRes.Body.Close()
+	}()
 
-	body = httpRes.Body
-	if cl.debug {
-		b, err := ioutil.ReadAll(body)
-		if err != nil {
+	// Buffer the whole body so that a truncated response can be told apart
+	// from a malformed one. Truncated responses are reported without a status
+	// code so that they are treated like any other transport error.
+	buf := getBuffer()
+	defer putBuffer(buf)
+	err = readBody(buf, httpRes, cl.maxResponseBytes)
+	b := buf.Bytes()
+	if err != nil {
+		if cl.debug {
 			log.Printf("luno: Error reading response body: %v", err)
-		} else {
-			log.Printf("Response: %s", string(b))
 		}
-		body = bytes.NewReader(b)
+		return nil, fmt.Errorf("luno: error reading response: %w", err)
+	}
+	if int64(len(b)) > cl.maxResponseBytes {
+		return httpRes, fmt.Errorf(
+			"luno: response exceeds %d bytes", cl.maxResponseBytes)
+	}
+	if cl.debug {
+		log.Printf("Response: %s", string(b))
+	}
+
+	if httpRes.StatusCode == http.StatusNotModified && isCached {
+		return httpRes, cl.decodeResponse(c, cached.body)
 	}
 
 	if httpRes.StatusCode == http.StatusTooManyRequests {
-		return errors.New("luno: too many requests")
+		return httpRes, ErrRateLimited
 	}
 
 	if httpRes.StatusCode != http.StatusOK {
 		var e Error
-		err := json.NewDecoder(body).Decode(&e)
+		err := cl.decodeJSON(b, &e)
 		if err != nil {
-			return fmt.Errorf("luno: error decoding response (%d %s)",
-				httpRes.StatusCode, http.StatusText(httpRes.StatusCode))
+			return httpRes, statusError{code: httpRes.StatusCode}
+		}
+		e.RequestID = httpRes.Header.Get(requestIDHeader)
+		e.StatusCode = httpRes.StatusCode
+		if len(b) > maxRawErrorBytes {
+			e.raw = string(b[:maxRawErrorBytes])
+		} else {
+			e.raw = string(b)
 		}
-		return e
+		if clockSkewErrorCodes[e.Code] {
+			return httpRes, cl.skew.makeError(e)
+		}
+		return httpRes, makeAPIError(b, e)
 	}
 
-	return json.NewDecoder(body).Decode(res)
+	if err := cl.decodeResponse(c, b); err != nil {
+		return httpRes, err
+	}
+	if cl.etags != nil && c.method == http.MethodGet {
+		if etag := httpRes.Header.Get("ETag"); etag != "" {
+			// b is reused once the attempt returns, so the cache needs its
+			// own copy.
+			body := append([]byte(nil), b...)
+			cl.etags.set(c.host+c.url, etagEntry{etag: etag, body: body})
+		}
+	}
+	return httpRes, nil
+}
+
+// statusError is returned for error responses whose body isn't a Luno API
+// error, e.g. from a proxy.
+type statusError struct {
+	code int
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("luno: error decoding response (%d %s)",
+		e.code, http.StatusText(e.code))
+}
+
+// shouldRetry returns whether a failed attempt should be retried, using the
+// retry predicate if one has been set.
+func (cl *Client) shouldRetry(ctx context.Context, method string, attempt int,
+	httpRes *http.Response, err error) bool {
+
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	if cl.retryPredicate != nil {
+		return cl.retryPredicate(attempt, httpRes, err)
+	}
+	var statusCode int
+	if httpRes != nil {
+		statusCode = httpRes.StatusCode
+	}
+	if cl.retryRateLimitOnly && statusCode != http.StatusTooManyRequests {
+		return false
+	}
+	return defaultShouldRetry(ctx, method, statusCode)
+}
+
+// defaultShouldRetry returns whether a failed attempt may safely be retried.
+// Rate limited requests were not processed so they are always retried. Server
+// and network errors are only retried for GET requests, or requests marked
+// with WithRetrySafe, since other requests may have had side effects.
+func defaultShouldRetry(ctx context.Context, method string, statusCode int) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if method != http.MethodGet && !retrySafeFromContext(ctx) {
+		return false
+	}
+	return statusCode == 0 || statusCode >= http.StatusInternalServerError
 }
 
 func makeUserAgent() string {
 	return fmt.Sprintf("LunoGoSDK/%s %s %s %s",
 		Version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
 }
+
+// userAgent returns the User-Agent header of requests, including any suffix
+// set with WithUserAgentSuffix.
+func (cl *Client) userAgent() string {
+	if cl.userAgentSuffix == "" {
+		return makeUserAgent()
+	}
+	return makeUserAgent() + " " + cl.userAgentSuffix
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -200,3 +205,557 @@ func TestDoJSONError(t *testing.T) {
 			str400, err.Error())
 	}
 }
+
+func TestDoRetry(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetMaxRetries(2)
+	cl.SetRetryBackoff(ConstantBackoff(time.Millisecond))
+
+	var res interface{}
+	err := cl.do(context.Background(), "POST", "/", nil, &res, false)
+	if err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestDoCallStats(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetMaxRetries(5)
+	cl.SetRetryBackoff(ConstantBackoff(time.Millisecond))
+
+	var stats CallStats
+	var res interface{}
+	err := cl.do(WithCallStats(context.Background(), &stats), "GET", "/", nil, &res, false)
+	if err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+	if stats.Attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", stats.Attempts)
+	}
+}
+
+func TestDoRetrySafe(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetMaxRetries(2)
+	cl.SetRetryBackoff(ConstantBackoff(time.Millisecond))
+
+	var res interface{}
+	err := cl.do(context.Background(), "POST", "/", nil, &res, false)
+	if err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected POST not to be retried, got %d attempts", attempts)
+	}
+
+	attempts = 0
+	err = cl.do(WithRetrySafe(context.Background()), "POST", "/", nil, &res, false)
+	if err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected retry-safe POST to be retried, got %d attempts", attempts)
+	}
+}
+
+func TestDoSingleAttempt(t *testing.T) {
+	helpers := map[string]func(context.Context) context.Context{
+		"WithNoRetry":       WithNoRetry,
+		"WithSingleAttempt": WithSingleAttempt,
+	}
+	for name, with := range helpers {
+		t.Run(name, func(t *testing.T) {
+			var attempts int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(http.StatusTooManyRequests)
+			}))
+			defer srv.Close()
+
+			cl := NewClient()
+			cl.SetBaseURL(srv.URL)
+			cl.SetMaxRetries(5)
+			cl.SetRetryBackoff(ConstantBackoff(time.Hour))
+
+			var stats CallStats
+			ctx := WithCallStats(with(context.Background()), &stats)
+
+			var res interface{}
+			start := time.Now()
+			err := cl.do(ctx, "GET", "/", nil, &res, false)
+			if err == nil {
+				t.Errorf("Expected error, got nil")
+			}
+			if d := time.Since(start); d > time.Second {
+				t.Errorf("Expected to return immediately, took %s", d)
+			}
+			if attempts != 1 {
+				t.Errorf("Expected 1 attempt, got %d", attempts)
+			}
+			if stats.Attempts != 1 {
+				t.Errorf("Expected 1 attempt in stats, got %d", stats.Attempts)
+			}
+		})
+	}
+}
+
+func TestDoRetryPredicate(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch r.URL.Path {
+		case "/transient":
+			if attempts < 3 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"try again","error_code":"ErrTransient"}`))
+				return
+			}
+			w.Write([]byte("{}"))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetMaxRetries(5)
+	cl.SetRetryBackoff(ConstantBackoff(time.Millisecond))
+
+	var seen []int
+	cl.SetRetryPredicate(func(attempt int, resp *http.Response, err error) bool {
+		seen = append(seen, attempt)
+		return resp != nil && resp.StatusCode == http.StatusBadRequest &&
+			IsErrorCode(err, "ErrTransient")
+	})
+
+	var res interface{}
+	err := cl.do(context.Background(), "GET", "/transient", nil, &res, false)
+	if err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+	if !reflect.DeepEqual(seen, []int{0, 1}) {
+		t.Errorf("Expected predicate to see attempts [0 1], got %v", seen)
+	}
+
+	// Server errors on GET requests are retried by default, but not by this
+	// predicate.
+	attempts = 0
+	err = cl.do(context.Background(), "GET", "/unavailable", nil, &res, false)
+	if err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected no retries, got %d attempts", attempts)
+	}
+
+	cl.SetRetryPredicate(nil)
+	attempts = 0
+	_ = cl.do(context.Background(), "GET", "/unavailable", nil, &res, false)
+	if attempts != 6 {
+		t.Errorf("Expected built-in retries to be restored, got %d attempts", attempts)
+	}
+}
+
+func TestDoRequestOptions(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		switch r.URL.Path {
+		case "/headers":
+			json.NewEncoder(w).Encode(map[string]string{
+				"value": r.Header.Get("X-Test"),
+			})
+		case "/slow":
+			time.Sleep(100 * time.Millisecond)
+			w.Write([]byte("{}"))
+		default:
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetMaxRetries(2)
+
+	t.Run("extra headers", func(t *testing.T) {
+		ctx := WithRequestOptions(context.Background(), RequestOptions{
+			ExtraHeaders: http.Header{"X-Test": []string{"foo"}},
+		})
+		var res struct {
+			Value string `json:"value"`
+		}
+		err := cl.do(ctx, "GET", "/headers", nil, &res, false)
+		if err != nil {
+			t.Errorf("Expected success, got %v", err)
+		}
+		if res.Value != "foo" {
+			t.Errorf("Expected header %q, got %q", "foo", res.Value)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ctx := WithRequestOptions(context.Background(), RequestOptions{
+			Timeout: 10 * time.Millisecond,
+		})
+		var res interface{}
+		err := cl.do(ctx, "GET", "/slow", nil, &res, false)
+		if err == nil {
+			t.Errorf("Expected timeout error, got nil")
+		}
+	})
+
+	t.Run("disable retry", func(t *testing.T) {
+		atomic.StoreInt32(&attempts, 0)
+		ctx := WithRequestOptions(context.Background(), RequestOptions{
+			DisableRetry: true,
+		})
+		var res interface{}
+		err := cl.do(ctx, "GET", "/limited", nil, &res, false)
+		if err == nil {
+			t.Errorf("Expected error, got nil")
+		}
+		if n := atomic.LoadInt32(&attempts); n != 1 {
+			t.Errorf("Expected 1 attempt, got %d", n)
+		}
+	})
+}
+
+func TestRequestOptionHelpers(t *testing.T) {
+	base := WithHeader(context.Background(), "X-Test", "foo")
+	ctx := WithRequestTimeout(WithNoRetry(base), time.Second)
+	ctx = WithHeader(ctx, "X-Test", "bar")
+	ctx = WithHeader(ctx, "X-Trace", "abc")
+
+	opts := requestOptionsFromContext(ctx)
+	if !opts.DisableRetry || opts.Timeout != time.Second {
+		t.Errorf("Expected options to compose, got %+v", opts)
+	}
+	if v := opts.ExtraHeaders["X-Test"]; len(v) != 2 || v[0] != "foo" || v[1] != "bar" {
+		t.Errorf("Expected both X-Test headers, got %v", v)
+	}
+	if v := opts.ExtraHeaders.Get("X-Trace"); v != "abc" {
+		t.Errorf("Expected X-Trace header, got %q", v)
+	}
+	if v := requestOptionsFromContext(base).ExtraHeaders["X-Test"]; len(v) != 1 {
+		t.Errorf("Expected parent context's headers to be unchanged, got %v", v)
+	}
+}
+
+func TestDoTruncatedResponse(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts > 1 {
+			w.Write([]byte(`{"value":"test"}`))
+			return
+		}
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack failed: %v", err)
+			return
+		}
+		buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 100\r\n\r\n{\"value\":")
+		buf.Flush()
+		conn.Close()
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetMaxRetries(1)
+	cl.SetRetryBackoff(ConstantBackoff(time.Millisecond))
+
+	var res struct {
+		Value string `json:"value"`
+	}
+	err := cl.do(context.Background(), "GET", "/", nil, &res, false)
+	if err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+	if res.Value != "test" {
+		t.Errorf("Expected %q, got %q", "test", res.Value)
+	}
+}
+
+func TestDoMaxResponseBytes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"value":"test"}`))
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetMaxResponseBytes(5)
+
+	var res interface{}
+	err := cl.do(context.Background(), "GET", "/", nil, &res, false)
+	if err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
+
+func TestDoReusesConnections(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/invalid":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("this is invalid JSON"))
+		case "/large":
+			w.Write([]byte(strings.Repeat(" ", 1<<20) + "{}"))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"bad","error_code":"ErrBad"}`))
+		}
+	}))
+	var conns int32
+	srv.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetMaxResponseBytes(100)
+
+	for _, path := range []string{"/invalid", "/error", "/large", "/invalid"} {
+		var res interface{}
+		if err := cl.do(context.Background(), "GET", path, nil, &res, false); err == nil {
+			t.Errorf("Expected error for %s, got nil", path)
+		}
+	}
+	if n := atomic.LoadInt32(&conns); n != 1 {
+		t.Errorf("Expected 1 connection, got %d", n)
+	}
+}
+
+func TestSetAuthConcurrent(t *testing.T) {
+	var mismatched int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || strings.TrimPrefix(id, "key") != strings.TrimPrefix(secret, "secret") {
+			atomic.AddInt32(&mismatched, 1)
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	if err := cl.SetAuth("key0", "secret0"); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			n := strconv.Itoa(i)
+			if err := cl.SetAuth("key"+n, "secret"+n); err != nil {
+				t.Errorf("Expected success, got %v", err)
+			}
+		}
+	}()
+
+	var reqs sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		reqs.Add(1)
+		go func() {
+			defer reqs.Done()
+			for j := 0; j < 20; j++ {
+				var res interface{}
+				if err := cl.do(context.Background(), "GET", "/", nil, &res, true); err != nil {
+					t.Errorf("Expected success, got %v", err)
+				}
+			}
+		}()
+	}
+	reqs.Wait()
+	close(done)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&mismatched); n != 0 {
+		t.Errorf("Expected no mismatched credentials, got %d", n)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestDoEndpointTimeouts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	// Record how long each request has left before its deadline.
+	var remaining time.Duration
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetHTTPClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		remaining = 0
+		if d, ok := r.Context().Deadline(); ok {
+			remaining = time.Until(d)
+		}
+		return http.DefaultTransport.RoundTrip(r)
+	})})
+
+	const read, write = time.Second, time.Hour
+	cl.SetReadTimeout(read)
+	cl.SetWriteTimeout(write)
+
+	deadlineCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	optsCtx := WithRequestOptions(context.Background(), RequestOptions{Timeout: time.Minute})
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		exp    time.Duration
+	}{
+		{"read", context.Background(), "GET", read},
+		{"write", context.Background(), "POST", write},
+		{"delete", context.Background(), "DELETE", write},
+		{"context deadline", deadlineCtx, "GET", time.Minute},
+		{"request options", optsCtx, "POST", time.Minute},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var res interface{}
+			err := cl.do(test.ctx, test.method, "/", nil, &res, false)
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if remaining <= test.exp-time.Second/2 || remaining > test.exp {
+				t.Errorf("Expected deadline in %v, got %v", test.exp, remaining)
+			}
+		})
+	}
+
+	cl.SetReadTimeout(0)
+	var res interface{}
+	if err := cl.do(context.Background(), "GET", "/", nil, &res, false); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("Expected no deadline, got %v", remaining)
+	}
+}
+
+func TestDoBaseURLOverride(t *testing.T) {
+	newServer := func(name string, hits *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(hits, 1)
+			json.NewEncoder(w).Encode(map[string]string{"host": name})
+		}))
+	}
+	var defaultHits, canaryHits int32
+	def := newServer("default", &defaultHits)
+	defer def.Close()
+	canary := newServer("canary", &canaryHits)
+	defer canary.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(def.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, exp := context.Background(), "default"
+			if i%2 == 0 {
+				ctx, exp = WithBaseURLOverride(ctx, canary.URL+"/"), "canary"
+			}
+			var res struct {
+				Host string `json:"host"`
+			}
+			if err := cl.do(ctx, "GET", "/", nil, &res, false); err != nil {
+				t.Errorf("Expected success, got %v", err)
+				return
+			}
+			if res.Host != exp {
+				t.Errorf("Expected request to go to %s, got %s", exp, res.Host)
+			}
+		}(i)
+	}
+	wg.Wait()
+	d, c := atomic.LoadInt32(&defaultHits), atomic.LoadInt32(&canaryHits)
+	if d != 5 || c != 5 {
+		t.Errorf("Expected 5 requests to each host, got %d and %d", d, c)
+	}
+	if cl.baseURL != def.URL {
+		t.Errorf("Expected base URL to be unchanged, got %s", cl.baseURL)
+	}
+
+	for _, u := range []string{"", "canary.example.com", "ftp://canary.example.com", "http://"} {
+		ctx := WithBaseURLOverride(context.Background(), u)
+		var res interface{}
+		if err := cl.do(ctx, "GET", "/", nil, &res, false); err == nil {
+			t.Errorf("Expected error for override %q, got nil", u)
+		}
+	}
+	if n := atomic.LoadInt32(&defaultHits) + atomic.LoadInt32(&canaryHits); n != 10 {
+		t.Errorf("Expected invalid overrides not to be sent, got %d requests", n)
+	}
+}
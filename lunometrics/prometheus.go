@@ -0,0 +1,144 @@
+// Package lunometrics provides metrics collectors for the Luno API client.
+// It is a separate module so that the Luno API client doesn't depend on any
+// metrics library.
+package lunometrics
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/streaming"
+)
+
+// PrometheusCollector is a luno.MetricsCollector which exports request
+// metrics to Prometheus. Metrics are labelled by endpoint path template,
+// method and HTTP status class, e.g. "2xx". Requests which received no
+// response have a status class of "none". Time spent throttled is labelled by
+// endpoint, method and reason, errors returned by the API by endpoint, method
+// and error code, and reconnects of streaming connections by pair.
+type PrometheusCollector struct {
+	requests   *prometheus.CounterVec
+	errors     *prometheus.CounterVec
+	apiErrors  *prometheus.CounterVec
+	latency    *prometheus.HistogramVec
+	throttled  *prometheus.CounterVec
+	reconnects *prometheus.CounterVec
+}
+
+var (
+	_ luno.MetricsCollector  = (*PrometheusCollector)(nil)
+	_ luno.ThrottleCollector = (*PrometheusCollector)(nil)
+)
+
+// NewPrometheusCollector returns a PrometheusCollector whose metrics are
+// registered with reg.
+func NewPrometheusCollector(reg prometheus.Registerer) (*PrometheusCollector, error) {
+	labels := []string{"endpoint", "method", "status_class"}
+	c := &PrometheusCollector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "luno",
+			Name:      "requests_total",
+			Help:      "Number of Luno API request attempts.",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "luno",
+			Name:      "request_errors_total",
+			Help:      "Number of failed Luno API request attempts.",
+		}, labels),
+		apiErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "luno",
+			Name:      "api_errors_total",
+			Help:      "Number of Luno API request attempts which failed with an API error.",
+		}, []string{"endpoint", "method", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "luno",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of Luno API request attempts.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		throttled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "luno",
+			Name:      "throttled_seconds_total",
+			Help:      "Time Luno API requests spent delayed by rate limiting.",
+		}, []string{"endpoint", "method", "reason"}),
+		reconnects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "luno",
+			Name:      "streaming_reconnects_total",
+			Help:      "Number of reconnects of Luno streaming connections.",
+		}, []string{"pair"}),
+	}
+	for _, col := range []prometheus.Collector{
+		c.requests, c.errors, c.apiErrors, c.latency, c.throttled, c.reconnects,
+	} {
+		if err := reg.Register(col); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// ObserveRequest records a request attempt.
+func (c *PrometheusCollector) ObserveRequest(info luno.ResponseInfo) {
+	labels := prometheus.Labels{
+		"endpoint":     info.Path,
+		"method":       info.Method,
+		"status_class": statusClass(info.StatusCode),
+	}
+	c.requests.With(labels).Inc()
+	if info.Err != nil {
+		c.errors.With(labels).Inc()
+		if code, ok := errorCode(info.Err); ok {
+			c.apiErrors.With(prometheus.Labels{
+				"endpoint": info.Path,
+				"method":   info.Method,
+				"code":     code,
+			}).Inc()
+		}
+	}
+	c.latency.With(labels).Observe(info.Duration.Seconds())
+}
+
+// ObserveThrottle records a delay due to rate limiting.
+func (c *PrometheusCollector) ObserveThrottle(info luno.ThrottleInfo) {
+	c.throttled.With(prometheus.Labels{
+		"endpoint": info.Path,
+		"method":   info.Method,
+		"reason":   string(info.Reason),
+	}).Add(info.Wait.Seconds())
+}
+
+// ReconnectCallback returns a callback for streaming.WithReconnectCallback
+// which counts the reconnects of a streaming connection.
+//
+// Example:
+//
+//	conn, err := streaming.Dial(keyID, keySecret, "XBTZAR",
+//		streaming.WithReconnectCallback(c.ReconnectCallback()))
+func (c *PrometheusCollector) ReconnectCallback() streaming.ReconnectCallback {
+	return func(conn *streaming.Conn, delay time.Duration, err error) {
+		c.reconnects.With(prometheus.Labels{"pair": conn.Pair()}).Inc()
+	}
+}
+
+// errorCode returns the error code of err if it was returned by the API.
+func errorCode(err error) (string, bool) {
+	var e luno.Error
+	if errors.As(err, &e) && e.Code != "" {
+		return e.Code, true
+	}
+	if errors.Is(err, luno.ErrRateLimited) {
+		return luno.ErrCodeTooManyRequests, true
+	}
+	return "", false
+}
+
+func statusClass(code int) string {
+	if code < 100 || code > 999 {
+		return "none"
+	}
+	return strconv.Itoa(code/100) + "xx"
+}
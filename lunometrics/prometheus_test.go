@@ -0,0 +1,162 @@
+package lunometrics_test
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/lunometrics"
+	"github.com/luno/luno-go/streaming"
+)
+
+func TestPrometheusCollector(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/1/ticker" {
+			w.Write([]byte(`{"pair":"XBTZAR"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"Not found","error_code":"ErrNotFound"}`))
+	}))
+	defer srv.Close()
+
+	reg := prometheus.NewRegistry()
+	c, err := lunometrics.NewPrometheusCollector(reg)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetMetricsCollector(c)
+
+	ctx := context.Background()
+	if _, err := cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if _, err := cl.GetWithdrawal(ctx, &luno.GetWithdrawalRequest{Id: 1}); err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	got := make(map[string][]string)
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			var labels []string
+			for _, l := range m.GetLabel() {
+				labels = append(labels, l.GetName()+"="+l.GetValue())
+			}
+			got[mf.GetName()] = append(got[mf.GetName()], strings.Join(labels, ","))
+		}
+		sort.Strings(got[mf.GetName()])
+	}
+
+	ok := "endpoint=/api/1/ticker,method=GET,status_class=2xx"
+	notFound := "endpoint=/api/1/withdrawals/{id},method=GET,status_class=4xx"
+	exp := map[string][]string{
+		"luno_requests_total":           {ok, notFound},
+		"luno_request_errors_total":     {notFound},
+		"luno_request_duration_seconds": {ok, notFound},
+		"luno_api_errors_total": {
+			"code=ErrNotFound,endpoint=/api/1/withdrawals/{id},method=GET",
+		},
+	}
+	for name, labels := range exp {
+		if strings.Join(got[name], ";") != strings.Join(labels, ";") {
+			t.Errorf("Expected %s with labels %v, got %v", name, labels, got[name])
+		}
+	}
+}
+
+func TestPrometheusCollectorThrottle(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	c, err := lunometrics.NewPrometheusCollector(reg)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	info := luno.ThrottleInfo{
+		RequestInfo: luno.RequestInfo{Method: "GET", Path: "/api/1/ticker"},
+		Reason:      luno.ThrottleServer,
+		Wait:        1500 * time.Millisecond,
+	}
+	c.ObserveThrottle(info)
+	c.ObserveThrottle(info)
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	for _, mf := range mfs {
+		if mf.GetName() != "luno_throttled_seconds_total" {
+			continue
+		}
+		if v := mf.GetMetric()[0].GetCounter().GetValue(); v != 3 {
+			t.Errorf("Expected 3s throttled, got %v", v)
+		}
+		return
+	}
+	t.Errorf("Expected luno_throttled_seconds_total to be gathered")
+}
+
+func TestPrometheusCollectorReconnects(t *testing.T) {
+	srv := httptest.NewServer(nil)
+	srv.Close()
+
+	host := flag.Lookup("luno_websocket_host").Value.String()
+	flag.Set("luno_websocket_host", "ws"+strings.TrimPrefix(srv.URL, "http"))
+	defer flag.Set("luno_websocket_host", host)
+
+	reg := prometheus.NewRegistry()
+	c, err := lunometrics.NewPrometheusCollector(reg)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	gaveUp := make(chan struct{})
+	conn, err := streaming.Dial("key", "secret", "XBTZAR",
+		streaming.WithReconnectCallback(c.ReconnectCallback()),
+		streaming.WithMaxReconnectAttempts(1, func(*streaming.Conn, error) {
+			close(gaveUp)
+		}))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	defer conn.Close()
+	<-gaveUp
+
+	// Giving up doesn't count as a reconnect, so simulate two.
+	fn := c.ReconnectCallback()
+	fn(conn, time.Second, nil)
+	fn(conn, time.Second, nil)
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	for _, mf := range mfs {
+		if mf.GetName() != "luno_streaming_reconnects_total" {
+			continue
+		}
+		m := mf.GetMetric()[0]
+		if l := m.GetLabel()[0]; l.GetName() != "pair" || l.GetValue() != "XBTZAR" {
+			t.Errorf("Expected pair=XBTZAR, got %s=%s", l.GetName(), l.GetValue())
+		}
+		if v := m.GetCounter().GetValue(); v != 2 {
+			t.Errorf("Expected 2 reconnects, got %v", v)
+		}
+		return
+	}
+	t.Errorf("Expected luno_streaming_reconnects_total to be gathered")
+}
@@ -0,0 +1,170 @@
+// Package lunotest provides a fake implementation of luno.API, so that code
+// using the Luno client can be unit tested without network access.
+//
+// Depend on luno.API rather than *luno.Client, then give the code a Fake in
+// tests:
+//
+//	f := lunotest.NewFake()
+//	f.SetResponse("GetTicker", &luno.GetTickerResponse{Pair: "XBTZAR", ...})
+//	f.SetError("PostLimitOrder", luno.ErrInsufficientBalance)
+//
+//	bot := NewBot(f)
+//	// Exercise the bot...
+//
+//	if calls := f.CallsTo("PostLimitOrder"); len(calls) != 1 {
+//		t.Errorf("Expected one order, got %d", len(calls))
+//	}
+//
+// Methods are named as in luno.API. Calls without a configured response
+// succeed with an empty response.
+package lunotest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	luno "github.com/luno/luno-go"
+)
+
+var _ luno.API = (*Fake)(nil)
+
+// Call is a recorded call of a Fake.
+type Call struct {
+	// Method is the name of the luno.API method called.
+	Method string
+
+	// Req is the request passed to the method, e.g. *luno.GetTickerRequest.
+	Req interface{}
+}
+
+// HandlerFunc computes the response of a call from its request. It must
+// return a response of the method's response type, e.g.
+// *luno.GetTickerResponse, or an error.
+type HandlerFunc func(ctx context.Context, req interface{}) (interface{}, error)
+
+// Fake is a configurable implementation of luno.API which records its calls.
+// It is safe for concurrent use.
+type Fake struct {
+	mu        sync.Mutex
+	responses map[string]interface{}
+	errs      map[string]error
+	queued    map[string][]error
+	handlers  map[string]HandlerFunc
+	calls     []Call
+}
+
+// NewFake returns a Fake with no responses configured.
+func NewFake() *Fake {
+	f := &Fake{}
+	f.Reset()
+	return f
+}
+
+// SetResponse sets the response returned by every call of method. res must
+// be of the method's response type, e.g. *luno.GetTickerResponse. Each call
+// returns a shallow copy of res.
+func (f *Fake) SetResponse(method string, res interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[method] = res
+}
+
+// SetError makes every call of method fail with err. A nil err clears the
+// error.
+func (f *Fake) SetError(method string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errs[method] = err
+}
+
+// FailNext makes the next len(errs) calls of method fail with errs in turn,
+// before any error set with SetError applies. It can be used to test
+// retries.
+func (f *Fake) FailNext(method string, errs ...error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queued[method] = append(f.queued[method], errs...)
+}
+
+// Handle makes calls of method return the result of fn, overriding any
+// response set with SetResponse. Errors set with SetError or FailNext still
+// take precedence.
+func (f *Fake) Handle(method string, fn HandlerFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[method] = fn
+}
+
+// Calls returns the calls made so far, in order.
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// CallsTo returns the calls of method made so far, in order.
+func (f *Fake) CallsTo(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []Call
+	for _, c := range f.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset clears the configured responses, errors and handlers, and the
+// recorded calls.
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses = make(map[string]interface{})
+	f.errs = make(map[string]error)
+	f.queued = make(map[string][]error)
+	f.handlers = make(map[string]HandlerFunc)
+	f.calls = nil
+}
+
+// call records a call of method and stores its configured response in res.
+func (f *Fake) call(ctx context.Context, method string, req, res interface{}) error {
+	f.mu.Lock()
+	f.calls = append(f.calls, Call{Method: method, Req: req})
+	err := f.errs[method]
+	if q := f.queued[method]; len(q) > 0 {
+		err, f.queued[method] = q[0], q[1:]
+	}
+	canned, handler := f.responses[method], f.handlers[method]
+	f.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if handler != nil {
+		var err error
+		canned, err = handler(ctx, req)
+		if err != nil {
+			return err
+		}
+	}
+	if canned == nil {
+		return nil
+	}
+
+	v := reflect.ValueOf(canned)
+	if v.Type() != reflect.TypeOf(res) {
+		panic(fmt.Sprintf("lunotest: response for %s is %T, want %T",
+			method, canned, res))
+	}
+	if v.IsNil() {
+		return nil
+	}
+	reflect.ValueOf(res).Elem().Set(v.Elem())
+	return nil
+}
@@ -0,0 +1,96 @@
+package lunotest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-go/lunotest"
+)
+
+func TestFakeResponses(t *testing.T) {
+	f := lunotest.NewFake()
+	f.SetResponse("GetTicker", &luno.GetTickerResponse{
+		Pair: "XBTZAR",
+		Bid:  decimal.NewFromInt64(100),
+	})
+
+	var api luno.API = f
+	ctx := context.Background()
+	res, err := api.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if res.Pair != "XBTZAR" || res.Bid.String() != "100" {
+		t.Errorf("Unexpected response %+v", res)
+	}
+	res.Pair = "ETHZAR"
+
+	res, err = api.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"})
+	if err != nil || res.Pair != "XBTZAR" {
+		t.Errorf("Expected configured response to be unchanged, got %+v, %v", res, err)
+	}
+
+	bal, err := api.GetBalances(ctx, &luno.GetBalancesRequest{})
+	if err != nil || bal == nil || len(bal.Balance) != 0 {
+		t.Errorf("Expected empty response, got %+v, %v", bal, err)
+	}
+}
+
+func TestFakeErrors(t *testing.T) {
+	f := lunotest.NewFake()
+	errTimeout := errors.New("timeout")
+	f.SetError("PostLimitOrder", luno.ErrInsufficientBalance)
+	f.FailNext("PostLimitOrder", errTimeout)
+
+	ctx := context.Background()
+	req := &luno.PostLimitOrderRequest{Pair: "XBTZAR"}
+	if _, err := f.PostLimitOrder(ctx, req); err != errTimeout {
+		t.Errorf("Expected queued error first, got %v", err)
+	}
+	if _, err := f.PostLimitOrder(ctx, req); !errors.Is(err, luno.ErrInsufficientBalance) {
+		t.Errorf("Expected ErrInsufficientBalance, got %v", err)
+	}
+
+	f.SetError("PostLimitOrder", nil)
+	f.SetResponse("PostLimitOrder", &luno.PostLimitOrderResponse{OrderId: "BXMC2CJ7HNB88U4"})
+	res, err := f.PostLimitOrder(ctx, req)
+	if err != nil || res.OrderId != "BXMC2CJ7HNB88U4" {
+		t.Errorf("Expected order, got %+v, %v", res, err)
+	}
+
+	calls := f.CallsTo("PostLimitOrder")
+	if len(calls) != 3 || calls[0].Req != req {
+		t.Errorf("Expected 3 recorded calls with the request, got %+v", calls)
+	}
+}
+
+func TestFakeHandle(t *testing.T) {
+	f := lunotest.NewFake()
+	f.Handle("GetOrder", func(ctx context.Context, req interface{}) (interface{}, error) {
+		id := req.(*luno.GetOrderRequest).Id
+		if id == "" {
+			return nil, errors.New("missing id")
+		}
+		return &luno.GetOrderResponse{OrderId: id}, nil
+	})
+
+	ctx := context.Background()
+	res, err := f.GetOrder(ctx, &luno.GetOrderRequest{Id: "BXHW6PFRRXKFSB4"})
+	if err != nil || res.OrderId != "BXHW6PFRRXKFSB4" {
+		t.Errorf("Expected handled response, got %+v, %v", res, err)
+	}
+	if _, err := f.GetOrder(ctx, &luno.GetOrderRequest{}); err == nil {
+		t.Errorf("Expected handler error")
+	}
+
+	f.SetResponse("GetTickers", &luno.GetTickerResponse{})
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic for a response of the wrong type")
+		}
+	}()
+	f.GetTickers(ctx, &luno.GetTickersRequest{})
+}
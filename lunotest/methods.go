@@ -0,0 +1,394 @@
+package lunotest
+
+import (
+	"context"
+
+	luno "github.com/luno/luno-go"
+)
+
+// CancelWithdrawal implements luno.API.
+func (f *Fake) CancelWithdrawal(ctx context.Context, req *luno.CancelWithdrawalRequest) (*luno.CancelWithdrawalResponse, error) {
+	var res luno.CancelWithdrawalResponse
+	if err := f.call(ctx, "CancelWithdrawal", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateAccount implements luno.API.
+func (f *Fake) CreateAccount(ctx context.Context, req *luno.CreateAccountRequest) (*luno.CreateAccountResponse, error) {
+	var res luno.CreateAccountResponse
+	if err := f.call(ctx, "CreateAccount", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateBeneficiary implements luno.API.
+func (f *Fake) CreateBeneficiary(ctx context.Context, req *luno.CreateBeneficiaryRequest) (*luno.CreateBeneficiaryResponse, error) {
+	var res luno.CreateBeneficiaryResponse
+	if err := f.call(ctx, "CreateBeneficiary", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateFundingAddress implements luno.API.
+func (f *Fake) CreateFundingAddress(ctx context.Context, req *luno.CreateFundingAddressRequest) (*luno.CreateFundingAddressResponse, error) {
+	var res luno.CreateFundingAddressResponse
+	if err := f.call(ctx, "CreateFundingAddress", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateQuote implements luno.API.
+func (f *Fake) CreateQuote(ctx context.Context, req *luno.CreateQuoteRequest) (*luno.CreateQuoteResponse, error) {
+	var res luno.CreateQuoteResponse
+	if err := f.call(ctx, "CreateQuote", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateWithdrawal implements luno.API.
+func (f *Fake) CreateWithdrawal(ctx context.Context, req *luno.CreateWithdrawalRequest) (*luno.CreateWithdrawalResponse, error) {
+	var res luno.CreateWithdrawalResponse
+	if err := f.call(ctx, "CreateWithdrawal", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteBeneficiary implements luno.API.
+func (f *Fake) DeleteBeneficiary(ctx context.Context, req *luno.DeleteBeneficiaryRequest) (*luno.DeleteBeneficiaryResponse, error) {
+	var res luno.DeleteBeneficiaryResponse
+	if err := f.call(ctx, "DeleteBeneficiary", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DiscardQuote implements luno.API.
+func (f *Fake) DiscardQuote(ctx context.Context, req *luno.DiscardQuoteRequest) (*luno.DiscardQuoteResponse, error) {
+	var res luno.DiscardQuoteResponse
+	if err := f.call(ctx, "DiscardQuote", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ExerciseQuote implements luno.API.
+func (f *Fake) ExerciseQuote(ctx context.Context, req *luno.ExerciseQuoteRequest) (*luno.ExerciseQuoteResponse, error) {
+	var res luno.ExerciseQuoteResponse
+	if err := f.call(ctx, "ExerciseQuote", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetBalances implements luno.API.
+func (f *Fake) GetBalances(ctx context.Context, req *luno.GetBalancesRequest) (*luno.GetBalancesResponse, error) {
+	var res luno.GetBalancesResponse
+	if err := f.call(ctx, "GetBalances", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetCandles implements luno.API.
+func (f *Fake) GetCandles(ctx context.Context, req *luno.GetCandlesRequest) (*luno.GetCandlesResponse, error) {
+	var res luno.GetCandlesResponse
+	if err := f.call(ctx, "GetCandles", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetFeeInfo implements luno.API.
+func (f *Fake) GetFeeInfo(ctx context.Context, req *luno.GetFeeInfoRequest) (*luno.GetFeeInfoResponse, error) {
+	var res luno.GetFeeInfoResponse
+	if err := f.call(ctx, "GetFeeInfo", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetFundingAddress implements luno.API.
+func (f *Fake) GetFundingAddress(ctx context.Context, req *luno.GetFundingAddressRequest) (*luno.GetFundingAddressResponse, error) {
+	var res luno.GetFundingAddressResponse
+	if err := f.call(ctx, "GetFundingAddress", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetMove implements luno.API.
+func (f *Fake) GetMove(ctx context.Context, req *luno.GetMoveRequest) (*luno.GetMoveResponse, error) {
+	var res luno.GetMoveResponse
+	if err := f.call(ctx, "GetMove", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetOrder implements luno.API.
+func (f *Fake) GetOrder(ctx context.Context, req *luno.GetOrderRequest) (*luno.GetOrderResponse, error) {
+	var res luno.GetOrderResponse
+	if err := f.call(ctx, "GetOrder", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetOrderBook implements luno.API.
+func (f *Fake) GetOrderBook(ctx context.Context, req *luno.GetOrderBookRequest) (*luno.GetOrderBookResponse, error) {
+	var res luno.GetOrderBookResponse
+	if err := f.call(ctx, "GetOrderBook", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetOrderBookFull implements luno.API.
+func (f *Fake) GetOrderBookFull(ctx context.Context, req *luno.GetOrderBookFullRequest) (*luno.GetOrderBookFullResponse, error) {
+	var res luno.GetOrderBookFullResponse
+	if err := f.call(ctx, "GetOrderBookFull", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetOrderV2 implements luno.API.
+func (f *Fake) GetOrderV2(ctx context.Context, req *luno.GetOrderV2Request) (*luno.GetOrderV2Response, error) {
+	var res luno.GetOrderV2Response
+	if err := f.call(ctx, "GetOrderV2", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetOrderV3 implements luno.API.
+func (f *Fake) GetOrderV3(ctx context.Context, req *luno.GetOrderV3Request) (*luno.GetOrderV3Response, error) {
+	var res luno.GetOrderV3Response
+	if err := f.call(ctx, "GetOrderV3", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetQuote implements luno.API.
+func (f *Fake) GetQuote(ctx context.Context, req *luno.GetQuoteRequest) (*luno.GetQuoteResponse, error) {
+	var res luno.GetQuoteResponse
+	if err := f.call(ctx, "GetQuote", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetServerTime implements luno.API.
+func (f *Fake) GetServerTime(ctx context.Context, req *luno.GetServerTimeRequest) (*luno.GetServerTimeResponse, error) {
+	var res luno.GetServerTimeResponse
+	if err := f.call(ctx, "GetServerTime", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetTicker implements luno.API.
+func (f *Fake) GetTicker(ctx context.Context, req *luno.GetTickerRequest) (*luno.GetTickerResponse, error) {
+	var res luno.GetTickerResponse
+	if err := f.call(ctx, "GetTicker", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetTickers implements luno.API.
+func (f *Fake) GetTickers(ctx context.Context, req *luno.GetTickersRequest) (*luno.GetTickersResponse, error) {
+	var res luno.GetTickersResponse
+	if err := f.call(ctx, "GetTickers", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetWithdrawal implements luno.API.
+func (f *Fake) GetWithdrawal(ctx context.Context, req *luno.GetWithdrawalRequest) (*luno.GetWithdrawalResponse, error) {
+	var res luno.GetWithdrawalResponse
+	if err := f.call(ctx, "GetWithdrawal", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListBeneficiaries implements luno.API.
+func (f *Fake) ListBeneficiaries(ctx context.Context, req *luno.ListBeneficiariesRequest) (*luno.ListBeneficiariesResponse, error) {
+	var res luno.ListBeneficiariesResponse
+	if err := f.call(ctx, "ListBeneficiaries", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListBeneficiariesResponse implements luno.API.
+func (f *Fake) ListBeneficiariesResponse(ctx context.Context, req *luno.ListBeneficiariesResponseRequest) (*luno.ListBeneficiariesResponseResponse, error) {
+	var res luno.ListBeneficiariesResponseResponse
+	if err := f.call(ctx, "ListBeneficiariesResponse", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListMoves implements luno.API.
+func (f *Fake) ListMoves(ctx context.Context, req *luno.ListMovesRequest) (*luno.ListMovesResponse, error) {
+	var res luno.ListMovesResponse
+	if err := f.call(ctx, "ListMoves", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListOrders implements luno.API.
+func (f *Fake) ListOrders(ctx context.Context, req *luno.ListOrdersRequest) (*luno.ListOrdersResponse, error) {
+	var res luno.ListOrdersResponse
+	if err := f.call(ctx, "ListOrders", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListOrdersV2 implements luno.API.
+func (f *Fake) ListOrdersV2(ctx context.Context, req *luno.ListOrdersV2Request) (*luno.ListOrdersV2Response, error) {
+	var res luno.ListOrdersV2Response
+	if err := f.call(ctx, "ListOrdersV2", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListPendingTransactions implements luno.API.
+func (f *Fake) ListPendingTransactions(ctx context.Context, req *luno.ListPendingTransactionsRequest) (*luno.ListPendingTransactionsResponse, error) {
+	var res luno.ListPendingTransactionsResponse
+	if err := f.call(ctx, "ListPendingTransactions", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListTrades implements luno.API.
+func (f *Fake) ListTrades(ctx context.Context, req *luno.ListTradesRequest) (*luno.ListTradesResponse, error) {
+	var res luno.ListTradesResponse
+	if err := f.call(ctx, "ListTrades", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListTransactions implements luno.API.
+func (f *Fake) ListTransactions(ctx context.Context, req *luno.ListTransactionsRequest) (*luno.ListTransactionsResponse, error) {
+	var res luno.ListTransactionsResponse
+	if err := f.call(ctx, "ListTransactions", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListUserTrades implements luno.API.
+func (f *Fake) ListUserTrades(ctx context.Context, req *luno.ListUserTradesRequest) (*luno.ListUserTradesResponse, error) {
+	var res luno.ListUserTradesResponse
+	if err := f.call(ctx, "ListUserTrades", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListWithdrawals implements luno.API.
+func (f *Fake) ListWithdrawals(ctx context.Context, req *luno.ListWithdrawalsRequest) (*luno.ListWithdrawalsResponse, error) {
+	var res luno.ListWithdrawalsResponse
+	if err := f.call(ctx, "ListWithdrawals", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Markets implements luno.API.
+func (f *Fake) Markets(ctx context.Context, req *luno.MarketsRequest) (*luno.MarketsResponse, error) {
+	var res luno.MarketsResponse
+	if err := f.call(ctx, "Markets", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Move implements luno.API.
+func (f *Fake) Move(ctx context.Context, req *luno.MoveRequest) (*luno.MoveResponse, error) {
+	var res luno.MoveResponse
+	if err := f.call(ctx, "Move", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// PostLimitOrder implements luno.API.
+func (f *Fake) PostLimitOrder(ctx context.Context, req *luno.PostLimitOrderRequest) (*luno.PostLimitOrderResponse, error) {
+	var res luno.PostLimitOrderResponse
+	if err := f.call(ctx, "PostLimitOrder", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// PostMarketOrder implements luno.API.
+func (f *Fake) PostMarketOrder(ctx context.Context, req *luno.PostMarketOrderRequest) (*luno.PostMarketOrderResponse, error) {
+	var res luno.PostMarketOrderResponse
+	if err := f.call(ctx, "PostMarketOrder", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Send implements luno.API.
+func (f *Fake) Send(ctx context.Context, req *luno.SendRequest) (*luno.SendResponse, error) {
+	var res luno.SendResponse
+	if err := f.call(ctx, "Send", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// SendFee implements luno.API.
+func (f *Fake) SendFee(ctx context.Context, req *luno.SendFeeRequest) (*luno.SendFeeResponse, error) {
+	var res luno.SendFeeResponse
+	if err := f.call(ctx, "SendFee", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// StopOrder implements luno.API.
+func (f *Fake) StopOrder(ctx context.Context, req *luno.StopOrderRequest) (*luno.StopOrderResponse, error) {
+	var res luno.StopOrderResponse
+	if err := f.call(ctx, "StopOrder", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateAccountName implements luno.API.
+func (f *Fake) UpdateAccountName(ctx context.Context, req *luno.UpdateAccountNameRequest) (*luno.UpdateAccountNameResponse, error) {
+	var res luno.UpdateAccountNameResponse
+	if err := f.call(ctx, "UpdateAccountName", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Validate implements luno.API.
+func (f *Fake) Validate(ctx context.Context, req *luno.ValidateRequest) (*luno.ValidateResponse, error) {
+	var res luno.ValidateResponse
+	if err := f.call(ctx, "Validate", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
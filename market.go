@@ -0,0 +1,185 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// marketsCacheTTL is how long market metadata returned by Markets is reused
+// by MarketInfo, TradeablePairs and IsPair.
+const marketsCacheTTL = time.Hour
+
+// IsTradeable returns whether a market with this trading status accepts
+// orders which may trade immediately. Unknown statuses are treated as not
+// tradeable.
+func (s TradingStatus) IsTradeable() bool {
+	return s == TradingStatusActive
+}
+
+// IsTradeable returns whether the market for pair is trading normally,
+// according to its ticker. Markets which are POSTONLY or DISABLED, or which
+// report an unknown status, are treated as not tradeable.
+func (cl *Client) IsTradeable(ctx context.Context, pair string) (bool, error) {
+	res, err := cl.GetTicker(ctx, &GetTickerRequest{Pair: pair})
+	if err != nil {
+		return false, err
+	}
+	return res.Status == StatusActive, nil
+}
+
+// TickSize returns the smallest price increment of the market. Luno doesn't
+// report increments explicitly so this is derived from PriceScale.
+func (m MarketInfo) TickSize() decimal.Decimal {
+	return decimal.New(big.NewInt(1), int(m.PriceScale))
+}
+
+// LotSize returns the smallest volume increment of the market. Luno doesn't
+// report increments explicitly so this is derived from VolumeScale.
+func (m MarketInfo) LotSize() decimal.Decimal {
+	return decimal.New(big.NewInt(1), int(m.VolumeScale))
+}
+
+// AmountRole says how an amount relates to a market, which determines the
+// scale it is formatted at.
+type AmountRole int
+
+const (
+	// RolePrice is a price in the counter currency, with PriceScale
+	// decimal places.
+	RolePrice AmountRole = iota
+
+	// RoleVolume is a volume in the base currency, with VolumeScale
+	// decimal places.
+	RoleVolume
+)
+
+// Format returns d formatted at the scale of the market for role, using
+// Decimal.StringFixed. For example, a price of 1234.5 on a market with a
+// price scale of 2 is formatted as "1234.50".
+func (m MarketInfo) Format(d decimal.Decimal, role AmountRole) string {
+	if role == RoleVolume {
+		return d.StringFixed(int(m.VolumeScale))
+	}
+	return d.StringFixed(int(m.PriceScale))
+}
+
+// FormatAmount returns d formatted at the scale of the market for pair, see
+// MarketInfo.Format. If the market metadata can't be fetched and the metadata
+// policy is to fail open, see SetMetadataPolicy, d is formatted as it is.
+func (cl *Client) FormatAmount(ctx context.Context, pair string,
+	d decimal.Decimal, role AmountRole) (string, error) {
+
+	m, err := cl.MarketInfo(ctx, pair)
+	if err != nil {
+		if err := cl.metadataUnavailable(ctx, "amount formatting", pair, err); err != nil {
+			return "", err
+		}
+		return d.String(), nil
+	}
+	return m.Format(d, role), nil
+}
+
+// marketsCache holds the result of Markets, keyed by market ID.
+type marketsCache struct {
+	mu      sync.Mutex
+	markets map[string]MarketInfo
+	fetched time.Time
+}
+
+// ErrUnknownMarket is returned, wrapped, by MarketInfo for a pair which isn't
+// the pair of a market listed by Markets.
+var ErrUnknownMarket = errors.New("luno: unknown market")
+
+// MarketInfo returns the parameters of the market for pair, such as its
+// minimum and maximum volume. The metadata of all markets is cached for an
+// hour.
+func (cl *Client) MarketInfo(ctx context.Context, pair string) (MarketInfo, error) {
+	markets, err := cl.markets(ctx)
+	if err != nil {
+		return MarketInfo{}, err
+	}
+	m, ok := markets[pair]
+	if !ok {
+		return MarketInfo{}, fmt.Errorf("%w %q", ErrUnknownMarket, pair)
+	}
+	return m, nil
+}
+
+// TradeablePairs returns the pairs of the markets listed by Markets whose
+// trading status is tradeable, in order, e.g. XBTZAR. It uses the same cache
+// as MarketInfo.
+func (cl *Client) TradeablePairs(ctx context.Context) ([]string, error) {
+	markets, err := cl.markets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pairs []string
+	for pair, m := range markets {
+		if m.TradingStatus.IsTradeable() {
+			pairs = append(pairs, pair)
+		}
+	}
+	sort.Strings(pairs)
+	return pairs, nil
+}
+
+// IsPair returns whether pair is the pair of a market listed by Markets, in
+// any trading status. It uses the same cache as MarketInfo.
+func (cl *Client) IsPair(ctx context.Context, pair string) (bool, error) {
+	markets, err := cl.markets(ctx)
+	if err != nil {
+		return false, err
+	}
+	_, ok := markets[pair]
+	return ok, nil
+}
+
+// markets returns the cached metadata of all markets by market ID, fetching
+// it if the cache is empty or expired. The map mustn't be modified.
+func (cl *Client) markets(ctx context.Context) (map[string]MarketInfo, error) {
+	c := &cl.marketsCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.markets == nil || cl.clock.Now().Sub(c.fetched) > marketsCacheTTL {
+		res, err := cl.Markets(ctx, &MarketsRequest{})
+		if err != nil {
+			return nil, err
+		}
+		c.markets = make(map[string]MarketInfo, len(res.Markets))
+		for _, m := range res.Markets {
+			c.markets[m.MarketId] = m
+		}
+		c.fetched = cl.clock.Now()
+	}
+	return c.markets, nil
+}
+
+// TickSize returns the smallest price increment of the market for pair. If
+// the market metadata can't be fetched and the metadata policy is to fail
+// open, see SetMetadataPolicy, zero is returned, i.e. no known increment.
+func (cl *Client) TickSize(ctx context.Context, pair string) (decimal.Decimal, error) {
+	m, err := cl.MarketInfo(ctx, pair)
+	if err != nil {
+		return decimal.Zero(), cl.metadataUnavailable(ctx, "tick size lookup", pair, err)
+	}
+	return m.TickSize(), nil
+}
+
+// LotSize returns the smallest volume increment of the market for pair. If
+// the market metadata can't be fetched and the metadata policy is to fail
+// open, see SetMetadataPolicy, zero is returned, i.e. no known increment.
+func (cl *Client) LotSize(ctx context.Context, pair string) (decimal.Decimal, error) {
+	m, err := cl.MarketInfo(ctx, pair)
+	if err != nil {
+		return decimal.Zero(), cl.metadataUnavailable(ctx, "lot size lookup", pair, err)
+	}
+	return m.LotSize(), nil
+}
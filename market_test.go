@@ -0,0 +1,272 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func TestIsTradeable(t *testing.T) {
+	testCases := []struct {
+		status string
+		exp    bool
+	}{
+		{status: "ACTIVE", exp: true},
+		{status: "POSTONLY", exp: false},
+		{status: "DISABLED", exp: false},
+		{status: "SOMETHING_NEW", exp: false},
+		{status: "", exp: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.status, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"pair":"XBTZAR","status":"` + tc.status + `"}`))
+			}))
+			defer srv.Close()
+
+			cl := luno.NewClient()
+			cl.SetBaseURL(srv.URL)
+
+			act, err := cl.IsTradeable(context.Background(), "XBTZAR")
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if act != tc.exp {
+				t.Errorf("Expected %t, got %t", tc.exp, act)
+			}
+		})
+	}
+}
+
+func TestTradingStatusIsTradeable(t *testing.T) {
+	testCases := []struct {
+		status luno.TradingStatus
+		exp    bool
+	}{
+		{status: luno.TradingStatusActive, exp: true},
+		{status: luno.TradingStatusPost_only, exp: false},
+		{status: luno.TradingStatusSuspended, exp: false},
+		{status: "UNKNOWN", exp: false},
+	}
+	for _, tc := range testCases {
+		if act := tc.status.IsTradeable(); act != tc.exp {
+			t.Errorf("Expected %s to be tradeable %t, got %t", tc.status, tc.exp, act)
+		}
+	}
+}
+
+func TestMarketIncrements(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/api/exchange/1/markets" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		w.Write([]byte(`{"markets":[
+			{"market_id":"XBTZAR","price_scale":0,"volume_scale":4,"min_volume":"0.0005","max_volume":"100"},
+			{"market_id":"ETHXBT","price_scale":6,"volume_scale":2,"min_volume":"0.01","max_volume":"1000"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	ctx := context.Background()
+
+	testCases := []struct {
+		pair       string
+		tick, lot  string
+		minV, maxV string
+	}{
+		{pair: "XBTZAR", tick: "1", lot: "0.0001", minV: "0.0005", maxV: "100"},
+		{pair: "ETHXBT", tick: "0.000001", lot: "0.01", minV: "0.01", maxV: "1000"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pair, func(t *testing.T) {
+			tick, err := cl.TickSize(ctx, tc.pair)
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if tick.String() != tc.tick {
+				t.Errorf("Expected tick size %s, got %s", tc.tick, tick)
+			}
+			lot, err := cl.LotSize(ctx, tc.pair)
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if lot.String() != tc.lot {
+				t.Errorf("Expected lot size %s, got %s", tc.lot, lot)
+			}
+			m, err := cl.MarketInfo(ctx, tc.pair)
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if m.MinVolume.String() != tc.minV || m.MaxVolume.String() != tc.maxV {
+				t.Errorf("Expected volume range %s-%s, got %s-%s",
+					tc.minV, tc.maxV, m.MinVolume, m.MaxVolume)
+			}
+		})
+	}
+
+	if _, err := cl.TickSize(ctx, "DOGEZAR"); err == nil {
+		t.Errorf("Expected error for unknown market, got nil")
+	}
+	if calls != 1 {
+		t.Errorf("Expected markets to be fetched once, got %d calls", calls)
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"markets":[
+			{"market_id":"XBTZAR","price_scale":0,"volume_scale":4},
+			{"market_id":"ETHXBT","price_scale":6,"volume_scale":2}
+		]}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	ctx := context.Background()
+
+	testCases := []struct {
+		pair string
+		d    string
+		role luno.AmountRole
+		exp  string
+	}{
+		{pair: "XBTZAR", d: "1234567.5", role: luno.RolePrice, exp: "1234568"},
+		{pair: "XBTZAR", d: "0.5", role: luno.RoleVolume, exp: "0.5000"},
+		{pair: "XBTZAR", d: "0.00012345", role: luno.RoleVolume, exp: "0.0001"},
+		{pair: "ETHXBT", d: "0.05", role: luno.RolePrice, exp: "0.050000"},
+		{pair: "ETHXBT", d: "12", role: luno.RoleVolume, exp: "12.00"},
+	}
+	for _, tc := range testCases {
+		d, err := decimal.NewFromString(tc.d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		act, err := cl.FormatAmount(ctx, tc.pair, d, tc.role)
+		if err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		if act != tc.exp {
+			t.Errorf("Expected %s on %s to format as %s, got %s", tc.d, tc.pair, tc.exp, act)
+		}
+	}
+
+	if _, err := cl.FormatAmount(ctx, "DOGEZAR", decimal.Zero(), luno.RolePrice); err == nil {
+		t.Errorf("Expected error for unknown market, got nil")
+	}
+}
+
+func TestMarketMetadataPolicy(t *testing.T) {
+	unavailable := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if unavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":"Unavailable","error_code":"ErrUnavailable"}`))
+			return
+		}
+		w.Write([]byte(`{"markets":[{"market_id":"XBTZAR","price_scale":0,"volume_scale":4}]}`))
+	}))
+	defer srv.Close()
+
+	var warnings []luno.Warning
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetHooks(luno.Hooks{Warning: func(ctx context.Context, w luno.Warning) {
+		warnings = append(warnings, w)
+	}})
+	ctx := context.Background()
+	d := decimal.NewFromFloat64(0.5, 1)
+
+	// Fail closed by default.
+	if _, err := cl.FormatAmount(ctx, "XBTZAR", d, luno.RoleVolume); err == nil {
+		t.Errorf("Expected FormatAmount error, got nil")
+	}
+	if _, err := cl.TickSize(ctx, "XBTZAR"); err == nil {
+		t.Errorf("Expected TickSize error, got nil")
+	}
+	if _, err := cl.LotSize(ctx, "XBTZAR"); err == nil {
+		t.Errorf("Expected LotSize error, got nil")
+	}
+
+	cl.SetMetadataPolicy(luno.MetadataFailOpen)
+	s, err := cl.FormatAmount(ctx, "XBTZAR", d, luno.RoleVolume)
+	if err != nil || s != "0.5" {
+		t.Errorf("Expected 0.5 unformatted, got %q, %v", s, err)
+	}
+	tick, err := cl.TickSize(ctx, "XBTZAR")
+	if err != nil || tick.Sign() != 0 {
+		t.Errorf("Expected zero tick size, got %s, %v", tick, err)
+	}
+	lot, err := cl.LotSize(ctx, "XBTZAR")
+	if err != nil || lot.Sign() != 0 {
+		t.Errorf("Expected zero lot size, got %s, %v", lot, err)
+	}
+	if len(warnings) != 3 {
+		t.Errorf("Expected 3 warnings, got %v", warnings)
+	}
+	for _, w := range warnings {
+		if w.Kind != luno.WarningValidationSkipped {
+			t.Errorf("Expected validation skipped warning, got %v", w)
+		}
+	}
+
+	// An unknown market isn't a metadata failure.
+	unavailable = false
+	_, err = cl.FormatAmount(ctx, "DOGEZAR", d, luno.RoleVolume)
+	if !errors.Is(err, luno.ErrUnknownMarket) {
+		t.Errorf("Expected ErrUnknownMarket, got %v", err)
+	}
+	if _, err := cl.TickSize(ctx, "DOGEZAR"); !errors.Is(err, luno.ErrUnknownMarket) {
+		t.Errorf("Expected ErrUnknownMarket, got %v", err)
+	}
+	if len(warnings) != 3 {
+		t.Errorf("Expected no more warnings, got %v", warnings)
+	}
+}
+
+func TestTradeablePairs(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"markets":[
+			{"market_id":"XBTZAR","trading_status":"ACTIVE"},
+			{"market_id":"ETHXBT","trading_status":"ACTIVE"},
+			{"market_id":"SOLZAR","trading_status":"POST_ONLY"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	ctx := context.Background()
+
+	pairs, err := cl.TradeablePairs(ctx)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if exp := []string{"ETHXBT", "XBTZAR"}; !reflect.DeepEqual(pairs, exp) {
+		t.Errorf("Expected %v, got %v", exp, pairs)
+	}
+	for pair, exp := range map[string]bool{"SOLZAR": true, "DOGEZAR": false} {
+		ok, err := cl.IsPair(ctx, pair)
+		if err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		if ok != exp {
+			t.Errorf("Expected IsPair(%s) to be %v", pair, exp)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected markets to be fetched once, got %d", calls)
+	}
+}
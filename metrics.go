@@ -0,0 +1,20 @@
+package luno
+
+// MetricsCollector records metrics about API requests. ObserveRequest is
+// called once for every attempt of a request, after it completes.
+type MetricsCollector interface {
+	ObserveRequest(info ResponseInfo)
+}
+
+// ThrottleCollector may be implemented by a MetricsCollector to also record
+// the time requests spend throttled. ObserveThrottle is called whenever the
+// Throttled hook is.
+type ThrottleCollector interface {
+	ObserveThrottle(info ThrottleInfo)
+}
+
+// SetMetricsCollector sets the collector which records metrics about the
+// requests made by the client.
+func (cl *Client) SetMetricsCollector(m MetricsCollector) {
+	cl.metrics = m
+}
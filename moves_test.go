@@ -0,0 +1,69 @@
+package luno_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func TestMoves(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/exchange/1/move":
+			if r.FormValue("debit_account_id") != "1" || r.FormValue("credit_account_id") != "2" ||
+				r.FormValue("amount") != "0.5" {
+				t.Errorf("Unexpected move %v", r.Form)
+			}
+			w.Write([]byte(`{"id":"M1","status":"CREATED"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/exchange/1/move":
+			if r.FormValue("id") != "M1" {
+				t.Errorf("Expected id M1, got %q", r.FormValue("id"))
+			}
+			if _, ok := r.Form["client_move_id"]; ok {
+				t.Errorf("Expected no client move id, got %q", r.FormValue("client_move_id"))
+			}
+			w.Write([]byte(`{"id":"M1","status":"SUCCESSFUL","amount":"0.5",` +
+				`"debit_account_id":1,"credit_account_id":2,"created_at":1000}`))
+		case r.URL.Path == "/api/exchange/1/move/list_moves":
+			w.Write([]byte(`{"moves":[{"id":"M1","status":"SUCCESSFUL"},{"id":"M0","status":"FAILED"}]}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL), luno.WithAuth("key", "secret"))
+
+	mv, err := cl.Move(ctx, &luno.MoveRequest{
+		Amount:          decimal.NewFromFloat64(0.5, 1),
+		DebitAccountId:  1,
+		CreditAccountId: 2,
+	})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if mv.Id != "M1" || mv.Status != luno.MoveStatusCreated {
+		t.Errorf("Expected created move M1, got %+v", mv)
+	}
+
+	got, err := cl.GetMove(ctx, &luno.GetMoveRequest{Id: mv.Id})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if got.Status != luno.MoveStatusSuccessful || got.Amount.String() != "0.5" || got.CreditAccountId != 2 {
+		t.Errorf("Expected successful move of 0.5 to account 2, got %+v", got)
+	}
+
+	list, err := cl.ListMoves(ctx, &luno.ListMovesRequest{Limit: 2})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(list.Moves) != 2 || list.Moves[1].Status != luno.MoveStatusFailed {
+		t.Errorf("Expected 2 moves, the second failed, got %+v", list.Moves)
+	}
+}
@@ -0,0 +1,188 @@
+package luno
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MultiClient makes requests for several Luno accounts, e.g. portfolios with
+// separate API keys, each identified by a label. Each account has its own
+// Client, cloned from a base client, so that caches such as those of account
+// IDs and fees aren't shared between accounts. It is safe for concurrent use.
+//
+// Example:
+//
+//	m := luno.NewMultiClient(luno.NewClient(luno.WithTimeout(5 * time.Second)))
+//	m.Add("growth", luno.CredentialProviderFunc(growthKey))
+//	m.Add("income", luno.CredentialProviderFunc(incomeKey))
+//	balances, err := m.GetBalances(ctx, &luno.GetBalancesRequest{})
+type MultiClient struct {
+	base *Client
+
+	mu      sync.RWMutex
+	clients map[string]*Client
+	limit   int
+}
+
+// NewMultiClient returns a MultiClient whose accounts are configured like
+// base.
+func NewMultiClient(base *Client) *MultiClient {
+	return &MultiClient{base: base, clients: make(map[string]*Client)}
+}
+
+// SetLimit sets the maximum number of accounts queried at once by ForEach and
+// the fan-out methods, such as GetBalances. Zero or less, the default,
+// queries all accounts at once.
+func (m *MultiClient) SetLimit(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.limit = n
+}
+
+// Add adds the account with the given label, whose requests are
+// authenticated with the API key from p, replacing any account with the same
+// label. opts are applied to the account's client on top of the base
+// client's configuration, see Client.Clone.
+//
+// The account's client shares the rate limiters of the base client, unless
+// opts include WithRateLimit. Luno limits requests per API key, so accounts
+// which make many requests should be given their own.
+func (m *MultiClient) Add(label string, p CredentialProvider, opts ...Option) *Client {
+	opts = append([]Option{WithCredentialProvider(p)}, opts...)
+	cl := m.base.Clone(opts...)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clients[label] = cl
+	return cl
+}
+
+// Remove removes the account with the given label.
+func (m *MultiClient) Remove(label string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.clients, label)
+}
+
+// Account returns the client of the account with the given label, which
+// makes requests for that account only.
+func (m *MultiClient) Account(label string) (*Client, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	cl, ok := m.clients[label]
+	return cl, ok
+}
+
+// Labels returns the labels of the accounts in order.
+func (m *MultiClient) Labels() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	labels := make([]string, 0, len(m.clients))
+	for label := range m.clients {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// AccountsError is returned by the fan-out methods of MultiClient if the
+// call failed for any account. The results of the other accounts are still
+// returned.
+type AccountsError struct {
+	// Errs maps the label of each account whose call failed to its error.
+	Errs map[string]error
+}
+
+func (e *AccountsError) Error() string {
+	label, err := e.first()
+	return fmt.Sprintf("luno: %d accounts failed, first: %s: %v", len(e.Errs), label, err)
+}
+
+// Unwrap returns the error of the first account which failed, by label.
+func (e *AccountsError) Unwrap() error {
+	_, err := e.first()
+	return err
+}
+
+func (e *AccountsError) first() (string, error) {
+	var first string
+	for label := range e.Errs {
+		if first == "" || label < first {
+			first = label
+		}
+	}
+	return first, e.Errs[first]
+}
+
+// ForEach calls fn with the label and client of each account concurrently,
+// at most the limit set with SetLimit at a time, and waits for the calls to
+// finish. If any call fails, an *AccountsError is returned.
+func (m *MultiClient) ForEach(ctx context.Context, fn func(ctx context.Context, label string, cl *Client) error) error {
+	m.mu.RLock()
+	limit := m.limit
+	labels := make([]string, 0, len(m.clients))
+	clients := make([]*Client, 0, len(m.clients))
+	for label, cl := range m.clients {
+		labels = append(labels, label)
+		clients = append(clients, cl)
+	}
+	m.mu.RUnlock()
+
+	calls := make([]func(context.Context) error, len(labels))
+	for i := range labels {
+		label, cl := labels[i], clients[i]
+		calls[i] = func(ctx context.Context) error {
+			return fn(ctx, label, cl)
+		}
+	}
+	err := Batch(ctx, limit, calls...)
+	batchErr, ok := err.(*BatchError)
+	if !ok {
+		return err
+	}
+	accErr := &AccountsError{Errs: make(map[string]error)}
+	for i, err := range batchErr.Errs {
+		if err != nil {
+			accErr.Errs[labels[i]] = err
+		}
+	}
+	return accErr
+}
+
+// GetBalances calls GetBalances for each account and returns the responses
+// by label.
+func (m *MultiClient) GetBalances(ctx context.Context, req *GetBalancesRequest) (map[string]*GetBalancesResponse, error) {
+	var mu sync.Mutex
+	res := make(map[string]*GetBalancesResponse)
+	err := m.ForEach(ctx, func(ctx context.Context, label string, cl *Client) error {
+		r, err := cl.GetBalances(ctx, req)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		res[label] = r
+		mu.Unlock()
+		return nil
+	})
+	return res, err
+}
+
+// ListOrders calls ListOrders for each account and returns the responses by
+// label.
+func (m *MultiClient) ListOrders(ctx context.Context, req *ListOrdersRequest) (map[string]*ListOrdersResponse, error) {
+	var mu sync.Mutex
+	res := make(map[string]*ListOrdersResponse)
+	err := m.ForEach(ctx, func(ctx context.Context, label string, cl *Client) error {
+		r, err := cl.ListOrders(ctx, req)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		res[label] = r
+		mu.Unlock()
+		return nil
+	})
+	return res, err
+}
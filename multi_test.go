@@ -0,0 +1,70 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestMultiClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _, _ := r.BasicAuth()
+		switch id {
+		case "growth", "income":
+			w.Write([]byte(`{"balance":[{"account_id":"` + id + `","asset":"XBT","balance":"1"}]}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"Unauthorised","error_code":"ErrUnauthorised"}`))
+		}
+	}))
+	defer srv.Close()
+
+	key := func(id string) luno.CredentialProvider {
+		return luno.CredentialProviderFunc(func(ctx context.Context) (string, string, error) {
+			return id, "secret", nil
+		})
+	}
+	m := luno.NewMultiClient(luno.NewClient(luno.WithBaseURL(srv.URL)))
+	m.Add("growth", key("growth"))
+	m.Add("income", key("income"))
+	m.Add("revoked", key("revoked"))
+	ctx := context.Background()
+
+	if labels := m.Labels(); !reflect.DeepEqual(labels, []string{"growth", "income", "revoked"}) {
+		t.Errorf("Expected labels in order, got %v", labels)
+	}
+
+	res, err := m.GetBalances(ctx, &luno.GetBalancesRequest{})
+	var accErr *luno.AccountsError
+	if !errors.As(err, &accErr) || len(accErr.Errs) != 1 || accErr.Errs["revoked"] == nil {
+		t.Fatalf("Expected revoked account to fail, got %v", err)
+	}
+	if !errors.Is(err, luno.ErrAuth) {
+		t.Errorf("Expected ErrAuth, got %v", err)
+	}
+	if len(res) != 2 || res["growth"].Balance[0].AccountId != "growth" ||
+		res["income"].Balance[0].AccountId != "income" {
+		t.Errorf("Expected balances of the other accounts by label, got %v", res)
+	}
+
+	m.Remove("revoked")
+	cl, ok := m.Account("income")
+	if !ok {
+		t.Fatalf("Expected income account")
+	}
+	b, err := cl.GetBalances(ctx, &luno.GetBalancesRequest{})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if b.Balance[0].AccountId != "income" {
+		t.Errorf("Expected income balance, got %s", b.Balance[0].AccountId)
+	}
+	if _, err := m.GetBalances(ctx, &luno.GetBalancesRequest{}); err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+}
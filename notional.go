@@ -0,0 +1,90 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// ErrMaxOrderNotional is returned, wrapped, when an order is rejected because
+// its notional exceeds the cap set with SetMaxOrderNotional.
+var ErrMaxOrderNotional = errors.New("luno: order exceeds maximum notional")
+
+// SetMaxOrderNotional caps the notional, i.e. the counter currency value, of
+// orders on markets with the given counter currency, e.g. "ZAR". Limit orders
+// whose price multiplied by volume exceeds max, and market orders estimated to
+// exceed it, are rejected by PostLimitOrder and PostMarketOrder without being
+// sent. A zero max removes the cap.
+//
+// Market orders for a base volume are estimated by walking the current full
+// order book, and the counter currency of each pair is looked up using
+// MarketInfo, so checked orders may take extra requests. If the market
+// metadata can't be fetched, the metadata policy decides whether the order is
+// sent unchecked, see SetMetadataPolicy.
+func (cl *Client) SetMaxOrderNotional(currency string, max decimal.Decimal) {
+	if max.Sign() <= 0 {
+		delete(cl.maxNotional, currency)
+		return
+	}
+	if cl.maxNotional == nil {
+		cl.maxNotional = make(map[string]decimal.Decimal)
+	}
+	cl.maxNotional[currency] = max
+}
+
+// checkLimitOrderNotional returns an error if req exceeds the notional cap of
+// its counter currency.
+func (cl *Client) checkLimitOrderNotional(ctx context.Context, req *PostLimitOrderRequest) error {
+	if len(cl.maxNotional) == 0 {
+		return nil
+	}
+	return cl.checkNotional(ctx, req.Pair, func() (decimal.Decimal, error) {
+		return req.Price.Mul(req.Volume), nil
+	})
+}
+
+// checkMarketOrderNotional returns an error if req is estimated to exceed the
+// notional cap of its counter currency.
+func (cl *Client) checkMarketOrderNotional(ctx context.Context, req *PostMarketOrderRequest) error {
+	if len(cl.maxNotional) == 0 {
+		return nil
+	}
+	return cl.checkNotional(ctx, req.Pair, func() (decimal.Decimal, error) {
+		if req.CounterVolume.Sign() > 0 {
+			return req.CounterVolume, nil
+		}
+		book, err := cl.GetOrderBookFull(ctx, &GetOrderBookFullRequest{Pair: req.Pair})
+		if err != nil {
+			return decimal.Decimal{}, err
+		}
+		levels, desc := book.Asks, false
+		if req.Type == OrderTypeSell {
+			levels, desc = book.Bids, true
+		}
+		return walkBook(req.Pair, levels, desc, req.BaseVolume)
+	})
+}
+
+func (cl *Client) checkNotional(ctx context.Context, pair string,
+	notional func() (decimal.Decimal, error)) error {
+
+	m, err := cl.MarketInfo(ctx, pair)
+	if err != nil {
+		return cl.metadataUnavailable(ctx, "notional check", pair, err)
+	}
+	max, ok := cl.maxNotional[m.CounterCurrency]
+	if !ok {
+		return nil
+	}
+	n, err := notional()
+	if err != nil {
+		return err
+	}
+	if n.Cmp(max) > 0 {
+		return fmt.Errorf("%w: %s %s on %s is above %s %s",
+			ErrMaxOrderNotional, n, m.CounterCurrency, pair, max, m.CounterCurrency)
+	}
+	return nil
+}
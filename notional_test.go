@@ -0,0 +1,155 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func TestMaxOrderNotional(t *testing.T) {
+	var posted int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/exchange/1/markets":
+			w.Write([]byte(`{"markets":[
+				{"market_id":"XBTZAR","base_currency":"XBT","counter_currency":"ZAR"},
+				{"market_id":"XBTEUR","base_currency":"XBT","counter_currency":"EUR"}
+			]}`))
+		case "/api/1/orderbook":
+			w.Write([]byte(`{
+				"bids":[{"price":"1000","volume":"1"},{"price":"900","volume":"1"}],
+				"asks":[{"price":"1100","volume":"1"},{"price":"1200","volume":"1"}]
+			}`))
+		case "/api/1/postorder", "/api/1/marketorder":
+			posted++
+			w.Write([]byte(`{"order_id":"BXO1"}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetMaxOrderNotional("ZAR", decimal.NewFromInt64(2000))
+	ctx := context.Background()
+
+	limit := func(pair string, price, volume int64) error {
+		_, err := cl.PostLimitOrder(ctx, &luno.PostLimitOrderRequest{
+			Pair:   pair,
+			Type:   luno.OrderTypeBid,
+			Price:  decimal.NewFromInt64(price),
+			Volume: decimal.NewFromInt64(volume),
+		})
+		return err
+	}
+	market := func(typ luno.OrderType, base, counter float64) error {
+		_, err := cl.PostMarketOrder(ctx, &luno.PostMarketOrderRequest{
+			Pair:          "XBTZAR",
+			Type:          typ,
+			BaseVolume:    decimal.NewFromFloat64(base, 1),
+			CounterVolume: decimal.NewFromFloat64(counter, 0),
+		})
+		return err
+	}
+
+	testCases := []struct {
+		name   string
+		place  func() error
+		expErr bool
+	}{
+		{"limit under cap", func() error { return limit("XBTZAR", 1000, 2) }, false},
+		{"limit over cap", func() error { return limit("XBTZAR", 1001, 2) }, true},
+		{"other currency", func() error { return limit("XBTEUR", 1000, 100) }, false},
+		{"market buy under cap", func() error { return market(luno.OrderTypeBuy, 0, 2000) }, false},
+		{"market buy over cap", func() error { return market(luno.OrderTypeBuy, 0, 2001) }, true},
+		// Selling 2 takes both bids for 1900, buying 1.5 takes 1 at 1100
+		// and 0.5 at 1200 for 1700, and buying 2 costs 2300.
+		{"market sell base under cap", func() error { return market(luno.OrderTypeSell, 2, 0) }, false},
+		{"market buy base under cap", func() error { return market(luno.OrderTypeBuy, 1.5, 0) }, false},
+		{"market buy base over cap", func() error { return market(luno.OrderTypeBuy, 2, 0) }, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			posted = 0
+			err := tc.place()
+			if tc.expErr {
+				if !errors.Is(err, luno.ErrMaxOrderNotional) {
+					t.Errorf("Expected ErrMaxOrderNotional, got %v", err)
+				}
+				if posted != 0 {
+					t.Errorf("Expected the order not to be sent")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if posted != 1 {
+				t.Errorf("Expected the order to be sent, got %d requests", posted)
+			}
+		})
+	}
+
+	cl.SetMaxOrderNotional("ZAR", decimal.Zero())
+	if err := limit("XBTZAR", 1000000, 1); err != nil {
+		t.Errorf("Expected success once the cap is removed, got %v", err)
+	}
+}
+
+func TestMetadataPolicy(t *testing.T) {
+	var posted int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/exchange/1/markets":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":"Unavailable","error_code":"ErrUnavailable"}`))
+		case "/api/1/postorder":
+			posted++
+			w.Write([]byte(`{"order_id":"BXO1"}`))
+		}
+	}))
+	defer srv.Close()
+
+	var warnings []luno.Warning
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetMaxOrderNotional("ZAR", decimal.NewFromInt64(2000))
+	cl.SetHooks(luno.Hooks{Warning: func(ctx context.Context, w luno.Warning) {
+		warnings = append(warnings, w)
+	}})
+
+	post := func() error {
+		_, err := cl.PostLimitOrder(context.Background(), &luno.PostLimitOrderRequest{
+			Pair:   "XBTZAR",
+			Type:   luno.OrderTypeBid,
+			Price:  decimal.NewFromInt64(1000),
+			Volume: decimal.NewFromInt64(1),
+		})
+		return err
+	}
+
+	// Fail closed by default.
+	if err := post(); !luno.IsErrorCode(err, "ErrUnavailable") {
+		t.Errorf("Expected ErrUnavailable, got %v", err)
+	}
+	if posted != 0 || len(warnings) != 0 {
+		t.Errorf("Expected no order and no warnings, got %d and %v", posted, warnings)
+	}
+
+	cl.SetMetadataPolicy(luno.MetadataFailOpen)
+	if err := post(); err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+	if posted != 1 {
+		t.Errorf("Expected the order to be posted, got %d", posted)
+	}
+	if len(warnings) != 1 || warnings[0].Kind != luno.WarningValidationSkipped {
+		t.Errorf("Expected a validation skipped warning, got %v", warnings)
+	}
+}
@@ -0,0 +1,95 @@
+package luno
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Client, see NewClient and Clone. Options are applied in
+// order, so WithTimeout must come after WithHTTPClient.
+type Option func(*Client)
+
+// WithAuth returns an option which sets the API key used to authenticate
+// requests. Use SetCredentialProvider to rotate keys while requests are in
+// flight. Unlike SetAuth, empty credentials aren't rejected up front, but
+// authenticated requests made with them will fail.
+func WithAuth(apiKeyID, apiKeySecret string) Option {
+	return func(cl *Client) {
+		cl.SetCredentialProvider(staticCredentials{
+			keyID:     apiKeyID,
+			keySecret: apiKeySecret,
+		})
+	}
+}
+
+// WithCredentialProvider returns an option which sets the provider of the API
+// key used to authenticate requests, as for SetCredentialProvider.
+//
+// Example:
+//
+//	cl := luno.NewClient(luno.WithCredentialProvider(
+//		luno.CredentialProviderFunc(func(ctx context.Context) (string, string, error) {
+//			return vault.LunoKey(ctx)
+//		})))
+func WithCredentialProvider(p CredentialProvider) Option {
+	return func(cl *Client) {
+		cl.SetCredentialProvider(p)
+	}
+}
+
+// WithHTTPClient returns an option which sets the HTTP client used for API
+// calls. Its CheckRedirect function is used as is, see SetRedirectPolicy.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(cl *Client) {
+		cl.httpClient = httpClient
+		cl.checksRedirect = false
+	}
+}
+
+// WithBaseURL returns an option which overrides the default base URL, e.g.
+// for testing against a local server.
+func WithBaseURL(baseURL string) Option {
+	return func(cl *Client) {
+		cl.baseURL = strings.TrimRight(baseURL, "/")
+		cl.hosts = nil
+	}
+}
+
+// WithUserAgentSuffix returns an option which appends s to the User-Agent
+// header sent with every request, e.g. to identify the application.
+func WithUserAgentSuffix(s string) Option {
+	return func(cl *Client) {
+		cl.userAgentSuffix = s
+	}
+}
+
+// WithTimeout returns an option which sets the timeout of requests made by
+// the client. It configures the client's current HTTP client.
+func WithTimeout(timeout time.Duration) Option {
+	return func(cl *Client) {
+		cl.httpClient.Timeout = timeout
+	}
+}
+
+// WithHooks returns an option which sets the client's hooks, as for SetHooks.
+func WithHooks(h Hooks) Option {
+	return func(cl *Client) {
+		cl.SetHooks(h)
+	}
+}
+
+// WithRateLimit returns an option which gives the client its own rate
+// limiter, as for SetRateLimit.
+func WithRateLimit(requestsPerMinute float64, burst int) Option {
+	return func(cl *Client) {
+		cl.SetRateLimit(requestsPerMinute, burst)
+	}
+}
+
+// WithClock returns an option which sets the client's clock, as for SetClock.
+func WithClock(c Clock) Option {
+	return func(cl *Client) {
+		cl.SetClock(c)
+	}
+}
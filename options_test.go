@@ -0,0 +1,63 @@
+package luno_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+)
+
+type countingTransport struct {
+	n int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.n, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewClientOptions(t *testing.T) {
+	var user, pass, ua string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ = r.BasicAuth()
+		ua = r.Header.Get("User-Agent")
+		if r.URL.Path == "/api/1/tickers" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	tr := new(countingTransport)
+	cl := luno.NewClient(
+		luno.WithHTTPClient(&http.Client{Transport: tr}),
+		luno.WithBaseURL(srv.URL+"/"),
+		luno.WithAuth("key", "secret"),
+		luno.WithTimeout(20*time.Millisecond),
+		luno.WithUserAgentSuffix("myapp/1.0"),
+	)
+
+	ctx := context.Background()
+	if _, err := cl.GetBalances(ctx, &luno.GetBalancesRequest{}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if user != "key" || pass != "secret" {
+		t.Errorf("Expected credentials key:secret, got %s:%s", user, pass)
+	}
+	if !strings.HasPrefix(ua, "LunoGoSDK/") || !strings.HasSuffix(ua, " myapp/1.0") {
+		t.Errorf("Expected user agent with suffix, got %q", ua)
+	}
+	if n := atomic.LoadInt32(&tr.n); n != 1 {
+		t.Errorf("Expected the given HTTP client to be used, got %d requests", n)
+	}
+
+	// The timeout applies to the given HTTP client.
+	if _, err := cl.GetTickers(ctx, &luno.GetTickersRequest{}); err == nil {
+		t.Errorf("Expected timeout")
+	}
+}
@@ -0,0 +1,422 @@
+package luno
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// averagePriceScale is the number of decimal places of computed average
+// prices.
+const averagePriceScale = 8
+
+// truncateOrderBook sorts bids and asks, see sortOrderBook, and caps each to
+// depth entries. They aren't capped if depth is not positive.
+func truncateOrderBook(bids, asks []OrderBookEntry, depth int) (
+	[]OrderBookEntry, []OrderBookEntry) {
+
+	sortOrderBook(bids, asks)
+	if depth <= 0 {
+		return bids, asks
+	}
+	if len(bids) > depth {
+		bids = bids[:depth]
+	}
+	if len(asks) > depth {
+		asks = asks[:depth]
+	}
+	return bids, asks
+}
+
+// QuoteMarketBuy returns the amount of counter currency needed to buy
+// baseVolume with a market order on pair, and the resulting average price, by
+// walking the ask side of the current full order book. Fees are not included.
+// An error is returned if the book is not deep enough.
+//
+// The quote is only an estimate since the book may change before an order is
+// placed.
+func (cl *Client) QuoteMarketBuy(ctx context.Context, pair string,
+	baseVolume decimal.Decimal) (counterNeeded, avgPrice decimal.Decimal, err error) {
+
+	if baseVolume.Sign() <= 0 {
+		return decimal.Decimal{}, decimal.Decimal{},
+			fmt.Errorf("luno: invalid base volume %s", baseVolume)
+	}
+
+	book, err := cl.GetOrderBookFull(ctx, &GetOrderBookFullRequest{Pair: pair})
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, err
+	}
+	counterNeeded, err = walkBook(pair, book.Asks, false, baseVolume)
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, err
+	}
+	return counterNeeded, counterNeeded.Div(baseVolume, averagePriceScale), nil
+}
+
+// walkBook returns the counter amount of trading baseVolume against levels,
+// best price first. Bids are sorted by price descending if desc is set, asks
+// ascending otherwise. An error is returned if the levels are not deep
+// enough, naming pair if it is set.
+func walkBook(pair string, levels []OrderBookEntry, desc bool,
+	baseVolume decimal.Decimal) (decimal.Decimal, error) {
+
+	sort.SliceStable(levels, func(i, j int) bool {
+		c := levels[i].Price.Cmp(levels[j].Price)
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	remaining := baseVolume
+	counter := decimal.Zero()
+	for _, l := range levels {
+		vol := l.Volume
+		if vol.Cmp(remaining) > 0 {
+			vol = remaining
+		}
+		counter = counter.Add(vol.Mul(l.Price))
+		remaining = remaining.Sub(vol)
+		if remaining.Sign() == 0 {
+			return counter, nil
+		}
+	}
+	book := "order book"
+	if pair != "" {
+		book += " for " + pair
+	}
+	return decimal.Decimal{}, fmt.Errorf(
+		"luno: %s only has %s of %s base volume available",
+		book, baseVolume.Sub(remaining), baseVolume)
+}
+
+// MarketOrderEstimate is the estimated outcome of a market order.
+type MarketOrderEstimate struct {
+	// Base is the base volume traded.
+	Base decimal.Decimal
+
+	// Counter is the counter amount traded, excluding fees.
+	Counter decimal.Decimal
+
+	// BestPrice is the price of the best level the order trades against,
+	// i.e. the best ask for a buy and the best bid for a sell.
+	BestPrice    decimal.Decimal
+	AveragePrice decimal.Decimal
+
+	// TakerFee is the fee rate applied, e.g. 0.001 for 0.1%.
+	TakerFee decimal.Decimal
+
+	// EstimatedFee is the fee in the counter currency.
+	EstimatedFee decimal.Decimal
+}
+
+// EstimateMarketOrder estimates the outcome of a market order to buy or sell
+// baseVolume on pair by walking the current full order book. Market orders
+// always take liquidity, so the fee is estimated using the account's taker
+// fee for pair, see TakerFee.
+//
+// The estimate may differ from the outcome of an order since the book may
+// change before the order is placed.
+func (cl *Client) EstimateMarketOrder(ctx context.Context, pair string,
+	side Side, baseVolume decimal.Decimal) (*MarketOrderEstimate, error) {
+
+	if side != SideBuy && side != SideSell {
+		return nil, fmt.Errorf("luno: invalid side %q", side)
+	}
+	if baseVolume.Sign() <= 0 {
+		return nil, fmt.Errorf("luno: invalid base volume %s", baseVolume)
+	}
+
+	fee, err := cl.TakerFee(ctx, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := cl.GetOrderBookFull(ctx, &GetOrderBookFullRequest{Pair: pair})
+	if err != nil {
+		return nil, err
+	}
+	levels, desc := book.Asks, false
+	if side == SideSell {
+		levels, desc = book.Bids, true
+	}
+	// walkBook sorts levels, so the best price is first.
+	counter, err := walkBook(pair, levels, desc, baseVolume)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MarketOrderEstimate{
+		Base:         baseVolume,
+		Counter:      counter,
+		BestPrice:    levels[0].Price,
+		AveragePrice: counter.Div(baseVolume, averagePriceScale),
+		TakerFee:     fee,
+		EstimatedFee: counter.Mul(fee),
+	}, nil
+}
+
+// Sequence is the sequence number of an order book update on the Luno
+// Streaming API. Sequence numbers increase by one with every update of a
+// market, so they order the updates and the snapshots they lead to.
+type Sequence uint64
+
+// sortOrderBook sorts bids by price descending and asks by price ascending,
+// comparing prices exactly. Entries with the same price are ordered by volume
+// in the same direction, so the order doesn't depend on the server's.
+func sortOrderBook(bids, asks []OrderBookEntry) {
+	sort.Slice(bids, func(i, j int) bool {
+		return compareEntries(bids[i], bids[j]) > 0
+	})
+	sort.Slice(asks, func(i, j int) bool {
+		return compareEntries(asks[i], asks[j]) < 0
+	})
+}
+
+// compareEntries compares a and b by price, then volume.
+func compareEntries(a, b OrderBookEntry) int {
+	if c := a.Price.Cmp(b.Price); c != 0 {
+		return c
+	}
+	return a.Volume.Cmp(b.Volume)
+}
+
+// OrderBook is a snapshot of the bids and asks of a market, e.g. from
+// GetOrderBook or a streaming connection. The entries don't need to be
+// sorted.
+//
+// Books from GetOrderBook and from streaming.Snapshot.OrderBook have the same
+// semantics: entries are sorted as described at GetOrderBook, and orders at
+// the same price are aggregated into a single level. Books from
+// GetOrderBookFull list each order separately.
+type OrderBook struct {
+	Bids []OrderBookEntry
+	Asks []OrderBookEntry
+
+	// Sequence is the sequence number of the last update included in the
+	// book, or zero if unknown. Order books from the REST API don't carry a
+	// sequence number.
+	Sequence Sequence
+
+	// Timestamp is the time of the book according to the server, or zero if
+	// unknown.
+	Timestamp time.Time
+}
+
+// Normalize returns a copy of the book with the entries sorted, see
+// GetOrderBook, entries at the same price merged into a single level with
+// their total volume, and levels without a positive volume removed. Prices
+// are compared exactly, so 100 and 100.0 are the same level.
+func (ob OrderBook) Normalize() OrderBook {
+	bids := append([]OrderBookEntry(nil), ob.Bids...)
+	asks := append([]OrderBookEntry(nil), ob.Asks...)
+	sortOrderBook(bids, asks)
+	return OrderBook{
+		Bids:      mergeLevels(bids),
+		Asks:      mergeLevels(asks),
+		Sequence:  ob.Sequence,
+		Timestamp: ob.Timestamp,
+	}
+}
+
+// mergeLevels merges sorted entries with the same price and drops levels
+// without a positive volume.
+func mergeLevels(entries []OrderBookEntry) []OrderBookEntry {
+	var res []OrderBookEntry
+	for _, e := range entries {
+		if e.Volume.Sign() <= 0 {
+			continue
+		}
+		if n := len(res); n > 0 && res[n-1].Price.Cmp(e.Price) == 0 {
+			res[n-1].Volume = res[n-1].Volume.Add(e.Volume)
+			continue
+		}
+		res = append(res, e)
+	}
+	return res
+}
+
+// OrderBook returns the bids, asks and timestamp of the response.
+func (r *GetOrderBookResponse) OrderBook() OrderBook {
+	return OrderBook{Bids: r.Bids, Asks: r.Asks, Timestamp: time.Time(r.Timestamp)}
+}
+
+// OrderBook returns the bids, asks and timestamp of the response.
+func (r *GetOrderBookFullResponse) OrderBook() OrderBook {
+	return OrderBook{Bids: r.Bids, Asks: r.Asks, Timestamp: time.Time(r.Timestamp)}
+}
+
+// BestBid returns the highest bid price. It returns false if there are no
+// bids.
+func (ob OrderBook) BestBid() (decimal.Decimal, bool) {
+	return bestPrice(ob.Bids, 1)
+}
+
+// BestAsk returns the lowest ask price. It returns false if there are no
+// asks.
+func (ob OrderBook) BestAsk() (decimal.Decimal, bool) {
+	return bestPrice(ob.Asks, -1)
+}
+
+// Spread returns the difference between the best ask and the best bid. It
+// returns false if either side is empty.
+func (ob OrderBook) Spread() (decimal.Decimal, bool) {
+	bid, ask, ok := ob.bestPrices()
+	if !ok {
+		return decimal.Decimal{}, false
+	}
+	return ask.Sub(bid), true
+}
+
+// MidPrice returns the average of the best bid and the best ask. It returns
+// false if either side is empty.
+func (ob OrderBook) MidPrice() (decimal.Decimal, bool) {
+	bid, ask, ok := ob.bestPrices()
+	if !ok {
+		return decimal.Decimal{}, false
+	}
+	return bid.Add(ask).Mul(half), true
+}
+
+// VolumeWithin returns the total base volume of the bids and asks priced
+// within priceRange of the mid price. It returns false if either side is
+// empty.
+func (ob OrderBook) VolumeWithin(priceRange decimal.Decimal) (decimal.Decimal, bool) {
+	mid, ok := ob.MidPrice()
+	if !ok {
+		return decimal.Decimal{}, false
+	}
+	low, high := mid.Sub(priceRange), mid.Add(priceRange)
+	vol := decimal.Zero()
+	for _, e := range ob.Bids {
+		if e.Price.Cmp(low) >= 0 {
+			vol = vol.Add(e.Volume)
+		}
+	}
+	for _, e := range ob.Asks {
+		if e.Price.Cmp(high) <= 0 {
+			vol = vol.Add(e.Volume)
+		}
+	}
+	return vol, true
+}
+
+var half = decimal.New(big.NewInt(5), 1)
+
+// metricScale is the number of decimal places of computed order book metrics.
+const metricScale = 8
+
+// SpreadBps returns the spread relative to the mid price in basis points,
+// i.e. hundredths of a percent. It returns false if either side is empty or
+// the mid price isn't positive.
+func (ob OrderBook) SpreadBps() (decimal.Decimal, bool) {
+	bid, ask, ok := ob.bestPrices()
+	if !ok {
+		return decimal.Decimal{}, false
+	}
+	mid := bid.Add(ask).Mul(half)
+	if mid.Sign() <= 0 {
+		return decimal.Decimal{}, false
+	}
+	return ask.Sub(bid).MulInt64(10000).Div(mid, metricScale), true
+}
+
+// DepthAtBps returns the total base volume of the bids and of the asks priced
+// within bps basis points of the mid price. It returns false if either side
+// is empty.
+func (ob OrderBook) DepthAtBps(bps int) (bidVol, askVol decimal.Decimal, ok bool) {
+	mid, ok := ob.MidPrice()
+	if !ok {
+		return decimal.Decimal{}, decimal.Decimal{}, false
+	}
+	low := mid.Mul(decimal.New(big.NewInt(int64(10000-bps)), 4))
+	high := mid.Mul(decimal.New(big.NewInt(int64(10000+bps)), 4))
+	bidVol, askVol = decimal.Zero(), decimal.Zero()
+	for _, e := range ob.Bids {
+		if e.Price.Cmp(low) >= 0 {
+			bidVol = bidVol.Add(e.Volume)
+		}
+	}
+	for _, e := range ob.Asks {
+		if e.Price.Cmp(high) <= 0 {
+			askVol = askVol.Add(e.Volume)
+		}
+	}
+	return bidVol, askVol, true
+}
+
+// Imbalance returns the ratio of the bid volume to the ask volume within bps
+// basis points of the mid price, see DepthAtBps. A ratio above 1 means there
+// is more demand than supply near the mid price. It returns false if either
+// side is empty or there are no asks within the band.
+func (ob OrderBook) Imbalance(bps int) (decimal.Decimal, bool) {
+	bidVol, askVol, ok := ob.DepthAtBps(bps)
+	if !ok || askVol.Sign() == 0 {
+		return decimal.Decimal{}, false
+	}
+	return bidVol.Div(askVol, metricScale), true
+}
+
+// VWAP returns the volume-weighted average price of a market order to buy or
+// sell baseVolume against the book, i.e. walking the asks for buys and the
+// bids for sells. Fees are not included. An error is returned if the book is
+// not deep enough.
+func (ob OrderBook) VWAP(side Side, baseVolume decimal.Decimal) (decimal.Decimal, error) {
+	if side != SideBuy && side != SideSell {
+		return decimal.Decimal{}, fmt.Errorf("luno: invalid side %q", side)
+	}
+	if baseVolume.Sign() <= 0 {
+		return decimal.Decimal{}, fmt.Errorf("luno: invalid base volume %s", baseVolume)
+	}
+	levels, desc := ob.Asks, false
+	if side == SideSell {
+		levels, desc = ob.Bids, true
+	}
+	// walkBook sorts the levels in place.
+	levels = append([]OrderBookEntry(nil), levels...)
+	counter, err := walkBook("", levels, desc, baseVolume)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	return counter.Div(baseVolume, averagePriceScale), nil
+}
+
+// DepthAt returns the total base volume of the bids and asks at exactly
+// price, or zero if there is no level at price.
+func (ob OrderBook) DepthAt(price decimal.Decimal) decimal.Decimal {
+	vol := decimal.Zero()
+	for _, entries := range [][]OrderBookEntry{ob.Bids, ob.Asks} {
+		for _, e := range entries {
+			if e.Price.Cmp(price) == 0 {
+				vol = vol.Add(e.Volume)
+			}
+		}
+	}
+	return vol
+}
+
+func (ob OrderBook) bestPrices() (bid, ask decimal.Decimal, ok bool) {
+	bid, bidOK := ob.BestBid()
+	ask, askOK := ob.BestAsk()
+	return bid, ask, bidOK && askOK
+}
+
+// bestPrice returns the highest price of entries if sign is 1, or the lowest
+// if sign is -1.
+func bestPrice(entries []OrderBookEntry, sign int) (decimal.Decimal, bool) {
+	if len(entries) == 0 {
+		return decimal.Decimal{}, false
+	}
+	best := entries[0].Price
+	for _, e := range entries[1:] {
+		if e.Price.Cmp(best) == sign {
+			best = e.Price
+		}
+	}
+	return best, true
+}
@@ -0,0 +1,241 @@
+// Package orderbook maintains a local copy of the order book of a market,
+// kept up to date by a streaming connection, which can be queried and
+// watched from any goroutine.
+//
+// Example:
+//
+//	conn, err := streaming.Dial(keyID, keySecret, "XBTZAR")
+//	...
+//	book, err := orderbook.New(ctx, cl, conn)
+//	...
+//	defer book.Close()
+//
+//	for top := range book.TopOfBook() {
+//		log.Printf("bid %s ask %s", top.Bid, top.Ask)
+//	}
+package orderbook
+
+import (
+	"context"
+	"sync"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-go/streaming"
+)
+
+// subscriptionBuffer is the number of events a Book's subscription may fall
+// behind by before it is dropped and resynchronised.
+const subscriptionBuffer = 1024
+
+// TopOfBook is the best bid and ask of a book.
+type TopOfBook struct {
+	// Bid and Ask are the best prices, or zero if HasBid or HasAsk is false.
+	Bid, Ask       decimal.Decimal
+	HasBid, HasAsk bool
+
+	// Sequence is the sequence number of the update which led to the
+	// change, or zero while the book is seeded from the REST API.
+	Sequence luno.Sequence
+}
+
+func (t TopOfBook) equal(o TopOfBook) bool {
+	return t.HasBid == o.HasBid && t.HasAsk == o.HasAsk &&
+		(!t.HasBid || t.Bid.Cmp(o.Bid) == 0) &&
+		(!t.HasAsk || t.Ask.Cmp(o.Ask) == 0)
+}
+
+// Book is a local order book kept up to date by a streaming connection. It
+// is safe for concurrent use.
+type Book struct {
+	conn *streaming.Conn
+	top  chan TopOfBook
+	done chan struct{}
+
+	mu     sync.RWMutex
+	sub    *streaming.Subscription
+	stream *streaming.Book
+	ob     luno.OrderBook
+	last   TopOfBook
+	closed bool
+
+	// stopped is set once top has been closed.
+	stopped bool
+}
+
+// New returns a book for the market of conn. Until conn has synchronised
+// with the stream, the book is seeded from GetOrderBookFull so that it can
+// be queried straight away; once the stream's own snapshot arrives it
+// replaces the seed, since order books from the REST API have no sequence
+// number to apply updates to. An error is returned if seeding fails.
+//
+// The book follows conn until Close is called or conn is closed, including
+// across reconnects.
+func New(ctx context.Context, cl luno.API, conn *streaming.Conn) (*Book, error) {
+	b := &Book{
+		conn: conn,
+		top:  make(chan TopOfBook, 1),
+		done: make(chan struct{}),
+		sub:  conn.Subscribe(subscriptionBuffer),
+	}
+	go b.run()
+
+	if conn.IsReady() {
+		return b, nil
+	}
+	res, err := cl.GetOrderBookFull(ctx, &luno.GetOrderBookFullRequest{Pair: conn.Pair()})
+	if err != nil {
+		b.Close()
+		return nil, err
+	}
+	b.mu.Lock()
+	if b.stream == nil {
+		b.setLocked(res.OrderBook().Normalize())
+	}
+	b.mu.Unlock()
+	return b, nil
+}
+
+// run applies the events of the subscription to the book until it's closed.
+func (b *Book) run() {
+	defer close(b.done)
+	defer func() {
+		b.mu.Lock()
+		b.stopped = true
+		close(b.top)
+		b.mu.Unlock()
+	}()
+
+	b.mu.RLock()
+	sub := b.sub
+	b.mu.RUnlock()
+	for {
+		for ev := range sub.Events() {
+			b.apply(ev)
+		}
+
+		b.mu.Lock()
+		if b.closed || sub.Err() != streaming.ErrSubscriberLagged {
+			b.mu.Unlock()
+			return
+		}
+		// The subscription is resynchronised from the current book of the
+		// connection.
+		sub = b.conn.Subscribe(subscriptionBuffer)
+		b.sub = sub
+		b.mu.Unlock()
+	}
+}
+
+func (b *Book) apply(ev streaming.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ev.Book != nil {
+		b.stream = ev.Book
+	} else if b.stream == nil {
+		return
+	} else if err := b.stream.Apply(*ev.Update); err != nil {
+		// The connection resynchronises on gaps, and the new snapshot will
+		// follow as another event.
+		return
+	}
+	b.setLocked(b.stream.OrderBook())
+}
+
+// setLocked replaces the book with ob and publishes the top of the book if
+// it changed. It must be called with b.mu held.
+func (b *Book) setLocked(ob luno.OrderBook) {
+	b.ob = ob
+	var top TopOfBook
+	top.Bid, top.HasBid = ob.BestBid()
+	top.Ask, top.HasAsk = ob.BestAsk()
+	top.Sequence = ob.Sequence
+	if top.equal(b.last) {
+		return
+	}
+	b.last = top
+	if b.stopped {
+		return
+	}
+
+	// Only the latest top of the book is kept for slow receivers.
+	select {
+	case b.top <- top:
+	default:
+		select {
+		case <-b.top:
+		default:
+		}
+		b.top <- top
+	}
+}
+
+// Live returns whether the book is kept up to date by the stream, rather
+// than seeded from the REST API.
+func (b *Book) Live() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.stream != nil
+}
+
+// Snapshot returns a copy of the current book, with orders at the same price
+// aggregated into a single level.
+func (b *Book) Snapshot() luno.OrderBook {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	ob := b.ob
+	ob.Bids = append([]luno.OrderBookEntry(nil), ob.Bids...)
+	ob.Asks = append([]luno.OrderBookEntry(nil), ob.Asks...)
+	return ob
+}
+
+// BestBid returns the highest bid price. It returns false if there are no
+// bids.
+func (b *Book) BestBid() (decimal.Decimal, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.last.Bid, b.last.HasBid
+}
+
+// BestAsk returns the lowest ask price. It returns false if there are no
+// asks.
+func (b *Book) BestAsk() (decimal.Decimal, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.last.Ask, b.last.HasAsk
+}
+
+// VWAP returns the volume-weighted average price of a market order to buy or
+// sell baseVolume against the book, see luno.OrderBook.VWAP.
+func (b *Book) VWAP(side luno.Side, baseVolume decimal.Decimal) (decimal.Decimal, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.ob.VWAP(side, baseVolume)
+}
+
+// DepthAt returns the total base volume at exactly price, or zero if there
+// is no level at price.
+func (b *Book) DepthAt(price decimal.Decimal) decimal.Decimal {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.ob.DepthAt(price)
+}
+
+// TopOfBook returns a channel which receives the best bid and ask whenever
+// either changes. Only the latest change is buffered, so a slow receiver
+// skips intermediate ones. The channel is closed once the book stops
+// following its connection.
+func (b *Book) TopOfBook() <-chan TopOfBook {
+	return b.top
+}
+
+// Close stops following the connection, which is left open. The book can
+// still be queried.
+func (b *Book) Close() {
+	b.mu.Lock()
+	b.closed = true
+	sub := b.sub
+	b.mu.Unlock()
+	sub.Close()
+	<-b.done
+}
@@ -0,0 +1,143 @@
+package orderbook_test
+
+import (
+	"context"
+	"flag"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-go/lunotest"
+	"github.com/luno/luno-go/orderbook"
+	"github.com/luno/luno-go/streaming"
+)
+
+func dec(t *testing.T, s string) decimal.Decimal {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	return d
+}
+
+func receiveTop(t *testing.T, b *orderbook.Book) orderbook.TopOfBook {
+	select {
+	case top, ok := <-b.TopOfBook():
+		if !ok {
+			t.Fatalf("Expected top of book, got closed channel")
+		}
+		return top
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for top of book")
+	}
+	return orderbook.TopOfBook{}
+}
+
+func TestBook(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		var cred map[string]string
+		if err := websocket.JSON.Receive(ws, &cred); err != nil {
+			return
+		}
+		<-release
+		_ = websocket.JSON.Send(ws, map[string]interface{}{
+			"sequence": "1",
+			"bids": []interface{}{
+				map[string]string{"id": "b1", "price": "99", "volume": "1"},
+			},
+			"asks": []interface{}{
+				map[string]string{"id": "a1", "price": "101", "volume": "1"},
+				map[string]string{"id": "a2", "price": "102", "volume": "2"},
+			},
+			"status": "ACTIVE",
+		})
+		_ = websocket.JSON.Send(ws, map[string]interface{}{
+			"sequence": "2",
+			"create_update": map[string]string{
+				"order_id": "b2", "type": "BID", "price": "100", "volume": "0.5",
+			},
+		})
+		var data []byte
+		_ = websocket.Message.Receive(ws, &data)
+	}))
+	defer srv.Close()
+
+	host := flag.Lookup("luno_websocket_host").Value.String()
+	flag.Set("luno_websocket_host", "ws"+strings.TrimPrefix(srv.URL, "http"))
+	defer flag.Set("luno_websocket_host", host)
+
+	conn, err := streaming.Dial("key", "secret", "XBTZAR")
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	defer func() {
+		close(release)
+		conn.Close()
+	}()
+
+	f := lunotest.NewFake()
+	f.SetResponse("GetOrderBookFull", &luno.GetOrderBookFullResponse{
+		Bids: []luno.OrderBookEntry{{Price: dec(t, "90"), Volume: dec(t, "1")}},
+		Asks: []luno.OrderBookEntry{{Price: dec(t, "110"), Volume: dec(t, "1")}},
+	})
+	b, err := orderbook.New(context.Background(), f, conn)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	defer b.Close()
+
+	calls := f.CallsTo("GetOrderBookFull")
+	if len(calls) != 1 || calls[0].Req.(*luno.GetOrderBookFullRequest).Pair != "XBTZAR" {
+		t.Fatalf("Expected book to be seeded for XBTZAR, got %+v", calls)
+	}
+	if top := receiveTop(t, b); top.Bid.String() != "90" || top.Ask.String() != "110" {
+		t.Errorf("Expected seeded top of book, got %+v", top)
+	}
+	if b.Live() {
+		t.Errorf("Expected seeded book not to be live")
+	}
+
+	// The snapshot's top of the book may be skipped since only the latest
+	// is buffered.
+	release <- struct{}{}
+	top := receiveTop(t, b)
+	if top.Sequence == 1 {
+		top = receiveTop(t, b)
+	}
+	if top.Bid.String() != "100" || top.Ask.String() != "101" || top.Sequence != 2 {
+		t.Errorf("Expected top after update, got %+v", top)
+	}
+	if !b.Live() {
+		t.Errorf("Expected book to be live")
+	}
+
+	if bid, ok := b.BestBid(); !ok || bid.String() != "100" {
+		t.Errorf("Expected best bid 100, got %s", bid)
+	}
+	if ask, ok := b.BestAsk(); !ok || ask.String() != "101" {
+		t.Errorf("Expected best ask 101, got %s", ask)
+	}
+	if p, err := b.VWAP(luno.SideBuy, dec(t, "2")); err != nil || p.Cmp(dec(t, "101.5")) != 0 {
+		t.Errorf("Expected VWAP 101.5, got %s, %v", p, err)
+	}
+	if d := b.DepthAt(dec(t, "102")); d.String() != "2" {
+		t.Errorf("Expected depth 2, got %s", d)
+	}
+
+	ss := b.Snapshot()
+	ss.Bids[0].Price = dec(t, "1")
+	if bid, _ := b.Snapshot().BestBid(); bid.String() != "100" {
+		t.Errorf("Expected snapshot to be a copy, got best bid %s", bid)
+	}
+
+	b.Close()
+	if _, ok := <-b.TopOfBook(); ok {
+		t.Errorf("Expected top of book channel to be closed")
+	}
+}
@@ -0,0 +1,404 @@
+package luno_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func TestGetOrderBookDepth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"bids":[{"price":"98","volume":"1"},{"price":"100","volume":"1"},{"price":"99","volume":"1"}],
+			"asks":[{"price":"103","volume":"1"},{"price":"101","volume":"1"},{"price":"102","volume":"1"}]
+		}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	res, err := cl.GetOrderBook(context.Background(),
+		&luno.GetOrderBookRequest{Pair: "XBTZAR", Depth: 2})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(res.Bids) != 2 || len(res.Asks) != 2 {
+		t.Fatalf("Expected 2 bids and asks, got %d and %d",
+			len(res.Bids), len(res.Asks))
+	}
+	if res.Bids[0].Price.String() != "100" || res.Bids[1].Price.String() != "99" {
+		t.Errorf("Expected bids 100, 99, got %v", res.Bids)
+	}
+	if res.Asks[0].Price.String() != "101" || res.Asks[1].Price.String() != "102" {
+		t.Errorf("Expected asks 101, 102, got %v", res.Asks)
+	}
+
+	full, err := cl.GetOrderBookFull(context.Background(),
+		&luno.GetOrderBookFullRequest{Pair: "XBTZAR"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(full.Bids) != 3 || len(full.Asks) != 3 {
+		t.Errorf("Expected untruncated book, got %d bids and %d asks",
+			len(full.Bids), len(full.Asks))
+	}
+}
+
+func TestQuoteMarketBuy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/1/orderbook" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		w.Write([]byte(`{
+			"bids":[{"price":"99","volume":"5"}],
+			"asks":[{"price":"102","volume":"2"},{"price":"100","volume":"0.5"},{"price":"101","volume":"1"}]
+		}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	testCases := []struct {
+		name       string
+		volume     string
+		expCounter string
+		expPrice   string
+		expErr     bool
+	}{
+		{name: "first level", volume: "0.25", expCounter: "25", expPrice: "100"},
+		// 0.5*100 + 1*101 + 0.5*102 = 202
+		{name: "three levels", volume: "2", expCounter: "202", expPrice: "101"},
+		// 0.5*100 + 1*101 + 2*102 = 355
+		{name: "whole book", volume: "3.5", expCounter: "355", expPrice: "101.42857142"},
+		{name: "too deep", volume: "3.6", expErr: true},
+		{name: "zero", volume: "0", expErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vol, err := decimal.NewFromString(tc.volume)
+			if err != nil {
+				t.Fatal(err)
+			}
+			counter, price, err := cl.QuoteMarketBuy(context.Background(), "XBTZAR", vol)
+			if tc.expErr {
+				if err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if counter.Cmp(mustDecimal(t, tc.expCounter)) != 0 {
+				t.Errorf("Expected counter %s, got %s", tc.expCounter, counter)
+			}
+			if price.Cmp(mustDecimal(t, tc.expPrice)) != 0 {
+				t.Errorf("Expected price %s, got %s", tc.expPrice, price)
+			}
+		})
+	}
+}
+
+func mustDecimal(t *testing.T, s string) decimal.Decimal {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestEstimateMarketOrder(t *testing.T) {
+	var feeCalls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/1/fee_info":
+			feeCalls++
+			w.Write([]byte(`{"maker_fee":"0","taker_fee":"0.0025","thirty_day_volume":"10"}`))
+		case "/api/1/orderbook":
+			w.Write([]byte(`{
+				"bids":[{"price":"98","volume":"1"},{"price":"99","volume":"0.5"}],
+				"asks":[{"price":"101","volume":"1"},{"price":"100","volume":"0.5"}]
+			}`))
+		default:
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	testCases := []struct {
+		name       string
+		side       luno.Side
+		expCounter string
+		expBest    string
+		expPrice   string
+		expFee     string
+	}{
+		// 0.5*100 + 0.5*101 = 100.5, fee 100.5*0.0025 = 0.25125
+		{name: "buy", side: luno.SideBuy, expCounter: "100.5", expBest: "100",
+			expPrice: "100.5", expFee: "0.25125"},
+		// 0.5*99 + 0.5*98 = 98.5, fee 98.5*0.0025 = 0.24625
+		{name: "sell", side: luno.SideSell, expCounter: "98.5", expBest: "99",
+			expPrice: "98.5", expFee: "0.24625"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			est, err := cl.EstimateMarketOrder(context.Background(), "XBTZAR",
+				tc.side, mustDecimal(t, "1"))
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if est.Counter.Cmp(mustDecimal(t, tc.expCounter)) != 0 {
+				t.Errorf("Expected counter %s, got %s", tc.expCounter, est.Counter)
+			}
+			if est.BestPrice.Cmp(mustDecimal(t, tc.expBest)) != 0 {
+				t.Errorf("Expected best price %s, got %s", tc.expBest, est.BestPrice)
+			}
+			if est.AveragePrice.Cmp(mustDecimal(t, tc.expPrice)) != 0 {
+				t.Errorf("Expected price %s, got %s", tc.expPrice, est.AveragePrice)
+			}
+			if est.TakerFee.Cmp(mustDecimal(t, "0.0025")) != 0 {
+				t.Errorf("Expected taker fee 0.0025, got %s", est.TakerFee)
+			}
+			if est.EstimatedFee.Cmp(mustDecimal(t, tc.expFee)) != 0 {
+				t.Errorf("Expected fee %s, got %s", tc.expFee, est.EstimatedFee)
+			}
+		})
+	}
+	if feeCalls != 1 {
+		t.Errorf("Expected fee info to be cached, got %d calls", feeCalls)
+	}
+
+	_, err := cl.EstimateMarketOrder(context.Background(), "XBTZAR",
+		luno.SideBuy, mustDecimal(t, "2"))
+	if err == nil {
+		t.Errorf("Expected error for insufficient depth, got nil")
+	}
+}
+
+func TestOrderBookQueries(t *testing.T) {
+	res := luno.GetOrderBookResponse{
+		Bids: []luno.OrderBookEntry{
+			{Price: mustDecimal(t, "99"), Volume: mustDecimal(t, "1")},
+			{Price: mustDecimal(t, "100"), Volume: mustDecimal(t, "0.5")},
+			{Price: mustDecimal(t, "90"), Volume: mustDecimal(t, "10")},
+		},
+		Asks: []luno.OrderBookEntry{
+			{Price: mustDecimal(t, "103"), Volume: mustDecimal(t, "2")},
+			{Price: mustDecimal(t, "101"), Volume: mustDecimal(t, "0.25")},
+			{Price: mustDecimal(t, "110"), Volume: mustDecimal(t, "5")},
+		},
+	}
+	ob := res.OrderBook()
+
+	// The mid price is 100.5, so a range of 2.5 spans from 98 to 103.
+	vol := func() (decimal.Decimal, bool) { return ob.VolumeWithin(mustDecimal(t, "2.5")) }
+	// A band of 200 bps spans from 98.49 to 102.51.
+	bidDepth := func() (decimal.Decimal, bool) {
+		bid, _, ok := ob.DepthAtBps(200)
+		return bid, ok
+	}
+	askDepth := func() (decimal.Decimal, bool) {
+		_, ask, ok := ob.DepthAtBps(200)
+		return ask, ok
+	}
+	imbalance := func() (decimal.Decimal, bool) { return ob.Imbalance(200) }
+	vwap := func(side luno.Side, vol string) func() (decimal.Decimal, bool) {
+		return func() (decimal.Decimal, bool) {
+			p, err := ob.VWAP(side, mustDecimal(t, vol))
+			return p, err == nil
+		}
+	}
+	depthAt := func(price string) func() (decimal.Decimal, bool) {
+		return func() (decimal.Decimal, bool) { return ob.DepthAt(mustDecimal(t, price)), true }
+	}
+	testCases := []struct {
+		name string
+		fn   func() (decimal.Decimal, bool)
+		exp  string
+	}{
+		{name: "best bid", fn: ob.BestBid, exp: "100"},
+		{name: "best ask", fn: ob.BestAsk, exp: "101"},
+		{name: "spread", fn: ob.Spread, exp: "1"},
+		{name: "mid price", fn: ob.MidPrice, exp: "100.5"},
+		{name: "volume within", fn: vol, exp: "3.75"},
+		{name: "spread bps", fn: ob.SpreadBps, exp: "99.50248756"},
+		{name: "bid depth", fn: bidDepth, exp: "1.5"},
+		{name: "ask depth", fn: askDepth, exp: "0.25"},
+		{name: "imbalance", fn: imbalance, exp: "6"},
+		{name: "vwap buy", fn: vwap(luno.SideBuy, "1"), exp: "102.5"},
+		{name: "vwap sell", fn: vwap(luno.SideSell, "1.5"), exp: "99.33333333"},
+		{name: "depth at level", fn: depthAt("99"), exp: "1"},
+		{name: "depth at gap", fn: depthAt("102"), exp: "0"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := tc.fn()
+			if !ok {
+				t.Fatalf("Expected ok")
+			}
+			if got.Cmp(mustDecimal(t, tc.exp)) != 0 {
+				t.Errorf("Expected %s, got %s", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestOrderBookVWAPTooShallow(t *testing.T) {
+	ob := luno.OrderBook{
+		Asks: []luno.OrderBookEntry{{Price: mustDecimal(t, "100"), Volume: mustDecimal(t, "1")}},
+	}
+	_, err := ob.VWAP(luno.SideBuy, mustDecimal(t, "2"))
+	exp := "luno: order book only has 1 of 2 base volume available"
+	if err == nil || err.Error() != exp {
+		t.Errorf("Expected %q, got %v", exp, err)
+	}
+}
+
+func TestOrderBookQueriesEmpty(t *testing.T) {
+	oneSided := luno.OrderBook{
+		Bids: []luno.OrderBookEntry{{Price: mustDecimal(t, "100"), Volume: mustDecimal(t, "1")}},
+	}
+	for name, ob := range map[string]luno.OrderBook{"empty": {}, "one sided": oneSided} {
+		t.Run(name, func(t *testing.T) {
+			if _, ok := ob.BestAsk(); ok {
+				t.Errorf("Expected no best ask")
+			}
+			if _, ok := ob.Spread(); ok {
+				t.Errorf("Expected no spread")
+			}
+			if _, ok := ob.MidPrice(); ok {
+				t.Errorf("Expected no mid price")
+			}
+			if _, ok := ob.VolumeWithin(mustDecimal(t, "1")); ok {
+				t.Errorf("Expected no volume")
+			}
+			if _, ok := ob.SpreadBps(); ok {
+				t.Errorf("Expected no spread bps")
+			}
+			if _, _, ok := ob.DepthAtBps(100); ok {
+				t.Errorf("Expected no depth")
+			}
+			if _, ok := ob.Imbalance(100); ok {
+				t.Errorf("Expected no imbalance")
+			}
+		})
+	}
+	if _, ok := (luno.OrderBook{}).BestBid(); ok {
+		t.Errorf("Expected no best bid for an empty book")
+	}
+	if bid, ok := oneSided.BestBid(); !ok || bid.Cmp(mustDecimal(t, "100")) != 0 {
+		t.Errorf("Expected best bid 100, got %s", bid)
+	}
+}
+
+func TestGetOrderBookSorted(t *testing.T) {
+	// Shuffled levels, including prices which only differ in scale and
+	// levels sharing a price.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"bids":[{"price":"99.5","volume":"1"},{"price":"100","volume":"2"},{"price":"99.50","volume":"3"},{"price":"9","volume":"1"}],
+			"asks":[{"price":"110","volume":"1"},{"price":"101.0","volume":"2"},{"price":"101","volume":"1"},{"price":"1000","volume":"1"}]
+		}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	ctx := context.Background()
+
+	check := func(name string, entries []luno.OrderBookEntry, exp []string) {
+		t.Helper()
+		var got []string
+		for _, e := range entries {
+			got = append(got, e.Price.String()+"@"+e.Volume.String())
+		}
+		if len(got) != len(exp) {
+			t.Fatalf("Expected %s %v, got %v", name, exp, got)
+		}
+		for i := range exp {
+			if got[i] != exp[i] {
+				t.Errorf("Expected %s %v, got %v", name, exp, got)
+				return
+			}
+		}
+	}
+	expBids := []string{"100@2", "99.50@3", "99.5@1", "9@1"}
+	expAsks := []string{"101@1", "101.0@2", "110@1", "1000@1"}
+
+	top, err := cl.GetOrderBook(ctx, &luno.GetOrderBookRequest{Pair: "XBTZAR"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	check("bids", top.Bids, expBids)
+	check("asks", top.Asks, expAsks)
+
+	full, err := cl.GetOrderBookFull(ctx, &luno.GetOrderBookFullRequest{Pair: "XBTZAR"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	check("bids", full.Bids, expBids)
+	check("asks", full.Asks, expAsks)
+}
+
+func TestOrderBookImbalanceNoAsksInBand(t *testing.T) {
+	ob := luno.OrderBook{
+		Bids: []luno.OrderBookEntry{{Price: mustDecimal(t, "100"), Volume: mustDecimal(t, "1")}},
+		Asks: []luno.OrderBookEntry{{Price: mustDecimal(t, "120"), Volume: mustDecimal(t, "1")}},
+	}
+	// The mid price is 110, so a band of 100 bps doesn't reach either side.
+	bid, ask, ok := ob.DepthAtBps(100)
+	if !ok || bid.Sign() != 0 || ask.Sign() != 0 {
+		t.Errorf("Expected zero depth, got %s and %s", bid, ask)
+	}
+	if _, ok := ob.Imbalance(100); ok {
+		t.Errorf("Expected no imbalance without asks in the band")
+	}
+}
+
+func TestOrderBookNormalize(t *testing.T) {
+	e := func(price, volume string) luno.OrderBookEntry {
+		return luno.OrderBookEntry{Price: mustDecimal(t, price), Volume: mustDecimal(t, volume)}
+	}
+	ob := luno.OrderBook{
+		Bids: []luno.OrderBookEntry{
+			e("99", "1"), e("100", "0.5"), e("99.0", "2"), e("98", "0"), e("100", "0.25"),
+		},
+		Asks: []luno.OrderBookEntry{
+			e("102", "0"), e("101", "1"), e("103", "1"), e("101", "0.1"),
+		},
+		Sequence: 7,
+	}
+	norm := ob.Normalize()
+
+	levels := func(entries []luno.OrderBookEntry) []string {
+		var res []string
+		for _, e := range entries {
+			res = append(res, e.Price.String()+"@"+e.Volume.String())
+		}
+		return res
+	}
+	expBids := []string{"100@0.75", "99.0@3"}
+	expAsks := []string{"101@1.1", "103@1"}
+	if got := levels(norm.Bids); fmt.Sprint(got) != fmt.Sprint(expBids) {
+		t.Errorf("Expected bids %v, got %v", expBids, got)
+	}
+	if got := levels(norm.Asks); fmt.Sprint(got) != fmt.Sprint(expAsks) {
+		t.Errorf("Expected asks %v, got %v", expAsks, got)
+	}
+	if norm.Sequence != 7 {
+		t.Errorf("Expected sequence 7, got %d", norm.Sequence)
+	}
+	if len(ob.Bids) != 5 || ob.Bids[0].Price.String() != "99" {
+		t.Errorf("Expected the original book to be unchanged, got %v", levels(ob.Bids))
+	}
+}
@@ -0,0 +1,530 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// defaultListOrdersLimit is the number of orders ListOrdersV2 returns if no
+// limit is specified.
+const defaultListOrdersLimit = 100
+
+// ListAllOrdersV2 pages through ListOrdersV2 until all orders matching req
+// have been returned, newest first, using IterOrdersV2.
+func (cl *Client) ListAllOrdersV2(ctx context.Context, req *ListOrdersV2Request) ([]OrderV2, error) {
+	var orders []OrderV2
+	it := cl.IterOrdersV2(req)
+	for it.Next(ctx) {
+		orders = append(orders, it.Order())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// CountOrders returns the number of open orders on pair, or on all pairs if
+// pair is empty.
+//
+// Luno doesn't provide order counts, so every open order is fetched using
+// ListAllOrdersV2. This takes one request per 100 open orders.
+func (cl *Client) CountOrders(ctx context.Context, pair string) (int, error) {
+	orders, err := cl.ListAllOrdersV2(ctx, &ListOrdersV2Request{Pair: pair})
+	if err != nil {
+		return 0, err
+	}
+	return len(orders), nil
+}
+
+// OrderRequest is a validated order built by OrderBuilder. Exactly one of
+// Limit and Market is set.
+type OrderRequest struct {
+	Limit  *PostLimitOrderRequest
+	Market *PostMarketOrderRequest
+}
+
+// OrderBuilder builds an OrderRequest, validating that the combination of
+// options is supported before the order is submitted.
+//
+// Example:
+//
+//	req, err := luno.NewOrderBuilder().
+//		Limit("XBTZAR", luno.SideBuy, price, volume).
+//		PostOnly().
+//		Build()
+type OrderBuilder struct {
+	kind      Type
+	pair      string
+	side      Side
+	price     decimal.Decimal
+	volume    decimal.Decimal
+	postOnly  bool
+	stopPrice decimal.Decimal
+	stopDir   StopDirection
+	tif       TimeInForce
+	baseID    int64
+	counterID int64
+	clientID  string
+	market    *MarketInfo
+	errs      []error
+}
+
+// NewOrderBuilder returns an empty OrderBuilder.
+func NewOrderBuilder() *OrderBuilder {
+	return &OrderBuilder{}
+}
+
+func (b *OrderBuilder) setKind(kind Type) {
+	if b.kind != "" {
+		b.errs = append(b.errs, errors.New("luno: order type set more than once"))
+	}
+	b.kind = kind
+}
+
+// Limit makes the order a limit order for volume at price.
+func (b *OrderBuilder) Limit(pair string, side Side, price, volume decimal.Decimal) *OrderBuilder {
+	b.setKind(TypeLimit)
+	b.pair, b.side, b.price, b.volume = pair, side, price, volume
+	return b
+}
+
+// Market makes the order a market order. For a buy order volume is the
+// amount of counter currency to spend and for a sell order it is the amount
+// of base currency to sell.
+func (b *OrderBuilder) Market(pair string, side Side, volume decimal.Decimal) *OrderBuilder {
+	b.setKind(TypeMarket)
+	b.pair, b.side, b.volume = pair, side, volume
+	return b
+}
+
+// PostOnly makes a limit order post-only.
+func (b *OrderBuilder) PostOnly() *OrderBuilder {
+	b.postOnly = true
+	return b
+}
+
+// Stop makes a limit order a stop limit order which is activated when the
+// last trade price crosses price in the given direction.
+func (b *OrderBuilder) Stop(price decimal.Decimal, direction StopDirection) *OrderBuilder {
+	b.stopPrice, b.stopDir = price, direction
+	return b
+}
+
+// TimeInForce sets how long a limit order remains open, e.g. TimeInForceIoc
+// for an immediate-or-cancel order. Immediate-or-cancel and fill-or-kill
+// orders can't be post-only or stop orders.
+func (b *OrderBuilder) TimeInForce(tif TimeInForce) *OrderBuilder {
+	b.tif = tif
+	return b
+}
+
+// OnAccount sets the base and counter accounts to use. Zero IDs select the
+// default accounts.
+func (b *OrderBuilder) OnAccount(baseID, counterID int64) *OrderBuilder {
+	b.baseID, b.counterID = baseID, counterID
+	return b
+}
+
+// ClientOrderID sets the client order ID of the order, which identifies it
+// if the response to placing it is lost, see PlaceOrderIdempotent.
+func (b *OrderBuilder) ClientOrderID(id string) *OrderBuilder {
+	b.clientID = id
+	return b
+}
+
+// ForMarket enables validation of the order against the parameters of its
+// market, as returned by Markets. Build then returns a *PrecisionError if the
+// price or volume has more decimal places than the market allows.
+func (b *OrderBuilder) ForMarket(m MarketInfo) *OrderBuilder {
+	b.market = &m
+	return b
+}
+
+// PrecisionError is returned by OrderBuilder.Build when a price or volume has
+// more decimal places than its market allows.
+type PrecisionError struct {
+	// Field is the offending order field, e.g. "price" or "volume".
+	Field string
+
+	// Scale is the number of decimal places allowed by the market.
+	Scale int64
+
+	Value decimal.Decimal
+}
+
+func (e *PrecisionError) Error() string {
+	return fmt.Sprintf("luno: order %s %s has more than %d decimal places",
+		e.Field, e.Value, e.Scale)
+}
+
+// checkPrecision returns a *PrecisionError if d can't be represented with
+// scale decimal places. Trailing zeros are ignored.
+func checkPrecision(field string, d decimal.Decimal, scale int64) error {
+	if d.ToScale(int(scale)).Cmp(d) != 0 {
+		return &PrecisionError{Field: field, Scale: scale, Value: d}
+	}
+	return nil
+}
+
+// checkMarket validates the order against b.market, if set.
+func (b *OrderBuilder) checkMarket() error {
+	m := b.market
+	if m == nil {
+		return nil
+	}
+	if m.MarketId != b.pair {
+		return fmt.Errorf("luno: order pair %q doesn't match market %q",
+			b.pair, m.MarketId)
+	}
+	if b.kind == TypeLimit {
+		if err := checkPrecision("price", b.price, m.PriceScale); err != nil {
+			return err
+		}
+		if b.stopPrice.Sign() != 0 {
+			err := checkPrecision("stop price", b.stopPrice, m.PriceScale)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// The volume of a market buy order is in the counter currency, which
+	// isn't covered by the market's volume scale.
+	if b.kind == TypeLimit || b.side == SideSell {
+		return checkPrecision("volume", b.volume, m.VolumeScale)
+	}
+	return nil
+}
+
+// Build validates the order and returns the request to submit.
+func (b *OrderBuilder) Build() (OrderRequest, error) {
+	if len(b.errs) > 0 {
+		return OrderRequest{}, b.errs[0]
+	}
+	if b.pair == "" {
+		return OrderRequest{}, errors.New("luno: order pair is required")
+	}
+	if err := b.checkMarket(); err != nil {
+		return OrderRequest{}, err
+	}
+	if b.side != SideBuy && b.side != SideSell {
+		return OrderRequest{}, fmt.Errorf("luno: invalid order side %q", b.side)
+	}
+	if b.volume.Sign() <= 0 {
+		return OrderRequest{}, errors.New("luno: order volume must be positive")
+	}
+	isStop := b.stopDir != "" || b.stopPrice.Sign() != 0
+
+	switch b.kind {
+	case TypeLimit:
+		if b.price.Sign() <= 0 {
+			return OrderRequest{}, errors.New("luno: order price must be positive")
+		}
+		if isStop && (b.stopDir == "" || b.stopPrice.Sign() <= 0) {
+			return OrderRequest{}, errors.New("luno: stop orders require a " +
+				"positive stop price and a direction")
+		}
+		if isStop && b.postOnly {
+			return OrderRequest{}, errors.New("luno: stop orders can't be post-only")
+		}
+		if err := b.checkTimeInForce(isStop); err != nil {
+			return OrderRequest{}, err
+		}
+		typ := OrderTypeBid
+		if b.side == SideSell {
+			typ = OrderTypeAsk
+		}
+		return OrderRequest{Limit: &PostLimitOrderRequest{
+			Pair:             b.pair,
+			Price:            b.price,
+			Type:             typ,
+			Volume:           b.volume,
+			BaseAccountId:    b.baseID,
+			ClientOrderId:    b.clientID,
+			CounterAccountId: b.counterID,
+			PostOnly:         b.postOnly,
+			StopDirection:    b.stopDir,
+			StopPrice:        b.stopPrice,
+			TimeInForce:      b.tif,
+		}}, nil
+
+	case TypeMarket:
+		if b.postOnly {
+			return OrderRequest{}, errors.New("luno: market orders can't be post-only")
+		}
+		if isStop {
+			return OrderRequest{}, errors.New("luno: market orders can't have a stop")
+		}
+		if b.tif != "" {
+			return OrderRequest{}, errors.New("luno: market orders can't have a time in force")
+		}
+		req := &PostMarketOrderRequest{
+			Pair:             b.pair,
+			BaseAccountId:    b.baseID,
+			ClientOrderId:    b.clientID,
+			CounterAccountId: b.counterID,
+		}
+		if b.side == SideBuy {
+			req.Type = OrderTypeBuy
+			req.CounterVolume = b.volume
+		} else {
+			req.Type = OrderTypeSell
+			req.BaseVolume = b.volume
+		}
+		return OrderRequest{Market: req}, nil
+	}
+
+	return OrderRequest{}, errors.New("luno: order type is required")
+}
+
+// checkTimeInForce returns an error if the time in force is unknown or can't
+// be combined with the other options of a limit order.
+func (b *OrderBuilder) checkTimeInForce(isStop bool) error {
+	switch b.tif {
+	case "", TimeInForceGtc:
+		return nil
+	case TimeInForceIoc, TimeInForceFok:
+	default:
+		return fmt.Errorf("luno: invalid time in force %q", b.tif)
+	}
+	if isStop {
+		return fmt.Errorf("luno: stop orders can't be %s", b.tif)
+	}
+	if b.postOnly {
+		return fmt.Errorf("luno: %s orders can't be post-only", b.tif)
+	}
+	return nil
+}
+
+// PlaceOrder submits an order built by OrderBuilder and returns its ID.
+func (cl *Client) PlaceOrder(ctx context.Context, req OrderRequest) (string, error) {
+	switch {
+	case req.Limit != nil && req.Market == nil:
+		res, err := cl.PostLimitOrder(ctx, req.Limit)
+		if err != nil {
+			return "", err
+		}
+		return res.OrderId, nil
+	case req.Market != nil && req.Limit == nil:
+		res, err := cl.PostMarketOrder(ctx, req.Market)
+		if err != nil {
+			return "", err
+		}
+		return res.OrderId, nil
+	}
+	return "", errors.New("luno: order request must be either limit or market")
+}
+
+// ReplaceOrderError is returned by ReplaceOrder when the old order was
+// stopped but the new order couldn't be placed, leaving neither open.
+type ReplaceOrderError struct {
+	// OldOrderID is the ID of the order which was stopped.
+	OldOrderID string
+
+	// Err is the error from placing the new order.
+	Err error
+}
+
+func (e *ReplaceOrderError) Error() string {
+	return fmt.Sprintf("luno: order %s was stopped but its replacement "+
+		"was not placed: %v", e.OldOrderID, e.Err)
+}
+
+// Unwrap returns the error from placing the new order.
+func (e *ReplaceOrderError) Unwrap() error {
+	return e.Err
+}
+
+// ReplaceOrder stops the order with ID oldID and then places req in its place,
+// returning the ID of the new order. Luno doesn't support modifying orders, so
+// the two steps aren't atomic: any part of the old order filled before it was
+// stopped isn't accounted for in req.
+//
+// If the old order can't be stopped, the new order isn't placed. If the new
+// order is rejected after the old one was stopped, a *ReplaceOrderError is
+// returned.
+func (cl *Client) ReplaceOrder(ctx context.Context, oldID string,
+	req *PostLimitOrderRequest) (string, error) {
+
+	if oldID == "" {
+		return "", errors.New("luno: no order ID to replace")
+	}
+	stop, err := cl.StopOrder(ctx, &StopOrderRequest{OrderId: oldID})
+	if err != nil {
+		return "", err
+	}
+	if !stop.Success {
+		return "", fmt.Errorf("luno: order %s could not be stopped", oldID)
+	}
+
+	res, err := cl.PostLimitOrder(ctx, req)
+	if err != nil {
+		return "", &ReplaceOrderError{OldOrderID: oldID, Err: err}
+	}
+	return res.OrderId, nil
+}
+
+// OrderProgress describes the fill state of an order observed by WatchOrder.
+type OrderProgress struct {
+	// Filled is the fraction of the limit volume which has been filled,
+	// between 0 and 1. It is zero for orders without a limit volume.
+	Filled decimal.Decimal
+
+	// Order is the latest state of the order.
+	Order GetOrderResponse
+}
+
+// WatchOrder polls the order with the given ID every interval until it is
+// complete or ctx is done, calling fn whenever the filled base or counter
+// amount has changed since the previous poll. The final state of the order is
+// returned. An error is returned if interval is not positive.
+func (cl *Client) WatchOrder(ctx context.Context, id string,
+	interval time.Duration, fn func(OrderProgress)) (*GetOrderResponse, error) {
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("luno: invalid watch interval %s", interval)
+	}
+	base, counter := decimal.Zero(), decimal.Zero()
+	for {
+		o, err := cl.GetOrder(ctx, &GetOrderRequest{Id: id})
+		if err != nil {
+			return nil, err
+		}
+		if o.Base.Cmp(base) != 0 || o.Counter.Cmp(counter) != 0 {
+			base, counter = o.Base, o.Counter
+			filled := decimal.Zero()
+			if o.LimitVolume.Sign() > 0 {
+				filled = o.Base.Div(o.LimitVolume, averagePriceScale)
+			}
+			fn(OrderProgress{Filled: filled, Order: *o})
+		}
+		if o.State == OrderStateComplete {
+			return o, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return o, ctx.Err()
+		case <-cl.clock.After(interval):
+		}
+	}
+}
+
+// GetOrderFills returns the trades which filled the order with the given ID
+// on pair, oldest first, with the price, volume and fees of each.
+//
+// Luno doesn't list the trades of an order, so every trade of the user on
+// pair is fetched using IterUserTrades and filtered by order ID. This takes
+// one request per 100 trades.
+func (cl *Client) GetOrderFills(ctx context.Context, orderID, pair string) ([]Trade, error) {
+	if orderID == "" {
+		return nil, errors.New("luno: no order ID provided")
+	}
+	var fills []Trade
+	it := cl.IterUserTrades(&ListUserTradesRequest{Pair: pair})
+	for it.Next(ctx) {
+		if t := it.Trade(); t.OrderId == orderID {
+			fills = append(fills, t)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return fills, nil
+}
+
+// marketOrderPollInterval is how often PostMarketOrderAndConfirm polls the
+// order until it is complete.
+const marketOrderPollInterval = 200 * time.Millisecond
+
+// MarketOrderResult is the final fill state of a market order placed by
+// PostMarketOrderAndConfirm.
+type MarketOrderResult struct {
+	OrderID string
+
+	// FilledBase and FilledCounter are the amounts of base and counter
+	// currency traded, excluding fees.
+	FilledBase    decimal.Decimal
+	FilledCounter decimal.Decimal
+
+	// FullyFilled is true if the whole requested volume was traded, i.e. the
+	// counter volume of a buy order or the base volume of a sell order, up
+	// to an amount too small to trade: less than the market's lot size, or
+	// for a buy order, the counter value of a lot at the average price.
+	FullyFilled bool
+
+	// Remaining is the part of the requested volume which wasn't traded, in
+	// the same currency. It may be non-zero but too small to trade, e.g.
+	// after a buy order since the base volume bought is rounded.
+	Remaining decimal.Decimal
+
+	// Order is the final state of the order.
+	Order GetOrderResponse
+}
+
+// PostMarketOrderAndConfirm places a market order and waits for it to
+// complete, returning how much of it was filled. A market order is only
+// partially filled if there isn't enough liquidity in the order book, in
+// which case the caller may want to place a follow-up order for the
+// remainder.
+//
+// The order is placed even if ctx is done before it completes, in which case
+// the error of ctx is returned along with the result for the last state seen,
+// if any.
+func (cl *Client) PostMarketOrderAndConfirm(ctx context.Context,
+	req *PostMarketOrderRequest) (*MarketOrderResult, error) {
+
+	res, err := cl.PostMarketOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	o, err := cl.WatchOrder(ctx, res.OrderId, marketOrderPollInterval,
+		func(OrderProgress) {})
+	if o == nil {
+		return nil, err
+	}
+
+	filled, requested := o.Counter, req.CounterVolume
+	if req.Type == OrderTypeSell {
+		filled, requested = o.Base, req.BaseVolume
+	}
+	remaining := requested.Sub(filled)
+	if remaining.Sign() < 0 {
+		remaining = decimal.Zero()
+	}
+	return &MarketOrderResult{
+		OrderID:       res.OrderId,
+		FilledBase:    o.Base,
+		FilledCounter: o.Counter,
+		FullyFilled:   cl.belowLot(ctx, req, o, remaining),
+		Remaining:     remaining,
+		Order:         *o,
+	}, err
+}
+
+// belowLot returns whether remaining, what is left of req after order o, is
+// too small to trade. If the market metadata can't be fetched, only a zero
+// remainder is.
+func (cl *Client) belowLot(ctx context.Context, req *PostMarketOrderRequest,
+	o *GetOrderResponse, remaining decimal.Decimal) bool {
+
+	if remaining.Sign() == 0 {
+		return true
+	}
+	m, err := cl.MarketInfo(ctx, req.Pair)
+	if err != nil {
+		return false
+	}
+	unit := m.LotSize()
+	if req.Type != OrderTypeSell {
+		if o.Base.Sign() == 0 {
+			return false
+		}
+		// The counter value of a lot at the average price of the order.
+		unit = unit.Mul(o.Counter).Div(o.Base, int(m.PriceScale)+int(m.VolumeScale))
+	}
+	return remaining.Cmp(unit) < 0
+}
@@ -0,0 +1,703 @@
+package luno_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func TestListAllOrdersV2(t *testing.T) {
+	type order struct {
+		OrderId           string `json:"order_id"`
+		CreationTimestamp int64  `json:"creation_timestamp"`
+	}
+	// Orders newest first, with several sharing a millisecond across the page
+	// boundary.
+	all := []order{
+		{"6", 400}, {"5", 300}, {"4", 300}, {"3", 300}, {"2", 200}, {"1", 100},
+	}
+
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		limit, _ := strconv.Atoi(r.FormValue("limit"))
+		before, _ := strconv.ParseInt(r.FormValue("created_before"), 10, 64)
+		var page []order
+		for _, o := range all {
+			if before > 0 && o.CreationTimestamp >= before {
+				continue
+			}
+			if len(page) == limit {
+				break
+			}
+			page = append(page, o)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"orders": page})
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	orders, err := cl.ListAllOrdersV2(context.Background(),
+		&luno.ListOrdersV2Request{Limit: 4})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(orders) != len(all) {
+		t.Fatalf("Expected %d orders, got %d", len(all), len(orders))
+	}
+	for i, o := range orders {
+		if o.OrderId != all[i].OrderId {
+			t.Errorf("Expected order %s at %d, got %s", all[i].OrderId, i, o.OrderId)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+}
+
+func TestCountOrders(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.FormValue("pair") != "XBTZAR" {
+			t.Errorf("Expected pair XBTZAR, got %q", r.FormValue("pair"))
+		}
+		if r.FormValue("closed") != "false" {
+			t.Errorf("Expected open orders, got closed=%q", r.FormValue("closed"))
+		}
+		// 250 open orders, one per millisecond, newest first.
+		before, _ := strconv.ParseInt(r.FormValue("created_before"), 10, 64)
+		if before == 0 {
+			before = 251
+		}
+		var page []map[string]interface{}
+		for ts := before - 1; ts > 0 && len(page) < 100; ts-- {
+			page = append(page, map[string]interface{}{
+				"order_id":           "BXO" + strconv.FormatInt(ts, 10),
+				"creation_timestamp": ts,
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"orders": page})
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	n, err := cl.CountOrders(context.Background(), "XBTZAR")
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if n != 250 {
+		t.Errorf("Expected 250 orders, got %d", n)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+}
+
+func TestWatchOrder(t *testing.T) {
+	states := []string{
+		`{"order_id":"BXO1","state":"PENDING","limit_volume":"2","base":"0.5","counter":"50"}`,
+		`{"order_id":"BXO1","state":"PENDING","limit_volume":"2","base":"0.5","counter":"50"}`,
+		`{"order_id":"BXO1","state":"PENDING","limit_volume":"2","base":"1.5","counter":"150"}`,
+		`{"order_id":"BXO1","state":"COMPLETE","limit_volume":"2","base":"2","counter":"200"}`,
+	}
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/1/orders/BXO1" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		w.Write([]byte(states[calls]))
+		calls++
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	var filled []string
+	o, err := cl.WatchOrder(context.Background(), "BXO1", time.Millisecond,
+		func(p luno.OrderProgress) {
+			filled = append(filled, p.Filled.String()+"@"+p.Order.Counter.String())
+		})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if o.State != luno.OrderStateComplete {
+		t.Errorf("Expected complete order, got %s", o.State)
+	}
+	exp := []string{"0.25000000@50", "0.75000000@150", "1.00000000@200"}
+	if !reflect.DeepEqual(filled, exp) {
+		t.Errorf("Expected progress %v, got %v", exp, filled)
+	}
+	if calls != len(states) {
+		t.Errorf("Expected %d polls, got %d", len(states), calls)
+	}
+}
+
+func TestWatchOrderCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"order_id":"BXO1","state":"PENDING","limit_volume":"2"}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := cl.WatchOrder(ctx, "BXO1", time.Millisecond, func(luno.OrderProgress) {
+		t.Errorf("Expected no progress for an unfilled order")
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestWatchOrderInterval(t *testing.T) {
+	cl := luno.NewClient(luno.WithBaseURL("http://127.0.0.1:0"))
+	for _, interval := range []time.Duration{0, -time.Second} {
+		_, err := cl.WatchOrder(context.Background(), "BXO1", interval, func(luno.OrderProgress) {})
+		if err == nil || !strings.Contains(err.Error(), "invalid watch interval") {
+			t.Errorf("Expected invalid interval error for %s, got %v", interval, err)
+		}
+	}
+}
+
+func TestOrderBuilder(t *testing.T) {
+	one := decimal.NewFromInt64(1)
+	testCases := []struct {
+		name      string
+		b         *luno.OrderBuilder
+		expErr    bool
+		expLimit  *luno.PostLimitOrderRequest
+		expMarket *luno.PostMarketOrderRequest
+	}{
+		{
+			name: "limit post-only",
+			b:    luno.NewOrderBuilder().Limit("XBTZAR", luno.SideBuy, one, one).PostOnly().OnAccount(1, 2),
+			expLimit: &luno.PostLimitOrderRequest{
+				Pair: "XBTZAR", Type: luno.OrderTypeBid, Price: one, Volume: one,
+				PostOnly: true, BaseAccountId: 1, CounterAccountId: 2,
+			},
+		},
+		{
+			name: "stop limit",
+			b:    luno.NewOrderBuilder().Limit("XBTZAR", luno.SideSell, one, one).Stop(one, luno.StopDirectionBelow),
+			expLimit: &luno.PostLimitOrderRequest{
+				Pair: "XBTZAR", Type: luno.OrderTypeAsk, Price: one, Volume: one,
+				StopPrice: one, StopDirection: luno.StopDirectionBelow,
+			},
+		},
+		{
+			name:      "market buy",
+			b:         luno.NewOrderBuilder().Market("XBTZAR", luno.SideBuy, one),
+			expMarket: &luno.PostMarketOrderRequest{Pair: "XBTZAR", Type: luno.OrderTypeBuy, CounterVolume: one},
+		},
+		{
+			name:      "market sell",
+			b:         luno.NewOrderBuilder().Market("XBTZAR", luno.SideSell, one),
+			expMarket: &luno.PostMarketOrderRequest{Pair: "XBTZAR", Type: luno.OrderTypeSell, BaseVolume: one},
+		},
+		{
+			name:   "no type",
+			b:      luno.NewOrderBuilder().PostOnly(),
+			expErr: true,
+		},
+		{
+			name:   "limit and market",
+			b:      luno.NewOrderBuilder().Limit("XBTZAR", luno.SideBuy, one, one).Market("XBTZAR", luno.SideBuy, one),
+			expErr: true,
+		},
+		{
+			name:   "market post-only",
+			b:      luno.NewOrderBuilder().Market("XBTZAR", luno.SideBuy, one).PostOnly(),
+			expErr: true,
+		},
+		{
+			name:   "market stop",
+			b:      luno.NewOrderBuilder().Market("XBTZAR", luno.SideBuy, one).Stop(one, luno.StopDirectionAbove),
+			expErr: true,
+		},
+		{
+			name:   "stop without direction",
+			b:      luno.NewOrderBuilder().Limit("XBTZAR", luno.SideBuy, one, one).Stop(one, ""),
+			expErr: true,
+		},
+		{
+			name:   "stop post-only",
+			b:      luno.NewOrderBuilder().Limit("XBTZAR", luno.SideBuy, one, one).Stop(one, luno.StopDirectionAbove).PostOnly(),
+			expErr: true,
+		},
+		{
+			name:   "zero price",
+			b:      luno.NewOrderBuilder().Limit("XBTZAR", luno.SideBuy, decimal.Zero(), one),
+			expErr: true,
+		},
+		{
+			name:   "invalid side",
+			b:      luno.NewOrderBuilder().Limit("XBTZAR", "BID", one, one),
+			expErr: true,
+		},
+		{
+			name:   "missing pair",
+			b:      luno.NewOrderBuilder().Market("", luno.SideBuy, one),
+			expErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := tc.b.Build()
+			if tc.expErr {
+				if err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if !reflect.DeepEqual(req.Limit, tc.expLimit) {
+				t.Errorf("Expected limit %+v, got %+v", tc.expLimit, req.Limit)
+			}
+			if !reflect.DeepEqual(req.Market, tc.expMarket) {
+				t.Errorf("Expected market %+v, got %+v", tc.expMarket, req.Market)
+			}
+		})
+	}
+}
+
+func TestOrderBuilderPrecision(t *testing.T) {
+	market := luno.MarketInfo{MarketId: "XBTZAR", PriceScale: 0, VolumeScale: 4}
+	one := decimal.NewFromInt64(1)
+	testCases := []struct {
+		name     string
+		price    string
+		volume   string
+		expField string
+		expScale int64
+	}{
+		{name: "valid", price: "500000", volume: "0.0001"},
+		{name: "trailing zeros", price: "500000.00", volume: "0.00010"},
+		{name: "price", price: "500000.5", volume: "0.0001", expField: "price", expScale: 0},
+		{name: "volume", price: "500000", volume: "0.00011", expField: "volume", expScale: 4},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			price, err := decimal.NewFromString(tc.price)
+			if err != nil {
+				t.Fatal(err)
+			}
+			volume, err := decimal.NewFromString(tc.volume)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = luno.NewOrderBuilder().
+				Limit("XBTZAR", luno.SideBuy, price, volume).
+				ForMarket(market).
+				Build()
+			if tc.expField == "" {
+				if err != nil {
+					t.Errorf("Expected success, got %v", err)
+				}
+				return
+			}
+			var pe *luno.PrecisionError
+			if !errors.As(err, &pe) {
+				t.Fatalf("Expected *PrecisionError, got %v", err)
+			}
+			if pe.Field != tc.expField || pe.Scale != tc.expScale {
+				t.Errorf("Expected %s with scale %d, got %s with scale %d",
+					tc.expField, tc.expScale, pe.Field, pe.Scale)
+			}
+		})
+	}
+
+	_, err := luno.NewOrderBuilder().
+		Limit("ETHZAR", luno.SideBuy, one, one).
+		ForMarket(market).
+		Build()
+	if err == nil {
+		t.Errorf("Expected error for mismatched market, got nil")
+	}
+}
+
+func TestOrderBuilderTimeInForce(t *testing.T) {
+	one := decimal.NewFromInt64(1)
+	limit := func() *luno.OrderBuilder {
+		return luno.NewOrderBuilder().Limit("XBTZAR", luno.SideBuy, one, one)
+	}
+	testCases := []struct {
+		name   string
+		b      *luno.OrderBuilder
+		expTIF luno.TimeInForce
+		expErr bool
+	}{
+		{name: "default", b: limit()},
+		{name: "gtc", b: limit().TimeInForce(luno.TimeInForceGtc), expTIF: luno.TimeInForceGtc},
+		{name: "ioc", b: limit().TimeInForce(luno.TimeInForceIoc), expTIF: luno.TimeInForceIoc},
+		{name: "fok", b: limit().TimeInForce(luno.TimeInForceFok), expTIF: luno.TimeInForceFok},
+		{name: "gtc post-only", b: limit().TimeInForce(luno.TimeInForceGtc).PostOnly(), expTIF: luno.TimeInForceGtc},
+		{name: "unknown", b: limit().TimeInForce("DAY"), expErr: true},
+		{name: "fok stop", b: limit().TimeInForce(luno.TimeInForceFok).Stop(one, luno.StopDirectionAbove), expErr: true},
+		{name: "ioc stop", b: limit().TimeInForce(luno.TimeInForceIoc).Stop(one, luno.StopDirectionAbove), expErr: true},
+		{name: "ioc post-only", b: limit().TimeInForce(luno.TimeInForceIoc).PostOnly(), expErr: true},
+		{name: "fok post-only", b: limit().TimeInForce(luno.TimeInForceFok).PostOnly(), expErr: true},
+		{
+			name:   "market",
+			b:      luno.NewOrderBuilder().Market("XBTZAR", luno.SideBuy, one).TimeInForce(luno.TimeInForceIoc),
+			expErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := tc.b.Build()
+			if tc.expErr {
+				if err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if req.Limit.TimeInForce != tc.expTIF {
+				t.Errorf("Expected time in force %q, got %q", tc.expTIF, req.Limit.TimeInForce)
+			}
+		})
+	}
+}
+
+func TestPostLimitOrderTimeInForce(t *testing.T) {
+	var form []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		_, ok := r.PostForm["time_in_force"]
+		form = append(form, strconv.FormatBool(ok)+":"+r.PostForm.Get("time_in_force"))
+		w.Write([]byte(`{"order_id":"BXO1"}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	one := decimal.NewFromInt64(1)
+	for _, tif := range []luno.TimeInForce{"", luno.TimeInForceIoc} {
+		req, err := luno.NewOrderBuilder().
+			Limit("XBTZAR", luno.SideBuy, one, one).
+			TimeInForce(tif).
+			Build()
+		if err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		if _, err := cl.PlaceOrder(context.Background(), req); err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+	}
+	exp := []string{"false:", "true:IOC"}
+	if !reflect.DeepEqual(form, exp) {
+		t.Errorf("Expected time_in_force %v, got %v", exp, form)
+	}
+}
+
+func TestReplaceOrder(t *testing.T) {
+	var (
+		calls      []string
+		stopOK     bool
+		rejectPost bool
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		switch r.URL.Path {
+		case "/api/1/stoporder":
+			calls = append(calls, "stop:"+r.PostForm.Get("order_id"))
+			json.NewEncoder(w).Encode(map[string]bool{"success": stopOK})
+		case "/api/1/postorder":
+			calls = append(calls, "post:"+r.PostForm.Get("price"))
+			if rejectPost {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"Insufficient balance","error_code":"ErrInsufficientBalance"}`))
+				return
+			}
+			w.Write([]byte(`{"order_id":"BXO2"}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	ctx := context.Background()
+	req := &luno.PostLimitOrderRequest{
+		Pair:   "XBTZAR",
+		Type:   luno.OrderTypeBid,
+		Price:  decimal.NewFromInt64(100),
+		Volume: decimal.NewFromInt64(1),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		calls, stopOK, rejectPost = nil, true, false
+		id, err := cl.ReplaceOrder(ctx, "BXO1", req)
+		if err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		if id != "BXO2" {
+			t.Errorf("Expected new order BXO2, got %q", id)
+		}
+		exp := []string{"stop:BXO1", "post:100"}
+		if !reflect.DeepEqual(calls, exp) {
+			t.Errorf("Expected calls %v, got %v", exp, calls)
+		}
+	})
+
+	t.Run("not stopped", func(t *testing.T) {
+		calls, stopOK, rejectPost = nil, false, false
+		if _, err := cl.ReplaceOrder(ctx, "BXO1", req); err == nil {
+			t.Fatalf("Expected error, got nil")
+		}
+		exp := []string{"stop:BXO1"}
+		if !reflect.DeepEqual(calls, exp) {
+			t.Errorf("Expected calls %v, got %v", exp, calls)
+		}
+	})
+
+	t.Run("new order rejected", func(t *testing.T) {
+		calls, stopOK, rejectPost = nil, true, true
+		_, err := cl.ReplaceOrder(ctx, "BXO1", req)
+		var rErr *luno.ReplaceOrderError
+		if !errors.As(err, &rErr) {
+			t.Fatalf("Expected ReplaceOrderError, got %v", err)
+		}
+		if rErr.OldOrderID != "BXO1" {
+			t.Errorf("Expected old order BXO1, got %q", rErr.OldOrderID)
+		}
+		if !luno.IsErrorCode(err, luno.ErrCodeInsufficientBalance) {
+			t.Errorf("Expected %s, got %v", luno.ErrCodeInsufficientBalance, err)
+		}
+	})
+}
+
+func TestPostLimitOrderPostOnlyRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("post_only") != "true" {
+			w.Write([]byte(`{"order_id":"BXO1"}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"Post-only order would trade","error_code":"ErrPostOnlyOrderWouldTrade"}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	req := &luno.PostLimitOrderRequest{
+		Pair:     "XBTZAR",
+		Type:     luno.OrderTypeBid,
+		Price:    decimal.NewFromInt64(100),
+		Volume:   decimal.NewFromInt64(1),
+		PostOnly: true,
+	}
+	_, err := cl.PostLimitOrder(context.Background(), req)
+	var poErr *luno.PostOnlyRejectedError
+	if !errors.As(err, &poErr) {
+		t.Fatalf("Expected PostOnlyRejectedError, got %T: %v", err, err)
+	}
+	if !luno.IsErrorCode(err, luno.ErrCodePostOnlyRejected) {
+		t.Errorf("Expected error to match code %s", luno.ErrCodePostOnlyRejected)
+	}
+
+	req.PostOnly = false
+	if _, err := cl.PostLimitOrder(context.Background(), req); err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+}
+
+func TestGetOrderFills(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/api/1/listtrades" || r.FormValue("pair") != "XBTZAR" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		// Two pages of 100 trades, with every tenth trade filling BXO1.
+		after, _ := strconv.ParseInt(r.FormValue("after_seq"), 10, 64)
+		var trades []map[string]interface{}
+		for seq := after; seq < 150 && len(trades) < 100; seq++ {
+			id := "BXO2"
+			if seq%10 == 0 {
+				id = "BXO1"
+			}
+			trades = append(trades, map[string]interface{}{
+				"sequence":    seq,
+				"order_id":    id,
+				"price":       strconv.FormatInt(100+seq, 10),
+				"volume":      "0.1",
+				"fee_counter": "0.01",
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"trades": trades})
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	fills, err := cl.GetOrderFills(context.Background(), "BXO1", "XBTZAR")
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 pages, got %d", calls)
+	}
+	if len(fills) != 15 {
+		t.Fatalf("Expected 15 fills, got %d", len(fills))
+	}
+	for i, f := range fills {
+		seq := int64(i) * 10
+		if f.OrderId != "BXO1" || f.Sequence != seq {
+			t.Errorf("Expected fill %d to be trade %d of BXO1, got %+v", i, seq, f)
+		}
+		if f.Price.String() != strconv.FormatInt(100+seq, 10) ||
+			f.Volume.String() != "0.1" || f.FeeCounter.String() != "0.01" {
+			t.Errorf("Unexpected fill %+v", f)
+		}
+	}
+}
+
+func TestPostMarketOrderAndConfirm(t *testing.T) {
+	testCases := []struct {
+		name         string
+		req          luno.PostMarketOrderRequest
+		order        string
+		expFull      bool
+		expRemaining string
+	}{
+		{
+			name: "buy filled",
+			req: luno.PostMarketOrderRequest{
+				Pair: "XBTZAR", Type: luno.OrderTypeBuy,
+				CounterVolume: decimal.NewFromInt64(200),
+			},
+			order:        `{"order_id":"BXO1","state":"COMPLETE","base":"2","counter":"200"}`,
+			expFull:      true,
+			expRemaining: "0",
+		},
+		{
+			name: "buy partial",
+			req: luno.PostMarketOrderRequest{
+				Pair: "XBTZAR", Type: luno.OrderTypeBuy,
+				CounterVolume: decimal.NewFromInt64(200),
+			},
+			order:        `{"order_id":"BXO1","state":"COMPLETE","base":"1.5","counter":"150"}`,
+			expRemaining: "50",
+		},
+		{
+			name: "sell filled",
+			req: luno.PostMarketOrderRequest{
+				Pair: "XBTZAR", Type: luno.OrderTypeSell,
+				BaseVolume: decimal.NewFromInt64(2),
+			},
+			order:        `{"order_id":"BXO1","state":"COMPLETE","base":"2","counter":"200"}`,
+			expFull:      true,
+			expRemaining: "0",
+		},
+		{
+			name: "sell partial",
+			req: luno.PostMarketOrderRequest{
+				Pair: "XBTZAR", Type: luno.OrderTypeSell,
+				BaseVolume: decimal.NewFromInt64(2),
+			},
+			order:        `{"order_id":"BXO1","state":"COMPLETE","base":"0.5","counter":"50"}`,
+			expRemaining: "1.5",
+		},
+		{
+			// 0.01 ZAR is less than 0.01 XBT at 100.5 ZAR.
+			name: "buy below lot",
+			req: luno.PostMarketOrderRequest{
+				Pair: "XBTZAR", Type: luno.OrderTypeBuy,
+				CounterVolume: decimal.NewFromInt64(200),
+			},
+			order:        `{"order_id":"BXO1","state":"COMPLETE","base":"1.99","counter":"199.99"}`,
+			expFull:      true,
+			expRemaining: "0.01",
+		},
+		{
+			name: "sell below lot",
+			req: luno.PostMarketOrderRequest{
+				Pair: "XBTZAR", Type: luno.OrderTypeSell,
+				BaseVolume: dec(t, "2.005"),
+			},
+			order:        `{"order_id":"BXO1","state":"COMPLETE","base":"2","counter":"200"}`,
+			expFull:      true,
+			expRemaining: "0.005",
+		},
+		{
+			name: "sell one lot left",
+			req: luno.PostMarketOrderRequest{
+				Pair: "XBTZAR", Type: luno.OrderTypeSell,
+				BaseVolume: dec(t, "2.01"),
+			},
+			order:        `{"order_id":"BXO1","state":"COMPLETE","base":"2","counter":"200"}`,
+			expRemaining: "0.01",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/exchange/1/markets":
+					w.Write([]byte(`{"markets":[{"market_id":"XBTZAR",` +
+						`"price_scale":0,"volume_scale":2}]}`))
+				case "/api/1/marketorder":
+					w.Write([]byte(`{"order_id":"BXO1"}`))
+				case "/api/1/orders/BXO1":
+					w.Write([]byte(tc.order))
+				default:
+					t.Errorf("Unexpected path %q", r.URL.Path)
+				}
+			}))
+			defer srv.Close()
+
+			cl := luno.NewClient()
+			cl.SetBaseURL(srv.URL)
+
+			res, err := cl.PostMarketOrderAndConfirm(context.Background(), &tc.req)
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if res.OrderID != "BXO1" {
+				t.Errorf("Expected order BXO1, got %q", res.OrderID)
+			}
+			if res.FullyFilled != tc.expFull {
+				t.Errorf("Expected fully filled %v, got %v", tc.expFull, res.FullyFilled)
+			}
+			if res.Remaining.Cmp(dec(t, tc.expRemaining)) != 0 {
+				t.Errorf("Expected remaining %s, got %s", tc.expRemaining, res.Remaining)
+			}
+			if res.FilledBase.Cmp(res.Order.Base) != 0 ||
+				res.FilledCounter.Cmp(res.Order.Counter) != 0 {
+				t.Errorf("Expected filled amounts of the order, got %s and %s",
+					res.FilledBase, res.FilledCounter)
+			}
+		})
+	}
+}
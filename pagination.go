@@ -0,0 +1,55 @@
+package luno
+
+// Luno's list endpoints don't return cursors or "has more" flags, so whether
+// another page may exist is inferred from the size of the page:
+//
+//   - ListUserTrades is paged by AfterSeq, oldest first. A page of fewer
+//     trades than the limit is the last one. The limit is capped at
+//     tradesPageSize by the server.
+//   - ListOrdersV2 is paged by CreatedBefore, newest first. A page of fewer
+//     orders than the limit is the last one. The limit is capped at
+//     maxListOrdersLimit by the server.
+//   - ListTransactions is paged by row range. Rows are numbered
+//     contiguously, so a page of fewer rows than the range is the last one.
+//
+// In every case a final page which is exactly full can't be told apart from
+// a full page followed by more, so it is followed by a request for an empty
+// page. Limits larger than the server's cap must be clamped before paging,
+// otherwise every page would look like the last one.
+
+// maxListOrdersLimit is the maximum number of orders ListOrdersV2 returns per
+// call.
+const maxListOrdersLimit = 1000
+
+// hasMoreTrades returns whether there may be trades after res, which was
+// returned for a request with the given limit.
+func hasMoreTrades(res *ListUserTradesResponse, limit int64) bool {
+	return isFullPage(len(res.Trades), limit)
+}
+
+// hasMoreOrders returns whether there may be orders after res, which was
+// returned for a request with the given limit.
+func hasMoreOrders(res *ListOrdersV2Response, limit int64) bool {
+	return isFullPage(len(res.Orders), limit)
+}
+
+// hasMoreTransactions returns whether there may be transactions after res,
+// which was returned for the row range [minRow, maxRow).
+func hasMoreTransactions(res *ListTransactionsResponse, minRow, maxRow int64) bool {
+	return isFullPage(len(res.Transactions), maxRow-minRow)
+}
+
+func isFullPage(n int, limit int64) bool {
+	return n > 0 && int64(n) >= limit
+}
+
+// clampLimit returns limit, or def if it is zero, capped at max.
+func clampLimit(limit, def, max int64) int64 {
+	if limit <= 0 {
+		return def
+	}
+	if limit > max {
+		return max
+	}
+	return limit
+}
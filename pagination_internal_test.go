@@ -0,0 +1,51 @@
+package luno
+
+import "testing"
+
+func TestHasMore(t *testing.T) {
+	testCases := []struct {
+		name string
+		got  bool
+		exp  bool
+	}{
+		{"trades empty", hasMoreTrades(&ListUserTradesResponse{}, 2), false},
+		{"trades short", hasMoreTrades(&ListUserTradesResponse{
+			Trades: make([]Trade, 1)}, 2), false},
+		{"trades full", hasMoreTrades(&ListUserTradesResponse{
+			Trades: make([]Trade, 2)}, 2), true},
+		{"orders empty", hasMoreOrders(&ListOrdersV2Response{}, 2), false},
+		{"orders short", hasMoreOrders(&ListOrdersV2Response{
+			Orders: make([]OrderV2, 1)}, 2), false},
+		{"orders full", hasMoreOrders(&ListOrdersV2Response{
+			Orders: make([]OrderV2, 2)}, 2), true},
+		{"transactions empty", hasMoreTransactions(
+			&ListTransactionsResponse{}, 1, 3), false},
+		{"transactions short", hasMoreTransactions(&ListTransactionsResponse{
+			Transactions: make([]Transaction, 1)}, 1, 3), false},
+		{"transactions full", hasMoreTransactions(&ListTransactionsResponse{
+			Transactions: make([]Transaction, 2)}, 1, 3), true},
+	}
+	for _, tc := range testCases {
+		if tc.got != tc.exp {
+			t.Errorf("%s: Expected %v, got %v", tc.name, tc.exp, tc.got)
+		}
+	}
+}
+
+func TestClampLimit(t *testing.T) {
+	testCases := []struct {
+		limit, exp int64
+	}{
+		{0, 100},
+		{-1, 100},
+		{50, 50},
+		{1000, 1000},
+		{5000, 1000},
+	}
+	for _, tc := range testCases {
+		if got := clampLimit(tc.limit, 100, 1000); got != tc.exp {
+			t.Errorf("Expected limit %d to be clamped to %d, got %d",
+				tc.limit, tc.exp, got)
+		}
+	}
+}
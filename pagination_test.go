@@ -0,0 +1,133 @@
+package luno_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+// countRequests wraps srv's handler to count the requests it receives.
+func countRequests(srv *httptest.Server, n *int) {
+	h := srv.Config.Handler
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*n++
+		h.ServeHTTP(w, r)
+	})
+}
+
+func TestPagingExactlyFullFinalPage(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		trades   int64
+		txs      int64
+		count    func(cl *luno.Client) int
+		exp      int
+		requests int
+	}{
+		{
+			name:   "trades",
+			trades: 200,
+			count: func(cl *luno.Client) int {
+				it := cl.IterUserTrades(&luno.ListUserTradesRequest{Pair: "XBTZAR", Limit: 100})
+				var n int
+				for it.Next(ctx) {
+					n++
+				}
+				return n
+			},
+			exp:      200,
+			requests: 3,
+		},
+		{
+			name:   "trades above server limit",
+			trades: 2500,
+			count: func(cl *luno.Client) int {
+				it := cl.IterUserTrades(&luno.ListUserTradesRequest{Pair: "XBTZAR", Limit: 5000})
+				var n int
+				for it.Next(ctx) {
+					n++
+				}
+				return n
+			},
+			exp:      2500,
+			requests: 3,
+		},
+		{
+			name: "transactions",
+			txs:  2000,
+			count: func(cl *luno.Client) int {
+				it := cl.IterTransactions("1")
+				var n int
+				for it.Next(ctx) {
+					n++
+				}
+				return n
+			},
+			exp:      2000,
+			requests: 3,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newListServer(tc.trades, tc.txs)
+			defer srv.Close()
+			var requests int
+			countRequests(srv, &requests)
+
+			cl := luno.NewClient()
+			cl.SetBaseURL(srv.URL)
+
+			if n := tc.count(cl); n != tc.exp {
+				t.Errorf("Expected %d values, got %d", tc.exp, n)
+			}
+			if requests != tc.requests {
+				t.Errorf("Expected %d requests, got %d", tc.requests, requests)
+			}
+		})
+	}
+}
+
+func TestListAllOrdersV2ExactlyFullFinalPage(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		limit, _ := strconv.Atoi(r.FormValue("limit"))
+		before, _ := strconv.ParseInt(r.FormValue("created_before"), 10, 64)
+		page := []map[string]interface{}{}
+		for ts := int64(4); ts >= 1 && len(page) < limit; ts-- {
+			if before > 0 && ts >= before {
+				continue
+			}
+			page = append(page, map[string]interface{}{
+				"order_id":           strconv.FormatInt(ts, 10),
+				"creation_timestamp": ts,
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"orders": page})
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	orders, err := cl.ListAllOrdersV2(context.Background(),
+		&luno.ListOrdersV2Request{Limit: 2})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(orders) != 4 {
+		t.Errorf("Expected 4 orders, got %d", len(orders))
+	}
+	// Pages overlap by the millisecond of the oldest order, so each full page
+	// after the first adds a single new order until the final short page.
+	if requests != 4 {
+		t.Errorf("Expected 4 requests, got %d", requests)
+	}
+}
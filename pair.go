@@ -0,0 +1,89 @@
+package luno
+
+import (
+	"fmt"
+	"strings"
+)
+
+// knownCurrencies are the currency codes recognised by NormalizePair.
+var knownCurrencies = map[string]bool{
+	"AAVE": true, "ADA": true, "ALGO": true, "ATOM": true, "AUD": true,
+	"AVAX": true, "BCH": true, "CRV": true, "DOT": true, "ETH": true,
+	"EUR": true, "GBP": true, "GRT": true, "IDR": true, "KES": true,
+	"LINK": true, "LTC": true, "MATIC": true, "MKR": true, "MYR": true,
+	"NGN": true, "SAND": true, "SNX": true, "SOL": true, "TRX": true,
+	"UGX": true, "UNI": true, "USD": true, "USDC": true, "USDT": true,
+	"XBT": true, "XLM": true, "XRP": true, "ZAR": true,
+}
+
+// pairSeparators are removed from pairs by NormalizePair.
+const pairSeparators = "/-_: "
+
+// NormalizePair returns pair in the canonical form expected by the API, e.g.
+// XBTZAR for xbtzar, XBT/ZAR or xbt-zar. An error is returned unless the
+// result is the concatenation of two different known currency codes.
+func NormalizePair(pair string) (string, error) {
+	p := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(pairSeparators, r) {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(pair)))
+
+	for i := 3; i < len(p); i++ {
+		base, counter := p[:i], p[i:]
+		if base != counter && knownCurrencies[base] && knownCurrencies[counter] {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("luno: invalid pair %q", pair)
+}
+
+// SetPairNormalization enables or disables normalizing the pair parameter of
+// requests with NormalizePair. Requests with an invalid pair fail without
+// being sent. It is disabled by default.
+func (cl *Client) SetPairNormalization(enabled bool) {
+	cl.normalizePairs = enabled
+}
+
+// MismatchError is returned if a response is for a different pair than the
+// one requested, which usually means that a cache or proxy between the
+// client and the server returned the wrong response.
+type MismatchError struct {
+	Requested string
+	Got       string
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("luno: requested pair %q, got response for %q",
+		e.Requested, e.Got)
+}
+
+// SetPairValidation enables or disables checking that the pair of GetTicker
+// responses matches the requested pair. A *MismatchError is returned if it
+// doesn't. The pair is compared exactly after any normalization enabled by
+// SetPairNormalization. It is disabled by default.
+//
+// The order book endpoints don't return the pair, so GetOrderBook and
+// GetOrderBookFull responses can't be checked.
+func (cl *Client) SetPairValidation(enabled bool) {
+	cl.validatePairs = enabled
+}
+
+// checkPair returns a *MismatchError if pair validation is enabled and got
+// isn't the requested pair.
+func (cl *Client) checkPair(requested, got string) error {
+	if !cl.validatePairs {
+		return nil
+	}
+	want := requested
+	if cl.normalizePairs {
+		if p, err := NormalizePair(requested); err == nil {
+			want = p
+		}
+	}
+	if got != want {
+		return &MismatchError{Requested: want, Got: got}
+	}
+	return nil
+}
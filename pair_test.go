@@ -0,0 +1,115 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestNormalizePair(t *testing.T) {
+	testCases := []struct {
+		in     string
+		exp    string
+		expErr bool
+	}{
+		{in: "XBTZAR", exp: "XBTZAR"},
+		{in: "xbtzar", exp: "XBTZAR"},
+		{in: "XBT/ZAR", exp: "XBTZAR"},
+		{in: "xbt-zar", exp: "XBTZAR"},
+		{in: "eth_myr", exp: "ETHMYR"},
+		{in: " Xbt Zar ", exp: "XBTZAR"},
+		{in: "USDC/USDT", exp: "USDCUSDT"},
+		{in: "link:xbt", exp: "LINKXBT"},
+		{in: "", expErr: true},
+		{in: "XBT", expErr: true},
+		{in: "XBTXBT", expErr: true},
+		{in: "FOOBAR", expErr: true},
+		{in: "XBTZAR1", expErr: true},
+		{in: "XBT/ZAR/NGN", expErr: true},
+		{in: "'; DROP TABLE", expErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := luno.NormalizePair(tc.in)
+			if tc.expErr {
+				if err == nil {
+					t.Errorf("Expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			if got != tc.exp {
+				t.Errorf("Expected %q, got %q", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestPairNormalization(t *testing.T) {
+	var calls int
+	var pair string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		pair = r.FormValue("pair")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetPairNormalization(true)
+
+	ctx := context.Background()
+	_, err := cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "xbt/zar"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if pair != "XBTZAR" {
+		t.Errorf("Expected pair XBTZAR, got %q", pair)
+	}
+
+	_, err = cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "garbage"})
+	if err == nil {
+		t.Errorf("Expected error for invalid pair, got nil")
+	}
+	if calls != 1 {
+		t.Errorf("Expected invalid request not to be sent, got %d calls", calls)
+	}
+}
+
+func TestPairValidation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"pair":"ETHZAR","last_trade":"100"}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	ctx := context.Background()
+	req := &luno.GetTickerRequest{Pair: "XBTZAR"}
+	if _, err := cl.GetTicker(ctx, req); err != nil {
+		t.Fatalf("Expected no validation by default, got %v", err)
+	}
+
+	cl.SetPairValidation(true)
+	_, err := cl.GetTicker(ctx, req)
+	var me *luno.MismatchError
+	if !errors.As(err, &me) {
+		t.Fatalf("Expected MismatchError, got %v", err)
+	}
+	if me.Requested != "XBTZAR" || me.Got != "ETHZAR" {
+		t.Errorf("Expected XBTZAR and ETHZAR, got %q and %q", me.Requested, me.Got)
+	}
+
+	cl.SetPairNormalization(true)
+	if _, err := cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "eth/zar"}); err != nil {
+		t.Errorf("Expected normalized pair to match, got %v", err)
+	}
+}
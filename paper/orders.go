@@ -0,0 +1,437 @@
+package paper
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+// order is a simulated order.
+type order struct {
+	luno.Order
+
+	bid    bool
+	market luno.MarketInfo
+
+	// reserved is the part of the balance still reserved for the order.
+	reserved decimal.Decimal
+}
+
+func orderNotFound() error {
+	return luno.Error{Code: luno.ErrCodeOrderNotFound, Message: "Order not found"}
+}
+
+// marketInfo returns the market of pair, fetching the markets from the real
+// API the first time.
+func (c *Client) marketInfo(ctx context.Context, pair string) (luno.MarketInfo, error) {
+	c.mu.Lock()
+	markets := c.markets
+	c.mu.Unlock()
+	if markets == nil {
+		res, err := c.API.Markets(ctx, &luno.MarketsRequest{})
+		if err != nil {
+			return luno.MarketInfo{}, err
+		}
+		markets = make(map[string]luno.MarketInfo, len(res.Markets))
+		for _, m := range res.Markets {
+			markets[m.MarketId] = m
+		}
+		c.mu.Lock()
+		c.markets = markets
+		c.mu.Unlock()
+	}
+	m, ok := markets[pair]
+	if !ok {
+		return luno.MarketInfo{}, luno.Error{Code: "ErrMarketNotFound", Message: "Market not found"}
+	}
+	return m, nil
+}
+
+// newOrder returns a pending order and records it. c.mu must be held.
+func (c *Client) newOrder(m luno.MarketInfo, typ luno.OrderType, bid bool) *order {
+	o := &order{
+		Order: luno.Order{
+			OrderId:           c.nextID("PAPER"),
+			Pair:              m.MarketId,
+			Type:              typ,
+			State:             luno.OrderStatePending,
+			CreationTimestamp: c.timestamp(),
+			Base:              decimal.Zero(),
+			Counter:           decimal.Zero(),
+			FeeBase:           decimal.Zero(),
+			FeeCounter:        decimal.Zero(),
+			LimitPrice:        decimal.Zero(),
+			LimitVolume:       decimal.Zero(),
+		},
+		bid:      bid,
+		market:   m,
+		reserved: decimal.Zero(),
+	}
+	c.orders[o.OrderId] = o
+	c.orderIDs = append(c.orderIDs, o.OrderId)
+	return o
+}
+
+// PostLimitOrder implements luno.API by simulating the order. The part of the
+// order which crosses the current order book is filled immediately, unless
+// it is post-only, and the rest rests until the last trade price reaches its
+// limit price. Stop-limit orders aren't simulated.
+func (c *Client) PostLimitOrder(ctx context.Context, req *luno.PostLimitOrderRequest) (*luno.PostLimitOrderResponse, error) {
+	if req.StopDirection != "" || req.StopPrice.Sign() != 0 {
+		return nil, ErrNotSimulated
+	}
+	if req.Type != luno.OrderTypeBid && req.Type != luno.OrderTypeAsk {
+		return nil, errors.New("paper: limit order type must be BID or ASK")
+	}
+	if req.Price.Sign() <= 0 || req.Volume.Sign() <= 0 {
+		return nil, errors.New("paper: price and volume must be positive")
+	}
+	m, err := c.marketInfo(ctx, req.Pair)
+	if err != nil {
+		return nil, err
+	}
+	book, err := c.API.GetOrderBook(ctx, &luno.GetOrderBookRequest{Pair: req.Pair})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bid := req.Type == luno.OrderTypeBid
+	asset, amount := m.BaseCurrency, req.Volume
+	if bid {
+		asset, amount = m.CounterCurrency, req.Volume.Mul(req.Price)
+	}
+	if err := c.reserve(asset, amount); err != nil {
+		return nil, err
+	}
+	o := c.newOrder(m, req.Type, bid)
+	o.LimitPrice, o.LimitVolume, o.reserved = req.Price, req.Volume, amount
+
+	levels := crossing(book, bid, req.Price)
+	switch {
+	case req.PostOnly && len(levels) > 0:
+		// Post-only orders are cancelled if they would trade immediately.
+		c.complete(o)
+		return &luno.PostLimitOrderResponse{OrderId: o.OrderId}, nil
+	case req.TimeInForce == luno.TimeInForceFok && sumVolume(levels).Cmp(req.Volume) < 0:
+		c.complete(o)
+		return &luno.PostLimitOrderResponse{OrderId: o.OrderId}, nil
+	}
+
+	for _, l := range levels {
+		remaining := o.LimitVolume.Sub(o.Base)
+		if remaining.Sign() <= 0 {
+			break
+		}
+		c.fill(o, l.Price, min(l.Volume, remaining), c.takerFee)
+	}
+	if o.Base.Cmp(o.LimitVolume) >= 0 || req.TimeInForce == luno.TimeInForceIoc ||
+		req.TimeInForce == luno.TimeInForceFok {
+		c.complete(o)
+	}
+	return &luno.PostLimitOrderResponse{OrderId: o.OrderId}, nil
+}
+
+// PostMarketOrder implements luno.API by filling the order against the
+// current order book.
+func (c *Client) PostMarketOrder(ctx context.Context, req *luno.PostMarketOrderRequest) (*luno.PostMarketOrderResponse, error) {
+	bid := req.Type == luno.OrderTypeBuy || req.Type == luno.OrderTypeBid
+	if !bid && req.Type != luno.OrderTypeSell && req.Type != luno.OrderTypeAsk {
+		return nil, errors.New("paper: market order type must be BUY or SELL")
+	}
+	volume := req.BaseVolume
+	if bid {
+		volume = req.CounterVolume
+	}
+	if volume.Sign() <= 0 {
+		return nil, errors.New("paper: volume must be positive")
+	}
+	m, err := c.marketInfo(ctx, req.Pair)
+	if err != nil {
+		return nil, err
+	}
+	book, err := c.API.GetOrderBook(ctx, &luno.GetOrderBookRequest{Pair: req.Pair})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	asset := m.BaseCurrency
+	if bid {
+		asset = m.CounterCurrency
+	}
+	if err := c.reserve(asset, volume); err != nil {
+		return nil, err
+	}
+	o := c.newOrder(m, req.Type, bid)
+	o.reserved = volume
+	if !bid {
+		o.LimitVolume = volume
+	}
+
+	levels := book.Bids
+	if bid {
+		levels = book.Asks
+	}
+	remaining := volume
+	for _, l := range levels {
+		vol := min(l.Volume, remaining)
+		if bid {
+			vol = min(l.Volume, remaining.Div(l.Price, int(m.VolumeScale)))
+		}
+		if vol.Sign() <= 0 {
+			break
+		}
+		t := c.fill(o, l.Price, vol, c.takerFee)
+		if bid {
+			remaining = remaining.Sub(t.Counter)
+		} else {
+			remaining = remaining.Sub(vol)
+		}
+	}
+	c.complete(o)
+	return &luno.PostMarketOrderResponse{OrderId: o.OrderId}, nil
+}
+
+// crossing returns the levels of book which an order on the given side at
+// price would trade against, best first.
+func crossing(book *luno.GetOrderBookResponse, bid bool, price decimal.Decimal) []luno.OrderBookEntry {
+	var levels []luno.OrderBookEntry
+	if bid {
+		for _, l := range book.Asks {
+			if l.Price.Cmp(price) <= 0 {
+				levels = append(levels, l)
+			}
+		}
+	} else {
+		for _, l := range book.Bids {
+			if l.Price.Cmp(price) >= 0 {
+				levels = append(levels, l)
+			}
+		}
+	}
+	return levels
+}
+
+func sumVolume(levels []luno.OrderBookEntry) decimal.Decimal {
+	sum := decimal.Zero()
+	for _, l := range levels {
+		sum = sum.Add(l.Volume)
+	}
+	return sum
+}
+
+func min(a, b decimal.Decimal) decimal.Decimal {
+	if a.Cmp(b) < 0 {
+		return a
+	}
+	return b
+}
+
+// fill trades volume of o at price, charging fee on the currency received,
+// and returns the trade. c.mu must be held.
+func (c *Client) fill(o *order, price, volume, fee decimal.Decimal) luno.Trade {
+	base := c.account(o.market.BaseCurrency)
+	counter := c.account(o.market.CounterCurrency)
+	t := luno.Trade{
+		Base:       volume,
+		Counter:    volume.Mul(price),
+		FeeBase:    decimal.Zero(),
+		FeeCounter: decimal.Zero(),
+		IsBuy:      o.bid,
+		OrderId:    o.OrderId,
+		Pair:       o.Pair,
+		Price:      price,
+		Timestamp:  c.timestamp(),
+		Type:       o.Type,
+		Volume:     volume,
+	}
+
+	if o.bid {
+		t.FeeBase = volume.Mul(fee)
+		// Limit bids reserve the counter amount at the limit price, which
+		// may be more than the amount traded.
+		release := t.Counter
+		if o.LimitPrice.Sign() > 0 {
+			release = volume.Mul(o.LimitPrice)
+		}
+		c.release(o, counter, release)
+		counter.balance = counter.balance.Sub(t.Counter)
+		base.balance = base.balance.Add(volume).Sub(t.FeeBase)
+	} else {
+		t.FeeCounter = t.Counter.Mul(fee)
+		c.release(o, base, volume)
+		base.balance = base.balance.Sub(volume)
+		counter.balance = counter.balance.Add(t.Counter).Sub(t.FeeCounter)
+	}
+
+	o.Base = o.Base.Add(t.Base)
+	o.Counter = o.Counter.Add(t.Counter)
+	o.FeeBase = o.FeeBase.Add(t.FeeBase)
+	o.FeeCounter = o.FeeCounter.Add(t.FeeCounter)
+	t.Sequence = int64(len(c.trades) + 1)
+	c.trades = append(c.trades, t)
+	return t
+}
+
+func (c *Client) release(o *order, a *account, amount decimal.Decimal) {
+	amount = min(amount, o.reserved)
+	o.reserved = o.reserved.Sub(amount)
+	a.reserved = a.reserved.Sub(amount)
+}
+
+// complete completes o, releasing the rest of its reservation. c.mu must be
+// held.
+func (c *Client) complete(o *order) {
+	asset := o.market.BaseCurrency
+	if o.bid {
+		asset = o.market.CounterCurrency
+	}
+	c.release(o, c.account(asset), o.reserved)
+	o.State = luno.OrderStateComplete
+	o.CompletedTimestamp = c.timestamp()
+}
+
+// match fills resting limit orders whose limit price has been reached by the
+// last trade price of their market.
+func (c *Client) match(ctx context.Context) error {
+	c.mu.Lock()
+	pairs := make(map[string]decimal.Decimal)
+	for _, o := range c.orders {
+		if o.State == luno.OrderStatePending {
+			pairs[o.Pair] = decimal.Zero()
+		}
+	}
+	c.mu.Unlock()
+
+	for pair := range pairs {
+		res, err := c.API.GetTicker(ctx, &luno.GetTickerRequest{Pair: pair})
+		if err != nil {
+			return err
+		}
+		pairs[pair] = res.LastTrade
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range c.orderIDs {
+		o := c.orders[id]
+		last, ok := pairs[o.Pair]
+		if o.State != luno.OrderStatePending || !ok || last.Sign() <= 0 {
+			continue
+		}
+		if (o.bid && last.Cmp(o.LimitPrice) <= 0) || (!o.bid && last.Cmp(o.LimitPrice) >= 0) {
+			c.fill(o, o.LimitPrice, o.LimitVolume.Sub(o.Base), c.makerFee)
+			c.complete(o)
+		}
+	}
+	return nil
+}
+
+// StopOrder implements luno.API by cancelling a simulated order. Success is
+// false if the order had already completed.
+func (c *Client) StopOrder(ctx context.Context, req *luno.StopOrderRequest) (*luno.StopOrderResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	o, ok := c.orders[req.OrderId]
+	if !ok {
+		return nil, orderNotFound()
+	}
+	if o.State != luno.OrderStatePending {
+		return &luno.StopOrderResponse{Success: false}, nil
+	}
+	c.complete(o)
+	return &luno.StopOrderResponse{Success: true}, nil
+}
+
+// GetOrder implements luno.API with the simulated orders.
+func (c *Client) GetOrder(ctx context.Context, req *luno.GetOrderRequest) (*luno.GetOrderResponse, error) {
+	if err := c.match(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	o, ok := c.orders[req.Id]
+	if !ok {
+		return nil, orderNotFound()
+	}
+	res := luno.GetOrderResponse(o.Order)
+	return &res, nil
+}
+
+// ListOrders implements luno.API with the simulated orders, newest first.
+func (c *Client) ListOrders(ctx context.Context, req *luno.ListOrdersRequest) (*luno.ListOrdersResponse, error) {
+	if err := c.match(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var res luno.ListOrdersResponse
+	for i := len(c.orderIDs) - 1; i >= 0; i-- {
+		o := c.orders[c.orderIDs[i]]
+		if (req.Pair != "" && o.Pair != req.Pair) || (req.State != "" && o.State != req.State) {
+			continue
+		}
+		if req.Limit > 0 && int64(len(res.Orders)) >= req.Limit {
+			break
+		}
+		res.Orders = append(res.Orders, o.Order)
+	}
+	return &res, nil
+}
+
+// ListUserTrades implements luno.API with the simulated trades of req.Pair
+// at or after req.Since, oldest first unless req.SortDesc is set.
+func (c *Client) ListUserTrades(ctx context.Context, req *luno.ListUserTradesRequest) (*luno.ListUserTradesResponse, error) {
+	if err := c.match(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var res luno.ListUserTradesResponse
+	for _, t := range c.trades {
+		if t.Pair != req.Pair || time.Time(req.Since).After(time.Time(t.Timestamp)) {
+			continue
+		}
+		res.Trades = append(res.Trades, t)
+	}
+	if req.SortDesc {
+		sort.SliceStable(res.Trades, func(i, j int) bool {
+			return res.Trades[i].Sequence > res.Trades[j].Sequence
+		})
+	}
+	if req.Limit > 0 && int64(len(res.Trades)) > req.Limit {
+		res.Trades = res.Trades[:req.Limit]
+	}
+	return &res, nil
+}
+
+// GetOrderV2 implements luno.API by returning ErrNotSimulated, since only
+// GetOrder returns simulated orders.
+func (c *Client) GetOrderV2(ctx context.Context, req *luno.GetOrderV2Request) (*luno.GetOrderV2Response, error) {
+	return nil, ErrNotSimulated
+}
+
+// GetOrderV3 implements luno.API by returning ErrNotSimulated, since only
+// GetOrder returns simulated orders.
+func (c *Client) GetOrderV3(ctx context.Context, req *luno.GetOrderV3Request) (*luno.GetOrderV3Response, error) {
+	return nil, ErrNotSimulated
+}
+
+// ListOrdersV2 implements luno.API by returning ErrNotSimulated, since only
+// ListOrders returns simulated orders.
+func (c *Client) ListOrdersV2(ctx context.Context, req *luno.ListOrdersV2Request) (*luno.ListOrdersV2Response, error) {
+	return nil, ErrNotSimulated
+}
@@ -0,0 +1,345 @@
+// Package paper provides a luno.API which simulates trading against live
+// market data, so that strategies can be developed and demonstrated without
+// risking funds.
+//
+// Orders, balances and withdrawals are simulated locally, starting from the
+// balances given to New, while market data is read from the real API:
+//
+//	cl := luno.NewClient(luno.WithAuth(keyID, keySecret))
+//	p := paper.New(cl,
+//		paper.WithBalance("ZAR", decimal.NewFromInt64(10000)),
+//		paper.WithFees(decimal.NewFromFloat64(0.0001, 4), decimal.NewFromFloat64(0.001, 3)))
+//
+//	// Code using luno.API trades with p instead of cl.
+//
+// Market orders, and limit orders which cross the order book, are filled
+// against the current order book. Limit orders resting on the book are filled
+// at their limit price once the last trade price reaches it, which is checked
+// whenever the simulated orders, trades or balances are read.
+//
+// Methods which read the user's orders, trades, balances and withdrawals
+// return the simulated state. Other write methods, such as Move or
+// CreateAccount, aren't simulated and return ErrNotSimulated instead of
+// calling the real API. All other methods, such as GetTicker or
+// ListTransactions, are passed through to the real API.
+package paper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+// ErrNotSimulated is returned by methods which would change the real account
+// but aren't simulated.
+var ErrNotSimulated = errors.New("paper: method is not simulated")
+
+var _ luno.API = (*Client)(nil)
+
+// Client is a luno.API which simulates orders and withdrawals. Methods which
+// aren't simulated are passed through to the embedded API. It is safe for
+// concurrent use.
+type Client struct {
+	luno.API
+
+	makerFee, takerFee decimal.Decimal
+	now                func() time.Time
+
+	mu          sync.Mutex
+	accounts    map[string]*account
+	markets     map[string]luno.MarketInfo
+	orders      map[string]*order
+	orderIDs    []string
+	trades      []luno.Trade
+	withdrawals []*luno.Withdrawal
+	lastID      int64
+}
+
+type account struct {
+	id                string
+	balance, reserved decimal.Decimal
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBalance returns an option which starts the simulated account of asset,
+// e.g. "ZAR", with amount.
+func WithBalance(asset string, amount decimal.Decimal) Option {
+	return func(c *Client) {
+		c.account(asset).balance = amount
+	}
+}
+
+// WithFees returns an option which charges the given maker and taker fee
+// rates on simulated trades, e.g. 0.001 for 0.1%. Fees aren't charged by
+// default.
+func WithFees(maker, taker decimal.Decimal) Option {
+	return func(c *Client) {
+		c.makerFee, c.takerFee = maker, taker
+	}
+}
+
+// New returns a Client which reads market data from api, usually a
+// *luno.Client.
+func New(api luno.API, opts ...Option) *Client {
+	c := &Client{
+		API:      api,
+		makerFee: decimal.Zero(),
+		takerFee: decimal.Zero(),
+		now:      time.Now,
+		accounts: make(map[string]*account),
+		orders:   make(map[string]*order),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// account returns the simulated account of asset, creating an empty one if
+// needed. c.mu must be held, except when applying options.
+func (c *Client) account(asset string) *account {
+	a, ok := c.accounts[asset]
+	if !ok {
+		c.lastID++
+		a = &account{
+			id:       strconv.FormatInt(c.lastID, 10),
+			balance:  decimal.Zero(),
+			reserved: decimal.Zero(),
+		}
+		c.accounts[asset] = a
+	}
+	return a
+}
+
+func (a *account) available() decimal.Decimal {
+	return a.balance.Sub(a.reserved)
+}
+
+// reserve reserves amount of the account of asset. c.mu must be held.
+func (c *Client) reserve(asset string, amount decimal.Decimal) error {
+	a := c.account(asset)
+	if a.available().Cmp(amount) < 0 {
+		return insufficientBalance()
+	}
+	a.reserved = a.reserved.Add(amount)
+	return nil
+}
+
+func insufficientBalance() error {
+	return luno.Error{
+		Code:    luno.ErrCodeInsufficientBalance,
+		Message: "Insufficient balance",
+	}
+}
+
+func (c *Client) nextID(prefix string) string {
+	c.lastID++
+	return prefix + strconv.FormatInt(c.lastID, 10)
+}
+
+func (c *Client) timestamp() luno.Time {
+	return luno.Time(c.now())
+}
+
+// GetBalances implements luno.API with the simulated balances.
+func (c *Client) GetBalances(ctx context.Context, req *luno.GetBalancesRequest) (*luno.GetBalancesResponse, error) {
+	if err := c.match(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var res luno.GetBalancesResponse
+	for asset, a := range c.accounts {
+		if len(req.Assets) > 0 && !contains(req.Assets, asset) {
+			continue
+		}
+		res.Balance = append(res.Balance, luno.AccountBalance{
+			AccountId:   a.id,
+			Asset:       asset,
+			Balance:     a.balance,
+			Reserved:    a.reserved,
+			Unconfirmed: decimal.Zero(),
+		})
+	}
+	sort.Slice(res.Balance, func(i, j int) bool {
+		return res.Balance[i].Asset < res.Balance[j].Asset
+	})
+	return &res, nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Send implements luno.API by debiting the simulated balance. The send
+// completes immediately.
+func (c *Client) Send(ctx context.Context, req *luno.SendRequest) (*luno.SendResponse, error) {
+	w, err := c.withdraw(req.Currency, req.Amount, "SEND", req.ExternalId, luno.SendStatusCompleted)
+	if err != nil {
+		return nil, err
+	}
+	return &luno.SendResponse{Success: true, WithdrawalId: w.Id}, nil
+}
+
+// CreateWithdrawal implements luno.API by debiting the simulated balance of
+// the currency of req.Type, e.g. ZAR for ZAR_EFT. The withdrawal stays
+// pending, so that it can be cancelled.
+func (c *Client) CreateWithdrawal(ctx context.Context, req *luno.CreateWithdrawalRequest) (*luno.CreateWithdrawalResponse, error) {
+	i := strings.IndexByte(req.Type, '_')
+	if i <= 0 {
+		return nil, fmt.Errorf("paper: unknown withdrawal type %q", req.Type)
+	}
+	w, err := c.withdraw(req.Type[:i], req.Amount, req.Type, req.ExternalId, luno.SendStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	res := luno.CreateWithdrawalResponse(*w)
+	return &res, nil
+}
+
+func (c *Client) withdraw(currency string, amount decimal.Decimal, typ, externalID string,
+	status luno.SendStatus) (*luno.Withdrawal, error) {
+
+	if amount.Sign() <= 0 {
+		return nil, errors.New("paper: amount must be positive")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	a := c.account(currency)
+	if a.available().Cmp(amount) < 0 {
+		return nil, insufficientBalance()
+	}
+	a.balance = a.balance.Sub(amount)
+	w := &luno.Withdrawal{
+		Amount:     amount,
+		CreatedAt:  c.timestamp(),
+		Currency:   currency,
+		ExternalId: externalID,
+		Fee:        decimal.Zero(),
+		Id:         c.nextID(""),
+		Status:     status,
+		Type:       typ,
+	}
+	c.withdrawals = append(c.withdrawals, w)
+	return w, nil
+}
+
+// CancelWithdrawal implements luno.API by refunding a pending simulated
+// withdrawal.
+func (c *Client) CancelWithdrawal(ctx context.Context, req *luno.CancelWithdrawalRequest) (*luno.CancelWithdrawalResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w, err := c.withdrawal(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if w.Status != luno.SendStatusPending {
+		return nil, luno.Error{
+			Code:    "ErrInvalidArguments",
+			Message: "Withdrawal cannot be cancelled",
+		}
+	}
+	w.Status = luno.SendStatusCancelled
+	a := c.account(w.Currency)
+	a.balance = a.balance.Add(w.Amount)
+	res := luno.CancelWithdrawalResponse(*w)
+	return &res, nil
+}
+
+// GetWithdrawal implements luno.API with the simulated withdrawals.
+func (c *Client) GetWithdrawal(ctx context.Context, req *luno.GetWithdrawalRequest) (*luno.GetWithdrawalResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w, err := c.withdrawal(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	res := luno.GetWithdrawalResponse(*w)
+	return &res, nil
+}
+
+func (c *Client) withdrawal(id int64) (*luno.Withdrawal, error) {
+	for _, w := range c.withdrawals {
+		if w.Id == strconv.FormatInt(id, 10) {
+			return w, nil
+		}
+	}
+	return nil, luno.Error{Code: "ErrWithdrawalNotFound", Message: "Withdrawal not found"}
+}
+
+// ListWithdrawals implements luno.API with the simulated withdrawals, newest
+// first.
+func (c *Client) ListWithdrawals(ctx context.Context, req *luno.ListWithdrawalsRequest) (*luno.ListWithdrawalsResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var res luno.ListWithdrawalsResponse
+	for i := len(c.withdrawals) - 1; i >= 0; i-- {
+		res.Withdrawals = append(res.Withdrawals, *c.withdrawals[i])
+	}
+	return &res, nil
+}
+
+// CreateAccount implements luno.API by returning ErrNotSimulated.
+func (c *Client) CreateAccount(ctx context.Context, req *luno.CreateAccountRequest) (*luno.CreateAccountResponse, error) {
+	return nil, ErrNotSimulated
+}
+
+// CreateBeneficiary implements luno.API by returning ErrNotSimulated.
+func (c *Client) CreateBeneficiary(ctx context.Context, req *luno.CreateBeneficiaryRequest) (*luno.CreateBeneficiaryResponse, error) {
+	return nil, ErrNotSimulated
+}
+
+// CreateFundingAddress implements luno.API by returning ErrNotSimulated.
+func (c *Client) CreateFundingAddress(ctx context.Context, req *luno.CreateFundingAddressRequest) (*luno.CreateFundingAddressResponse, error) {
+	return nil, ErrNotSimulated
+}
+
+// CreateQuote implements luno.API by returning ErrNotSimulated.
+func (c *Client) CreateQuote(ctx context.Context, req *luno.CreateQuoteRequest) (*luno.CreateQuoteResponse, error) {
+	return nil, ErrNotSimulated
+}
+
+// DeleteBeneficiary implements luno.API by returning ErrNotSimulated.
+func (c *Client) DeleteBeneficiary(ctx context.Context, req *luno.DeleteBeneficiaryRequest) (*luno.DeleteBeneficiaryResponse, error) {
+	return nil, ErrNotSimulated
+}
+
+// DiscardQuote implements luno.API by returning ErrNotSimulated.
+func (c *Client) DiscardQuote(ctx context.Context, req *luno.DiscardQuoteRequest) (*luno.DiscardQuoteResponse, error) {
+	return nil, ErrNotSimulated
+}
+
+// ExerciseQuote implements luno.API by returning ErrNotSimulated.
+func (c *Client) ExerciseQuote(ctx context.Context, req *luno.ExerciseQuoteRequest) (*luno.ExerciseQuoteResponse, error) {
+	return nil, ErrNotSimulated
+}
+
+// Move implements luno.API by returning ErrNotSimulated.
+func (c *Client) Move(ctx context.Context, req *luno.MoveRequest) (*luno.MoveResponse, error) {
+	return nil, ErrNotSimulated
+}
+
+// UpdateAccountName implements luno.API by returning ErrNotSimulated.
+func (c *Client) UpdateAccountName(ctx context.Context, req *luno.UpdateAccountNameRequest) (*luno.UpdateAccountNameResponse, error) {
+	return nil, ErrNotSimulated
+}
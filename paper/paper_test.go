@@ -0,0 +1,270 @@
+package paper_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-go/lunotest"
+	"github.com/luno/luno-go/paper"
+)
+
+func dec(s string) decimal.Decimal {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// newMarket returns a fake of the real API with an XBTZAR market whose best
+// ask is 100 and best bid 99.
+func newMarket() *lunotest.Fake {
+	f := lunotest.NewFake()
+	f.SetResponse("Markets", &luno.MarketsResponse{Markets: []luno.MarketInfo{{
+		MarketId:        "XBTZAR",
+		BaseCurrency:    "XBT",
+		CounterCurrency: "ZAR",
+		VolumeScale:     2,
+		PriceScale:      0,
+	}}})
+	f.SetResponse("GetOrderBook", &luno.GetOrderBookResponse{
+		Asks: []luno.OrderBookEntry{
+			{Price: dec("100"), Volume: dec("1")},
+			{Price: dec("101"), Volume: dec("2")},
+		},
+		Bids: []luno.OrderBookEntry{
+			{Price: dec("99"), Volume: dec("1")},
+			{Price: dec("98"), Volume: dec("2")},
+		},
+	})
+	f.SetResponse("GetTicker", &luno.GetTickerResponse{Pair: "XBTZAR", LastTrade: dec("98.5")})
+	return f
+}
+
+func balances(t *testing.T, c *paper.Client) map[string]string {
+	t.Helper()
+	res, err := c.GetBalances(context.Background(), &luno.GetBalancesRequest{})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	m := make(map[string]string)
+	for _, b := range res.Balance {
+		m[b.Asset] = b.Balance.String() + "/" + b.Reserved.String()
+	}
+	return m
+}
+
+func checkBalances(t *testing.T, c *paper.Client, xbt, zar string) {
+	t.Helper()
+	b := balances(t, c)
+	if b["XBT"] != xbt || b["ZAR"] != zar {
+		t.Errorf("Expected XBT %s and ZAR %s (balance/reserved), got XBT %s and ZAR %s",
+			xbt, zar, b["XBT"], b["ZAR"])
+	}
+}
+
+func TestMarketOrder(t *testing.T) {
+	f := newMarket()
+	c := paper.New(f, paper.WithBalance("ZAR", dec("1000")),
+		paper.WithFees(dec("0"), dec("0.01")))
+	ctx := context.Background()
+
+	res, err := c.PostMarketOrder(ctx, &luno.PostMarketOrderRequest{
+		Pair:          "XBTZAR",
+		Type:          luno.OrderTypeBuy,
+		CounterVolume: dec("201"),
+	})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	o, err := c.GetOrder(ctx, &luno.GetOrderRequest{Id: res.OrderId})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if o.State != luno.OrderStateComplete || o.Base.String() != "2.00" || o.Counter.String() != "201.00" {
+		t.Errorf("Expected 2.00 bought for 201.00, got %s for %s (%s)", o.Base, o.Counter, o.State)
+	}
+	checkBalances(t, c, "1.9800/0", "799.00/0")
+
+	if len(f.CallsTo("PostMarketOrder")) != 0 {
+		t.Errorf("Expected no order to be placed with the real API")
+	}
+}
+
+func TestLimitOrder(t *testing.T) {
+	f := newMarket()
+	c := paper.New(f, paper.WithBalance("XBT", dec("2")))
+	ctx := context.Background()
+
+	// Crosses the best bid, and rests for the remaining volume.
+	res, err := c.PostLimitOrder(ctx, &luno.PostLimitOrderRequest{
+		Pair:   "XBTZAR",
+		Type:   luno.OrderTypeAsk,
+		Price:  dec("99"),
+		Volume: dec("1.5"),
+	})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	o, err := c.GetOrder(ctx, &luno.GetOrderRequest{Id: res.OrderId})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if o.State != luno.OrderStatePending || o.Base.String() != "1" {
+		t.Errorf("Expected pending order with 1 sold, got %s with %s", o.State, o.Base)
+	}
+	checkBalances(t, c, "1/0.5", "99/0")
+
+	// Fills once the last trade price reaches the limit price.
+	f.SetResponse("GetTicker", &luno.GetTickerResponse{Pair: "XBTZAR", LastTrade: dec("99")})
+	o, err = c.GetOrder(ctx, &luno.GetOrderRequest{Id: res.OrderId})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if o.State != luno.OrderStateComplete || o.Base.String() != "1.5" {
+		t.Errorf("Expected complete order with 1.5 sold, got %s with %s", o.State, o.Base)
+	}
+	checkBalances(t, c, "0.5/0.0", "148.5/0")
+
+	trades, err := c.ListUserTrades(ctx, &luno.ListUserTradesRequest{Pair: "XBTZAR"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(trades.Trades) != 2 {
+		t.Errorf("Expected 2 trades, got %d", len(trades.Trades))
+	}
+}
+
+func TestStopOrder(t *testing.T) {
+	c := paper.New(newMarket(), paper.WithBalance("ZAR", dec("1000")))
+	ctx := context.Background()
+
+	res, err := c.PostLimitOrder(ctx, &luno.PostLimitOrderRequest{
+		Pair:   "XBTZAR",
+		Type:   luno.OrderTypeBid,
+		Price:  dec("90"),
+		Volume: dec("2"),
+	})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	checkBalances(t, c, "", "1000/180")
+
+	stop, err := c.StopOrder(ctx, &luno.StopOrderRequest{OrderId: res.OrderId})
+	if err != nil || !stop.Success {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	checkBalances(t, c, "", "1000/0")
+
+	orders, err := c.ListOrders(ctx, &luno.ListOrdersRequest{State: luno.OrderStatePending})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(orders.Orders) != 0 {
+		t.Errorf("Expected no pending orders, got %d", len(orders.Orders))
+	}
+
+	_, err = c.StopOrder(ctx, &luno.StopOrderRequest{OrderId: "BXUNKNOWN"})
+	if !errors.Is(err, luno.ErrOrderNotFound) {
+		t.Errorf("Expected ErrOrderNotFound, got %v", err)
+	}
+}
+
+func TestPostOnly(t *testing.T) {
+	c := paper.New(newMarket(), paper.WithBalance("ZAR", dec("1000")))
+	ctx := context.Background()
+
+	res, err := c.PostLimitOrder(ctx, &luno.PostLimitOrderRequest{
+		Pair:     "XBTZAR",
+		Type:     luno.OrderTypeBid,
+		Price:    dec("100"),
+		Volume:   dec("1"),
+		PostOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	o, err := c.GetOrder(ctx, &luno.GetOrderRequest{Id: res.OrderId})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if o.State != luno.OrderStateComplete || o.Base.Sign() != 0 {
+		t.Errorf("Expected cancelled order without trades, got %s with %s", o.State, o.Base)
+	}
+	checkBalances(t, c, "", "1000/0")
+}
+
+func TestInsufficientBalance(t *testing.T) {
+	c := paper.New(newMarket(), paper.WithBalance("ZAR", dec("50")))
+	_, err := c.PostLimitOrder(context.Background(), &luno.PostLimitOrderRequest{
+		Pair:   "XBTZAR",
+		Type:   luno.OrderTypeBid,
+		Price:  dec("90"),
+		Volume: dec("1"),
+	})
+	if !errors.Is(err, luno.ErrInsufficientBalance) {
+		t.Errorf("Expected ErrInsufficientBalance, got %v", err)
+	}
+}
+
+func TestWithdrawals(t *testing.T) {
+	f := newMarket()
+	c := paper.New(f, paper.WithBalance("ZAR", dec("1000")), paper.WithBalance("XBT", dec("1")))
+	ctx := context.Background()
+
+	send, err := c.Send(ctx, &luno.SendRequest{Currency: "XBT", Amount: dec("0.25"), Address: "addr"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	w, err := c.CreateWithdrawal(ctx, &luno.CreateWithdrawalRequest{Type: "ZAR_EFT", Amount: dec("400")})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	checkBalances(t, c, "0.75/0", "600/0")
+
+	if _, err := c.CancelWithdrawal(ctx, &luno.CancelWithdrawalRequest{Id: mustParse(t, send.WithdrawalId)}); err == nil {
+		t.Errorf("Expected error cancelling a completed send, got nil")
+	}
+	if _, err := c.CancelWithdrawal(ctx, &luno.CancelWithdrawalRequest{Id: mustParse(t, w.Id)}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	checkBalances(t, c, "0.75/0", "1000/0")
+
+	list, err := c.ListWithdrawals(ctx, &luno.ListWithdrawalsRequest{})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(list.Withdrawals) != 2 || list.Withdrawals[0].Status != "CANCELLED" {
+		t.Errorf("Expected 2 withdrawals, the newest cancelled, got %+v", list.Withdrawals)
+	}
+	if len(f.CallsTo("Send")) != 0 || len(f.CallsTo("CreateWithdrawal")) != 0 {
+		t.Errorf("Expected no withdrawals with the real API")
+	}
+}
+
+func mustParse(t *testing.T, id string) int64 {
+	t.Helper()
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return i
+}
+
+func TestNotSimulated(t *testing.T) {
+	f := newMarket()
+	c := paper.New(f)
+	if _, err := c.Move(context.Background(), &luno.MoveRequest{}); err != paper.ErrNotSimulated {
+		t.Errorf("Expected ErrNotSimulated, got %v", err)
+	}
+	if _, err := c.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"}); err != nil {
+		t.Errorf("Expected ticker from the real API, got %v", err)
+	}
+	if len(f.CallsTo("Move")) != 0 || len(f.CallsTo("GetTicker")) != 1 {
+		t.Errorf("Expected only GetTicker to be passed through, got %v", f.Calls())
+	}
+}
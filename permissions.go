@@ -0,0 +1,104 @@
+package luno
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Permission is an API key permission.
+type Permission string
+
+const (
+	PermReadAddresses     Permission = "Perm_R_Addresses"
+	PermReadBalance       Permission = "Perm_R_Balance"
+	PermReadBeneficiaries Permission = "Perm_R_Beneficiaries"
+	PermReadOrders        Permission = "Perm_R_Orders"
+	PermReadTransactions  Permission = "Perm_R_Transactions"
+	PermReadTransfers     Permission = "Perm_R_Transfers"
+	PermReadWithdrawals   Permission = "Perm_R_Withdrawals"
+	PermWriteAddresses    Permission = "Perm_W_Addresses"
+	PermWriteOrders       Permission = "Perm_W_Orders"
+	PermWriteSend         Permission = "Perm_W_Send"
+	PermWriteTransfers    Permission = "Perm_W_Transfers"
+	PermWriteWithdrawals  Permission = "Perm_W_Withdrawals"
+)
+
+// ErrCodeInsufficientPermissions is the error code returned when the API key
+// lacks the permission required by an endpoint.
+const ErrCodeInsufficientPermissions = "ErrInsufficientPerms"
+
+// permissionProbes are read-only calls which require a single permission.
+var permissionProbes = map[Permission]func(context.Context, *Client) error{
+	PermReadAddresses: func(ctx context.Context, cl *Client) error {
+		_, err := cl.GetFundingAddress(ctx, &GetFundingAddressRequest{Asset: "XBT"})
+		return err
+	},
+	PermReadBalance: func(ctx context.Context, cl *Client) error {
+		_, err := cl.GetBalances(ctx, &GetBalancesRequest{})
+		return err
+	},
+	PermReadBeneficiaries: func(ctx context.Context, cl *Client) error {
+		_, err := cl.ListBeneficiaries(ctx, &ListBeneficiariesRequest{})
+		return err
+	},
+	PermReadOrders: func(ctx context.Context, cl *Client) error {
+		_, err := cl.ListOrders(ctx, &ListOrdersRequest{Limit: 1})
+		return err
+	},
+	PermReadTransactions: probeTransactions,
+	PermReadTransfers: func(ctx context.Context, cl *Client) error {
+		_, err := cl.ListMoves(ctx, &ListMovesRequest{Limit: 1})
+		return err
+	},
+	PermReadWithdrawals: func(ctx context.Context, cl *Client) error {
+		_, err := cl.ListWithdrawals(ctx, &ListWithdrawalsRequest{})
+		return err
+	},
+}
+
+// probeTransactions lists the first transaction of the first account. The
+// account is looked up with GetBalances, since transactions can only be
+// listed by account.
+func probeTransactions(ctx context.Context, cl *Client) error {
+	bal, err := cl.GetBalances(ctx, &GetBalancesRequest{})
+	if IsErrorCode(err, ErrCodeInsufficientPermissions) {
+		return fmt.Errorf("luno: checking %s requires %s", PermReadTransactions, PermReadBalance)
+	} else if err != nil {
+		return err
+	}
+	if len(bal.Balance) == 0 {
+		return fmt.Errorf("luno: no account to check %s with", PermReadTransactions)
+	}
+	id, err := strconv.ParseInt(bal.Balance[0].AccountId, 10, 64)
+	if err != nil {
+		return fmt.Errorf("luno: invalid account id %q: %w", bal.Balance[0].AccountId, err)
+	}
+	_, err = cl.ListTransactions(ctx, &ListTransactionsRequest{Id: id, MinRow: 1, MaxRow: 2})
+	return err
+}
+
+// CheckPermissions returns which of the required permissions the client's API
+// key lacks. Luno doesn't expose the permissions of a key, so each permission
+// is checked by making a read-only call which requires it. Permissions which
+// can't be checked without side effects, such as the write permissions,
+// result in an error. Checking PermReadTransactions also requires
+// PermReadBalance, to find an account to list the transactions of.
+func (cl *Client) CheckPermissions(ctx context.Context, required ...Permission) ([]Permission, error) {
+	for _, p := range required {
+		if _, ok := permissionProbes[p]; !ok {
+			return nil, fmt.Errorf("luno: permission %s can't be checked", p)
+		}
+	}
+
+	var missing []Permission
+	for _, p := range required {
+		err := permissionProbes[p](ctx, cl)
+		if IsErrorCode(err, ErrCodeInsufficientPermissions) {
+			missing = append(missing, p)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return missing, nil
+}
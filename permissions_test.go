@@ -0,0 +1,52 @@
+package luno_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestCheckPermissions(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/api/1/balance":
+			w.Write([]byte(`{"balance":[{"account_id":"123","asset":"XBT"}]}`))
+		case "/api/1/funding_address":
+			w.Write([]byte(`{"asset":"XBT","address":"addr"}`))
+		case "/api/1/listorders":
+			w.Write([]byte(`{"orders":[]}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"Insufficient permissions","error_code":"ErrInsufficientPerms"}`))
+		}
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	missing, err := cl.CheckPermissions(context.Background(),
+		luno.PermReadBalance, luno.PermReadOrders, luno.PermReadWithdrawals,
+		luno.PermReadAddresses, luno.PermReadTransactions)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	exp := []luno.Permission{luno.PermReadWithdrawals, luno.PermReadTransactions}
+	if !reflect.DeepEqual(missing, exp) {
+		t.Errorf("Expected missing %v, got %v", exp, missing)
+	}
+	if p := paths[len(paths)-1]; p != "/api/1/accounts/123/transactions" {
+		t.Errorf("Expected transactions of account 123 to be listed, got %s", p)
+	}
+
+	_, err = cl.CheckPermissions(context.Background(), luno.PermWriteSend)
+	if err == nil {
+		t.Errorf("Expected error for write permission, got nil")
+	}
+}
@@ -0,0 +1,55 @@
+package luno
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// tradesPageSize is the maximum number of trades ListUserTrades returns per
+// call.
+const tradesPageSize = 1000
+
+// NetPosition returns the net change in base and counter currency from the
+// user's trades on pair since the given time. Bids add base and spend
+// counter, asks do the opposite, and fees are deducted in the currency they
+// were charged in.
+func (cl *Client) NetPosition(ctx context.Context, pair string,
+	since time.Time) (base, counter decimal.Decimal, err error) {
+
+	base, counter = decimal.Zero(), decimal.Zero()
+	req := &ListUserTradesRequest{
+		Pair:  pair,
+		Since: Time(since),
+		Limit: tradesPageSize,
+	}
+	for {
+		res, err := cl.ListUserTrades(ctx, req)
+		if err != nil {
+			return decimal.Decimal{}, decimal.Decimal{}, err
+		}
+
+		for _, t := range res.Trades {
+			switch t.Type {
+			case OrderTypeBid:
+				base = base.Add(t.Base)
+				counter = counter.Sub(t.Counter)
+			case OrderTypeAsk:
+				base = base.Sub(t.Base)
+				counter = counter.Add(t.Counter)
+			default:
+				return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf(
+					"luno: trade %d has unknown type %q", t.Sequence, t.Type)
+			}
+			base = base.Sub(t.FeeBase)
+			counter = counter.Sub(t.FeeCounter)
+			req.AfterSeq = t.Sequence + 1
+		}
+
+		if !hasMoreTrades(res, tradesPageSize) {
+			return base, counter, nil
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package luno_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestNetPosition(t *testing.T) {
+	trades := []string{
+		// Bought 1 BTC for 100 ZAR with a 0.01 BTC fee.
+		`{"sequence":1,"type":"BID","base":"1","counter":"100","fee_base":"0.01","fee_counter":"0"}`,
+		// Sold 0.5 BTC for 60 ZAR with a 0.6 ZAR fee.
+		`{"sequence":2,"type":"ASK","base":"0.5","counter":"60","fee_base":"0","fee_counter":"0.6"}`,
+		// Bought 0.25 BTC for 30 ZAR as maker with no fee.
+		`{"sequence":3,"type":"BID","base":"0.25","counter":"30","fee_base":"0","fee_counter":"0"}`,
+	}
+
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/api/1/listtrades" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		if r.FormValue("pair") != "XBTZAR" {
+			t.Errorf("Expected pair XBTZAR, got %q", r.FormValue("pair"))
+		}
+		if r.FormValue("since") != "1000" {
+			t.Errorf("Expected since 1000, got %q", r.FormValue("since"))
+		}
+		after, _ := strconv.Atoi(r.FormValue("after_seq"))
+		var page []string
+		for i, tr := range trades {
+			if i+1 >= after {
+				page = append(page, tr)
+			}
+		}
+		fmt.Fprintf(w, `{"trades":[%s]}`, strings.Join(page, ","))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	base, counter, err := cl.NetPosition(context.Background(), "XBTZAR", time.Unix(1, 0))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if base.String() != "0.74" {
+		t.Errorf("Expected base 0.74, got %s", base)
+	}
+	if counter.String() != "-70.6" {
+		t.Errorf("Expected counter -70.6, got %s", counter)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
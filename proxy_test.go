@@ -0,0 +1,96 @@
+package luno_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"sync/atomic"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+// newFakeProxy returns a forward proxy which answers every request itself and
+// records the proxied host and Proxy-Authorization header.
+func newFakeProxy(t *testing.T, hits *int32, auth *atomic.Value) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "api.luno.test" {
+			t.Errorf("Expected proxied host api.luno.test, got %q", r.URL.Host)
+		}
+		atomic.AddInt32(hits, 1)
+		if auth != nil {
+			auth.Store(r.Header.Get("Proxy-Authorization"))
+		}
+		w.Write([]byte(`{"pair":"XBTZAR"}`))
+	}))
+}
+
+func TestSetProxy(t *testing.T) {
+	var hits int32
+	var auth atomic.Value
+	proxy := newFakeProxy(t, &hits, &auth)
+	defer proxy.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL("http://api.luno.test")
+	if err := cl.SetProxy("http://user:pass@" + proxy.Listener.Addr().String()); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	_, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if atomic.LoadInt32(&hits) != 1 {
+		t.Errorf("Expected request to go through the proxy")
+	}
+	exp := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:pass"))
+	if got, _ := auth.Load().(string); got != exp {
+		t.Errorf("Expected Proxy-Authorization %q, got %q", exp, got)
+	}
+}
+
+func TestSetProxyInvalid(t *testing.T) {
+	for _, u := range []string{"ftp://proxy:21", "http://", "://bad", "proxy:3128"} {
+		cl := luno.NewClient()
+		if err := cl.SetProxy(u); err == nil {
+			t.Errorf("Expected error for %q, got nil", u)
+		}
+	}
+}
+
+func TestProxyFromEnvironment(t *testing.T) {
+	// The environment is only read once per process, so the request is made
+	// from a child process.
+	if os.Getenv("LUNO_TEST_PROXY_CHILD") == "1" {
+		cl := luno.NewClient()
+		cl.SetBaseURL("http://api.luno.test")
+		_, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+		if err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		return
+	}
+
+	var hits int32
+	proxy := newFakeProxy(t, &hits, nil)
+	defer proxy.Close()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestProxyFromEnvironment$")
+	cmd.Env = append(os.Environ(),
+		"LUNO_TEST_PROXY_CHILD=1",
+		"HTTP_PROXY="+proxy.URL,
+		"http_proxy="+proxy.URL,
+		"NO_PROXY=",
+		"no_proxy=",
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Expected child to succeed, got %v: %s", err, out)
+	}
+	if atomic.LoadInt32(&hits) != 1 {
+		t.Errorf("Expected request to go through the environment proxy")
+	}
+}
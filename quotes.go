@@ -0,0 +1,76 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// ErrQuoteExpired is returned by BuyAtQuote when the quote expires before it
+// is exercised.
+var ErrQuoteExpired = errors.New("luno: quote expired")
+
+// Expired returns whether q can no longer be exercised at now. A quote
+// without an expiry time never expires.
+func (q Quote) Expired(now time.Time) bool {
+	return q.TimeLeft(now) <= 0
+}
+
+// TimeLeft returns how long after now q can still be exercised, or zero if it
+// has expired. It returns the maximum duration for a quote without an expiry
+// time.
+func (q Quote) TimeLeft(now time.Time) time.Duration {
+	expires := time.Time(q.ExpiresAt)
+	if expires.IsZero() {
+		return time.Duration(1<<63 - 1)
+	}
+	if d := expires.Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// QuoteTimeLeft returns how long q can still be exercised. Quotes expire by
+// the server clock, so the time left is measured from ServerNow.
+func (cl *Client) QuoteTimeLeft(q Quote) time.Duration {
+	return q.TimeLeft(cl.ServerNow())
+}
+
+// BuyAtQuote buys amount of the base currency of pair, e.g. XBT for XBTZAR,
+// by creating a quote and exercising it straight away. Use CreateQuote and
+// ExerciseQuote directly to inspect the price before trading.
+//
+// ErrQuoteExpired is returned if the quote expires before it can be
+// exercised.
+func (cl *Client) BuyAtQuote(ctx context.Context, pair string, amount decimal.Decimal) (*ExerciseQuoteResponse, error) {
+	q, err := cl.CreateQuote(ctx, &CreateQuoteRequest{
+		Pair:       pair,
+		Type:       OrderTypeBuy,
+		BaseAmount: amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseInt(q.Id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("luno: invalid quote id %q: %w", q.Id, err)
+	}
+	if cl.QuoteTimeLeft(Quote(*q)) == 0 {
+		return nil, ErrQuoteExpired
+	}
+
+	res, err := cl.ExerciseQuote(ctx, &ExerciseQuoteRequest{Id: id})
+	if err != nil {
+		// Luno rejects expired quotes, so report the expiry rather than the
+		// rejection if the quote ran out while it was being exercised.
+		if cl.QuoteTimeLeft(Quote(*q)) == 0 {
+			return nil, ErrQuoteExpired
+		}
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,92 @@
+package luno_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func TestQuoteTimeLeft(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	q := luno.Quote{ExpiresAt: luno.Time(now.Add(30 * time.Second))}
+
+	if d := q.TimeLeft(now); d != 30*time.Second {
+		t.Errorf("Expected 30s left, got %v", d)
+	}
+	if q.Expired(now) {
+		t.Errorf("Expected quote not to be expired")
+	}
+	if !q.Expired(now.Add(30 * time.Second)) {
+		t.Errorf("Expected quote to be expired at its expiry time")
+	}
+	if d := q.TimeLeft(now.Add(time.Minute)); d != 0 {
+		t.Errorf("Expected no time left, got %v", d)
+	}
+	if (luno.Quote{}).Expired(now) {
+		t.Errorf("Expected quote without expiry time not to expire")
+	}
+}
+
+func quoteServer(expiresAt time.Time, exercises *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exercised := false
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/1/quotes":
+			if r.FormValue("pair") != "XBTZAR" || r.FormValue("type") != "BUY" ||
+				r.FormValue("base_amount") != "0.1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		case r.Method == http.MethodPut && r.URL.Path == "/api/1/quotes/42":
+			*exercises++
+			exercised = true
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"id":"42","pair":"XBTZAR","type":"BUY","base_amount":"0.1",`+
+			`"counter_amount":"1000","expires_at":%d,"exercised":%t}`,
+			expiresAt.UnixNano()/1e6, exercised)
+	}))
+}
+
+func TestBuyAtQuote(t *testing.T) {
+	var exercises int
+	srv := quoteServer(time.Now().Add(time.Minute), &exercises)
+	defer srv.Close()
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	res, err := cl.BuyAtQuote(context.Background(), "XBTZAR", decimal.NewFromFloat64(0.1, 1))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if !res.Exercised || res.CounterAmount.String() != "1000" {
+		t.Errorf("Expected exercised quote for 1000, got %+v", res)
+	}
+	if exercises != 1 {
+		t.Errorf("Expected quote to be exercised once, got %d", exercises)
+	}
+}
+
+func TestBuyAtQuoteExpired(t *testing.T) {
+	var exercises int
+	srv := quoteServer(time.Now().Add(-time.Second), &exercises)
+	defer srv.Close()
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	_, err := cl.BuyAtQuote(context.Background(), "XBTZAR", decimal.NewFromFloat64(0.1, 1))
+	if err != luno.ErrQuoteExpired {
+		t.Errorf("Expected ErrQuoteExpired, got %v", err)
+	}
+	if exercises != 0 {
+		t.Errorf("Expected expired quote not to be exercised, got %d", exercises)
+	}
+}
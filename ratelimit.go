@@ -0,0 +1,257 @@
+package luno
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Priority determines the order in which requests waiting on the client's
+// rate limiter are let through. Higher priority requests go first; requests
+// of equal priority are let through in the order they arrived.
+type Priority int
+
+const (
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 1
+)
+
+// SetRateLimit limits the rate of requests made by the client to
+// requestsPerMinute, allowing bursts of up to burst requests. Requests block
+// until they are allowed through or their context is done, unless
+// RateLimitReject is set with SetRateLimitPolicy. A requestsPerMinute of zero
+// disables rate limiting, which is the default.
+//
+// If a separate limit for authenticated requests is set with
+// SetAuthRateLimit, this limit only applies to public requests.
+func (cl *Client) SetRateLimit(requestsPerMinute float64, burst int) {
+	cl.limiter = cl.makeRateLimiter(requestsPerMinute, burst)
+}
+
+// SetAuthRateLimit limits the rate of authenticated requests separately from
+// public requests, since Luno applies separate quotas to them. It works like
+// SetRateLimit, which then only limits public requests. A requestsPerMinute
+// of zero removes the separate limit, so that authenticated requests share
+// the limit set with SetRateLimit again.
+func (cl *Client) SetAuthRateLimit(requestsPerMinute float64, burst int) {
+	cl.authLimiter = cl.makeRateLimiter(requestsPerMinute, burst)
+}
+
+func (cl *Client) makeRateLimiter(requestsPerMinute float64, burst int) *rateLimiter {
+	if requestsPerMinute <= 0 {
+		return nil
+	}
+	return newRateLimiter(cl.clock, requestsPerMinute/60, burst)
+}
+
+// rateLimiterFor returns the rate limiter which applies to a request, or nil.
+func (cl *Client) rateLimiterFor(auth bool) *rateLimiter {
+	if auth && cl.authLimiter != nil {
+		return cl.authLimiter
+	}
+	return cl.limiter
+}
+
+// waitRateLimit applies the rate limit of c, if any, before an attempt.
+func (cl *Client) waitRateLimit(ctx context.Context, c *call) error {
+	l := cl.rateLimiterFor(c.auth)
+	if l == nil {
+		return nil
+	}
+	if cl.rateLimitPolicy == RateLimitReject {
+		if ok, wait := l.take(); !ok {
+			return &RateLimitError{RetryAfter: wait}
+		}
+		return nil
+	}
+
+	t0 := cl.clock.Now()
+	blocked, err := l.wait(ctx, priorityFromContext(ctx))
+	if blocked {
+		cl.reportThrottle(ctx, ThrottleInfo{
+			RequestInfo: c.info,
+			Reason:      ThrottleRateLimiter,
+			Wait:        cl.clock.Now().Sub(t0),
+		})
+	}
+	return err
+}
+
+// RateLimitPolicy determines what happens to a request which would exceed
+// the client's rate limit.
+type RateLimitPolicy int
+
+const (
+	// RateLimitWait blocks the request until it is allowed through or its
+	// context is done. This is the default.
+	RateLimitWait RateLimitPolicy = iota
+
+	// RateLimitReject fails the request with a *RateLimitError without
+	// sending it, so that the caller can decide whether to wait.
+	RateLimitReject
+)
+
+// SetRateLimitPolicy sets what happens to requests which would exceed the
+// limits set with SetRateLimit and SetAuthRateLimit.
+func (cl *Client) SetRateLimitPolicy(p RateLimitPolicy) {
+	cl.rateLimitPolicy = p
+}
+
+// RateLimitError is returned for a request which would have exceeded the
+// client's rate limit when RateLimitReject is set. The request wasn't sent.
+type RateLimitError struct {
+	// RetryAfter is how long until a request is expected to be allowed
+	// through, if no other requests are made in the meantime.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("luno: client rate limit exceeded, retry after %s",
+		e.RetryAfter)
+}
+
+type rateWaiter struct {
+	priority Priority
+	ready    chan struct{}
+	granted  bool
+}
+
+// rateLimiter is a token bucket which grants tokens to waiters in order of
+// priority.
+type rateLimiter struct {
+	mu      sync.Mutex
+	clock   Clock
+	rate    float64 // tokens per second
+	burst   float64
+	tokens  float64
+	last    time.Time
+	waiters []*rateWaiter
+	armed   bool
+}
+
+func newRateLimiter(clk Clock, rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		clock:  clk,
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   clk.Now(),
+	}
+}
+
+// wait blocks until a token is available or ctx is done. It returns whether
+// it had to block.
+func (l *rateLimiter) wait(ctx context.Context, p Priority) (bool, error) {
+	l.mu.Lock()
+	l.refill()
+	if len(l.waiters) == 0 && l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return false, nil
+	}
+
+	w := &rateWaiter{priority: p, ready: make(chan struct{})}
+	i := len(l.waiters)
+	for i > 0 && l.waiters[i-1].priority < p {
+		i--
+	}
+	l.waiters = append(l.waiters, nil)
+	copy(l.waiters[i+1:], l.waiters[i:])
+	l.waiters[i] = w
+	l.schedule()
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return true, nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if w.granted {
+			// Lost the race, so give the token back.
+			l.tokens++
+			l.release()
+			return true, ctx.Err()
+		}
+		for i, o := range l.waiters {
+			if o == w {
+				l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+				break
+			}
+		}
+		return true, ctx.Err()
+	}
+}
+
+// take takes a token without blocking if one is available. Otherwise it
+// returns how long until the next token is due.
+func (l *rateLimiter) take() (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	if len(l.waiters) == 0 && l.tokens >= 1 {
+		l.tokens--
+		return true, 0
+	}
+	// Waiters are ahead of this request, so count the tokens they need.
+	need := float64(len(l.waiters)) + 1 - l.tokens
+	return false, time.Duration(need / l.rate * float64(time.Second))
+}
+
+// refill adds the tokens accrued since the last refill. It must be called
+// with mu held.
+func (l *rateLimiter) refill() {
+	now := l.clock.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
+
+// setClock switches the limiter to clk, keeping the tokens accrued so far.
+// A timer already armed still fires on the previous clock.
+func (l *rateLimiter) setClock(clk Clock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	l.clock = clk
+	l.last = clk.Now()
+}
+
+// schedule arms a timer to release waiters when the next token is due. It
+// must be called with mu held.
+func (l *rateLimiter) schedule() {
+	if l.armed || len(l.waiters) == 0 {
+		return
+	}
+	l.armed = true
+	d := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	ch := l.clock.After(d)
+	go func() {
+		<-ch
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.armed = false
+		l.refill()
+		l.release()
+	}()
+}
+
+// release grants available tokens to waiters in order. It must be called
+// with mu held.
+func (l *rateLimiter) release() {
+	for len(l.waiters) > 0 && l.tokens >= 1 {
+		w := l.waiters[0]
+		l.waiters = l.waiters[1:]
+		l.tokens--
+		w.granted = true
+		close(w.ready)
+	}
+	l.schedule()
+}
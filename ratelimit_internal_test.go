@@ -0,0 +1,130 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func (l *rateLimiter) numWaiters() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.waiters)
+}
+
+func TestRateLimiterPriority(t *testing.T) {
+	l := newRateLimiter(realClock{}, 50, 1) // One token every 20ms.
+	ctx := context.Background()
+
+	// Use up the burst.
+	if _, err := l.wait(ctx, PriorityNormal); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	start := func(name string, p Priority) {
+		n := l.numWaiters()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := l.wait(ctx, p); err != nil {
+				t.Errorf("Expected success, got %v", err)
+			}
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}()
+		for l.numWaiters() == n {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	start("low1", PriorityLow)
+	start("normal", PriorityNormal)
+	start("low2", PriorityLow)
+	start("high", PriorityHigh)
+	wg.Wait()
+
+	exp := []string{"high", "normal", "low1", "low2"}
+	for i := range exp {
+		if order[i] != exp[i] {
+			t.Fatalf("Expected order %v, got %v", exp, order)
+		}
+	}
+}
+
+func TestRateLimiterContextDone(t *testing.T) {
+	l := newRateLimiter(realClock{}, 1, 1)
+	if _, err := l.wait(context.Background(), PriorityNormal); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.wait(ctx, PriorityHigh); err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if n := l.numWaiters(); n != 0 {
+		t.Errorf("Expected no waiters, got %d", n)
+	}
+}
+
+func TestRateLimitBuckets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	clk := newFakeClock()
+	cl := NewClient()
+	cl.clock = clk
+	cl.SetBaseURL(srv.URL)
+	if err := cl.SetAuth("key", "secret"); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	cl.SetRateLimitPolicy(RateLimitReject)
+	cl.SetRateLimit(60, 1)
+
+	do := func(auth bool) error {
+		var res interface{}
+		return cl.do(context.Background(), "GET", "/", nil, &res, auth)
+	}
+
+	// Without a separate limit, authenticated requests share the bucket.
+	if err := do(false); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	var rle *RateLimitError
+	if err := do(true); !errors.As(err, &rle) {
+		t.Fatalf("Expected RateLimitError, got %v", err)
+	}
+	if rle.RetryAfter != time.Second {
+		t.Errorf("Expected retry after 1s, got %s", rle.RetryAfter)
+	}
+
+	cl.SetAuthRateLimit(60, 1)
+	if err := do(true); err != nil {
+		t.Errorf("Expected separate bucket for authenticated requests, got %v", err)
+	}
+	if err := do(true); !errors.As(err, &rle) {
+		t.Errorf("Expected RateLimitError, got %v", err)
+	}
+	if err := do(false); !errors.As(err, &rle) {
+		t.Errorf("Expected RateLimitError, got %v", err)
+	}
+
+	clk.Advance(time.Second)
+	if err := do(false); err != nil {
+		t.Errorf("Expected success after refill, got %v", err)
+	}
+	if err := do(true); err != nil {
+		t.Errorf("Expected success after refill, got %v", err)
+	}
+}
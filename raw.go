@@ -0,0 +1,34 @@
+package luno
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// GetJSON makes a GET request to an arbitrary API path, e.g. /api/1/tickers,
+// and returns the decoded JSON object. It can be used to call endpoints which
+// don't have a typed method yet. Numbers are returned as json.Number rather
+// than float64 so that decimal amounts keep their full precision.
+func (cl *Client) GetJSON(ctx context.Context, path string,
+	params map[string]string, auth bool) (map[string]interface{}, error) {
+
+	var req interface{}
+	if params != nil {
+		req = params
+	}
+	var raw json.RawMessage
+	err := cl.do(ctx, http.MethodGet, path, req, &raw, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	var res map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
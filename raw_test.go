@@ -0,0 +1,80 @@
+package luno_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestGetJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/1/new_endpoint" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		if r.FormValue("pair") != "XBTZAR" {
+			t.Errorf("Expected pair XBTZAR, got %q", r.FormValue("pair"))
+		}
+		if _, _, ok := r.BasicAuth(); !ok {
+			t.Errorf("Expected basic auth")
+		}
+		w.Write([]byte(`{"sequence":9007199254740993,"price":0.12345678901234567890,"ask":"1.5","nested":{"volume":1e-20}}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	if err := cl.SetAuth("key", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := cl.GetJSON(context.Background(), "/api/1/new_endpoint",
+		map[string]string{"pair": "XBTZAR"}, true)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	expNumbers := map[string]string{
+		"sequence": "9007199254740993",
+		"price":    "0.12345678901234567890",
+	}
+	for k, exp := range expNumbers {
+		n, ok := res[k].(json.Number)
+		if !ok {
+			t.Errorf("Expected %s to be a json.Number, got %T", k, res[k])
+			continue
+		}
+		if n.String() != exp {
+			t.Errorf("Expected %s %s, got %s", k, exp, n)
+		}
+	}
+	if res["ask"] != "1.5" {
+		t.Errorf("Expected ask 1.5, got %v", res["ask"])
+	}
+	nested, ok := res["nested"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected nested object, got %T", res["nested"])
+	}
+	if n, ok := nested["volume"].(json.Number); !ok || n.String() != "1e-20" {
+		t.Errorf("Expected nested volume 1e-20, got %v", nested["volume"])
+	}
+}
+
+func TestGetJSONError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"not found","error_code":"ErrNotFound"}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	_, err := cl.GetJSON(context.Background(), "/api/1/missing", nil, false)
+	if !luno.IsErrorCode(err, "ErrNotFound") {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package recorder_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/recorder"
+)
+
+func TestRecordReplay(t *testing.T) {
+	var tickerCalls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req1")
+		switch r.URL.Path {
+		case "/api/1/ticker":
+			tickerCalls++
+			if tickerCalls == 1 {
+				w.Write([]byte(`{"pair":"XBTZAR","last_trade":"100"}`))
+				return
+			}
+			w.Write([]byte(`{"pair":"XBTZAR","last_trade":"101"}`))
+		case "/api/1/balance":
+			w.Write([]byte(`{"balance":[{"account_id":"1","asset":"XBT","balance":"0.5"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Not found","error_code":"ErrNotFound"}`))
+		}
+	}))
+
+	dir, err := ioutil.TempDir("", "recorder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "session.json")
+
+	// run makes the same calls against whichever transport is installed.
+	run := func(rt http.RoundTripper) []interface{} {
+		cl := luno.NewClient()
+		cl.SetBaseURL(srv.URL)
+		cl.SetHTTPClient(&http.Client{Transport: rt})
+		if err := cl.SetAuth("key", "supersecret"); err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.Background()
+
+		var results []interface{}
+		for i := 0; i < 2; i++ {
+			res, err := cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"})
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			results = append(results, res)
+		}
+		bals, err := cl.GetBalances(ctx, &luno.GetBalancesRequest{Assets: []string{"XBT", "ETH"}})
+		if err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		results = append(results, bals)
+		_, err = cl.GetWithdrawal(ctx, &luno.GetWithdrawalRequest{Id: 1})
+		if !luno.IsErrorCode(err, "ErrNotFound") {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+		return append(results, err)
+	}
+
+	rec := recorder.New(path, nil)
+	recorded := run(rec)
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	srv.Close()
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "supersecret") || strings.Contains(string(b), "Authorization") {
+		t.Errorf("Expected credentials to be left out of the recording")
+	}
+
+	rep, err := recorder.Load(path)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	replayed := run(rep)
+	if !reflect.DeepEqual(replayed, recorded) {
+		t.Errorf("Expected replayed responses %+v, got %+v", recorded, replayed)
+	}
+
+	// Every recorded response has been served.
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetHTTPClient(&http.Client{Transport: rep})
+	if _, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"}); err == nil {
+		t.Errorf("Expected error once the recording is exhausted, got nil")
+	}
+}
+
+func TestReplayQueryOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recorder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "session.json")
+	err = ioutil.WriteFile(path, []byte(`[{
+		"method": "GET",
+		"path": "/api/1/trades",
+		"query": "pair=XBTZAR&since=1",
+		"status_code": 200,
+		"body": "{\"trades\":[{\"sequence\":7}]}"
+	}]`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rep, err := recorder.Load(path)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	req, err := http.NewRequest("GET", "http://example.com/api/1/trades?since=1&pair=XBTZAR", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := rep.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Expected a match regardless of query order, got %v", err)
+	}
+	b, _ := ioutil.ReadAll(res.Body)
+	if string(b) != `{"trades":[{"sequence":7}]}` {
+		t.Errorf("Unexpected body %s", b)
+	}
+
+	if err := rep.Save(); err == nil {
+		t.Errorf("Expected saving a replay to fail")
+	}
+}
@@ -0,0 +1,66 @@
+package luno
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// maxRedirects is the number of redirects followed for a request, as for
+// http.Client.
+const maxRedirects = 10
+
+// RedirectPolicy determines how redirect responses from the API are handled.
+type RedirectPolicy int
+
+const (
+	// RedirectSameHost follows redirects to the same host, including the
+	// Authorization header, and refuses redirects to other hosts so that the
+	// API key is neither sent elsewhere nor silently dropped. This is the
+	// default.
+	RedirectSameHost RedirectPolicy = iota
+
+	// RedirectReapplyAuth follows redirects to any host and sends the
+	// Authorization header of the original request to the new host, for
+	// example a regional API host. Redirects from https to http are refused.
+	RedirectReapplyAuth
+
+	// RedirectNone refuses all redirects.
+	RedirectNone
+)
+
+// SetRedirectPolicy sets how redirect responses are handled. Note: like
+// SetTimeout, this configures the current HTTP client, so it must be called
+// again after SetHTTPClient.
+func (cl *Client) SetRedirectPolicy(p RedirectPolicy) {
+	cl.redirectPolicy = p
+	cl.httpClient.CheckRedirect = cl.checkRedirect
+	cl.checksRedirect = true
+}
+
+// checkRedirect implements the redirect policy as the CheckRedirect function
+// of the HTTP client.
+func (cl *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("luno: stopped after %d redirects", maxRedirects)
+	}
+	orig, prev := via[0], via[len(via)-1]
+
+	switch cl.redirectPolicy {
+	case RedirectNone:
+		return fmt.Errorf("luno: refusing redirect to %s", req.URL.Host)
+	case RedirectReapplyAuth:
+		if prev.URL.Scheme == "https" && req.URL.Scheme != "https" {
+			return fmt.Errorf("luno: refusing insecure redirect to %s", req.URL)
+		}
+		if auth := orig.Header.Get("Authorization"); auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		return nil
+	default:
+		if req.URL.Host != orig.URL.Host {
+			return fmt.Errorf("luno: refusing redirect from %s to %s",
+				orig.URL.Host, req.URL.Host)
+		}
+		return nil
+	}
+}
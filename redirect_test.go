@@ -0,0 +1,92 @@
+package luno_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestRedirectPolicy(t *testing.T) {
+	var auth map[string]string
+	record := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			id, _, _ := r.BasicAuth()
+			auth[name] = id
+			w.Write([]byte(`{"balance":[]}`))
+		}
+	}
+	other := httptest.NewServer(record("other"))
+	defer other.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/same/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/moved", http.StatusFound)
+	})
+	mux.HandleFunc("/cross/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL+"/moved", http.StatusFound)
+	})
+	mux.HandleFunc("/moved", record("same"))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	testCases := []struct {
+		name    string
+		policy  *luno.RedirectPolicy
+		path    string
+		expErr  bool
+		expAuth map[string]string
+	}{
+		{name: "default same host", path: "/same", expAuth: map[string]string{"same": "key"}},
+		{name: "default cross host", path: "/cross", expErr: true, expAuth: map[string]string{}},
+		{
+			name:    "reapply cross host",
+			policy:  policy(luno.RedirectReapplyAuth),
+			path:    "/cross",
+			expAuth: map[string]string{"other": "key"},
+		},
+		{
+			name:    "none",
+			policy:  policy(luno.RedirectNone),
+			path:    "/same",
+			expErr:  true,
+			expAuth: map[string]string{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auth = make(map[string]string)
+			cl := luno.NewClient()
+			cl.SetBaseURL(srv.URL + tc.path)
+			if err := cl.SetAuth("key", "secret"); err != nil {
+				t.Fatal(err)
+			}
+			if tc.policy != nil {
+				cl.SetRedirectPolicy(*tc.policy)
+			}
+
+			// The base URL includes the path to redirect from, so the
+			// request path is appended to it.
+			_, err := cl.GetBalances(context.Background(), &luno.GetBalancesRequest{})
+			if tc.expErr && err == nil {
+				t.Errorf("Expected error, got nil")
+			} else if !tc.expErr && err != nil {
+				t.Errorf("Expected success, got %v", err)
+			}
+			if len(auth) != len(tc.expAuth) {
+				t.Fatalf("Expected requests %v, got %v", tc.expAuth, auth)
+			}
+			for host, id := range tc.expAuth {
+				if auth[host] != id {
+					t.Errorf("Expected %s to get key %q, got %q", host, id, auth[host])
+				}
+			}
+		})
+	}
+}
+
+func policy(p luno.RedirectPolicy) *luno.RedirectPolicy {
+	return &p
+}
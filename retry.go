@@ -0,0 +1,91 @@
+package luno
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultRetryJitter is the jitter of a RetryPolicy which doesn't set one.
+const defaultRetryJitter = 0.2
+
+// defaultMaxRetryDelay is the MaxDelay of a RetryPolicy which doesn't set
+// one, and the longest wait for a Retry-After header by default.
+const defaultMaxRetryDelay = 30 * time.Second
+
+// RetryPolicy configures how failed requests are retried, see SetRetryPolicy.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a failed request is retried.
+	MaxRetries int
+
+	// BaseDelay is the delay before the first retry. The delay doubles with
+	// every retry.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between retries, including delays asked for
+	// by a Retry-After header. It defaults to 30 seconds if zero.
+	MaxDelay time.Duration
+
+	// Jitter randomises each delay by up to the given fraction in either
+	// direction. It defaults to 0.2, i.e. ±20%, if zero.
+	Jitter float64
+
+	// RateLimitOnly restricts retries to requests rejected with 429 Too Many
+	// Requests, so that server and network errors aren't retried.
+	RateLimitOnly bool
+}
+
+// SetRetryPolicy sets how failed requests are retried. It replaces the
+// settings of SetMaxRetries and SetRetryBackoff.
+//
+// Requests rejected with 429 Too Many Requests weren't processed, so they're
+// always retried. Unless RateLimitOnly is set, server and network errors are
+// retried for GET requests, and for other requests only if they're made with
+// a context from WithRetrySafe, since they may have had side effects. If the
+// response has a Retry-After header, the retry waits as long as it asks, up
+// to MaxDelay.
+//
+// Example:
+//
+//	cl.SetRetryPolicy(luno.RetryPolicy{MaxRetries: 5, BaseDelay: time.Second})
+func (cl *Client) SetRetryPolicy(p RetryPolicy) {
+	jitter := p.Jitter
+	if jitter == 0 {
+		jitter = defaultRetryJitter
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxRetryDelay
+	}
+	cl.maxRetries = p.MaxRetries
+	cl.backoff = ExponentialBackoff{
+		Base:   p.BaseDelay,
+		Max:    maxDelay,
+		Jitter: jitter,
+	}
+	cl.maxRetryDelay = maxDelay
+	cl.retryRateLimitOnly = p.RateLimitOnly
+}
+
+// retryAfter returns the delay requested by the Retry-After header of res,
+// which may be a number of seconds or an HTTP date, or zero if there is none.
+func retryAfter(res *http.Response, now time.Time) time.Duration {
+	if res == nil {
+		return 0
+	}
+	v := res.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	t, err := http.ParseTime(v)
+	if err != nil || !t.After(now) {
+		return 0
+	}
+	return t.Sub(now)
+}
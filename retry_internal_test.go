@@ -0,0 +1,153 @@
+package luno
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		header string
+		exp    time.Duration
+	}{
+		{header: "", exp: 0},
+		{header: "5", exp: 5 * time.Second},
+		{header: "0", exp: 0},
+		{header: "-1", exp: 0},
+		{header: "Wed, 01 Jan 2020 00:00:30 GMT", exp: 30 * time.Second},
+		{header: "Tue, 31 Dec 2019 23:59:00 GMT", exp: 0},
+		{header: "soon", exp: 0},
+	}
+	for _, tc := range testCases {
+		res := &http.Response{Header: http.Header{}}
+		if tc.header != "" {
+			res.Header.Set("Retry-After", tc.header)
+		}
+		if got := retryAfter(res, now); got != tc.exp {
+			t.Errorf("Expected %s for %q, got %s", tc.exp, tc.header, got)
+		}
+	}
+	if got := retryAfter(nil, now); got != 0 {
+		t.Errorf("Expected 0 without a response, got %s", got)
+	}
+}
+
+func TestRetryPolicyRetryAfter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		maxDelay time.Duration
+		exp      time.Duration
+	}{
+		{name: "within max delay", exp: 5 * time.Second},
+		{name: "capped", maxDelay: 2 * time.Second, exp: 2 * time.Second},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) == 1 {
+					w.Header().Set("Retry-After", "5")
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.Write([]byte("{}"))
+			}))
+			defer srv.Close()
+
+			clk := newFakeClock()
+			cl := NewClient()
+			cl.clock = clk
+			cl.SetBaseURL(srv.URL)
+			cl.SetRetryPolicy(RetryPolicy{
+				MaxRetries: 3,
+				BaseDelay:  10 * time.Millisecond,
+				MaxDelay:   tc.maxDelay,
+			})
+
+			errc := make(chan error, 1)
+			go func() {
+				var res interface{}
+				errc <- cl.do(context.Background(), "POST", "/", nil, &res, false)
+			}()
+
+			delays := clk.BlockUntil(1)
+			if delays[0] != tc.exp {
+				t.Errorf("Expected to wait %s for Retry-After, got %s", tc.exp, delays[0])
+			}
+			clk.Advance(tc.exp)
+			if err := <-errc; err != nil {
+				t.Errorf("Expected success, got %v", err)
+			}
+			if n := atomic.LoadInt32(&attempts); n != 2 {
+				t.Errorf("Expected 2 attempts, got %d", n)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyRateLimitOnly(t *testing.T) {
+	var attempts int
+	status := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetRetryPolicy(RetryPolicy{
+		MaxRetries:    2,
+		BaseDelay:     time.Millisecond,
+		RateLimitOnly: true,
+	})
+
+	var res interface{}
+	err := cl.do(context.Background(), "GET", "/", nil, &res, false)
+	if err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected server error not to be retried, got %d attempts", attempts)
+	}
+
+	attempts = 0
+	status = http.StatusTooManyRequests
+	err = cl.do(context.Background(), "GET", "/", nil, &res, false)
+	if err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected rate limited request to be retried, got %d attempts", attempts)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	cl := NewClient()
+	cl.SetRetryPolicy(RetryPolicy{MaxRetries: 5, BaseDelay: time.Second})
+	if cl.maxRetries != 5 {
+		t.Errorf("Expected 5 retries, got %d", cl.maxRetries)
+	}
+	for attempt, exp := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		d := cl.backoff.Next(attempt)
+		if d < exp*8/10 || d > exp*12/10 {
+			t.Errorf("Expected retry %d after %s ±20%%, got %s", attempt, exp, d)
+		}
+	}
+
+	// Without MaxDelay, delays are capped at the default rather than
+	// growing without bound.
+	max := 30 * time.Second
+	if d := cl.backoff.Next(100); d < max*8/10 || d > max*12/10 {
+		t.Errorf("Expected retry 100 after %s ±20%%, got %s", max, d)
+	}
+}
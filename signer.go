@@ -0,0 +1,24 @@
+package luno
+
+import "net/http"
+
+// signer authenticates API requests. body is the request body, which has
+// already been set on req.
+type signer interface {
+	Sign(req *http.Request, body []byte) error
+}
+
+// basicAuthSigner authenticates requests with the client's API key using
+// HTTP basic authentication, which is what the API currently supports.
+type basicAuthSigner struct {
+	cl *Client
+}
+
+func (s basicAuthSigner) Sign(req *http.Request, body []byte) error {
+	keyID, keySecret, err := s.cl.credentials(req.Context())
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(keyID, keySecret)
+	return nil
+}
@@ -0,0 +1,75 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeSigner struct {
+	bodies []string
+	err    error
+}
+
+func (s *fakeSigner) Sign(req *http.Request, body []byte) error {
+	s.bodies = append(s.bodies, string(body))
+	req.Header.Set("X-Signature", "sig:"+string(body))
+	return s.err
+}
+
+func TestSigner(t *testing.T) {
+	var sig string
+	var basicAuth bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sig = r.Header.Get("X-Signature")
+		_, _, basicAuth = r.BasicAuth()
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	if err := cl.SetAuth("key", "secret"); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	ctx := context.Background()
+	var res interface{}
+
+	// Basic authentication is used by default.
+	if err := cl.do(ctx, "GET", "/", nil, &res, true); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if !basicAuth || sig != "" {
+		t.Errorf("Expected basic authentication, got %t and signature %q", basicAuth, sig)
+	}
+
+	s := &fakeSigner{}
+	cl.signer = s
+	req := struct {
+		Pair string `url:"pair"`
+	}{Pair: "XBTZAR"}
+	if err := cl.do(ctx, "POST", "/", &req, &res, true); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if basicAuth {
+		t.Errorf("Expected no basic authentication with a signer")
+	}
+	if sig != "sig:pair=XBTZAR" {
+		t.Errorf("Expected signature header for the body, got %q", sig)
+	}
+
+	// Requests without authentication aren't signed.
+	if err := cl.do(ctx, "GET", "/", nil, &res, false); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(s.bodies) != 1 {
+		t.Errorf("Expected the signer to be called once, got %d calls", len(s.bodies))
+	}
+
+	s.err = errors.New("signing failed")
+	if err := cl.do(ctx, "GET", "/", nil, &res, true); err != s.err {
+		t.Errorf("Expected signing error, got %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package luno
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Error codes returned when a request is rejected because its timestamp is
+// too far from the server's clock.
+const (
+	ErrCodeTimestampExpired = "ErrTimestampExpired"
+	ErrCodeSignatureExpired = "ErrSignatureExpired"
+	ErrCodeInvalidTimestamp = "ErrInvalidTimestamp"
+)
+
+var clockSkewErrorCodes = map[string]bool{
+	ErrCodeTimestampExpired: true,
+	ErrCodeSignatureExpired: true,
+	ErrCodeInvalidTimestamp: true,
+}
+
+// ClockSkewError is returned when the API rejects a request because of the
+// difference between the local and server clocks. The local clock should be
+// synchronised, e.g. using NTP.
+type ClockSkewError struct {
+	Err Error
+
+	// Skew is how far the server clock was ahead of the local clock, as
+	// measured from the Date header of the latest response. It is negative if
+	// the local clock is ahead. It is only set if SkewKnown is true.
+	Skew      time.Duration
+	SkewKnown bool
+}
+
+func (e *ClockSkewError) Error() string {
+	if !e.SkewKnown {
+		return fmt.Sprintf("luno: request rejected due to clock skew, "+
+			"check that the system clock is synchronised: %s", e.Err.Error())
+	}
+	return fmt.Sprintf("luno: request rejected due to clock skew of %s, "+
+		"check that the system clock is synchronised: %s", e.Skew, e.Err.Error())
+}
+
+// Unwrap returns the underlying API error.
+func (e *ClockSkewError) Unwrap() error {
+	return e.Err
+}
+
+// skewTracker estimates the offset of the server clock from the Date headers
+// of responses, or more precisely with SyncServerTime.
+type skewTracker struct {
+	mu    sync.Mutex
+	skew  time.Duration
+	known bool
+
+	// synced is true if skew was measured by SyncServerTime.
+	synced bool
+
+	// threshold is set by SetClockSkewThreshold, and exceeded is true while
+	// the skew exceeds it.
+	threshold time.Duration
+	exceeded  bool
+}
+
+// observe records the skew implied by the Date header of a response to a
+// request which was sent and received at the given local times. The header
+// is compared with the midpoint of the round trip. It returns the skew and
+// whether it has just exceeded the threshold.
+func (s *skewTracker) observe(header http.Header, sent, received time.Time) (time.Duration, bool) {
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		return 0, false
+	}
+	rtt := received.Sub(sent)
+	skew := date.Sub(sent.Add(rtt / 2))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The Date header only has a resolution of a second, so it doesn't
+	// replace a synced skew which it agrees with.
+	if s.synced && abs(skew-s.skew) <= time.Second+rtt {
+		return s.skew, false
+	}
+	s.synced = false
+	return skew, s.setLocked(skew)
+}
+
+// sync records the skew measured by SyncServerTime. It returns whether the
+// skew has just exceeded the threshold.
+func (s *skewTracker) sync(skew time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.synced = true
+	return s.setLocked(skew)
+}
+
+func (s *skewTracker) setLocked(skew time.Duration) bool {
+	s.skew, s.known = skew, true
+	over := s.threshold > 0 && abs(skew) > s.threshold
+	crossed := over && !s.exceeded
+	s.exceeded = over
+	return crossed
+}
+
+func (s *skewTracker) setThreshold(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.threshold, s.exceeded = d, false
+}
+
+func (s *skewTracker) getThreshold() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.threshold
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+func (s *skewTracker) get() (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.skew, s.known
+}
+
+func (s *skewTracker) makeError(e Error) *ClockSkewError {
+	skew, known := s.get()
+	return &ClockSkewError{Err: e, Skew: skew, SkewKnown: known}
+}
+
+// ServerClockSkew returns how far the server clock is ahead of the local
+// clock, estimated to within about a second from the latest response. It
+// returns false if no response with a Date header has been received.
+func (cl *Client) ServerClockSkew() (time.Duration, bool) {
+	return cl.skew.get()
+}
+
+// SetClockSkewThreshold sets how far the local clock may be from the server
+// clock before a WarningClockSkew is reported to the Warning hook, and so
+// logged by the logger set with SetLogger. The skew is checked on every
+// response and by SyncServerTime, and reported again only after it has
+// returned within the threshold. Zero disables the warning, which is the
+// default.
+func (cl *Client) SetClockSkewThreshold(d time.Duration) {
+	cl.skew.setThreshold(d)
+}
+
+// SyncServerTime measures how far the server clock is ahead of the local
+// clock with GetServerTime, to within half the round trip time, and returns
+// it. It's more precise than the estimate from the Date headers of
+// responses, which ServerNow and ServerClockSkew use until SyncServerTime is
+// called, and which then only replace the measurement if they disagree with
+// it.
+func (cl *Client) SyncServerTime(ctx context.Context) (time.Duration, error) {
+	sent := cl.clock.Now()
+	res, err := cl.GetServerTime(ctx, &GetServerTimeRequest{})
+	if err != nil {
+		return 0, err
+	}
+	received := cl.clock.Now()
+	if res.Now <= 0 {
+		return 0, fmt.Errorf("luno: invalid server time %d", res.Now)
+	}
+
+	server := time.Unix(0, res.Now*int64(time.Millisecond))
+	skew := server.Sub(sent.Add(received.Sub(sent) / 2))
+	if cl.skew.sync(skew) {
+		cl.reportSkew(ctx, RequestInfo{
+			Method:        endpointGetServerTime.method,
+			Path:          endpointGetServerTime.path,
+			CorrelationID: CorrelationIDFromContext(ctx),
+		}, skew)
+	}
+	return skew, nil
+}
+
+// observeSkew records the clock skew implied by the headers of the response
+// to the request described by info.
+func (cl *Client) observeSkew(ctx context.Context, info RequestInfo, header http.Header, sent, received time.Time) {
+	if skew, exceeded := cl.skew.observe(header, sent, received); exceeded {
+		cl.reportSkew(ctx, info, skew)
+	}
+}
+
+// reportSkew reports a WarningClockSkew for skew, which was observed in the
+// response to the request described by info.
+func (cl *Client) reportSkew(ctx context.Context, info RequestInfo, skew time.Duration) {
+	cl.hooks.warning(ctx, Warning{
+		RequestInfo: info,
+		Kind:        WarningClockSkew,
+		Message: fmt.Sprintf("clock skew of %s exceeds %s",
+			skew.Round(time.Millisecond), cl.skew.getThreshold()),
+	})
+}
@@ -0,0 +1,189 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestClockSkewError(t *testing.T) {
+	const skew = -30 * time.Second
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(skew).UTC().Format(http.TimeFormat))
+		if r.URL.Path == "/api/1/ticker" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"Timestamp expired","error_code":"ErrTimestampExpired"}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	ctx := context.Background()
+	if _, ok := cl.ServerClockSkew(); ok {
+		t.Errorf("Expected unknown skew before any request")
+	}
+	if _, err := cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	measured, ok := cl.ServerClockSkew()
+	if !ok || measured > skew+2*time.Second || measured < skew-2*time.Second {
+		t.Errorf("Expected skew of about %s, got %s", skew, measured)
+	}
+
+	_, err := cl.GetBalances(ctx, &luno.GetBalancesRequest{})
+	var cse *luno.ClockSkewError
+	if !errors.As(err, &cse) {
+		t.Fatalf("Expected *ClockSkewError, got %v", err)
+	}
+	if !cse.SkewKnown || cse.Skew > skew+2*time.Second || cse.Skew < skew-2*time.Second {
+		t.Errorf("Expected skew of about %s, got %s", skew, cse.Skew)
+	}
+	if !luno.IsErrorCode(err, luno.ErrCodeTimestampExpired) {
+		t.Errorf("Expected error code %s, got %v", luno.ErrCodeTimestampExpired, err)
+	}
+}
+
+func TestClockSkewErrorUnknownSkew(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header()["Date"] = nil
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"Signature expired","error_code":"ErrSignatureExpired"}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	_, err := cl.GetBalances(context.Background(), &luno.GetBalancesRequest{})
+	var cse *luno.ClockSkewError
+	if !errors.As(err, &cse) {
+		t.Fatalf("Expected *ClockSkewError, got %v", err)
+	}
+	if cse.SkewKnown {
+		t.Errorf("Expected unknown skew, got %s", cse.Skew)
+	}
+}
+
+// skewServer serves tickers and the server time with a clock which is the
+// number of nanoseconds in skew ahead of the local clock.
+func skewServer(skew *int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now().Add(time.Duration(atomic.LoadInt64(skew)))
+		w.Header().Set("Date", now.UTC().Format(http.TimeFormat))
+		if r.URL.Path == "/api/1/time" {
+			fmt.Fprintf(w, `{"now":%d,"timezone":"UTC"}`, now.UnixNano()/1e6)
+			return
+		}
+		w.Write([]byte(`{"pair":"XBTZAR"}`))
+	}))
+}
+
+func TestSyncServerTime(t *testing.T) {
+	skew := int64(10*time.Minute + 300*time.Millisecond)
+	srv := skewServer(&skew)
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	ctx := context.Background()
+
+	measured, err := cl.SyncServerTime(ctx)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	exp := time.Duration(skew)
+	if measured < exp-100*time.Millisecond || measured > exp+100*time.Millisecond {
+		t.Errorf("Expected skew of about %s, got %s", exp, measured)
+	}
+
+	// Date headers, which are truncated to the second, don't replace the
+	// synced skew.
+	if _, err := cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if got, _ := cl.ServerClockSkew(); got != measured {
+		t.Errorf("Expected synced skew %s to be kept, got %s", measured, got)
+	}
+	if d := cl.ServerNow().Sub(time.Now().Add(exp)); d < -time.Second || d > time.Second {
+		t.Errorf("Expected server time to include the skew, got %s off", d)
+	}
+}
+
+func TestClockSkewThreshold(t *testing.T) {
+	skew := int64(5 * time.Minute)
+	srv := skewServer(&skew)
+	defer srv.Close()
+
+	var warnings []luno.Warning
+	cl := luno.NewClient(luno.WithHooks(luno.Hooks{
+		Warning: func(ctx context.Context, w luno.Warning) {
+			if w.Kind == luno.WarningClockSkew {
+				warnings = append(warnings, w)
+			}
+		},
+	}))
+	cl.SetBaseURL(srv.URL)
+	cl.SetClockSkewThreshold(time.Minute)
+
+	ticker := func() {
+		t.Helper()
+		_, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+		if err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+	}
+	ticker()
+	ticker()
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %d", len(warnings))
+	}
+	if w := warnings[0]; w.Path != "/api/1/ticker" || !strings.HasSuffix(w.Message, "exceeds 1m0s") {
+		t.Errorf("Expected warning for the ticker request, got %+v", w)
+	}
+
+	atomic.StoreInt64(&skew, 0)
+	ticker()
+	atomic.StoreInt64(&skew, int64(-5*time.Minute))
+	ticker()
+	if len(warnings) != 2 {
+		t.Errorf("Expected another warning once the skew returned, got %d", len(warnings))
+	}
+}
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time                         { return c.now }
+func (c fixedClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (c fixedClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+
+func TestSetClock(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	cl := luno.NewClient(luno.WithClock(fixedClock{now: now}))
+	if !cl.ServerNow().Equal(now) {
+		t.Errorf("Expected %s, got %s", now, cl.ServerNow())
+	}
+
+	q := luno.Quote{ExpiresAt: luno.Time(now.Add(time.Second))}
+	if d := cl.QuoteTimeLeft(q); d != time.Second {
+		t.Errorf("Expected 1s left by the client's clock, got %s", d)
+	}
+
+	cl.SetClock(nil)
+	if d := time.Since(cl.ServerNow()); d < 0 || d > time.Minute {
+		t.Errorf("Expected the system clock to be restored, got %s", cl.ServerNow())
+	}
+}
@@ -0,0 +1,95 @@
+//go:build go1.21
+// +build go1.21
+
+package luno
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SetLogger makes the client log what it does to l: each attempt of an API
+// request and its outcome at debug level, and retries and 429 Too Many
+// Requests responses at warn level. Waits for the client's own rate limiter
+// are logged at debug level. A nil l disables logging.
+//
+// Request URLs and bodies are logged with sensitive parameters redacted, see
+// SetRedactedParams. Headers, including the Authorization header, are never
+// logged.
+func (cl *Client) SetLogger(l *slog.Logger) {
+	if l == nil {
+		cl.logger = nil
+		return
+	}
+	cl.logger = slogLogger{l: l}
+}
+
+// WithLogger returns an option which sets the client's logger, as for
+// SetLogger.
+func WithLogger(l *slog.Logger) Option {
+	return func(cl *Client) {
+		cl.SetLogger(l)
+	}
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s slogLogger) logRequest(ctx context.Context, info RequestInfo, url string) {
+	if !s.l.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := append(requestAttrs(info), slog.String("url", url))
+	if info.Body != "" {
+		attrs = append(attrs, slog.String("body", info.Body))
+	}
+	s.l.LogAttrs(ctx, slog.LevelDebug, "luno: request", attrs...)
+}
+
+func (s slogLogger) logResponse(ctx context.Context, info ResponseInfo) {
+	if !s.l.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	s.l.LogAttrs(ctx, slog.LevelDebug, "luno: response", responseAttrs(info)...)
+}
+
+func (s slogLogger) logRetry(ctx context.Context, info ResponseInfo, delay time.Duration) {
+	attrs := append(responseAttrs(info), slog.Duration("delay", delay))
+	s.l.LogAttrs(ctx, slog.LevelWarn, "luno: retrying request", attrs...)
+}
+
+func (s slogLogger) logThrottle(ctx context.Context, info ThrottleInfo) {
+	attrs := append(requestAttrs(info.RequestInfo), slog.Duration("wait", info.Wait))
+	if info.Reason == ThrottleServer {
+		s.l.LogAttrs(ctx, slog.LevelWarn, "luno: rate limited by server", attrs...)
+		return
+	}
+	s.l.LogAttrs(ctx, slog.LevelDebug, "luno: waiting for rate limiter", attrs...)
+}
+
+func requestAttrs(info RequestInfo) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("method", info.Method),
+		slog.String("path", info.Path),
+		slog.Int("attempt", info.Attempt),
+	}
+	if info.CorrelationID != "" {
+		attrs = append(attrs, slog.String("correlation_id", info.CorrelationID))
+	}
+	return attrs
+}
+
+func responseAttrs(info ResponseInfo) []slog.Attr {
+	attrs := append(requestAttrs(info.RequestInfo),
+		slog.Int("status", info.StatusCode),
+		slog.Duration("duration", info.Duration))
+	if info.RequestID != "" {
+		attrs = append(attrs, slog.String("request_id", info.RequestID))
+	}
+	if info.Err != nil {
+		attrs = append(attrs, slog.String("error", info.Err.Error()))
+	}
+	return attrs
+}
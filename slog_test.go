@@ -0,0 +1,87 @@
+//go:build go1.21
+// +build go1.21
+
+package luno_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestSetLogger(t *testing.T) {
+	var n int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&n, 1) {
+		case 1:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error_code":"ErrInternal","error":"oops"}`))
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Header().Set("X-Request-Id", "req-1")
+			w.Write([]byte(`{"pair":"XBTZAR"}`))
+		}
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	cl := luno.NewClient(
+		luno.WithBaseURL(srv.URL),
+		luno.WithAuth("key", "supersecret"),
+		luno.WithLogger(l))
+	cl.SetMaxRetries(2)
+	cl.SetRetryBackoff(luno.ConstantBackoff(0))
+
+	_, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	out := buf.String()
+	for _, exp := range []string{
+		`level=DEBUG msg="luno: request" method=GET path=/api/1/ticker attempt=0 url="/api/1/ticker?pair=XBTZAR"`,
+		`level=WARN msg="luno: retrying request" method=GET path=/api/1/ticker attempt=0 status=500`,
+		`level=WARN msg="luno: rate limited by server" method=GET path=/api/1/ticker attempt=1`,
+		`level=DEBUG msg="luno: response" method=GET path=/api/1/ticker attempt=2 status=200`,
+		`request_id=req-1`,
+	} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("Expected log to contain %q, got:\n%s", exp, out)
+		}
+	}
+	for _, s := range []string{"supersecret", "Authorization"} {
+		if strings.Contains(out, s) {
+			t.Errorf("Expected log not to contain %q, got:\n%s", s, out)
+		}
+	}
+}
+
+func TestSetLoggerRedacts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL), luno.WithLogger(l))
+	cl.SetRedactedParams("pair")
+
+	_, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if out := buf.String(); strings.Contains(out, "XBTZAR") || !strings.Contains(out, "pair=REDACTED") {
+		t.Errorf("Expected pair to be redacted, got:\n%s", out)
+	}
+}
@@ -0,0 +1,241 @@
+package luno
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// EntryType classifies a statement row for accounting.
+type EntryType string
+
+const (
+	EntryTrade    EntryType = "trade"
+	EntryFee      EntryType = "fee"
+	EntrySend     EntryType = "send"
+	EntryReceive  EntryType = "receive"
+	EntryInterest EntryType = "interest"
+	EntryOther    EntryType = "other"
+)
+
+// StatementRow is a transaction of one of the user's accounts, classified
+// and joined with the trade which caused it, if known.
+type StatementRow struct {
+	AccountID string    `json:"account_id"`
+	RowIndex  int64     `json:"row_index"`
+	Timestamp time.Time `json:"timestamp"`
+	Type      EntryType `json:"type"`
+	Currency  string    `json:"currency"`
+
+	// Signed change in balance, and the balance afterwards.
+	Amount  decimal.Decimal `json:"amount"`
+	Balance decimal.Decimal `json:"balance"`
+
+	// Human-readable description of the transaction.
+	Description string `json:"description"`
+
+	// OrderID, Pair and Price are those of the trade which caused trade and
+	// fee rows, if it was found. Price is zero otherwise.
+	OrderID string          `json:"order_id,omitempty"`
+	Pair    string          `json:"pair,omitempty"`
+	Price   decimal.Decimal `json:"price"`
+
+	// Address and TxID are set for crypto sends and receives.
+	Address string `json:"address,omitempty"`
+	TxID    string `json:"txid,omitempty"`
+}
+
+// Statement holds the transactions of all of the user's accounts over a
+// date range.
+type Statement struct {
+	From, To time.Time
+
+	// Rows are ordered by timestamp, then account and row index.
+	Rows []StatementRow
+}
+
+// Statement returns the transactions of all of the user's accounts with
+// timestamps in the range [from, to), classified by type. Both from and to
+// must be set and from must be before to.
+//
+// Trade and fee rows are joined, by timestamp and currency, with the user's
+// trades on pairs, e.g. "XBTZAR", so that they carry the order ID, pair and
+// price. Each pair costs at least one more request, so pass only the pairs
+// traded. Rows of accounts are verified as for GetLedger.
+func (cl *Client) Statement(ctx context.Context, from, to time.Time,
+	pairs ...string) (*Statement, error) {
+
+	if from.IsZero() || to.IsZero() || !from.Before(to) {
+		return nil, fmt.Errorf("luno: invalid date range %s to %s", from, to)
+	}
+
+	balances, err := cl.GetBalances(ctx, &GetBalancesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	trades, err := cl.statementTrades(ctx, from, to, pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Statement{From: from, To: to}
+	for _, b := range balances.Balance {
+		txs, err := cl.ledgerTransactions(ctx, b.AccountId, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("luno: account %s: %w", b.AccountId, err)
+		}
+		for _, tx := range txs {
+			s.Rows = append(s.Rows, makeStatementRow(b.AccountId, tx, trades))
+		}
+	}
+	sort.SliceStable(s.Rows, func(i, j int) bool {
+		a, b := s.Rows[i], s.Rows[j]
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.Before(b.Timestamp)
+		}
+		if a.AccountID != b.AccountID {
+			return a.AccountID < b.AccountID
+		}
+		return a.RowIndex < b.RowIndex
+	})
+	return s, nil
+}
+
+// statementTrades returns the user's trades on pairs in the range
+// [from, to), by timestamp in milliseconds.
+func (cl *Client) statementTrades(ctx context.Context, from, to time.Time,
+	pairs []string) (map[int64][]Trade, error) {
+
+	trades := make(map[int64][]Trade)
+	for _, pair := range pairs {
+		it := cl.IterUserTrades(&ListUserTradesRequest{
+			Pair:  pair,
+			Since: Time(from),
+			Limit: tradesPageSize,
+		})
+		for it.Next(ctx) {
+			t := it.Trade()
+			ts := time.Time(t.Timestamp)
+			if !ts.Before(to) {
+				break
+			}
+			if t.Pair == "" {
+				t.Pair = pair
+			}
+			ms := timeMillis(ts)
+			trades[ms] = append(trades[ms], t)
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return trades, nil
+}
+
+func makeStatementRow(accountID string, tx Transaction,
+	trades map[int64][]Trade) StatementRow {
+
+	ts := time.Time(tx.Timestamp)
+	r := StatementRow{
+		AccountID:   accountID,
+		RowIndex:    tx.RowIndex,
+		Timestamp:   ts,
+		Type:        classifyTransaction(tx),
+		Currency:    tx.Currency,
+		Amount:      tx.BalanceDelta,
+		Balance:     tx.Balance,
+		Description: tx.Description,
+		Address:     tx.DetailFields.CryptoDetails.Address,
+		TxID:        tx.DetailFields.CryptoDetails.Txid,
+	}
+	if r.Type == EntryTrade || r.Type == EntryFee {
+		for _, t := range trades[timeMillis(ts)] {
+			if strings.HasPrefix(t.Pair, tx.Currency) || strings.HasSuffix(t.Pair, tx.Currency) {
+				r.OrderID, r.Pair, r.Price = t.OrderId, t.Pair, t.Price
+				break
+			}
+		}
+	}
+	return r
+}
+
+// classifyTransaction returns the statement entry type of tx.
+func classifyTransaction(tx Transaction) EntryType {
+	switch tx.Kind {
+	case KindExchange:
+		return EntryTrade
+	case KindFee:
+		return EntryFee
+	case KindInterest:
+		return EntryInterest
+	case KindTransfer:
+		if tx.BalanceDelta.Sign() < 0 {
+			return EntrySend
+		}
+		return EntryReceive
+	}
+	return EntryOther
+}
+
+func timeMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+var statementCSVHeader = []string{
+	"timestamp", "account_id", "row_index", "type", "currency", "amount",
+	"balance", "description", "order_id", "pair", "price", "address", "txid",
+}
+
+// WriteCSV writes the rows of s as CSV to w, starting with a header row.
+// Timestamps and amounts are formatted as by ListTransactionsResponse.WriteCSV.
+func (s *Statement) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(statementCSVHeader); err != nil {
+		return err
+	}
+	for _, r := range s.Rows {
+		var price string
+		if r.Pair != "" {
+			price = r.Price.String()
+		}
+		err := cw.Write([]string{
+			r.Timestamp.UTC().Format(csvTimeFormat),
+			r.AccountID,
+			strconv.FormatInt(r.RowIndex, 10),
+			string(r.Type),
+			r.Currency,
+			r.Amount.String(),
+			r.Balance.String(),
+			r.Description,
+			r.OrderID,
+			r.Pair,
+			price,
+			r.Address,
+			r.TxID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the rows of s to w as a JSON array.
+func (s *Statement) WriteJSON(w io.Writer) error {
+	rows := s.Rows
+	if rows == nil {
+		rows = []StatementRow{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
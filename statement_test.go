@@ -0,0 +1,114 @@
+package luno_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+)
+
+func newStatementServer(t *testing.T) *httptest.Server {
+	txs := map[string]string{
+		"1": `{"transactions":[
+			{"row_index":1,"timestamp":1000,"balance":"1","balance_delta":"1","currency":"XBT","kind":"TRANSFER",
+			 "description":"Received Bitcoin","detail_fields":{"crypto_details":{"address":"addr1","txid":"tx1"}}},
+			{"row_index":2,"timestamp":3000,"balance":"0.5","balance_delta":"-0.5","currency":"XBT","kind":"EXCHANGE",
+			 "description":"Sold 0.5 BTC"},
+			{"row_index":3,"timestamp":5000,"balance":"0.4","balance_delta":"-0.1","currency":"XBT","kind":"TRANSFER",
+			 "description":"Sent Bitcoin"}
+		]}`,
+		"2": `{"transactions":[
+			{"row_index":1,"timestamp":3000,"balance":"500","balance_delta":"500","currency":"ZAR","kind":"EXCHANGE",
+			 "description":"Sold 0.5 BTC"},
+			{"row_index":2,"timestamp":3000,"balance":"495","balance_delta":"-5","currency":"ZAR","kind":"FEE",
+			 "description":"Trading fee"},
+			{"row_index":3,"timestamp":4000,"balance":"496","balance_delta":"1","currency":"ZAR","kind":"INTEREST",
+			 "description":"Interest"}
+		]}`,
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/1/balance":
+			w.Write([]byte(`{"balance":[{"account_id":"1","asset":"XBT"},{"account_id":"2","asset":"ZAR"}]}`))
+		case strings.HasPrefix(r.URL.Path, "/api/1/accounts/"):
+			id := strings.Split(r.URL.Path, "/")[4]
+			if r.FormValue("min_row") != "1" {
+				w.Write([]byte(`{"transactions":[]}`))
+				return
+			}
+			w.Write([]byte(txs[id]))
+		case r.URL.Path == "/api/1/listtrades":
+			if r.FormValue("pair") != "XBTZAR" {
+				t.Errorf("Expected trades of XBTZAR, got %q", r.FormValue("pair"))
+			}
+			w.Write([]byte(`{"trades":[
+				{"pair":"XBTZAR","order_id":"BXORDER","timestamp":3000,"price":"1000","volume":"0.5","sequence":1}
+			]}`))
+		default:
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+	}))
+}
+
+func TestStatement(t *testing.T) {
+	srv := newStatementServer(t)
+	defer srv.Close()
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL))
+
+	s, err := cl.Statement(context.Background(), time.Unix(1, 0), time.Unix(5, 0), "XBTZAR")
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	var got []string
+	for _, r := range s.Rows {
+		got = append(got, strings.Join([]string{r.AccountID, string(r.Type),
+			r.Currency, r.Amount.String(), r.OrderID, r.Address}, " "))
+	}
+	exp := []string{
+		"1 receive XBT 1  addr1",
+		"1 trade XBT -0.5 BXORDER ",
+		"2 trade ZAR 500 BXORDER ",
+		"2 fee ZAR -5 BXORDER ",
+		"2 interest ZAR 1  ",
+	}
+	if strings.Join(got, "\n") != strings.Join(exp, "\n") {
+		t.Errorf("Expected rows:\n%s\ngot:\n%s", strings.Join(exp, "\n"), strings.Join(got, "\n"))
+	}
+
+	var buf bytes.Buffer
+	if err := s.WriteCSV(&buf); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("Expected header and 5 rows, got %q", buf.String())
+	}
+	if exp := "1970-01-01T00:00:03.000Z,1,2,trade,XBT,-0.5,0.5,Sold 0.5 BTC,BXORDER,XBTZAR,1000,,"; lines[2] != exp {
+		t.Errorf("Expected row %q, got %q", exp, lines[2])
+	}
+
+	buf.Reset()
+	if err := s.WriteJSON(&buf); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	if len(rows) != 5 || rows[0]["txid"] != "tx1" {
+		t.Errorf("Expected 5 rows with txid of the first, got %v", rows)
+	}
+}
+
+func TestStatementInvalidRange(t *testing.T) {
+	cl := luno.NewClient()
+	if _, err := cl.Statement(context.Background(), time.Unix(5, 0), time.Unix(1, 0)); err == nil {
+		t.Errorf("Expected error for invalid range, got nil")
+	}
+}
@@ -0,0 +1,21 @@
+package streaming
+
+import "time"
+
+// clock is the source of time for the reconnect loop. It's replaced by a fake
+// in tests so that the backoff schedule can be tested without waiting.
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is a clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
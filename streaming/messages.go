@@ -1,6 +1,8 @@
 package streaming
 
 import (
+	"time"
+
 	"github.com/luno/luno-go"
 	"github.com/luno/luno-go/decimal"
 )
@@ -12,10 +14,11 @@ type order struct {
 }
 
 type orderBook struct {
-	Sequence int64       `json:"sequence,string"`
-	Asks     []*order    `json:"asks"`
-	Bids     []*order    `json:"bids"`
-	Status   luno.Status `json:"status"`
+	Sequence  luno.Sequence `json:"sequence,string"`
+	Asks      []*order      `json:"asks"`
+	Bids      []*order      `json:"bids"`
+	Status    luno.Status   `json:"status"`
+	Timestamp int64         `json:"timestamp"`
 }
 
 type TradeUpdate struct {
@@ -24,6 +27,27 @@ type TradeUpdate struct {
 	OrderID string          `json:"order_id"`
 }
 
+// Trade is a trade on the market of a connection, see WithTradeCallback.
+type Trade struct {
+	Pair      string
+	Sequence  luno.Sequence
+	Timestamp time.Time
+
+	// Price is the price of the maker order.
+	Price decimal.Decimal
+
+	// Volume is the base amount traded and Counter the counter amount.
+	Volume  decimal.Decimal
+	Counter decimal.Decimal
+
+	// MakerOrderID is the ID of the resting order which was traded against.
+	MakerOrderID string
+
+	// TakerSide is the side of the order which took liquidity, i.e.
+	// SideSell if the maker order was a bid.
+	TakerSide luno.Side
+}
+
 type CreateUpdate struct {
 	OrderID string          `json:"order_id"`
 	Type    string          `json:"type"`
@@ -40,7 +64,7 @@ type StatusUpdate struct {
 }
 
 type Update struct {
-	Sequence     int64          `json:"sequence,string"`
+	Sequence     luno.Sequence  `json:"sequence,string"`
 	TradeUpdates []*TradeUpdate `json:"trade_updates"`
 	CreateUpdate *CreateUpdate  `json:"create_update"`
 	DeleteUpdate *DeleteUpdate  `json:"delete_update"`
@@ -0,0 +1,130 @@
+package streaming
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// multiStreamBuffer is the size of the update channel of each pair and of the
+// merged event channel.
+const multiStreamBuffer = 64
+
+// PairUpdate is an update of the order book of Pair.
+type PairUpdate struct {
+	Pair   string
+	Update Update
+}
+
+// MultiStream streams the order books of several market pairs, using one
+// connection per pair. Each connection reconnects independently, so an error
+// on one pair doesn't interrupt the others.
+type MultiStream struct {
+	conns  map[string]*Conn
+	events chan PairUpdate
+
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// DialMulti initiates a connection to the streaming service for each of the
+// given market pairs. The options are applied to every connection. Updates are
+// delivered on the channel returned by Events, so options which configure an
+// update channel are overridden.
+func DialMulti(keyID, keySecret string, pairs []string,
+	opts ...DialOption) (*MultiStream, error) {
+
+	if len(pairs) == 0 {
+		return nil, errors.New("streaming: no pairs provided")
+	}
+	seen := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		if seen[pair] {
+			return nil, fmt.Errorf("streaming: duplicate pair %s", pair)
+		}
+		seen[pair] = true
+	}
+
+	m := &MultiStream{
+		conns:  make(map[string]*Conn, len(pairs)),
+		events: make(chan PairUpdate, multiStreamBuffer),
+		done:   make(chan struct{}),
+	}
+	opts = append(opts, WithUpdateChannel(multiStreamBuffer, BackpressureBlock))
+	for _, pair := range pairs {
+		c, err := Dial(keyID, keySecret, pair, opts...)
+		if err != nil {
+			m.Close()
+			return nil, err
+		}
+		m.conns[pair] = c
+		m.wg.Add(1)
+		go m.forward(pair, c)
+	}
+	return m, nil
+}
+
+// forward copies the updates of a single pair to the merged event channel.
+func (m *MultiStream) forward(pair string, c *Conn) {
+	defer m.wg.Done()
+	for {
+		select {
+		case u, ok := <-c.Updates():
+			if !ok {
+				return
+			}
+			select {
+			case m.events <- PairUpdate{Pair: pair, Update: u}:
+			case <-m.done:
+				return
+			}
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// Events returns the channel on which the updates of all pairs are delivered,
+// in order for each pair. The channel is closed once the MultiStream has been
+// closed.
+func (m *MultiStream) Events() <-chan PairUpdate {
+	return m.events
+}
+
+// Snapshot returns the current state of the streamed data of pair. It returns
+// false if pair isn't being streamed.
+func (m *MultiStream) Snapshot(pair string) (Snapshot, bool) {
+	c, ok := m.conns[pair]
+	if !ok {
+		return Snapshot{}, false
+	}
+	return c.Snapshot(), true
+}
+
+// Subscribe returns a subscription to the order book of pair, see
+// Conn.Subscribe. It returns false if pair isn't being streamed.
+func (m *MultiStream) Subscribe(pair string, buffer int) (*Subscription, bool) {
+	c, ok := m.conns[pair]
+	if !ok {
+		return nil, false
+	}
+	return c.Subscribe(buffer), true
+}
+
+// Conn returns the connection of pair, or nil if pair isn't being streamed.
+func (m *MultiStream) Conn(pair string) *Conn {
+	return m.conns[pair]
+}
+
+// Close closes the connections of all pairs.
+func (m *MultiStream) Close() {
+	m.closeOnce.Do(func() {
+		for _, c := range m.conns {
+			c.Close()
+		}
+		close(m.done)
+		m.wg.Wait()
+		close(m.events)
+	})
+}
@@ -0,0 +1,121 @@
+package streaming
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// streamPair serves a fake order book for a single pair. If drop is set, the
+// first connection is closed after the snapshot.
+func streamPair(connects *int32, drop bool) websocket.Handler {
+	return func(ws *websocket.Conn) {
+		var cred credentials
+		if err := websocket.JSON.Receive(ws, &cred); err != nil {
+			return
+		}
+		n := atomic.AddInt32(connects, 1)
+		_ = websocket.JSON.Send(ws, map[string]interface{}{
+			"sequence": "1",
+			"asks":     []interface{}{},
+			"bids":     []interface{}{},
+			"status":   "ACTIVE",
+		})
+		if drop && n == 1 {
+			return
+		}
+
+		for seq := 2; ; seq++ {
+			err := websocket.JSON.Send(ws, map[string]interface{}{
+				"sequence": fmt.Sprint(seq),
+				"create_update": map[string]interface{}{
+					"order_id": fmt.Sprint("o", seq),
+					"type":     "BID",
+					"price":    "1",
+					"volume":   "1",
+				},
+			})
+			if err != nil {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestMultiStream(t *testing.T) {
+	var xbtConnects, ethConnects int32
+	mux := http.NewServeMux()
+	mux.Handle("/api/1/stream/XBTZAR", streamPair(&xbtConnects, true))
+	mux.Handle("/api/1/stream/ETHZAR", streamPair(&ethConnects, false))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	oldHost := *wsHost
+	*wsHost = "ws" + strings.TrimPrefix(srv.URL, "http")
+	defer func() { *wsHost = oldHost }()
+
+	m, err := DialMulti("key", "secret", []string{"XBTZAR", "ETHZAR"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	// The ETHZAR stream keeps delivering updates while XBTZAR is waiting to
+	// reconnect, and XBTZAR resumes once it has.
+	var ethDuringOutage bool
+	deadline := time.After(10 * time.Second)
+	for {
+		var pu PairUpdate
+		select {
+		case pu = <-m.Events():
+		case <-deadline:
+			t.Fatalf("Expected XBTZAR to reconnect, got %d connects",
+				atomic.LoadInt32(&xbtConnects))
+		}
+		if pu.Pair == "ETHZAR" && atomic.LoadInt32(&xbtConnects) == 1 {
+			ethDuringOutage = true
+		}
+		if pu.Pair == "XBTZAR" {
+			break
+		}
+	}
+	if !ethDuringOutage {
+		t.Errorf("Expected ETHZAR updates while XBTZAR was disconnected")
+	}
+	if n := atomic.LoadInt32(&ethConnects); n != 1 {
+		t.Errorf("Expected ETHZAR to stay connected, got %d connects", n)
+	}
+
+	s, ok := m.Snapshot("ETHZAR")
+	if !ok {
+		t.Fatalf("Expected ETHZAR snapshot")
+	}
+	if s.Sequence < 2 || len(s.Bids) == 0 {
+		t.Errorf("Expected ETHZAR updates to be applied, got %+v", s)
+	}
+	if _, ok := m.Snapshot("LTCZAR"); ok {
+		t.Errorf("Expected no snapshot for unknown pair")
+	}
+
+	m.Close()
+	for range m.Events() {
+	}
+}
+
+func TestDialMultiInvalid(t *testing.T) {
+	if _, err := DialMulti("key", "secret", nil); err == nil {
+		t.Errorf("Expected error for no pairs, got nil")
+	}
+	if _, err := DialMulti("key", "secret", []string{"XBTZAR", "XBTZAR"}); err == nil {
+		t.Errorf("Expected error for duplicate pairs, got nil")
+	}
+	if _, err := DialMulti("", "", []string{"XBTZAR"}); err == nil {
+		t.Errorf("Expected error for missing credentials, got nil")
+	}
+}
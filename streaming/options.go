@@ -1,5 +1,7 @@
 package streaming
 
+import "time"
+
 type DialOption func(*Conn)
 
 // WithUpdateCallback returns an options which sets a callback function for
@@ -11,6 +13,27 @@ func WithUpdateCallback(fn UpdateCallback) DialOption {
 	}
 }
 
+// WithTradeCallback returns an option which calls fn with each trade on the
+// market, after the update containing it has been applied to the order book
+// and passed to the update callback. Like updates, trades are passed on once
+// even if they are received again after a reconnect.
+func WithTradeCallback(fn TradeCallback) DialOption {
+	return func(c *Conn) {
+		c.tradeCallback = fn
+	}
+}
+
+// WithTradeGapCallback returns an option which calls fn when updates were
+// missed, so that trades may have been missed too, e.g. while reconnecting.
+// The connection resynchronises from a new snapshot by itself, but the trades
+// in the missed updates can't be recovered from the stream; use ListTrades to
+// backfill them if needed.
+func WithTradeGapCallback(fn TradeGapCallback) DialOption {
+	return func(c *Conn) {
+		c.tradeGapCallback = fn
+	}
+}
+
 // WithConnectCallback returns an options which sets a callback function for
 // when the connection is fully initialised and the orderbook has been set up.
 func WithConnectCallback(fn ConnectCallback) DialOption {
@@ -18,3 +41,87 @@ func WithConnectCallback(fn ConnectCallback) DialOption {
 		c.connectCallback = fn
 	}
 }
+
+// WithDisconnectCallback returns an option which calls fn when the
+// connection has lost its order book for longer than grace, e.g. to cancel
+// resting orders placed on the basis of the stream. The grace period starts
+// when an established connection drops and ends when the order book has been
+// received again after reconnecting, so brief disconnects don't call fn. It is
+// called at most once per disconnect, from its own goroutine, and not after
+// the Conn has been closed.
+func WithDisconnectCallback(grace time.Duration, fn DisconnectCallback) DialOption {
+	return func(c *Conn) {
+		c.disconnectGrace = grace
+		c.disconnectFn = fn
+	}
+}
+
+// WithReconnectCallback returns an option which calls fn whenever the
+// connection has dropped, or failed to connect, and is about to reconnect,
+// e.g. to count reconnects. It is called from the goroutine managing the
+// connection, so it must not block.
+func WithReconnectCallback(fn ReconnectCallback) DialOption {
+	return func(c *Conn) {
+		c.reconnectFn = fn
+	}
+}
+
+// WithHeartbeatTimeout returns an option which sets how long the connection
+// may go without receiving any message, including keepalives, before it is
+// considered stale. A stale connection is closed and reconnected.
+func WithHeartbeatTimeout(d time.Duration) DialOption {
+	return func(c *Conn) {
+		c.heartbeatTimeout = d
+	}
+}
+
+// WithBookValidation returns an option which checks the integrity of the order
+// book after every update. The Luno Streaming API doesn't provide checksums,
+// so the book is considered corrupt if the best bid is at or above the best
+// ask. A corrupt book causes the connection to be reset and the book to be
+// resynchronised from a new snapshot.
+func WithBookValidation() DialOption {
+	return func(c *Conn) {
+		c.validateBook = true
+	}
+}
+
+// BackpressurePolicy determines what happens when an update channel is full.
+type BackpressurePolicy int
+
+const (
+	// BackpressureBlock stops reading from the connection until the consumer
+	// has received the update. No updates are lost, but if the consumer falls
+	// too far behind the server may disconnect, in which case the order book
+	// is resynchronised from a new snapshot.
+	BackpressureBlock BackpressurePolicy = iota
+
+	// BackpressureResnapshot drops updates while the channel is full. Once
+	// the consumer has drained the channel, the connection is reset and the
+	// order book resynchronised from a new snapshot, which is signalled by the
+	// connect callback.
+	BackpressureResnapshot
+)
+
+// WithUpdateChannel returns an option which delivers applied updates on a
+// channel of the given size, available from Conn.Updates. The policy
+// determines what happens when the consumer falls behind.
+func WithUpdateChannel(size int, policy BackpressurePolicy) DialOption {
+	return func(c *Conn) {
+		c.updates = make(chan Update, size)
+		c.backpressure = policy
+	}
+}
+
+// WithMaxReconnectAttempts returns an option which stops reconnecting after n
+// consecutive attempts fail to receive the order book, e.g. during a prolonged
+// outage. The delay between attempts grows exponentially up to a minute. On
+// giving up fn, if not nil, is called with the last error and the Conn stays
+// disconnected until Restart is called. Attempts which receive the order book
+// reset the count. By default the Conn reconnects forever.
+func WithMaxReconnectAttempts(n int, fn GiveUpCallback) DialOption {
+	return func(c *Conn) {
+		c.maxAttempts = n
+		c.giveUpFn = fn
+	}
+}
@@ -25,8 +25,8 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"math"
 	"math/rand"
+	"net"
 	"sort"
 	"sync"
 	"time"
@@ -42,6 +42,10 @@ const websocketTimeout = time.Minute
 func convertOrders(ol []*order) (map[string]order, error) {
 	r := make(map[string]order)
 	for _, o := range ol {
+		if o.Volume.Sign() <= 0 {
+			// Empty orders don't affect the book.
+			continue
+		}
 		r[o.ID] = *o
 	}
 	return r, nil
@@ -49,8 +53,13 @@ func convertOrders(ol []*order) (map[string]order, error) {
 
 type orderList []luno.OrderBookEntry
 
+// Less orders entries by exact price, then volume, so that entries with the
+// same price are always flattened in the same order.
 func (ol orderList) Less(i, j int) bool {
-	return ol[i].Price.Cmp(ol[j].Price) < 0
+	if c := ol[i].Price.Cmp(ol[j].Price); c != 0 {
+		return c < 0
+	}
+	return ol[i].Volume.Cmp(ol[j].Volume) < 0
 }
 func (ol orderList) Swap(i, j int) {
 	ol[i], ol[j] = ol[j], ol[i]
@@ -78,20 +87,85 @@ func flatten(m map[string]order, reverse bool) []luno.OrderBookEntry {
 type ConnectCallback func(*Conn)
 type UpdateCallback func(Update)
 
+// DisconnectCallback is called when a Conn has been disconnected for longer
+// than the grace period set with WithDisconnectCallback.
+type DisconnectCallback func(*Conn)
+
+// TradeCallback is called with each trade, see WithTradeCallback.
+type TradeCallback func(Trade)
+
+// TradeGapCallback is called when the updates with sequence numbers from
+// from to to inclusive were missed, see WithTradeGapCallback.
+type TradeGapCallback func(from, to luno.Sequence)
+
+// ReconnectCallback is called when a Conn is about to reconnect after delay,
+// with the error which ended the last connection attempt, if any, see
+// WithReconnectCallback.
+type ReconnectCallback func(c *Conn, delay time.Duration, err error)
+
+// GiveUpCallback is called with the last connection error when a Conn stops
+// reconnecting after the attempts set with WithMaxReconnectAttempts.
+type GiveUpCallback func(*Conn, error)
+
 type Conn struct {
 	keyID, keySecret string
 	pair             string
+
+	// host is the websocket host, read from the luno_websocket_host flag
+	// when the Conn is dialled.
+	host string
+
 	connectCallback  ConnectCallback
 	updateCallback   UpdateCallback
+	tradeCallback    TradeCallback
+	tradeGapCallback TradeGapCallback
+	disconnectFn     DisconnectCallback
+	reconnectFn      ReconnectCallback
+	disconnectGrace  time.Duration
+	heartbeatTimeout time.Duration
+	validateBook     bool
+	clock            clock
+
+	// maxAttempts is the number of consecutive attempts which fail to
+	// receive the order book after which the Conn gives up, or zero to
+	// reconnect forever.
+	maxAttempts int
+	giveUpFn    GiveUpCallback
+	gaveUp      bool
+	restart     chan struct{}
+
+	updates       chan Update
+	backpressure  BackpressurePolicy
+	laggedUpdates int64
+	resync        bool
 
 	closed bool
+	done   chan struct{}
+
+	subs map[*Subscription]bool
 
-	seq  int64
+	// delivered is the sequence of the last update passed to the update
+	// callback and channel. It survives reconnects, so that updates which
+	// are redelivered after a new snapshot are applied to the book but not
+	// passed on again.
+	delivered luno.Sequence
+
+	seq  luno.Sequence
 	bids map[string]order
 	asks map[string]order
 
+	// ready, if not nil, is closed once the next order book is received.
+	ready chan struct{}
+
+	// disconnectTimer runs from a disconnect until the grace period expires
+	// or the order book is received again.
+	disconnectTimer *time.Timer
+
 	status luno.Status
 
+	// timestamp is the server time of the last order book or update.
+	timestamp time.Time
+
 	lastMessage time.Time
 	lastTrade   TradeUpdate
 
@@ -107,9 +181,14 @@ func Dial(keyID, keySecret, pair string, opts ...DialOption) (*Conn, error) {
 	}
 
 	c := &Conn{
-		keyID:     keyID,
-		keySecret: keySecret,
-		pair:      pair,
+		keyID:            keyID,
+		keySecret:        keySecret,
+		pair:             pair,
+		host:             *wsHost,
+		heartbeatTimeout: websocketTimeout,
+		clock:            realClock{},
+		done:             make(chan struct{}),
+		restart:          make(chan struct{}, 1),
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -119,16 +198,78 @@ func Dial(keyID, keySecret, pair string, opts ...DialOption) (*Conn, error) {
 	return c, nil
 }
 
+// StreamFromSnapshot is like Dial, but for consumers which already hold a
+// snapshot of the order book, e.g. saved from an earlier connection using
+// Snapshot.OrderBook. Updates up to and including snapshot.Sequence are
+// discarded and only later updates are passed to the update callback and
+// channel, so the consumer can continue applying updates to its own copy of
+// the book. The connection's own book is still built from the stream, so
+// Snapshot may lag behind the given snapshot until the stream catches up.
+//
+// The connection is closed once ctx is done. An error is returned if
+// snapshot has no sequence number, which is the case for order books from the
+// REST API.
+func StreamFromSnapshot(ctx context.Context, keyID, keySecret, pair string,
+	snapshot luno.OrderBook, opts ...DialOption) (*Conn, error) {
+
+	if snapshot.Sequence == 0 {
+		return nil, errors.New("streaming: snapshot has no sequence number")
+	}
+	c, err := Dial(keyID, keySecret, pair, append(opts, func(c *Conn) {
+		c.delivered = snapshot.Sequence
+	})...)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.Close()
+		case <-c.done:
+		}
+	}()
+	return c, nil
+}
+
 var wsHost = flag.String(
 	"luno_websocket_host", "wss://ws.luno.com", "Luno API websocket host")
 
+// Reconnect delays double with every attempt up to maxReconnectDelay, with up
+// to reconnectJitter added or removed.
+const (
+	maxReconnectDelay = time.Minute
+	reconnectJitter   = 100 * time.Millisecond
+)
+
+// reconnectDelay returns how long to wait before reconnecting after the given
+// number of attempts, i.e. 2^attempts seconds up to a minute.
+func reconnectDelay(attempts int) time.Duration {
+	backoff := time.Second
+	for i := 0; i < attempts && backoff < maxReconnectDelay; i++ {
+		backoff *= 2
+	}
+	if backoff > maxReconnectDelay {
+		backoff = maxReconnectDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(2*reconnectJitter))) - reconnectJitter
+	return backoff + jitter
+}
+
 func (c *Conn) manageForever() {
+	if c.updates != nil {
+		// This goroutine is the only sender.
+		defer close(c.updates)
+	}
+
 	attempts := 0
+	failures := 0
 	var lastAttempt time.Time
 	for {
-		lastAttempt = time.Now()
+		lastAttempt = c.clock.Now()
 		attempts++
-		if err := c.connect(); err != nil {
+		synced, err := c.connect()
+		if err != nil {
 			log.Printf("luno/streaming: Connection error key=%s pair=%s: %v",
 				c.keyID, c.pair, err)
 		}
@@ -136,22 +277,94 @@ func (c *Conn) manageForever() {
 			return
 		}
 
-		if time.Now().Sub(lastAttempt) > 30*time.Minute {
+		if synced {
+			failures = 0
+		} else {
+			failures++
+		}
+		if c.maxAttempts > 0 && failures >= c.maxAttempts {
+			if err == nil {
+				err = errors.New("streaming: connection closed before " +
+					"receiving the order book")
+			}
+			log.Printf("luno/streaming: Giving up after %d attempts key=%s "+
+				"pair=%s", failures, c.keyID, c.pair)
+			if !c.giveUp(err) {
+				return
+			}
+			attempts, failures = 0, 0
+			continue
+		}
+
+		if c.clock.Now().Sub(lastAttempt) > 30*time.Minute {
 			attempts = 0
 		}
-		jitter := time.Duration(rand.Intn(200)-100) * time.Millisecond // ±100ms
-		backoff := time.Duration(math.Min(math.Pow(2, float64(attempts)), 60)) * time.Second // Exponential backoff up to 60s
-		dt := backoff + jitter
+		dt := reconnectDelay(attempts)
 		log.Printf("luno/streaming: Waiting %s before reconnecting", dt)
-		time.Sleep(dt)
+		if c.reconnectFn != nil {
+			c.reconnectFn(c, dt, err)
+		}
+		select {
+		case <-c.clock.After(dt):
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// giveUp calls the give up callback with err and waits until the Conn is
+// restarted, in which case it returns true, or closed.
+func (c *Conn) giveUp(err error) bool {
+	c.mu.Lock()
+	c.gaveUp = true
+	c.mu.Unlock()
+
+	if c.giveUpFn != nil {
+		c.giveUpFn(c, err)
+	}
+
+	select {
+	case <-c.restart:
+		return true
+	case <-c.done:
+		return false
 	}
 }
 
-func (c *Conn) connect() error {
-	url := *wsHost + "/api/1/stream/" + c.pair
+// GaveUp returns true if the Conn has stopped reconnecting after the number
+// of attempts set with WithMaxReconnectAttempts. It stays stopped until
+// Restart is called.
+func (c *Conn) GaveUp() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.gaveUp
+}
+
+// Restart resumes reconnecting after the Conn gave up. The attempts count
+// from zero again. An error is returned if the Conn is closed or hasn't given
+// up.
+func (c *Conn) Restart() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return errors.New("streaming: connection closed")
+	}
+	if !c.gaveUp {
+		return errors.New("streaming: connection hasn't given up")
+	}
+	c.gaveUp = false
+	c.restart <- struct{}{}
+	return nil
+}
+
+// connect runs a single connection until it fails or the Conn is closed. It
+// returns whether the order book was received.
+func (c *Conn) connect() (synced bool, err error) {
+	url := c.host + "/api/1/stream/" + c.pair
 	ws, err := websocket.Dial(url, "", "http://localhost/")
 	if err != nil {
-		return fmt.Errorf("unable to dial server: %w", err)
+		return false, fmt.Errorf("unable to dial server: %w", err)
 	}
 	defer func() {
 		_ = ws.Close()
@@ -160,7 +373,7 @@ func (c *Conn) connect() error {
 
 	cred := credentials{c.keyID, c.keySecret}
 	if err := websocket.JSON.Send(ws, cred); err != nil {
-		return fmt.Errorf("failed to send credentials: %w", err)
+		return synced, fmt.Errorf("failed to send credentials: %w", err)
 	}
 
 	log.Printf("luno/streaming: Connection established key=%s pair=%s",
@@ -172,18 +385,31 @@ func (c *Conn) connect() error {
 
 	for {
 		if c.IsClosed() {
-			return nil
+			return synced, nil
 		}
 
+		// The server sends keepalive messages, so a connection that has been
+		// silent for longer than the heartbeat timeout is considered stale.
+		// Ping frames are answered with pongs by the websocket package.
 		var data []byte
-		_ = ws.SetReadDeadline(time.Now().Add(websocketTimeout))
+		_ = ws.SetReadDeadline(time.Now().Add(c.heartbeatTimeout))
 		err := websocket.Message.Receive(ws, &data)
 		if errors.Is(err, io.EOF) {
 			// Server closed the connection. Return gracefully.
-			return nil
+			return synced, nil
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return synced, fmt.Errorf("streaming: no message received in %s, "+
+				"connection is stale: %w", c.heartbeatTimeout, err)
 		}
 		if err != nil {
-			return fmt.Errorf("failed to receive message: %w", err)
+			return synced, fmt.Errorf("failed to receive message: %w", err)
+		}
+
+		if c.resyncDue() {
+			return synced, errors.New("streaming: update consumer lagged, " +
+				"resynchronising order book")
 		}
 
 		if string(data) == "\"\"" {
@@ -193,13 +419,14 @@ func (c *Conn) connect() error {
 
 		var ob orderBook
 		if err := json.Unmarshal(data, &ob); err != nil {
-			return fmt.Errorf("failed to unmarshal order book: %w", err)
+			return synced, fmt.Errorf("failed to unmarshal order book: %w", err)
 		}
 		if ob.Asks != nil || ob.Bids != nil {
 			// Received an order book.
 			if err := c.receivedOrderBook(ob); err != nil {
-				return fmt.Errorf("failed to process order book: %w", err)
+				return synced, fmt.Errorf("failed to process order book: %w", err)
 			}
+			synced = true
 			if c.connectCallback != nil {
 				c.connectCallback(c)
 			}
@@ -208,10 +435,10 @@ func (c *Conn) connect() error {
 
 		var u Update
 		if err := json.Unmarshal(data, &u); err != nil {
-			return fmt.Errorf("failed to unmarshal update: %w", err)
+			return synced, fmt.Errorf("failed to unmarshal update: %w", err)
 		}
 		if err := c.receivedUpdate(u); err != nil {
-			return fmt.Errorf("failed to process update: %w", err)
+			return synced, fmt.Errorf("failed to process update: %w", err)
 		}
 	}
 }
@@ -257,66 +484,192 @@ func (c *Conn) receivedOrderBook(ob orderBook) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if c.delivered != 0 && ob.Sequence > c.delivered {
+		// Updates after the last one delivered are folded into the new
+		// book, so their trades can't be recovered.
+		if c.tradeGapCallback != nil {
+			c.tradeGapCallback(c.delivered+1, ob.Sequence)
+		}
+		c.delivered = ob.Sequence
+	}
+
 	c.lastMessage = time.Now()
 	c.seq = ob.Sequence
 	c.status = ob.Status
+	c.timestamp = time.Time(luno.TimeFromMillis(ob.Timestamp))
 	c.bids = bids
 	c.asks = asks
+	if c.disconnectTimer != nil {
+		c.disconnectTimer.Stop()
+		c.disconnectTimer = nil
+	}
+	if c.ready != nil {
+		close(c.ready)
+		c.ready = nil
+	}
+
+	// Every subscriber gets its own copy of the book to apply updates to.
+	c.publishLocked(func() Event { return Event{Book: c.bookLocked()} })
 	return nil
 }
 
 func (c *Conn) receivedUpdate(u Update) error {
+	deliver, err := c.applyUpdate(u)
+	if err != nil || !deliver {
+		return err
+	}
+	c.sendUpdate(u)
+	return nil
+}
+
+// applyUpdate applies u to the order book and returns whether it should be
+// delivered, i.e. it was applied and hasn't been delivered before.
+func (c *Conn) applyUpdate(u Update) (bool, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if c.seq == 0 {
 		// State not initialized so we can't update it.
-		return nil
+		return false, nil
 	}
 
 	if u.Sequence <= c.seq {
 		// Old update. We can just discard it.
-		return nil
+		return false, nil
 	}
 	if u.Sequence != c.seq+1 {
-		return errors.New("streaming: update received out of sequence")
+		return false, errors.New("streaming: update received out of sequence")
 	}
 
 	// Process trades
+	trades := make([]Trade, 0, len(u.TradeUpdates))
 	for _, t := range u.TradeUpdates {
-		if err := c.processTrade(*t); err != nil {
-			return err
+		trade, err := c.processTrade(*t)
+		if err != nil {
+			return false, err
 		}
+		trade.Pair = c.pair
+		trade.Sequence = u.Sequence
+		trade.Timestamp = time.Time(luno.TimeFromMillis(u.Timestamp))
+		trades = append(trades, trade)
 	}
 
 	// Process create
 	if u.CreateUpdate != nil {
 		if err := c.processCreate(*u.CreateUpdate); err != nil {
-			return err
+			return false, err
 		}
 	}
 
 	// Process delete
 	if u.DeleteUpdate != nil {
 		if err := c.processDelete(*u.DeleteUpdate); err != nil {
-			return err
+			return false, err
 		}
 	}
 
 	// Process status
 	if u.StatusUpdate != nil {
 		if err := c.processStatus(*u.StatusUpdate); err != nil {
-			return err
+			return false, err
+		}
+	}
+
+	if c.validateBook {
+		if err := checkBook(c.bids, c.asks); err != nil {
+			return false, err
 		}
 	}
 
 	c.lastMessage = time.Now()
 	c.seq = u.Sequence
+	if u.Timestamp != 0 {
+		c.timestamp = time.Time(luno.TimeFromMillis(u.Timestamp))
+	}
 
-	if c.updateCallback != nil {
-		c.updateCallback(u)
+	deliver := u.Sequence > c.delivered
+	if deliver {
+		c.delivered = u.Sequence
+		if c.updateCallback != nil {
+			c.updateCallback(u)
+		}
+		if c.tradeCallback != nil {
+			for _, t := range trades {
+				c.tradeCallback(t)
+			}
+		}
+	}
+	// Subscribers get a new book after a reconnect, so they need every
+	// update applied to it.
+	c.publishLocked(func() Event { return Event{Update: &u} })
+
+	return deliver, nil
+}
+
+// sendUpdate delivers u on the update channel, if any, applying the
+// backpressure policy when the channel is full.
+func (c *Conn) sendUpdate(u Update) {
+	if c.updates == nil {
+		return
+	}
+	select {
+	case c.updates <- u:
+		return
+	default:
+	}
+
+	c.mu.Lock()
+	c.laggedUpdates++
+	if c.backpressure == BackpressureResnapshot {
+		c.resync = true
+	}
+	resync := c.resync
+	c.mu.Unlock()
+
+	if resync {
+		// The consumer has missed an update so there's no point in sending
+		// any more until it has been resynchronised.
+		return
+	}
+	select {
+	case c.updates <- u:
+	case <-c.done:
 	}
+}
+
+// resyncDue returns true once the consumer of a lagged update channel has
+// caught up, at which point the connection should be reset to resynchronise
+// the order book.
+func (c *Conn) resyncDue() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.resync && len(c.updates) == 0
+}
 
+// checkBook returns an error if the best bid is not below the best ask. Luno
+// matches crossing orders before they enter the book, so a crossed book means
+// that updates were missed or misapplied.
+func checkBook(bids, asks map[string]order) error {
+	var bestBid, bestAsk *order
+	for id := range bids {
+		o := bids[id]
+		if bestBid == nil || o.Price.Cmp(bestBid.Price) > 0 {
+			bestBid = &o
+		}
+	}
+	for id := range asks {
+		o := asks[id]
+		if bestAsk == nil || o.Price.Cmp(bestAsk.Price) < 0 {
+			bestAsk = &o
+		}
+	}
+	if bestBid == nil || bestAsk == nil {
+		return nil
+	}
+	if bestBid.Price.Cmp(bestAsk.Price) >= 0 {
+		return fmt.Errorf("streaming: order book crossed: bid %s >= ask %s",
+			bestBid.Price, bestAsk.Price)
+	}
 	return nil
 }
 
@@ -342,9 +695,11 @@ func decTrade(m map[string]order, id string, base decimal.Decimal) (
 	return true, nil
 }
 
-func (c *Conn) processTrade(t TradeUpdate) error {
+// processTrade applies t to the order book and returns the trade, without its
+// pair, sequence and timestamp.
+func (c *Conn) processTrade(t TradeUpdate) (Trade, error) {
 	if t.Base.Sign() <= 0 {
-		return errors.New("streaming: nonpositive trade")
+		return Trade{}, errors.New("streaming: nonpositive trade")
 	}
 
 	c.lastTrade = TradeUpdate{
@@ -352,22 +707,33 @@ func (c *Conn) processTrade(t TradeUpdate) error {
 		Counter: t.Counter,
 	}
 
+	trade := Trade{
+		Volume:       t.Base,
+		Counter:      t.Counter,
+		MakerOrderID: t.OrderID,
+	}
+	if o, ok := c.bids[t.OrderID]; ok {
+		trade.Price, trade.TakerSide = o.Price, luno.SideSell
+	} else if o, ok := c.asks[t.OrderID]; ok {
+		trade.Price, trade.TakerSide = o.Price, luno.SideBuy
+	}
+
 	ok, err := decTrade(c.bids, t.OrderID, t.Base)
 	if err != nil {
-		return err
+		return Trade{}, err
 	}
 	if ok {
-		return nil
+		return trade, nil
 	}
 
 	ok, err = decTrade(c.asks, t.OrderID, t.Base)
 	if err != nil {
-		return err
+		return Trade{}, err
 	}
 	if ok {
-		return nil
+		return trade, nil
 	}
-	return errors.New("streaming: trade for unknown order")
+	return Trade{}, errors.New("streaming: trade for unknown order")
 }
 
 func (c *Conn) processCreate(u CreateUpdate) error {
@@ -377,14 +743,19 @@ func (c *Conn) processCreate(u CreateUpdate) error {
 		Volume: u.Volume,
 	}
 
+	var m map[string]order
 	if u.Type == string(luno.OrderTypeBid) {
-		c.bids[o.ID] = o
+		m = c.bids
 	} else if u.Type == string(luno.OrderTypeAsk) {
-		c.asks[o.ID] = o
+		m = c.asks
 	} else {
 		return errors.New("streaming: unknown order type")
 	}
 
+	// Empty orders don't affect the book, so they're not kept.
+	if o.Volume.Sign() > 0 {
+		m[o.ID] = o
+	}
 	return nil
 }
 
@@ -418,30 +789,61 @@ func (c *Conn) OrderBookSnapshot() (
 
 	bids := flatten(c.bids, true)
 	asks := flatten(c.asks, false)
-	return c.seq, bids, asks
+	return int64(c.seq), bids, asks
 }
 
 type Snapshot struct {
-	Sequence   int64
+	Sequence   luno.Sequence
 	Bids, Asks []luno.OrderBookEntry
 	Status     luno.Status
 	LastTrade  TradeUpdate
+
+	// Timestamp is the server time of the last order book or update
+	// received.
+	Timestamp time.Time
 }
 
-// Snapshot returns the current state of the streamed data.
+// Snapshot returns the current state of the streamed data. Bids are sorted by
+// price descending and asks by price ascending, with orders at the same price
+// ordered by volume in the same direction.
 func (c *Conn) Snapshot() Snapshot {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+	return c.snapshotLocked()
+}
 
+// snapshotLocked returns the current state of the streamed data. It must be
+// called with c.mu held.
+func (c *Conn) snapshotLocked() Snapshot {
 	return Snapshot{
 		Sequence:  c.seq,
 		Bids:      flatten(c.bids, true),
 		Asks:      flatten(c.asks, false),
 		Status:    c.status,
 		LastTrade: c.lastTrade,
+		Timestamp: c.timestamp,
 	}
 }
 
+// OrderBook returns the bids, asks, sequence and timestamp of the snapshot,
+// with the orders at each price merged into a single level, see
+// luno.OrderBook.Normalize. The result has the same semantics as the book
+// from luno.Client.GetOrderBook, so the same code can handle both.
+func (s Snapshot) OrderBook() luno.OrderBook {
+	ob := luno.OrderBook{
+		Bids:      s.Bids,
+		Asks:      s.Asks,
+		Sequence:  s.Sequence,
+		Timestamp: s.Timestamp,
+	}
+	return ob.Normalize()
+}
+
+// Pair returns the market pair of the connection.
+func (c *Conn) Pair() string {
+	return c.pair
+}
+
 // Status returns the currenct status of the streaming connection.
 func (c *Conn) Status() luno.Status {
 	c.mu.RLock()
@@ -454,7 +856,17 @@ func (c *Conn) Status() luno.Status {
 // struct (Snapshot, Status...) will be zeroed values.
 func (c *Conn) Close() {
 	c.mu.Lock()
+	if !c.closed {
+		close(c.done)
+	}
 	c.closed = true
+	for s := range c.subs {
+		c.unsubscribeLocked(s, nil)
+	}
+	if c.disconnectTimer != nil {
+		c.disconnectTimer.Stop()
+		c.disconnectTimer = nil
+	}
 	c.mu.Unlock()
 
 	c.reset()
@@ -464,10 +876,99 @@ func (c *Conn) reset() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if c.disconnectFn != nil && c.seq != 0 && !c.closed &&
+		c.disconnectTimer == nil {
+		var t *time.Timer
+		t = time.AfterFunc(c.disconnectGrace, func() {
+			c.mu.Lock()
+			// t is read with mu held, so after it has been set below.
+			c.disconnected(t)
+		})
+		c.disconnectTimer = t
+	}
+
 	c.seq = 0
 	c.bids = nil
 	c.asks = nil
 	c.status = ""
+	c.timestamp = time.Time{}
+	c.resync = false
+}
+
+// disconnected calls the disconnect callback once timer t has fired, unless
+// the order book has been received again, the Conn closed or t replaced by
+// the timer of a later disconnect since. It must be called with mu held, and
+// unlocks it.
+func (c *Conn) disconnected(t *time.Timer) {
+	if c.closed || c.seq != 0 || c.disconnectTimer != t {
+		c.mu.Unlock()
+		return
+	}
+	c.disconnectTimer = nil
+	c.mu.Unlock()
+
+	c.disconnectFn(c)
+}
+
+// Updates returns the channel configured with WithUpdateChannel, or nil. The
+// channel is closed once the Conn has been closed.
+func (c *Conn) Updates() <-chan Update {
+	return c.updates
+}
+
+// LaggedUpdates returns the number of updates which found the update channel
+// full. With BackpressureBlock these updates were delayed, and with
+// BackpressureResnapshot they were dropped.
+func (c *Conn) LaggedUpdates() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.laggedUpdates
+}
+
+// LastMessage returns the time at which the last message, including
+// keepalives, was received from the server.
+func (c *Conn) LastMessage() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastMessage
+}
+
+// IsReady returns true if an order book snapshot has been received and the
+// order book is up to date. It returns false while the connection is being
+// (re)established, in which case the order book is empty.
+func (c *Conn) IsReady() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.seq != 0
+}
+
+// WaitReady blocks until an order book snapshot has been received, ctx is done
+// or the Conn is closed. Since the order book is reset on reconnecting, it
+// may be called again to wait for the book to be resynchronised.
+func (c *Conn) WaitReady(ctx context.Context) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return errors.New("streaming: connection closed")
+	}
+	if c.seq != 0 {
+		c.mu.Unlock()
+		return nil
+	}
+	if c.ready == nil {
+		c.ready = make(chan struct{})
+	}
+	ready := c.ready
+	c.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-c.done:
+		return errors.New("streaming: connection closed")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // IsClosed returns true if the Conn has been closed.
@@ -1,7 +1,18 @@
 package streaming
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
 
 	"github.com/luno/luno-go"
 	"github.com/luno/luno-go/decimal"
@@ -12,7 +23,7 @@ type expected struct {
 	asks      map[string]order
 	bids      map[string]order
 	lastTrade TradeUpdate
-	seq       int64
+	seq       luno.Sequence
 	status    luno.Status
 }
 
@@ -318,6 +329,51 @@ func TestReceivedDelete(t *testing.T) {
 	}
 }
 
+func TestBookValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		create  CreateUpdate
+		wantErr bool
+	}{
+		{
+			name: "valid bid",
+			create: CreateUpdate{OrderID: "7", Type: "BID",
+				Price:  decimal.NewFromFloat64(130.0, 1),
+				Volume: decimal.NewFromFloat64(0.01, 2)},
+		},
+		{
+			name: "crossed bid",
+			create: CreateUpdate{OrderID: "7", Type: "BID",
+				Price:  decimal.NewFromFloat64(150.0, 1),
+				Volume: decimal.NewFromFloat64(0.01, 2)},
+			wantErr: true,
+		},
+		{
+			name: "crossed ask",
+			create: CreateUpdate{OrderID: "8", Type: "ASK",
+				Price:  decimal.NewFromFloat64(110.0, 1),
+				Volume: decimal.NewFromFloat64(0.01, 2)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Conn{
+				asks:         asksMap(),
+				bids:         bidsMap(),
+				seq:          1,
+				status:       luno.StatusActive,
+				validateBook: true,
+			}
+			create := tt.create
+			err := c.receivedUpdate(Update{Sequence: 2, CreateUpdate: &create})
+			if tt.wantErr != (err != nil) {
+				t.Errorf("Expected error %t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func bidsMap(o ...order) map[string]order {
 	res := map[string]order{
 		"1": {ID: "1", Price: decimal.NewFromFloat64(120.0, 1),
@@ -430,3 +486,786 @@ func book() orderBook {
 		Status:   luno.StatusActive,
 	}
 }
+
+func TestHeartbeatTimeoutReconnects(t *testing.T) {
+	var connects int32
+	srv := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		var cred credentials
+		if err := websocket.JSON.Receive(ws, &cred); err != nil {
+			return
+		}
+		atomic.AddInt32(&connects, 1)
+		_ = websocket.JSON.Send(ws, map[string]interface{}{
+			"sequence": "1",
+			"asks":     []interface{}{},
+			"bids":     []interface{}{},
+			"status":   "ACTIVE",
+		})
+
+		// Go silent until the client gives up on the connection.
+		var data []byte
+		_ = websocket.Message.Receive(ws, &data)
+	}))
+	defer srv.Close()
+
+	oldHost := *wsHost
+	*wsHost = "ws" + strings.TrimPrefix(srv.URL, "http")
+	defer func() { *wsHost = oldHost }()
+
+	c, err := Dial("key", "secret", "XBTZAR",
+		WithHeartbeatTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	defer c.Close()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for atomic.LoadInt32(&connects) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected stale connection to be reconnected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if c.LastMessage().IsZero() {
+		t.Errorf("Expected last message time to be set")
+	}
+}
+
+func newChannelConn(policy BackpressurePolicy) *Conn {
+	c := &Conn{
+		asks:   asksMap(),
+		bids:   bidsMap(),
+		seq:    1,
+		status: luno.StatusActive,
+		done:   make(chan struct{}),
+	}
+	WithUpdateChannel(1, policy)(c)
+	return c
+}
+
+func deleteUpdate(seq luno.Sequence) Update {
+	return Update{Sequence: seq, DeleteUpdate: &DeleteUpdate{OrderID: "missing"}}
+}
+
+func TestBackpressureBlock(t *testing.T) {
+	c := newChannelConn(BackpressureBlock)
+
+	sent := make(chan error)
+	go func() {
+		for seq := luno.Sequence(2); seq <= 4; seq++ {
+			if err := c.receivedUpdate(deleteUpdate(seq)); err != nil {
+				sent <- err
+				return
+			}
+		}
+		close(sent)
+	}()
+
+	// The slow consumer doesn't read until the reader is blocked.
+	deadline := time.Now().Add(5 * time.Second)
+	for c.LaggedUpdates() < 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected reader to block on a full channel")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for seq := luno.Sequence(2); seq <= 4; seq++ {
+		u := <-c.Updates()
+		if u.Sequence != seq {
+			t.Errorf("Expected update %d, got %d", seq, u.Sequence)
+		}
+	}
+	if err := <-sent; err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if c.resyncDue() {
+		t.Errorf("Expected no resync with BackpressureBlock")
+	}
+}
+
+func TestBackpressureResnapshot(t *testing.T) {
+	c := newChannelConn(BackpressureResnapshot)
+
+	// The reader never blocks, so updates beyond the channel size are
+	// dropped.
+	for seq := luno.Sequence(2); seq <= 4; seq++ {
+		if err := c.receivedUpdate(deleteUpdate(seq)); err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+	}
+	if c.LaggedUpdates() != 2 {
+		t.Errorf("Expected 2 lagged updates, got %d", c.LaggedUpdates())
+	}
+	if c.Snapshot().Sequence != 4 {
+		t.Errorf("Expected book at sequence 4, got %d", c.Snapshot().Sequence)
+	}
+	if c.resyncDue() {
+		t.Errorf("Expected no resync before the consumer catches up")
+	}
+
+	u := <-c.Updates()
+	if u.Sequence != 2 {
+		t.Errorf("Expected update 2, got %d", u.Sequence)
+	}
+	if !c.resyncDue() {
+		t.Errorf("Expected resync once the consumer caught up")
+	}
+
+	c.reset()
+	if c.resyncDue() {
+		t.Errorf("Expected no resync after reset")
+	}
+}
+
+func TestStartFromSnapshot(t *testing.T) {
+	c := newChannelConn(BackpressureBlock)
+	c.updates = make(chan Update, 10)
+	c.delivered = 3
+	var called []luno.Sequence
+	c.updateCallback = func(u Update) { called = append(called, u.Sequence) }
+
+	// Updates 2 and 3 are already included in the snapshot, and update 1 is
+	// older than the book.
+	for _, seq := range []luno.Sequence{1, 2, 3, 4, 5} {
+		if err := c.receivedUpdate(deleteUpdate(seq)); err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+	}
+	if c.Snapshot().Sequence != 5 {
+		t.Errorf("Expected book at sequence 5, got %d", c.Snapshot().Sequence)
+	}
+	exp := []luno.Sequence{4, 5}
+	if !reflect.DeepEqual(called, exp) {
+		t.Errorf("Expected callbacks for %v, got %v", exp, called)
+	}
+	close(c.updates)
+	var sent []luno.Sequence
+	for u := range c.Updates() {
+		sent = append(sent, u.Sequence)
+	}
+	if !reflect.DeepEqual(sent, exp) {
+		t.Errorf("Expected updates %v, got %v", exp, sent)
+	}
+}
+
+func TestStreamFromSnapshotNoSequence(t *testing.T) {
+	_, err := StreamFromSnapshot(context.Background(), "key", "secret", "XBTZAR",
+		luno.OrderBook{})
+	if err == nil {
+		t.Errorf("Expected error for snapshot without sequence")
+	}
+}
+
+func TestSnapshotOrderBookSequence(t *testing.T) {
+	c := newChannelConn(BackpressureBlock)
+	if seq := c.Snapshot().OrderBook().Sequence; seq != 1 {
+		t.Errorf("Expected sequence 1, got %d", seq)
+	}
+}
+
+func TestDuplicateUpdates(t *testing.T) {
+	var delivered []luno.Sequence
+	c := &Conn{
+		done: make(chan struct{}),
+		updateCallback: func(u Update) {
+			delivered = append(delivered, u.Sequence)
+		},
+	}
+	receive := func(updates ...Update) {
+		for _, u := range updates {
+			if err := c.receivedUpdate(u); err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+		}
+	}
+
+	newBid := order{ID: "7", Price: decimal.NewFromFloat64(115.0, 1),
+		Volume: decimal.NewFromFloat64(0.2, 1)}
+	create := Update{Sequence: 2, CreateUpdate: &CreateUpdate{
+		OrderID: newBid.ID,
+		Type:    string(luno.OrderTypeBid),
+		Price:   newBid.Price,
+		Volume:  newBid.Volume,
+	}}
+	deleteAsk := Update{Sequence: 3, DeleteUpdate: &DeleteUpdate{OrderID: "2"}}
+	deleteBid := Update{Sequence: 4, DeleteUpdate: &DeleteUpdate{OrderID: "7"}}
+
+	if err := c.receivedOrderBook(book()); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	receive(create, deleteAsk, create, deleteAsk)
+
+	asks := asksMap()
+	delete(asks, "2")
+	if !compareOrderMaps(c.bids, bidsMap(newBid)) {
+		t.Errorf("Expected bids %v, got %v", bidsMap(newBid), c.bids)
+	}
+	if !compareOrderMaps(c.asks, asks) {
+		t.Errorf("Expected asks %v, got %v", asks, c.asks)
+	}
+
+	// After a reconnect the new snapshot is older than the last update
+	// applied, so update 3 is redelivered.
+	c.reset()
+	ob := book()
+	ob.Sequence = 2
+	ob.Bids = append(ob.Bids, &newBid)
+	if err := c.receivedOrderBook(ob); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	receive(create, deleteAsk, deleteBid, deleteAsk)
+
+	if !compareOrderMaps(c.bids, bidsMap()) {
+		t.Errorf("Expected bids %v, got %v", bidsMap(), c.bids)
+	}
+	if !compareOrderMaps(c.asks, asks) {
+		t.Errorf("Expected asks %v, got %v", asks, c.asks)
+	}
+	exp := []luno.Sequence{2, 3, 4}
+	if !reflect.DeepEqual(delivered, exp) {
+		t.Errorf("Expected updates %v to be delivered once, got %v",
+			exp, delivered)
+	}
+
+	// A gap still requires a resync.
+	if err := c.receivedUpdate(deleteUpdate(6)); err == nil {
+		t.Errorf("Expected error for update out of sequence")
+	}
+}
+
+func TestWaitReady(t *testing.T) {
+	c := &Conn{done: make(chan struct{})}
+	if c.IsReady() {
+		t.Errorf("Expected conn without a book not to be ready")
+	}
+
+	waited := make(chan error)
+	go func() {
+		waited <- c.WaitReady(context.Background())
+	}()
+	select {
+	case err := <-waited:
+		t.Fatalf("Expected WaitReady to block before the snapshot, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := c.receivedOrderBook(book()); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	select {
+	case err := <-waited:
+		if err != nil {
+			t.Errorf("Expected success, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected WaitReady to return after the snapshot")
+	}
+	if !c.IsReady() {
+		t.Errorf("Expected conn to be ready")
+	}
+	if err := c.WaitReady(context.Background()); err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+
+	c.reset()
+	if c.IsReady() {
+		t.Errorf("Expected conn not to be ready after reset")
+	}
+}
+
+func TestWaitReadyCancel(t *testing.T) {
+	c := &Conn{done: make(chan struct{})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	waited := make(chan error)
+	go func() {
+		waited <- c.WaitReady(ctx)
+	}()
+	cancel()
+	select {
+	case err := <-waited:
+		if err != context.Canceled {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected WaitReady to return on cancellation")
+	}
+
+	c.Close()
+	if err := c.WaitReady(context.Background()); err == nil {
+		t.Errorf("Expected error once closed")
+	}
+}
+
+func TestSnapshotSorted(t *testing.T) {
+	o := func(id, price, volume string) *order {
+		p, _ := decimal.NewFromString(price)
+		v, _ := decimal.NewFromString(volume)
+		return &order{ID: id, Price: p, Volume: v}
+	}
+	c := &Conn{done: make(chan struct{})}
+	err := c.receivedOrderBook(orderBook{
+		Sequence: 1,
+		Bids: []*order{
+			o("b1", "99.5", "1"), o("b2", "100", "2"), o("b3", "99.50", "3"),
+			o("b4", "9", "1"), o("b5", "100", "1"),
+		},
+		Asks: []*order{
+			o("a1", "110", "1"), o("a2", "101.0", "2"), o("a3", "101", "1"),
+			o("a4", "1000", "1"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	entries := func(ol []luno.OrderBookEntry) []string {
+		var res []string
+		for _, e := range ol {
+			res = append(res, e.Price.String()+"@"+e.Volume.String())
+		}
+		return res
+	}
+	expBids := []string{"100@2", "100@1", "99.50@3", "99.5@1", "9@1"}
+	expAsks := []string{"101@1", "101.0@2", "110@1", "1000@1"}
+
+	// Orders are held in maps, so flatten repeatedly to cover their random
+	// iteration order.
+	for i := 0; i < 20; i++ {
+		s := c.Snapshot()
+		if got := entries(s.Bids); !reflect.DeepEqual(got, expBids) {
+			t.Fatalf("Expected bids %v, got %v", expBids, got)
+		}
+		if got := entries(s.Asks); !reflect.DeepEqual(got, expAsks) {
+			t.Fatalf("Expected asks %v, got %v", expAsks, got)
+		}
+	}
+}
+
+func TestDisconnectCallback(t *testing.T) {
+	fired := make(chan *Conn, 10)
+	newConn := func() *Conn {
+		c := &Conn{done: make(chan struct{})}
+		WithDisconnectCallback(50*time.Millisecond, func(c *Conn) {
+			fired <- c
+		})(c)
+		if err := c.receivedOrderBook(book()); err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		return c
+	}
+
+	t.Run("sustained", func(t *testing.T) {
+		c := newConn()
+		c.reset()
+		select {
+		case got := <-fired:
+			if got != c {
+				t.Errorf("Expected callback with the disconnected conn")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected callback after a sustained disconnect")
+		}
+		// Failed reconnects don't restart the grace period.
+		c.reset()
+		select {
+		case <-fired:
+			t.Errorf("Expected a single callback per disconnect")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("brief", func(t *testing.T) {
+		c := newConn()
+		c.reset()
+		time.Sleep(10 * time.Millisecond)
+		if err := c.receivedOrderBook(book()); err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		select {
+		case <-fired:
+			t.Errorf("Expected no callback after a brief disconnect")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("superseded", func(t *testing.T) {
+		c := newConn()
+		c.reset()
+		// Let the timer fire while a reconnect and a second disconnect
+		// replace it, so that it runs once they are done.
+		c.mu.Lock()
+		time.Sleep(100 * time.Millisecond)
+		later := time.AfterFunc(time.Hour, func() {})
+		defer later.Stop()
+		c.disconnectTimer = later
+		c.mu.Unlock()
+		select {
+		case <-fired:
+			t.Errorf("Expected no callback before the later disconnect's grace period")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		c := newConn()
+		c.Close()
+		select {
+		case <-fired:
+			t.Errorf("Expected no callback after closing")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
+
+func TestSnapshotOrderBookNormalized(t *testing.T) {
+	c := &Conn{done: make(chan struct{})}
+	if err := c.receivedOrderBook(book()); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	// A second bid at the best price and an empty ask.
+	updates := []Update{
+		{Sequence: 2, CreateUpdate: &CreateUpdate{OrderID: "7", Type: "BID",
+			Price: decimal.NewFromInt64(120), Volume: decimal.NewFromFloat64(0.2, 1)}},
+		{Sequence: 3, CreateUpdate: &CreateUpdate{OrderID: "8", Type: "ASK",
+			Price: decimal.NewFromInt64(160), Volume: decimal.Zero()}},
+	}
+	for _, u := range updates {
+		if err := c.receivedUpdate(u); err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+	}
+	if _, ok := c.asks["8"]; ok {
+		t.Errorf("Expected empty order not to be kept")
+	}
+
+	ob := c.Snapshot().OrderBook()
+	if len(ob.Bids) != 3 || len(ob.Asks) != 3 {
+		t.Fatalf("Expected 3 levels per side, got %d bids and %d asks",
+			len(ob.Bids), len(ob.Asks))
+	}
+	best := ob.Bids[0]
+	if best.Price.Cmp(decimal.NewFromInt64(120)) != 0 ||
+		best.Volume.Cmp(decimal.NewFromFloat64(0.3, 1)) != 0 {
+		t.Errorf("Expected merged best bid 0.3@120, got %s@%s",
+			best.Volume, best.Price)
+	}
+}
+
+func TestSnapshotOrderBookMatchesREST(t *testing.T) {
+	c := &Conn{done: make(chan struct{})}
+	ob := book()
+	ob.Timestamp = 1600000000000
+	if err := c.receivedOrderBook(ob); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	updates := []Update{
+		{Sequence: 2, Timestamp: 1600000001000, CreateUpdate: &CreateUpdate{
+			OrderID: "7", Type: "BID",
+			Price: decimal.NewFromFloat64(110.0, 1), Volume: decimal.NewFromFloat64(0.3, 1)}},
+		{Sequence: 3, Timestamp: 1600000002000, DeleteUpdate: &DeleteUpdate{OrderID: "2"}},
+	}
+	for _, u := range updates {
+		if err := c.receivedUpdate(u); err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+	}
+
+	// The top order book Luno would return for the same state.
+	var res luno.GetOrderBookResponse
+	err := json.Unmarshal([]byte(`{
+		"timestamp": 1600000002000,
+		"bids": [{"price":"120.0","volume":"0.1"},{"price":"110.0","volume":"0.8"},{"price":"100.0","volume":"1.0"}],
+		"asks": [{"price":"180.0","volume":"1.0"},{"price":"200.0","volume":"0.1"}]
+	}`), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	canonical := func(ob luno.OrderBook) string {
+		s := ob.Timestamp.String()
+		for _, side := range [][]luno.OrderBookEntry{ob.Bids, ob.Asks} {
+			s += "|"
+			for _, e := range side {
+				s += fmt.Sprintf(" %s@%s", e.Volume.String(), e.Price.String())
+			}
+		}
+		return s
+	}
+	stream := c.Snapshot().OrderBook()
+	rest := res.OrderBook()
+	if canonical(stream) != canonical(rest) {
+		t.Errorf("Expected stream book %s to match REST book %s",
+			canonical(stream), canonical(rest))
+	}
+	if stream.Sequence != 3 || rest.Sequence != 0 {
+		t.Errorf("Expected sequence only from the stream, got %d and %d",
+			stream.Sequence, rest.Sequence)
+	}
+}
+
+// fakeClock records the delays waited for and doesn't wait.
+type fakeClock struct {
+	mu     sync.Mutex
+	delays []time.Duration
+}
+
+func (f *fakeClock) Now() time.Time {
+	return time.Unix(0, 0)
+}
+
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	f.delays = append(f.delays, d)
+	f.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	ch <- time.Unix(0, 0)
+	return ch
+}
+
+func (f *fakeClock) Delays() []time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]time.Duration(nil), f.delays...)
+}
+
+func checkDelays(t *testing.T, got []time.Duration, exp ...time.Duration) {
+	t.Helper()
+	if len(got) != len(exp) {
+		t.Fatalf("Expected %d delays, got %v", len(exp), got)
+	}
+	for i := range exp {
+		if got[i] < exp[i]-reconnectJitter || got[i] > exp[i]+reconnectJitter {
+			t.Errorf("Expected delay %d to be %s, got %s", i, exp[i], got[i])
+		}
+	}
+}
+
+func TestReconnectDelay(t *testing.T) {
+	exp := []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second,
+		16 * time.Second, 32 * time.Second, time.Minute, time.Minute,
+	}
+	var got []time.Duration
+	for attempts := range exp {
+		got = append(got, reconnectDelay(attempts))
+	}
+	checkDelays(t, got, exp...)
+	checkDelays(t, []time.Duration{reconnectDelay(1000)}, time.Minute)
+}
+
+func TestMaxReconnectAttempts(t *testing.T) {
+	srv := httptest.NewServer(nil)
+	srv.Close()
+
+	oldHost := *wsHost
+	*wsHost = "ws" + strings.TrimPrefix(srv.URL, "http")
+	defer func() { *wsHost = oldHost }()
+
+	clk := new(fakeClock)
+	gaveUp := make(chan error, 1)
+	c, err := Dial("key", "secret", "XBTZAR",
+		WithMaxReconnectAttempts(3, func(c *Conn, err error) {
+			gaveUp <- err
+		}),
+		func(c *Conn) { c.clock = clk })
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	defer c.Close()
+
+	waitGiveUp := func() {
+		t.Helper()
+		select {
+		case err := <-gaveUp:
+			if err == nil {
+				t.Errorf("Expected the last connection error")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected to give up")
+		}
+		if !c.GaveUp() {
+			t.Errorf("Expected GaveUp to be true")
+		}
+	}
+
+	waitGiveUp()
+	checkDelays(t, clk.Delays(), 2*time.Second, 4*time.Second)
+
+	// No more attempts are made until restarted.
+	time.Sleep(50 * time.Millisecond)
+	if n := len(clk.Delays()); n != 2 {
+		t.Errorf("Expected no attempts after giving up, got %d delays", n)
+	}
+
+	if err := c.Restart(); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	waitGiveUp()
+	checkDelays(t, clk.Delays(),
+		2*time.Second, 4*time.Second, 2*time.Second, 4*time.Second)
+
+	c.Close()
+	if err := c.Restart(); err == nil {
+		t.Errorf("Expected error restarting a closed conn")
+	}
+}
+
+func TestMaxReconnectAttemptsSynced(t *testing.T) {
+	var connects int32
+	srv := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		var cred credentials
+		if err := websocket.JSON.Receive(ws, &cred); err != nil {
+			return
+		}
+		atomic.AddInt32(&connects, 1)
+		// Send the order book, then drop the connection.
+		_ = websocket.JSON.Send(ws, map[string]interface{}{
+			"sequence": "1",
+			"asks":     []interface{}{},
+			"bids":     []interface{}{},
+			"status":   "ACTIVE",
+		})
+	}))
+	defer srv.Close()
+
+	oldHost := *wsHost
+	*wsHost = "ws" + strings.TrimPrefix(srv.URL, "http")
+	defer func() { *wsHost = oldHost }()
+
+	c, err := Dial("key", "secret", "XBTZAR",
+		WithMaxReconnectAttempts(1, nil),
+		WithUpdateChannel(1, BackpressureBlock),
+		func(c *Conn) { c.clock = new(fakeClock) })
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	defer func() {
+		// Wait for the reconnect loop to stop before restoring wsHost.
+		c.Close()
+		for range c.Updates() {
+		}
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&connects) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected to keep reconnecting, got %d connects", connects)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if c.GaveUp() {
+		t.Errorf("Expected not to give up while connections are synced")
+	}
+}
+
+func TestTradeCallback(t *testing.T) {
+	var trades []Trade
+	var gaps [][2]luno.Sequence
+	c := &Conn{
+		pair:          "XBTZAR",
+		done:          make(chan struct{}),
+		tradeCallback: func(tr Trade) { trades = append(trades, tr) },
+		tradeGapCallback: func(from, to luno.Sequence) {
+			gaps = append(gaps, [2]luno.Sequence{from, to})
+		},
+	}
+	trade := Update{Sequence: 2, Timestamp: 1000, TradeUpdates: []*TradeUpdate{
+		{OrderID: "1", Base: decimal.NewFromFloat64(0.05, 2),
+			Counter: decimal.NewFromFloat64(6, 0)},
+		{OrderID: "2", Base: decimal.NewFromFloat64(0.1, 1),
+			Counter: decimal.NewFromFloat64(15, 0)},
+	}}
+
+	if err := c.receivedOrderBook(book()); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if err := c.receivedUpdate(trade); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	// A reconnect replays the update on top of an older book.
+	if err := c.receivedOrderBook(book()); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if err := c.receivedUpdate(trade); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	if len(trades) != 2 {
+		t.Fatalf("Expected 2 trades, got %+v", trades)
+	}
+	exp := []struct {
+		price int64
+		side  luno.Side
+	}{{120, luno.SideSell}, {150, luno.SideBuy}}
+	for i, tr := range trades {
+		if tr.Pair != "XBTZAR" || tr.Sequence != 2 || tr.Timestamp != time.Time(luno.TimeFromMillis(1000)) {
+			t.Errorf("Unexpected trade %+v", tr)
+		}
+		if tr.Price.Cmp(decimal.NewFromInt64(exp[i].price)) != 0 ||
+			tr.TakerSide != exp[i].side {
+			t.Errorf("Expected trade at %d taken by %s, got %+v",
+				exp[i].price, exp[i].side, tr)
+		}
+	}
+	if len(gaps) != 0 {
+		t.Errorf("Expected no gaps, got %v", gaps)
+	}
+
+	// Updates 3 to 5 are missed while reconnecting.
+	ob := book()
+	ob.Sequence = 5
+	if err := c.receivedOrderBook(ob); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(gaps) != 1 || gaps[0] != [2]luno.Sequence{3, 5} {
+		t.Errorf("Expected gap from 3 to 5, got %v", gaps)
+	}
+}
+
+func TestReconnectCallback(t *testing.T) {
+	srv := httptest.NewServer(nil)
+	srv.Close()
+
+	oldHost := *wsHost
+	*wsHost = "ws" + strings.TrimPrefix(srv.URL, "http")
+	defer func() { *wsHost = oldHost }()
+
+	type reconnect struct {
+		delay time.Duration
+		err   error
+	}
+	reconnects := make(chan reconnect, 10)
+	c, err := Dial("key", "secret", "XBTZAR",
+		WithReconnectCallback(func(c *Conn, delay time.Duration, err error) {
+			reconnects <- reconnect{delay, err}
+		}),
+		WithMaxReconnectAttempts(3, nil),
+		func(c *Conn) { c.clock = new(fakeClock) })
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	defer func() {
+		// Wait for the reconnect loop to give up before the test returns.
+		deadline := time.Now().Add(5 * time.Second)
+		for !c.GaveUp() && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		c.Close()
+	}()
+
+	var delays []time.Duration
+	for i := 0; i < 2; i++ {
+		select {
+		case r := <-reconnects:
+			delays = append(delays, r.delay)
+			if r.err == nil {
+				t.Errorf("Expected the connection error")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected reconnect")
+		}
+	}
+	checkDelays(t, delays, 2*time.Second, 4*time.Second)
+}
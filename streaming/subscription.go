@@ -0,0 +1,167 @@
+package streaming
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/luno/luno-go"
+)
+
+// ErrSubscriberLagged is returned by Subscription.Err when a subscription was
+// dropped because its buffer was full.
+var ErrSubscriberLagged = errors.New("streaming: subscriber lagged")
+
+// Event is delivered to subscribers. Exactly one of Book and Update is set.
+// A Book replaces any previous state of the order book, and is followed by
+// the updates to apply to it.
+type Event struct {
+	Book   *Book
+	Update *Update
+}
+
+// Book is an order book which can be kept up to date by applying the events
+// of a Subscription:
+//
+//	var book *streaming.Book
+//	for ev := range sub.Events() {
+//		if ev.Book != nil {
+//			book = ev.Book
+//			continue
+//		}
+//		if err := book.Apply(*ev.Update); err != nil {
+//			return err
+//		}
+//	}
+type Book struct {
+	// c holds the state of the book and is never connected.
+	c *Conn
+}
+
+// bookLocked returns a copy of the order book of c. It must be called with
+// c.mu held.
+func (c *Conn) bookLocked() *Book {
+	copyOrders := func(m map[string]order) map[string]order {
+		r := make(map[string]order, len(m))
+		for id, o := range m {
+			r[id] = o
+		}
+		return r
+	}
+	return &Book{c: &Conn{
+		pair:      c.pair,
+		seq:       c.seq,
+		bids:      copyOrders(c.bids),
+		asks:      copyOrders(c.asks),
+		status:    c.status,
+		timestamp: c.timestamp,
+		lastTrade: c.lastTrade,
+		done:      make(chan struct{}),
+	}}
+}
+
+// Apply applies u to the book. Updates which are older than the book are
+// ignored, and an error is returned if u is out of sequence.
+func (b *Book) Apply(u Update) error {
+	_, err := b.c.applyUpdate(u)
+	return err
+}
+
+// Sequence returns the sequence number of the last update applied.
+func (b *Book) Sequence() luno.Sequence {
+	return b.Snapshot().Sequence
+}
+
+// Snapshot returns the current state of the book.
+func (b *Book) Snapshot() Snapshot {
+	return b.c.Snapshot()
+}
+
+// OrderBook returns the current bids and asks of the book.
+func (b *Book) OrderBook() luno.OrderBook {
+	return b.Snapshot().OrderBook()
+}
+
+// Subscription receives the order book of a connection as a stream of events.
+type Subscription struct {
+	c  *Conn
+	ch chan Event
+
+	mu  sync.Mutex
+	err error
+}
+
+// Subscribe returns a subscription which first receives the current order
+// book, if the connection has one, followed by every update applied after it.
+// A new snapshot is delivered whenever the connection resynchronises, so
+// subscribers which attach at different times see the same book.
+//
+// The connection never waits for a subscriber. If a subscriber falls more
+// than buffer events behind, its subscription is closed and Err returns
+// ErrSubscriberLagged; it can subscribe again to resynchronise.
+func (c *Conn) Subscribe(buffer int) *Subscription {
+	if buffer < 1 {
+		buffer = 1
+	}
+	s := &Subscription{c: c, ch: make(chan Event, buffer)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		close(s.ch)
+		return s
+	}
+	if c.seq != 0 {
+		s.ch <- Event{Book: c.bookLocked()}
+	}
+	if c.subs == nil {
+		c.subs = make(map[*Subscription]bool)
+	}
+	c.subs[s] = true
+	return s
+}
+
+// Events returns the channel on which events are delivered. It is closed
+// when the subscription or its connection is closed.
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Err returns ErrSubscriberLagged if the subscription was dropped because the
+// subscriber fell behind, or nil otherwise.
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close stops delivering events to the subscription.
+func (s *Subscription) Close() {
+	s.c.mu.Lock()
+	defer s.c.mu.Unlock()
+	s.c.unsubscribeLocked(s, nil)
+}
+
+// publishLocked delivers an event made by newEvent to each subscriber,
+// dropping those which are full. It must be called with c.mu held.
+func (c *Conn) publishLocked(newEvent func() Event) {
+	for s := range c.subs {
+		select {
+		case s.ch <- newEvent():
+		default:
+			c.unsubscribeLocked(s, ErrSubscriberLagged)
+		}
+	}
+}
+
+// unsubscribeLocked removes s, recording err as the reason. It must be
+// called with c.mu held.
+func (c *Conn) unsubscribeLocked(s *Subscription, err error) {
+	if !c.subs[s] {
+		return
+	}
+	delete(c.subs, s)
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+	close(s.ch)
+}
@@ -0,0 +1,182 @@
+package streaming
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func newSubscribedConn(t *testing.T) *Conn {
+	c := &Conn{done: make(chan struct{})}
+	if err := c.receivedOrderBook(book()); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	return c
+}
+
+// updates returns a mix of creates, trades and deletes with sequence numbers
+// from seq.
+func updates(seq luno.Sequence) []Update {
+	id := fmt.Sprint("new", seq)
+	return []Update{
+		{Sequence: seq, CreateUpdate: &CreateUpdate{
+			OrderID: id, Type: "BID",
+			Price:  decimal.NewFromFloat64(105, 0),
+			Volume: decimal.NewFromFloat64(1, 0),
+		}},
+		{Sequence: seq + 1, TradeUpdates: []*TradeUpdate{{
+			OrderID: id,
+			Base:    decimal.NewFromFloat64(0.5, 1),
+			Counter: decimal.NewFromFloat64(52.5, 1),
+		}}},
+		{Sequence: seq + 2, DeleteUpdate: &DeleteUpdate{OrderID: id}},
+		{Sequence: seq + 3, CreateUpdate: &CreateUpdate{
+			OrderID: id + "ask", Type: "ASK",
+			Price:  decimal.NewFromFloat64(160, 0),
+			Volume: decimal.NewFromFloat64(2, 0),
+		}},
+	}
+}
+
+// replay builds a book from the events of s, which must have been closed.
+func replay(t *testing.T, s *Subscription) *Book {
+	var b *Book
+	for ev := range s.Events() {
+		if ev.Book != nil {
+			b = ev.Book
+			continue
+		}
+		if b == nil {
+			t.Fatalf("Expected a book before update %d", ev.Update.Sequence)
+		}
+		if err := b.Apply(*ev.Update); err != nil {
+			t.Fatalf("Expected update %d to apply, got %v", ev.Update.Sequence, err)
+		}
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return b
+}
+
+func TestSubscribersConverge(t *testing.T) {
+	c := newSubscribedConn(t)
+	apply := func(us []Update) {
+		for _, u := range us {
+			if err := c.receivedUpdate(u); err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+		}
+	}
+
+	early := c.Subscribe(100)
+	apply(updates(2))
+	middle := c.Subscribe(100)
+	apply(updates(6))
+	late := c.Subscribe(100)
+	apply(updates(10))
+
+	exp := c.Snapshot()
+	if exp.Sequence != 13 {
+		t.Fatalf("Expected sequence 13, got %d", exp.Sequence)
+	}
+	c.Close()
+
+	for name, s := range map[string]*Subscription{
+		"early": early, "middle": middle, "late": late,
+	} {
+		b := replay(t, s)
+		if !reflect.DeepEqual(b.Snapshot(), exp) {
+			t.Errorf("Expected %s subscriber to converge to %+v, got %+v",
+				name, exp, b.Snapshot())
+		}
+	}
+}
+
+func TestSubscriberResync(t *testing.T) {
+	c := newSubscribedConn(t)
+	s := c.Subscribe(100)
+	for _, u := range updates(2) {
+		if err := c.receivedUpdate(u); err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+	}
+
+	// A reconnection delivers a new book, which replaces the old one.
+	ob := book()
+	ob.Sequence = 100
+	ob.Bids = ob.Bids[:1]
+	if err := c.receivedOrderBook(ob); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	exp := c.Snapshot()
+	c.Close()
+
+	if b := replay(t, s); !reflect.DeepEqual(b.Snapshot(), exp) {
+		t.Errorf("Expected %+v, got %+v", exp, b.Snapshot())
+	}
+}
+
+func TestSubscriberLagged(t *testing.T) {
+	c := newSubscribedConn(t)
+	slow := c.Subscribe(2)
+	fast := c.Subscribe(100)
+
+	// The slow subscriber doesn't read, which mustn't block the connection.
+	for _, u := range updates(2) {
+		if err := c.receivedUpdate(u); err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+	}
+	if slow.Err() != ErrSubscriberLagged {
+		t.Errorf("Expected ErrSubscriberLagged, got %v", slow.Err())
+	}
+	var n int
+	for range slow.Events() {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("Expected the 2 buffered events, got %d", n)
+	}
+
+	exp := c.Snapshot()
+	fast.Close()
+	if b := replay(t, fast); !reflect.DeepEqual(b.Snapshot(), exp) {
+		t.Errorf("Expected %+v, got %+v", exp, b.Snapshot())
+	}
+
+	// Closing a subscription twice, or after its connection, is harmless.
+	fast.Close()
+	c.Close()
+	fast.Close()
+	if _, ok := <-c.Subscribe(1).Events(); ok {
+		t.Errorf("Expected subscription to a closed connection to be closed")
+	}
+}
+
+func TestSubscriberBookTimestamp(t *testing.T) {
+	c := &Conn{pair: "XBTZAR", done: make(chan struct{})}
+	ob := book()
+	ob.Timestamp = 1600000000000
+	if err := c.receivedOrderBook(ob); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	s := c.Subscribe(100)
+	exp := c.Snapshot()
+	c.Close()
+
+	b := replay(t, s)
+	if ts := b.OrderBook().Timestamp; !ts.Equal(time.Time(luno.TimeFromMillis(ob.Timestamp))) {
+		t.Errorf("Expected timestamp %s, got %s", time.Time(luno.TimeFromMillis(ob.Timestamp)), ts)
+	}
+	if !reflect.DeepEqual(b.Snapshot(), exp) {
+		t.Errorf("Expected %+v, got %+v", exp, b.Snapshot())
+	}
+	if b.c.pair != "XBTZAR" {
+		t.Errorf("Expected pair XBTZAR, got %q", b.c.pair)
+	}
+}
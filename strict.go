@@ -0,0 +1,281 @@
+package luno
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// SetStrictDecoding enables or disables strict decoding of responses. In
+// strict mode a response which has fields missing from the response type, or
+// which fails the checks of its type, e.g. a negative price or an unknown
+// order state, results in a *DecodeError instead of a partially decoded
+// response. It is disabled by default, since Luno may add fields to
+// responses at any time.
+//
+// Strict mode always decodes with encoding/json, ignoring any codec set with
+// SetJSONCodec.
+func (cl *Client) SetStrictDecoding(enabled bool) {
+	cl.strictDecoding = enabled
+}
+
+// DecodeError is returned in strict decoding mode when a response doesn't
+// match its type, see SetStrictDecoding.
+type DecodeError struct {
+	// Method and Path of the request, e.g. "GET" and "/api/1/ticker".
+	Method string
+	Path   string
+
+	// Err describes the mismatch.
+	Err error
+
+	// body is the response body, truncated to maxRawErrorBytes.
+	body string
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("luno: invalid response to %s %s: %v", e.Method, e.Path, e.Err)
+}
+
+// Unwrap returns the error describing the mismatch.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Raw returns the response body, truncated to 4 KiB.
+func (e *DecodeError) Raw() []byte {
+	return []byte(e.body)
+}
+
+// decodeResponse decodes the body of a successful response to c into c.res,
+// strictly if strict decoding is enabled.
+func (cl *Client) decodeResponse(c *call, b []byte) error {
+	if !cl.strictDecoding {
+		return cl.decodeJSON(b, c.res)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(c.res)
+	if v, ok := c.res.(validator); ok && err == nil {
+		err = v.validate()
+	}
+	if err == nil {
+		return nil
+	}
+	path := c.url
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	if len(b) > maxRawErrorBytes {
+		b = b[:maxRawErrorBytes]
+	}
+	return &DecodeError{Method: c.method, Path: path, Err: err, body: string(b)}
+}
+
+// validator is implemented by response types which can check their fields
+// after decoding in strict mode.
+type validator interface {
+	validate() error
+}
+
+// checker collects the problems found in a response.
+type checker struct {
+	prefix   string
+	problems []string
+}
+
+func (c *checker) add(field, problem string) {
+	c.problems = append(c.problems, c.prefix+field+" "+problem)
+}
+
+func (c *checker) required(field, value string) {
+	if value == "" {
+		c.add(field, "is missing")
+	}
+}
+
+func (c *checker) nonNegative(field string, d decimal.Decimal) {
+	if d.Sign() < 0 {
+		c.add(field, "is negative: "+d.String())
+	}
+}
+
+// enum checks that value, which isn't empty, is valid.
+func (c *checker) enum(field, value string, valid bool) {
+	if value != "" && !valid {
+		c.add(field, fmt.Sprintf("has unknown value %q", value))
+	}
+}
+
+// at checks element i of the list field with check.
+func (c *checker) at(field string, i int, check func(*checker)) {
+	prefix := c.prefix
+	c.prefix = fmt.Sprintf("%s%s[%d].", prefix, field, i)
+	check(c)
+	c.prefix = prefix
+}
+
+func (c *checker) err() error {
+	if len(c.problems) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(c.problems, "; "))
+}
+
+func checkOrderState(c *checker, s OrderState) {
+	c.required("state", string(s))
+	c.enum("state", string(s), s.IsValid())
+}
+
+func checkOrderType(c *checker, t OrderType) {
+	c.enum("type", string(t), t.IsValid())
+}
+
+func (o Order) check(c *checker) {
+	c.required("order_id", o.OrderId)
+	checkOrderState(c, o.State)
+	checkOrderType(c, o.Type)
+	c.nonNegative("base", o.Base)
+	c.nonNegative("counter", o.Counter)
+	c.nonNegative("fee_base", o.FeeBase)
+	c.nonNegative("fee_counter", o.FeeCounter)
+	c.nonNegative("limit_price", o.LimitPrice)
+	c.nonNegative("limit_volume", o.LimitVolume)
+}
+
+func (t Ticker) check(c *checker) {
+	c.required("pair", t.Pair)
+	c.nonNegative("ask", t.Ask)
+	c.nonNegative("bid", t.Bid)
+	c.nonNegative("last_trade", t.LastTrade)
+	c.nonNegative("rolling_24_hour_volume", t.Rolling24HourVolume)
+	c.enum("status", string(t.Status), t.Status.IsValid())
+}
+
+func (t Trade) check(c *checker) {
+	checkOrderType(c, t.Type)
+	c.nonNegative("price", t.Price)
+	c.nonNegative("volume", t.Volume)
+	c.nonNegative("base", t.Base)
+	c.nonNegative("counter", t.Counter)
+	c.nonNegative("fee_base", t.FeeBase)
+	c.nonNegative("fee_counter", t.FeeCounter)
+}
+
+func (e OrderBookEntry) check(c *checker) {
+	c.nonNegative("price", e.Price)
+	c.nonNegative("volume", e.Volume)
+}
+
+func (b AccountBalance) check(c *checker) {
+	c.required("account_id", b.AccountId)
+	c.required("asset", b.Asset)
+	c.nonNegative("reserved", b.Reserved)
+	c.nonNegative("unconfirmed", b.Unconfirmed)
+}
+
+func (cd Candle) check(c *checker) {
+	c.nonNegative("open", cd.Open)
+	c.nonNegative("high", cd.High)
+	c.nonNegative("low", cd.Low)
+	c.nonNegative("close", cd.Close)
+	c.nonNegative("volume", cd.Volume)
+}
+
+func checkBook(c *checker, asks, bids []OrderBookEntry) {
+	for i, e := range asks {
+		c.at("asks", i, e.check)
+	}
+	for i, e := range bids {
+		c.at("bids", i, e.check)
+	}
+}
+
+func (r *GetOrderResponse) validate() error {
+	var c checker
+	Order(*r).check(&c)
+	return c.err()
+}
+
+func (r *ListOrdersResponse) validate() error {
+	var c checker
+	for i, o := range r.Orders {
+		c.at("orders", i, o.check)
+	}
+	return c.err()
+}
+
+func (r *GetTickerResponse) validate() error {
+	var c checker
+	Ticker(*r).check(&c)
+	return c.err()
+}
+
+func (r *GetTickersResponse) validate() error {
+	var c checker
+	for i, t := range r.Tickers {
+		c.at("tickers", i, t.check)
+	}
+	return c.err()
+}
+
+func (r *ListTradesResponse) validate() error {
+	var c checker
+	for i, t := range r.Trades {
+		c.at("trades", i, t.check)
+	}
+	return c.err()
+}
+
+func (r *ListUserTradesResponse) validate() error {
+	var c checker
+	for i, t := range r.Trades {
+		c.at("trades", i, t.check)
+	}
+	return c.err()
+}
+
+func (r *GetOrderBookResponse) validate() error {
+	var c checker
+	checkBook(&c, r.Asks, r.Bids)
+	return c.err()
+}
+
+func (r *GetOrderBookFullResponse) validate() error {
+	var c checker
+	checkBook(&c, r.Asks, r.Bids)
+	return c.err()
+}
+
+func (r *GetBalancesResponse) validate() error {
+	var c checker
+	for i, b := range r.Balance {
+		c.at("balance", i, b.check)
+	}
+	return c.err()
+}
+
+func (r *GetCandlesResponse) validate() error {
+	var c checker
+	for i, cd := range r.Candles {
+		c.at("candles", i, cd.check)
+	}
+	return c.err()
+}
+
+func (r *PostLimitOrderResponse) validate() error {
+	var c checker
+	c.required("order_id", r.OrderId)
+	return c.err()
+}
+
+func (r *PostMarketOrderResponse) validate() error {
+	var c checker
+	c.required("order_id", r.OrderId)
+	return c.err()
+}
@@ -0,0 +1,139 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+func strictClient(body string) (*luno.Client, func()) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetStrictDecoding(true)
+	return cl, srv.Close
+}
+
+func TestStrictDecoding(t *testing.T) {
+	testCases := []struct {
+		name   string
+		body   string
+		expErr string
+	}{
+		{
+			name: "valid",
+			body: `{"pair":"XBTZAR","ask":"100","bid":"99","last_trade":"99.5","status":"ACTIVE"}`,
+		},
+		{
+			name:   "unknown field",
+			body:   `{"pair":"XBTZAR","ask":"100","spread":"1"}`,
+			expErr: `json: unknown field "spread"`,
+		},
+		{
+			name:   "missing pair",
+			body:   `{"ask":"100"}`,
+			expErr: "pair is missing",
+		},
+		{
+			name:   "negative price",
+			body:   `{"pair":"XBTZAR","bid":"-1"}`,
+			expErr: "bid is negative: -1",
+		},
+		{
+			name:   "unknown status",
+			body:   `{"pair":"XBTZAR","status":"HALTED"}`,
+			expErr: `status has unknown value "HALTED"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl, stop := strictClient(tc.body)
+			defer stop()
+
+			_, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+			if tc.expErr == "" {
+				if err != nil {
+					t.Errorf("Expected success, got %v", err)
+				}
+				return
+			}
+			var decErr *luno.DecodeError
+			if !errors.As(err, &decErr) {
+				t.Fatalf("Expected DecodeError, got %v", err)
+			}
+			if !strings.Contains(decErr.Err.Error(), tc.expErr) {
+				t.Errorf("Expected %q, got %q", tc.expErr, decErr.Err)
+			}
+			if decErr.Method != "GET" || decErr.Path != "/api/1/ticker" {
+				t.Errorf("Expected GET /api/1/ticker, got %s %s", decErr.Method, decErr.Path)
+			}
+			if string(decErr.Raw()) != tc.body {
+				t.Errorf("Expected raw body %s, got %s", tc.body, decErr.Raw())
+			}
+		})
+	}
+}
+
+func TestStrictDecodingLists(t *testing.T) {
+	cl, stop := strictClient(`{"orders":[` +
+		`{"order_id":"BX1","state":"PENDING","type":"BID"},` +
+		`{"order_id":"BX2","state":"OPEN","type":"BID","base":"-0.1"}]}`)
+	defer stop()
+
+	_, err := cl.ListOrders(context.Background(), &luno.ListOrdersRequest{})
+	exp := `luno: invalid response to GET /api/1/listorders: ` +
+		`orders[1].state has unknown value "OPEN"; orders[1].base is negative: -0.1`
+	if err == nil || err.Error() != exp {
+		t.Errorf("Expected %q, got %v", exp, err)
+	}
+}
+
+func TestStrictDecodingDisabled(t *testing.T) {
+	cl, stop := strictClient(`{"pair":"XBTZAR","spread":"1","status":"HALTED"}`)
+	defer stop()
+	cl.SetStrictDecoding(false)
+
+	res, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if res.Status != "HALTED" {
+		t.Errorf("Expected status HALTED, got %s", res.Status)
+	}
+}
+
+func TestStrictDecodingDedup(t *testing.T) {
+	testCases := []struct {
+		name   string
+		body   string
+		expErr string
+	}{
+		{name: "unknown field", body: `{"pair":"XBTZAR","spread":"1"}`,
+			expErr: `json: unknown field "spread"`},
+		{name: "invalid", body: `{"pair":"XBTZAR","status":"HALTED"}`,
+			expErr: `status has unknown value "HALTED"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl, stop := strictClient(tc.body)
+			defer stop()
+			cl.SetRequestDedup(true)
+
+			_, err := cl.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+			var decErr *luno.DecodeError
+			if !errors.As(err, &decErr) {
+				t.Fatalf("Expected DecodeError, got %v", err)
+			}
+			if !strings.Contains(decErr.Err.Error(), tc.expErr) {
+				t.Errorf("Expected %q, got %q", tc.expErr, decErr.Err)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package luno
+
+import "github.com/luno/luno-go/decimal"
+
+// SumBase returns the total base amount of trades.
+func SumBase(trades []Trade) decimal.Decimal {
+	return sumTrades(trades, func(t *Trade) decimal.Decimal { return t.Base })
+}
+
+// SumCounter returns the total counter amount of trades.
+func SumCounter(trades []Trade) decimal.Decimal {
+	return sumTrades(trades, func(t *Trade) decimal.Decimal { return t.Counter })
+}
+
+// SumFeeBase returns the total fees of trades charged in the base currency.
+func SumFeeBase(trades []Trade) decimal.Decimal {
+	return sumTrades(trades, func(t *Trade) decimal.Decimal { return t.FeeBase })
+}
+
+// SumFeeCounter returns the total fees of trades charged in the counter
+// currency.
+func SumFeeCounter(trades []Trade) decimal.Decimal {
+	return sumTrades(trades, func(t *Trade) decimal.Decimal { return t.FeeCounter })
+}
+
+// SumBalanceDelta returns the total change in balance caused by txs.
+func SumBalanceDelta(txs []Transaction) decimal.Decimal {
+	sum := decimal.Zero()
+	for i := range txs {
+		sum = sum.Add(txs[i].BalanceDelta)
+	}
+	return sum
+}
+
+// SumAvailableDelta returns the total change in available balance caused by
+// txs.
+func SumAvailableDelta(txs []Transaction) decimal.Decimal {
+	sum := decimal.Zero()
+	for i := range txs {
+		sum = sum.Add(txs[i].AvailableDelta)
+	}
+	return sum
+}
+
+func sumTrades(trades []Trade, amount func(*Trade) decimal.Decimal) decimal.Decimal {
+	sum := decimal.Zero()
+	for i := range trades {
+		sum = sum.Add(amount(&trades[i]))
+	}
+	return sum
+}
@@ -0,0 +1,65 @@
+package luno_test
+
+import (
+	"testing"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func TestSumTrades(t *testing.T) {
+	// Summing 0.1 ten thousand times as a float64 gives 1000.0000000001588.
+	tenth := decimal.NewFromFloat64(0.1, 1)
+	trades := make([]luno.Trade, 10000)
+	for i := range trades {
+		trades[i] = luno.Trade{
+			Base:       tenth,
+			Counter:    decimal.NewFromFloat64(0.3, 1),
+			FeeBase:    decimal.NewFromFloat64(0.00000001, 8),
+			FeeCounter: decimal.NewFromFloat64(0.01, 2),
+		}
+	}
+
+	testCases := []struct {
+		name string
+		sum  func([]luno.Trade) decimal.Decimal
+		exp  string
+	}{
+		{"base", luno.SumBase, "1000.0"},
+		{"counter", luno.SumCounter, "3000.0"},
+		{"fee base", luno.SumFeeBase, "0.00010000"},
+		{"fee counter", luno.SumFeeCounter, "100.00"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if act := tc.sum(trades).String(); act != tc.exp {
+				t.Errorf("Expected %s, got %s", tc.exp, act)
+			}
+			if act := tc.sum(nil).Sign(); act != 0 {
+				t.Errorf("Expected zero sum of no trades, got sign %d", act)
+			}
+		})
+	}
+}
+
+func TestSumTransactions(t *testing.T) {
+	var txs []luno.Transaction
+	for i := 0; i < 1000; i++ {
+		txs = append(txs,
+			luno.Transaction{
+				BalanceDelta:   decimal.NewFromFloat64(0.1, 1),
+				AvailableDelta: decimal.NewFromFloat64(0.1, 1),
+			},
+			luno.Transaction{
+				BalanceDelta:   decimal.NewFromFloat64(-0.07, 2),
+				AvailableDelta: decimal.NewFromFloat64(-0.01, 2),
+			},
+		)
+	}
+	if act := luno.SumBalanceDelta(txs).String(); act != "30.00" {
+		t.Errorf("Expected balance delta 30.00, got %s", act)
+	}
+	if act := luno.SumAvailableDelta(txs).String(); act != "90.00" {
+		t.Errorf("Expected available delta 90.00, got %s", act)
+	}
+}
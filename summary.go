@@ -0,0 +1,142 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// maxSummaryPages is the maximum number of pages of transactions
+// AccountSummary fetches.
+const maxSummaryPages = 100
+
+// ErrSummaryTruncated is returned by AccountSummary if the account has too
+// many transactions to summarise.
+var ErrSummaryTruncated = errors.New("luno: account summary truncated")
+
+// AccountSummary holds the totals of the transactions of an account over a
+// date range. Accounts hold a single currency, so all totals are in Currency.
+type AccountSummary struct {
+	AccountID string
+	Currency  string
+	From, To  time.Time
+
+	// Number of transactions in the range.
+	Count int
+
+	// Total received and sent by transfers, both positive.
+	Deposits    decimal.Decimal
+	Withdrawals decimal.Decimal
+
+	// Total fees paid, positive.
+	Fees decimal.Decimal
+
+	// Net change in balance caused by trades and other exchanges.
+	Trades decimal.Decimal
+
+	// Total interest received.
+	Interest decimal.Decimal
+
+	// Net change in balance caused by transactions of other kinds.
+	Other decimal.Decimal
+}
+
+// Net returns the net change in balance over the range.
+func (s *AccountSummary) Net() decimal.Decimal {
+	return s.Deposits.Sub(s.Withdrawals).Sub(s.Fees).Add(s.Trades).
+		Add(s.Interest).Add(s.Other)
+}
+
+func (s *AccountSummary) add(tx Transaction) {
+	s.Count++
+	if s.Currency == "" {
+		s.Currency = tx.Currency
+	}
+
+	delta := tx.BalanceDelta
+	switch tx.Kind {
+	case KindTransfer:
+		if delta.Sign() >= 0 {
+			s.Deposits = s.Deposits.Add(delta)
+		} else {
+			s.Withdrawals = s.Withdrawals.Sub(delta)
+		}
+	case KindFee:
+		s.Fees = s.Fees.Sub(delta)
+	case KindExchange:
+		s.Trades = s.Trades.Add(delta)
+	case KindInterest:
+		s.Interest = s.Interest.Add(delta)
+	default:
+		s.Other = s.Other.Add(delta)
+	}
+}
+
+// AccountSummary returns the totals of the transactions of an account with
+// timestamps in the range [from, to), e.g. for monthly statements. Both from
+// and to must be set and from must be before to.
+//
+// Transactions are paged through from the first row of the account, so
+// ErrSummaryTruncated is returned if the range ends after more transactions
+// than can be fetched in 100 pages.
+func (cl *Client) AccountSummary(ctx context.Context, accountID string,
+	from, to time.Time) (*AccountSummary, error) {
+
+	id, err := strconv.ParseInt(accountID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("luno: invalid account id %q: %w", accountID, err)
+	}
+	if from.IsZero() || to.IsZero() || !from.Before(to) {
+		return nil, fmt.Errorf("luno: invalid date range %s to %s", from, to)
+	}
+
+	s := &AccountSummary{
+		AccountID:   accountID,
+		From:        from,
+		To:          to,
+		Deposits:    decimal.Zero(),
+		Withdrawals: decimal.Zero(),
+		Fees:        decimal.Zero(),
+		Trades:      decimal.Zero(),
+		Interest:    decimal.Zero(),
+		Other:       decimal.Zero(),
+	}
+	minRow := int64(1)
+	for page := 0; page < maxSummaryPages; page++ {
+		maxRow := minRow + ledgerPageSize
+		res, err := cl.ListTransactions(ctx, &ListTransactionsRequest{
+			Id:     id,
+			MinRow: minRow,
+			MaxRow: maxRow,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		txs := res.Transactions
+		sort.Slice(txs, func(i, j int) bool {
+			return txs[i].RowIndex < txs[j].RowIndex
+		})
+		for _, tx := range txs {
+			ts := time.Time(tx.Timestamp)
+			if !ts.Before(to) {
+				return s, nil
+			}
+			if !ts.Before(from) {
+				s.add(tx)
+			}
+		}
+
+		if !hasMoreTransactions(res, minRow, maxRow) {
+			return s, nil
+		}
+		minRow = maxRow
+	}
+	return nil, fmt.Errorf("%w: more than %d transactions before %s",
+		ErrSummaryTruncated, maxSummaryPages*ledgerPageSize, to)
+}
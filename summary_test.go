@@ -0,0 +1,116 @@
+package luno_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+)
+
+// newSummaryServer serves numTxs transactions for account 123 starting at
+// start, one per hour and cycling through deposits, fees, trades and
+// withdrawals.
+func newSummaryServer(start time.Time, numTxs int64) *httptest.Server {
+	kinds := []struct {
+		kind  string
+		delta string
+	}{
+		{"TRANSFER", "10"},
+		{"FEE", "-0.1"},
+		{"EXCHANGE", "-2.5"},
+		{"TRANSFER", "-1"},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		min, _ := strconv.ParseInt(r.FormValue("min_row"), 10, 64)
+		max, _ := strconv.ParseInt(r.FormValue("max_row"), 10, 64)
+		txs := []map[string]interface{}{}
+		for row := min; row < max && row <= numTxs; row++ {
+			k := kinds[(row-1)%int64(len(kinds))]
+			ts := start.Add(time.Duration(row-1) * time.Hour)
+			txs = append(txs, map[string]interface{}{
+				"row_index":     row,
+				"timestamp":     ts.UnixNano() / 1e6,
+				"currency":      "ZAR",
+				"kind":          k.kind,
+				"balance_delta": k.delta,
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"transactions": txs})
+	}))
+}
+
+func TestAccountSummary(t *testing.T) {
+	// Transactions from the start of January until well into March.
+	jan := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	srv := newSummaryServer(jan, 24*70)
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	// February has 28*24 transactions, a quarter of each kind.
+	feb := jan.AddDate(0, 1, 0)
+	s, err := cl.AccountSummary(context.Background(), "123",
+		feb, feb.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if s.Count != 28*24 || s.Currency != "ZAR" {
+		t.Errorf("Expected 672 ZAR transactions, got %d %s", s.Count, s.Currency)
+	}
+	totals := []struct {
+		name string
+		got  string
+		exp  string
+	}{
+		{"deposits", s.Deposits.String(), "1680"},
+		{"withdrawals", s.Withdrawals.String(), "168"},
+		{"fees", s.Fees.String(), "16.8"},
+		{"trades", s.Trades.String(), "-420.0"},
+		{"interest", s.Interest.String(), "0"},
+		{"net", s.Net().String(), "1075.2"},
+	}
+	for _, tc := range totals {
+		if tc.got != tc.exp {
+			t.Errorf("Expected %s %s, got %s", tc.name, tc.exp, tc.got)
+		}
+	}
+}
+
+func TestAccountSummaryInvalidRange(t *testing.T) {
+	cl := luno.NewClient()
+	now := time.Now()
+	ranges := [][2]time.Time{
+		{{}, now},
+		{now, {}},
+		{now, now},
+		{now, now.Add(-time.Hour)},
+	}
+	for _, r := range ranges {
+		if _, err := cl.AccountSummary(context.Background(), "123",
+			r[0], r[1]); err == nil {
+			t.Errorf("Expected error for range %s to %s", r[0], r[1])
+		}
+	}
+}
+
+func TestAccountSummaryTruncated(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	srv := newSummaryServer(start, 1000000)
+	defer srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+
+	_, err := cl.AccountSummary(context.Background(), "123",
+		start, start.AddDate(100, 0, 0))
+	if !errors.Is(err, luno.ErrSummaryTruncated) {
+		t.Errorf("Expected ErrSummaryTruncated, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package luno
+
+import (
+	"context"
+	"time"
+)
+
+// ThrottleReason is the cause of a delay reported by the Throttled hook.
+type ThrottleReason string
+
+const (
+	// ThrottleRateLimiter means that the request was held back by the rate
+	// limiter configured with SetRateLimit.
+	ThrottleRateLimiter ThrottleReason = "rate_limiter"
+
+	// ThrottleServer means that the server responded with 429 Too Many
+	// Requests and the request is retried after a delay.
+	ThrottleServer ThrottleReason = "server"
+)
+
+// ThrottleInfo describes a delay of an API request due to rate limiting.
+type ThrottleInfo struct {
+	RequestInfo
+
+	Reason ThrottleReason
+
+	// Wait is how long the request was delayed by the rate limiter, or how
+	// long it will be delayed before being retried after a 429 response.
+	Wait time.Duration
+}
+
+// reportThrottle reports info to the Throttled hook, the logger, the metrics
+// collector if it implements ThrottleCollector and the call stats of ctx, if
+// any.
+func (cl *Client) reportThrottle(ctx context.Context, info ThrottleInfo) {
+	cl.hooks.throttled(ctx, info)
+	if cl.logger != nil {
+		cl.logger.logThrottle(ctx, info)
+	}
+	if tc, ok := cl.metrics.(ThrottleCollector); ok {
+		tc.ObserveThrottle(info)
+	}
+	if stats := callStatsFromContext(ctx); stats != nil {
+		stats.Throttles = append(stats.Throttles, info)
+	}
+}
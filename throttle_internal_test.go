@@ -0,0 +1,122 @@
+package luno
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type throttleCollector struct {
+	infos []ThrottleInfo
+}
+
+func (c *throttleCollector) ObserveRequest(ResponseInfo) {}
+
+func (c *throttleCollector) ObserveThrottle(info ThrottleInfo) {
+	c.infos = append(c.infos, info)
+}
+
+func TestThrottleRateLimiter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	clk := newFakeClock()
+	cl := NewClient()
+	cl.clock = clk
+	cl.SetBaseURL(srv.URL)
+	cl.SetRateLimit(60, 1)
+
+	var hooked []ThrottleInfo
+	cl.SetHooks(Hooks{Throttled: func(ctx context.Context, info ThrottleInfo) {
+		hooked = append(hooked, info)
+	}})
+	var col throttleCollector
+	cl.SetMetricsCollector(&col)
+
+	var res interface{}
+	if err := cl.do(context.Background(), "GET", "/a", nil, &res, false); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(hooked) != 0 {
+		t.Fatalf("Expected no throttling within burst, got %v", hooked)
+	}
+
+	var stats CallStats
+	done := make(chan error)
+	go func() {
+		done <- cl.do(WithCallStats(context.Background(), &stats), "GET", "/b", nil, &res, false)
+	}()
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	if len(hooked) != 1 {
+		t.Fatalf("Expected 1 throttle, got %d", len(hooked))
+	}
+	info := hooked[0]
+	if info.Reason != ThrottleRateLimiter || info.Wait != time.Second || info.Path != "/b" {
+		t.Errorf("Expected 1s rate limiter throttle of /b, got %s %s of %s",
+			info.Wait, info.Reason, info.Path)
+	}
+	if len(col.infos) != 1 || col.infos[0] != info {
+		t.Errorf("Expected collector to observe %+v, got %+v", info, col.infos)
+	}
+	if len(stats.Throttles) != 1 || stats.Throttles[0] != info {
+		t.Errorf("Expected call stats to record %+v, got %+v", info, stats.Throttles)
+	}
+}
+
+func TestThrottleServer(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	clk := newFakeClock()
+	cl := NewClient()
+	cl.clock = clk
+	cl.SetBaseURL(srv.URL)
+	cl.SetMaxRetries(1)
+	cl.SetRetryBackoff(ConstantBackoff(2 * time.Second))
+
+	var hooked []ThrottleInfo
+	cl.SetHooks(Hooks{Throttled: func(ctx context.Context, info ThrottleInfo) {
+		hooked = append(hooked, info)
+	}})
+
+	var stats CallStats
+	done := make(chan error)
+	go func() {
+		var res interface{}
+		done <- cl.do(WithCallStats(context.Background(), &stats), "POST", "/", nil, &res, false)
+	}()
+	clk.BlockUntil(1)
+	clk.Advance(2 * time.Second)
+	if err := <-done; err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	if len(hooked) != 1 {
+		t.Fatalf("Expected 1 throttle, got %d", len(hooked))
+	}
+	info := hooked[0]
+	if info.Reason != ThrottleServer || info.Wait != 2*time.Second || info.Attempt != 0 {
+		t.Errorf("Expected 2s server throttle after attempt 0, got %s %s after attempt %d",
+			info.Wait, info.Reason, info.Attempt)
+	}
+	if len(stats.Throttles) != 1 || stats.Throttles[0].Reason != ThrottleServer {
+		t.Errorf("Expected call stats to record the server throttle, got %+v", stats.Throttles)
+	}
+}
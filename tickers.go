@@ -0,0 +1,149 @@
+package luno
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// tickerCache holds a snapshot of all tickers from GetTickers, keyed by pair.
+type tickerCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	tickers map[string]Ticker
+	fetched time.Time
+}
+
+// SetTickerCache enables serving GetTicker from a snapshot of every ticker,
+// which is refreshed with a single GetTickers request once it is older than
+// ttl. This coalesces frequent polls for several pairs into one request per
+// ttl. Pairs missing from the snapshot, and all pairs while the snapshot can't
+// be fetched, are requested directly. A zero ttl disables the cache, which is
+// the default.
+func (cl *Client) SetTickerCache(ttl time.Duration) {
+	c := &cl.tickerCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+	c.tickers = nil
+}
+
+// cachedTicker returns the ticker for pair from the snapshot, refreshing it
+// if needed. It returns false if the ticker cache is disabled or the ticker
+// isn't available.
+func (cl *Client) cachedTicker(ctx context.Context, pair string) (*GetTickerResponse, bool) {
+	c := &cl.tickerCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ttl <= 0 {
+		return nil, false
+	}
+	if cl.normalizePairs {
+		p, err := NormalizePair(pair)
+		if err != nil {
+			return nil, false
+		}
+		pair = p
+	}
+
+	if c.tickers == nil || cl.clock.Now().Sub(c.fetched) > c.ttl {
+		res, err := cl.GetTickers(ctx, &GetTickersRequest{})
+		if err != nil {
+			return nil, false
+		}
+		c.tickers = make(map[string]Ticker, len(res.Tickers))
+		for _, t := range res.Tickers {
+			c.tickers[t.Pair] = t
+		}
+		c.fetched = cl.clock.Now()
+	}
+
+	t, ok := c.tickers[pair]
+	if !ok {
+		return nil, false
+	}
+	res := GetTickerResponse(t)
+	return &res, true
+}
+
+// TickerPoller polls the ticker of a pair and reports whether it changed
+// since the previous poll, e.g. so that a display is only updated when the
+// price moves. It uses GetTicker, so it is served from the ticker cache if
+// one has been set with SetTickerCache. A TickerPoller is safe for concurrent
+// use, but concurrent polls compare against whichever poll finished last.
+type TickerPoller struct {
+	cl   *Client
+	pair string
+
+	mu   sync.Mutex
+	last *GetTickerResponse
+}
+
+// NewTickerPoller returns a TickerPoller for pair.
+func (cl *Client) NewTickerPoller(pair string) *TickerPoller {
+	return &TickerPoller{cl: cl, pair: pair}
+}
+
+// Poll fetches the ticker and returns whether it changed since the previous
+// successful poll. The first poll always reports a change. Tickers are
+// compared on their prices, volume and status. The timestamp is ignored since
+// it is set when the response is generated.
+func (p *TickerPoller) Poll(ctx context.Context) (*GetTickerResponse, bool, error) {
+	res, err := p.cl.GetTicker(ctx, &GetTickerRequest{Pair: p.pair})
+	if err != nil {
+		return nil, false, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	changed := p.last == nil || tickerChanged(p.last, res)
+	p.last = res
+	return res, changed, nil
+}
+
+func tickerChanged(a, b *GetTickerResponse) bool {
+	return a.Pair != b.Pair ||
+		a.Status != b.Status ||
+		a.Ask.Cmp(b.Ask) != 0 ||
+		a.Bid.Cmp(b.Bid) != 0 ||
+		a.LastTrade.Cmp(b.LastTrade) != 0 ||
+		a.Rolling24HourVolume.Cmp(b.Rolling24HourVolume) != 0
+}
+
+// maxConcurrentTickers is the maximum number of tickers
+// GetTickersConcurrently fetches at once.
+const maxConcurrentTickers = 4
+
+// GetTickersConcurrently fetches the ticker of each pair with GetTicker, at
+// most 4 at a time, and returns them keyed by pair. Requests go through the
+// client's rate limiter and ticker cache as usual.
+//
+// If any request fails, the tickers which were fetched are returned along
+// with a *BatchError holding the error for each pair, in the order of pairs.
+// To fetch several tickers in a single request instead, use GetTickers with
+// GetTickersRequest.Pair.
+func (cl *Client) GetTickersConcurrently(ctx context.Context, pairs []string) (
+	map[string]*GetTickerResponse, error) {
+
+	var mu sync.Mutex
+	tickers := make(map[string]*GetTickerResponse, len(pairs))
+	calls := make([]func(context.Context) error, len(pairs))
+	for i, pair := range pairs {
+		pair := pair
+		calls[i] = func(ctx context.Context) error {
+			res, err := cl.GetTicker(ctx, &GetTickerRequest{Pair: pair})
+			if err != nil {
+				return fmt.Errorf("%s: %w", pair, err)
+			}
+			mu.Lock()
+			tickers[pair] = res
+			mu.Unlock()
+			return nil
+		}
+	}
+	err := Batch(ctx, maxConcurrentTickers, calls...)
+	return tickers, err
+}
@@ -0,0 +1,102 @@
+package luno
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTickerCache(t *testing.T) {
+	calls := make(map[string]int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		switch r.URL.Path {
+		case "/api/1/tickers":
+			w.Write([]byte(`{"tickers":[
+				{"pair":"XBTZAR","last_trade":"100"},
+				{"pair":"ETHZAR","last_trade":"10"}
+			]}`))
+		case "/api/1/ticker":
+			w.Write([]byte(`{"pair":"` + r.URL.Query().Get("pair") + `","last_trade":"1"}`))
+		}
+	}))
+	defer srv.Close()
+
+	clk := newFakeClock()
+	cl := NewClient()
+	cl.clock = clk
+	cl.SetBaseURL(srv.URL)
+	cl.SetTickerCache(time.Second)
+	ctx := context.Background()
+
+	get := func(pair, exp string) {
+		t.Helper()
+		res, err := cl.GetTicker(ctx, &GetTickerRequest{Pair: pair})
+		if err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		if res.Pair != pair || res.LastTrade.String() != exp {
+			t.Errorf("Expected %s ticker with last trade %s, got %+v", pair, exp, res)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		get("XBTZAR", "100")
+		get("ETHZAR", "10")
+	}
+	if calls["/api/1/tickers"] != 1 || calls["/api/1/ticker"] != 0 {
+		t.Errorf("Expected a single tickers request, got %v", calls)
+	}
+
+	// Pairs missing from the snapshot are requested directly.
+	get("LTCZAR", "1")
+	if calls["/api/1/tickers"] != 1 || calls["/api/1/ticker"] != 1 {
+		t.Errorf("Expected a direct ticker request, got %v", calls)
+	}
+
+	clk.Advance(2 * time.Second)
+	get("XBTZAR", "100")
+	if calls["/api/1/tickers"] != 2 {
+		t.Errorf("Expected the snapshot to be refreshed after the TTL, got %v", calls)
+	}
+
+	cl.SetTickerCache(0)
+	get("XBTZAR", "1")
+	if calls["/api/1/tickers"] != 2 || calls["/api/1/ticker"] != 2 {
+		t.Errorf("Expected a direct ticker request once disabled, got %v", calls)
+	}
+}
+
+func TestTickerPoller(t *testing.T) {
+	var polls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		last := "100"
+		if polls >= 4 {
+			last = "101"
+		}
+		// The timestamp changes with every response.
+		fmt.Fprintf(w, `{"pair":"XBTZAR","bid":"99","ask":"102","last_trade":"%s","timestamp":%d}`,
+			last, polls)
+	}))
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.SetBaseURL(srv.URL)
+	p := cl.NewTickerPoller("XBTZAR")
+
+	exp := []bool{true, false, false, true, false}
+	for i, e := range exp {
+		res, changed, err := p.Poll(context.Background())
+		if err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		if changed != e {
+			t.Errorf("Expected poll %d changed to be %v, got %v (%+v)",
+				i, e, changed, res)
+		}
+	}
+}
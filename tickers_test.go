@@ -0,0 +1,49 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestGetTickersConcurrently(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pair := r.URL.Query().Get("pair")
+		if pair == "FOOBAR" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Unknown pair","error_code":"ErrMarketNotFound"}`))
+			return
+		}
+		w.Write([]byte(`{"pair":"` + pair + `","last_trade":"1"}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL))
+	pairs := []string{"XBTZAR", "FOOBAR", "ETHZAR", "XBTEUR", "ETHXBT"}
+	tickers, err := cl.GetTickersConcurrently(context.Background(), pairs)
+
+	var be *luno.BatchError
+	if !errors.As(err, &be) {
+		t.Fatalf("Expected BatchError, got %v", err)
+	}
+	for i, err := range be.Errs {
+		if failed := err != nil; failed != (pairs[i] == "FOOBAR") {
+			t.Errorf("Unexpected error for %s: %v", pairs[i], err)
+		}
+	}
+	if exp := "FOOBAR: Unknown pair (ErrMarketNotFound)"; be.Errs[1].Error() != exp {
+		t.Errorf("Expected %q, got %q", exp, be.Errs[1])
+	}
+	if len(tickers) != 4 {
+		t.Errorf("Expected 4 tickers, got %d", len(tickers))
+	}
+	for _, pair := range pairs[2:] {
+		if tickers[pair] == nil || tickers[pair].Pair != pair {
+			t.Errorf("Expected ticker for %s, got %+v", pair, tickers[pair])
+		}
+	}
+}
@@ -1,27 +1,57 @@
 package luno
 
 import (
+	"bytes"
 	"strconv"
 	"time"
 )
 
+// Time is a timestamp returned by the Luno API. It is decoded from either a
+// Unix timestamp in milliseconds or an RFC 3339 string and encoded as a Unix
+// timestamp in milliseconds. Decoded times are always in UTC, regardless of
+// the local time zone or the offset of an RFC 3339 string, so that time
+// components such as the hour and day are the same on every host.
 type Time time.Time
 
 func (t *Time) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*t = Time{}
+		return nil
+	}
+	if len(b) > 1 && b[0] == '"' && b[len(b)-1] == '"' {
+		s := string(bytes.Trim(b, `"`))
+		if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+			tt, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return err
+			}
+			*t = Time(tt.UTC())
+			return nil
+		}
+		b = []byte(s)
+	}
 	i, err := strconv.ParseInt(string(b), 10, 64)
 	if err != nil {
 		return err
 	}
-	if i == 0 {
-		*t = Time{}
-		return nil
-	}
-	*t = Time(time.Unix(0, i*1e6))
+	*t = TimeFromMillis(i)
 	return nil
 }
 
+// TimeFromMillis returns the Time of a Unix timestamp in milliseconds, in
+// UTC, or the zero Time if ms is zero, as for timestamps decoded from JSON.
+func TimeFromMillis(ms int64) Time {
+	if ms == 0 {
+		return Time{}
+	}
+	return Time(time.Unix(0, ms*1e6).UTC())
+}
+
 func (t Time) MarshalJSON() ([]byte, error) {
-	return []byte(t.String()), nil
+	if time.Time(t).IsZero() {
+		return []byte("0"), nil
+	}
+	return []byte(strconv.FormatInt(time.Time(t).UnixNano()/1e6, 10)), nil
 }
 
 func (t Time) String() string {
@@ -1,6 +1,8 @@
 package luno_test
 
 import (
+	"os"
+	"os/exec"
 	"testing"
 	"time"
 
@@ -28,11 +30,23 @@ func TestTimeUnmarshalJSON(t *testing.T) {
 		},
 		testCase{
 			in:  []byte("123456"),
-			exp: luno.Time(time.Unix(0, 123456e6)),
+			exp: luno.Time(time.Unix(0, 123456e6).UTC()),
 		},
 		testCase{
 			in:  []byte("-123456"),
-			exp: luno.Time(time.Unix(0, -123456e6)),
+			exp: luno.Time(time.Unix(0, -123456e6).UTC()),
+		},
+		testCase{
+			in:  []byte(`"123456"`),
+			exp: luno.Time(time.Unix(0, 123456e6).UTC()),
+		},
+		testCase{
+			in:  []byte("null"),
+			exp: luno.Time{},
+		},
+		testCase{
+			in:  []byte(`"abc"`),
+			err: true,
 		},
 	}
 
@@ -52,26 +66,44 @@ func TestTimeUnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestTimeUnmarshalJSONRepresentations(t *testing.T) {
+	exp := time.Date(2018, 1, 1, 0, 0, 0, 123e6, time.UTC)
+
+	for _, in := range []string{
+		"1514764800123",
+		`"1514764800123"`,
+		`"2018-01-01T00:00:00.123Z"`,
+		`"2018-01-01T02:00:00.123+02:00"`,
+	} {
+		var act luno.Time
+		if err := act.UnmarshalJSON([]byte(in)); err != nil {
+			t.Errorf("Expected %s to unmarshal, got %v", in, err)
+			continue
+		}
+		if !time.Time(act).Equal(exp) {
+			t.Errorf("Expected %s to unmarshal as %v, got %v", in, exp, act)
+		}
+	}
+}
+
 func TestTimeMarshalJSON(t *testing.T) {
 	type testCase struct {
 		in  luno.Time
 		exp string
 	}
 
-	now := time.Now()
-
 	testCases := []testCase{
 		testCase{
 			in:  luno.Time{},
-			exp: time.Time{}.String(),
+			exp: "0",
 		},
 		testCase{
-			in:  luno.Time(now),
-			exp: now.String(),
+			in:  luno.Time(time.Unix(0, 123456e6)),
+			exp: "123456",
 		},
 		testCase{
 			in:  luno.Time(time.Date(2006, 1, 2, 3, 4, 5, 999, time.UTC)),
-			exp: time.Date(2006, 1, 2, 3, 4, 5, 999, time.UTC).String(),
+			exp: "1136171045000",
 		},
 	}
 
@@ -88,3 +120,52 @@ func TestTimeMarshalJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestTimeUnmarshalJSONUTC(t *testing.T) {
+	// The local time zone is only read once per process, so the check is made
+	// from a child process with TZ set.
+	if os.Getenv("LUNO_TEST_TZ_CHILD") != "1" {
+		for _, tz := range []string{"Africa/Johannesburg", "Asia/Kolkata", "America/New_York"} {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestTimeUnmarshalJSONUTC$")
+			cmd.Env = append(os.Environ(), "LUNO_TEST_TZ_CHILD=1", "TZ="+tz)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("Expected success with TZ=%s, got %v: %s", tz, err, out)
+			}
+		}
+		return
+	}
+
+	if _, offset := time.Unix(1514764800, 0).Zone(); offset == 0 {
+		t.Skipf("TZ=%s is not available", os.Getenv("TZ"))
+	}
+
+	for _, in := range []string{
+		"1514764800123",
+		`"1514764800123"`,
+		`"2018-01-01T02:00:00.123+02:00"`,
+	} {
+		var act luno.Time
+		if err := act.UnmarshalJSON([]byte(in)); err != nil {
+			t.Fatalf("Expected %s to unmarshal, got %v", in, err)
+		}
+		tt := time.Time(act)
+		if tt.Location() != time.UTC {
+			t.Errorf("Expected %s to unmarshal in UTC, got %s", in, tt.Location())
+		}
+		y, m, d := tt.Date()
+		if y != 2018 || m != time.January || d != 1 || tt.Hour() != 0 ||
+			tt.Minute() != 0 || tt.Nanosecond() != 123e6 {
+			t.Errorf("Expected %s to unmarshal as 2018-01-01 00:00:00.123, got %s", in, tt)
+		}
+	}
+}
+
+func TestTimeFromMillis(t *testing.T) {
+	if tt := time.Time(luno.TimeFromMillis(0)); !tt.IsZero() {
+		t.Errorf("Expected zero time, got %s", tt)
+	}
+	tt := time.Time(luno.TimeFromMillis(1514764800123))
+	if exp := time.Date(2018, 1, 1, 0, 0, 0, 123e6, time.UTC); !tt.Equal(exp) || tt.Location() != time.UTC {
+		t.Errorf("Expected %s, got %s", exp, tt)
+	}
+}
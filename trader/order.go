@@ -0,0 +1,421 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+// State is the state of an Order.
+type State int
+
+const (
+	// StateOpen means the order has been placed and hasn't completed yet.
+	StateOpen State = iota
+
+	// StateFilled means the whole volume of the order has been traded.
+	StateFilled
+
+	// StateCancelled means the order completed without its whole volume
+	// being traded, e.g. because it was stopped or expired.
+	StateCancelled
+
+	// StateFailed means the order could no longer be watched or replaced.
+	// It may still be open on the exchange if it couldn't be stopped either.
+	StateFailed
+)
+
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateFilled:
+		return "filled"
+	case StateCancelled:
+		return "cancelled"
+	case StateFailed:
+		return "failed"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// ErrOrderDone is returned by Amend and Cancel once the order isn't open.
+var ErrOrderDone = errors.New("trader: order is no longer open")
+
+// OrderOption configures an Order placed with PlaceLimit.
+type OrderOption func(*Order)
+
+// WithStopLoss returns an option which emulates a stop loss: once the last
+// trade price of the pair reaches trigger, moving against the order (down
+// for an ask, up for a bid), the order is amended to price. Placing an exit
+// order at the take-profit price with a stop loss closes a position at
+// whichever level is reached first.
+//
+// The last trade price is checked with GetTicker on every poll. If the
+// amendment fails, it is retried at the next poll.
+func WithStopLoss(trigger, price decimal.Decimal) OrderOption {
+	return func(o *Order) {
+		o.stopLoss = &stopLoss{trigger: trigger, price: price}
+	}
+}
+
+type stopLoss struct {
+	trigger, price decimal.Decimal
+}
+
+type command struct {
+	ctx    context.Context
+	amend  bool
+	price  decimal.Decimal
+	result chan error
+}
+
+// Order is a limit order placed by a Trader. Amendments replace the order on
+// the exchange with a new one, so its ID may change over its lifetime, but
+// the fills of all the orders are accounted for together.
+type Order struct {
+	t        *Trader
+	req      luno.PostLimitOrderRequest
+	stopLoss *stopLoss
+
+	cmds   chan command
+	wakeCh chan struct{}
+	done   chan struct{}
+
+	mu     sync.Mutex
+	latest luno.GetOrderResponse
+	state  State
+	err    error
+
+	// priorBase and priorCounter are the fills of orders which were
+	// replaced by amendments.
+	priorBase, priorCounter decimal.Decimal
+}
+
+// PlaceLimit places a limit order and watches it until it completes. If ctx
+// is done before then, the order is stopped.
+func (t *Trader) PlaceLimit(ctx context.Context, req *luno.PostLimitOrderRequest,
+	opts ...OrderOption) (*Order, error) {
+
+	if req.Volume.Sign() <= 0 {
+		return nil, errors.New("trader: order volume must be positive")
+	}
+	res, err := t.api.PostLimitOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &Order{
+		t:            t,
+		req:          *req,
+		cmds:         make(chan command),
+		wakeCh:       make(chan struct{}, 1),
+		done:         make(chan struct{}),
+		priorBase:    decimal.Zero(),
+		priorCounter: decimal.Zero(),
+	}
+	o.latest = o.placed(res.OrderId, req.Price, req.Volume)
+	for _, opt := range opts {
+		opt(o)
+	}
+	t.track(o, "", res.OrderId)
+	go o.run(ctx)
+	return o, nil
+}
+
+// placed returns the state of a newly placed order.
+func (o *Order) placed(id string, price, volume decimal.Decimal) luno.GetOrderResponse {
+	return luno.GetOrderResponse{
+		OrderId:     id,
+		Pair:        o.req.Pair,
+		Type:        o.req.Type,
+		State:       luno.OrderStatePending,
+		LimitPrice:  price,
+		LimitVolume: volume,
+		Base:        decimal.Zero(),
+		Counter:     decimal.Zero(),
+	}
+}
+
+// ID returns the ID of the current order on the exchange.
+func (o *Order) ID() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.latest.OrderId
+}
+
+// State returns the current state of the order.
+func (o *Order) State() State {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.state
+}
+
+// Latest returns the last state polled of the current order on the exchange.
+func (o *Order) Latest() luno.GetOrderResponse {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.latest
+}
+
+// Filled returns the base and counter amounts traded so far, including those
+// of orders replaced by amendments.
+func (o *Order) Filled() (base, counter decimal.Decimal) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.priorBase.Add(o.latest.Base), o.priorCounter.Add(o.latest.Counter)
+}
+
+// Done returns a channel which is closed once the order isn't open.
+func (o *Order) Done() <-chan struct{} {
+	return o.done
+}
+
+// Await waits until the order isn't open, returning its final state and, if
+// it failed, the error. If ctx is done first, the current state and the error
+// of ctx are returned; the order is left as it is.
+func (o *Order) Await(ctx context.Context) (State, error) {
+	select {
+	case <-o.done:
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		return o.state, o.err
+	case <-ctx.Done():
+		return o.State(), ctx.Err()
+	}
+}
+
+// Amend replaces the order with one at price for the volume which hasn't
+// been traded yet. Luno doesn't support modifying orders, so the order is
+// stopped and a new one placed once it has completed. If the new order is
+// rejected, the order fails with a *luno.ReplaceOrderError.
+func (o *Order) Amend(ctx context.Context, price decimal.Decimal) error {
+	return o.do(ctx, command{amend: true, price: price})
+}
+
+// Cancel stops the order and waits for it to complete.
+func (o *Order) Cancel(ctx context.Context) error {
+	return o.do(ctx, command{})
+}
+
+func (o *Order) do(ctx context.Context, cmd command) error {
+	cmd.ctx = ctx
+	cmd.result = make(chan error, 1)
+	select {
+	case o.cmds <- cmd:
+	case <-o.done:
+		return ErrOrderDone
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return <-cmd.result
+}
+
+// run watches the order until it isn't open. All calls which change the
+// order are made from run, so that they don't race with each other.
+func (o *Order) run(ctx context.Context) {
+	defer close(o.done)
+	for {
+		complete, err := o.poll(ctx)
+		if err == nil && !complete {
+			err = o.checkStopLoss(ctx)
+		}
+		if o.State() != StateOpen {
+			return
+		} else if ctx.Err() != nil {
+			o.stopOnDone()
+			return
+		} else if err != nil && fatal(err) {
+			// The order can't be watched any more, so stop it rather than
+			// leave it open on the exchange.
+			o.stopOnDone()
+			if o.State() != StateFilled {
+				o.finish(StateFailed, err)
+			}
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			o.stopOnDone()
+			return
+		case cmd := <-o.cmds:
+			if cmd.amend {
+				cmd.result <- o.amend(cmd.ctx, cmd.price)
+			} else {
+				cmd.result <- o.cancel(cmd.ctx)
+			}
+			if o.State() != StateOpen {
+				return
+			}
+		case <-o.wakeCh:
+		case <-time.After(o.t.pollInterval):
+		}
+	}
+}
+
+// fatal returns whether err is a definitive error from the API, after which
+// the order can't be watched. Other errors, e.g. timeouts, server errors and
+// rate limiting, are retried at the next poll.
+func fatal(err error) bool {
+	if errors.Is(err, luno.ErrRateLimited) {
+		return false
+	}
+	if errors.Is(err, luno.ErrOrderNotFound) || errors.Is(err, luno.ErrAuth) {
+		return true
+	}
+	var apiErr luno.Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode < http.StatusInternalServerError
+}
+
+// poll fetches the current order, returning whether it has completed.
+func (o *Order) poll(ctx context.Context) (bool, error) {
+	res, err := o.t.api.GetOrder(ctx, &luno.GetOrderRequest{Id: o.ID()})
+	if err != nil {
+		return false, err
+	}
+	o.mu.Lock()
+	o.latest = *res
+	o.mu.Unlock()
+	if res.State != luno.OrderStateComplete {
+		return false, nil
+	}
+	o.complete()
+	return true, nil
+}
+
+// complete sets the final state of the order from its fills.
+func (o *Order) complete() {
+	base, _ := o.Filled()
+	if base.Cmp(o.req.Volume) >= 0 {
+		o.finish(StateFilled, nil)
+	} else {
+		o.finish(StateCancelled, nil)
+	}
+}
+
+func (o *Order) finish(state State, err error) {
+	o.mu.Lock()
+	o.state, o.err = state, err
+	id := o.latest.OrderId
+	o.mu.Unlock()
+	o.t.track(o, id, "")
+}
+
+// stop stops the current order and waits for it to complete, by polling it
+// every poll interval.
+func (o *Order) stop(ctx context.Context) error {
+	id := o.ID()
+	res, err := o.t.api.StopOrder(ctx, &luno.StopOrderRequest{OrderId: id})
+	if err != nil {
+		return err
+	}
+	if !res.Success {
+		return fmt.Errorf("trader: order %s could not be stopped", id)
+	}
+	for {
+		res, err := o.t.api.GetOrder(ctx, &luno.GetOrderRequest{Id: id})
+		if err != nil {
+			return err
+		}
+		o.mu.Lock()
+		o.latest = *res
+		o.mu.Unlock()
+		if res.State == luno.OrderStateComplete {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(o.t.pollInterval):
+		}
+	}
+}
+
+func (o *Order) cancel(ctx context.Context) error {
+	if err := o.stop(ctx); err != nil {
+		return err
+	}
+	o.complete()
+	return nil
+}
+
+// stopOnDone stops the order once the context it was placed with is done.
+func (o *Order) stopOnDone() {
+	ctx, cancel := stopContext()
+	defer cancel()
+	if err := o.cancel(ctx); err != nil {
+		o.finish(StateFailed, fmt.Errorf("trader: stopping order %s: %w", o.ID(), err))
+	}
+}
+
+func (o *Order) amend(ctx context.Context, price decimal.Decimal) error {
+	if err := o.stop(ctx); err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	old := o.latest
+	remaining := o.req.Volume.Sub(o.priorBase).Sub(old.Base)
+	o.mu.Unlock()
+	if remaining.Sign() <= 0 {
+		o.finish(StateFilled, nil)
+		return nil
+	}
+
+	req := o.req
+	req.Price = price
+	req.Volume = remaining
+	req.ClientOrderId = ""
+	req.StopPrice = decimal.Zero()
+	req.StopDirection = ""
+	res, err := o.t.api.PostLimitOrder(ctx, &req)
+	if err != nil {
+		err = &luno.ReplaceOrderError{OldOrderID: old.OrderId, Err: err}
+		o.finish(StateFailed, err)
+		return err
+	}
+
+	o.mu.Lock()
+	o.priorBase = o.priorBase.Add(old.Base)
+	o.priorCounter = o.priorCounter.Add(old.Counter)
+	o.latest = o.placed(res.OrderId, price, remaining)
+	o.mu.Unlock()
+	o.t.track(o, old.OrderId, res.OrderId)
+	return nil
+}
+
+// checkStopLoss amends the order if its stop loss has been triggered.
+func (o *Order) checkStopLoss(ctx context.Context) error {
+	sl := o.stopLoss
+	if sl == nil {
+		return nil
+	}
+	res, err := o.t.api.GetTicker(ctx, &luno.GetTickerRequest{Pair: o.req.Pair})
+	if err != nil {
+		return err
+	}
+	last := res.LastTrade
+	var hit bool
+	switch o.req.Type {
+	case luno.OrderTypeAsk:
+		hit = last.Cmp(sl.trigger) <= 0
+	case luno.OrderTypeBid:
+		hit = last.Cmp(sl.trigger) >= 0
+	}
+	if !hit || last.Sign() == 0 {
+		return nil
+	}
+	// If the amendment fails while the order is still open, it is retried at
+	// the next poll. Otherwise the order has failed and run stops.
+	if err := o.amend(ctx, sl.price); err == nil {
+		o.stopLoss = nil
+	}
+	return nil
+}
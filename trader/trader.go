@@ -0,0 +1,119 @@
+// Package trader manages the lifecycle of limit orders placed with the Luno
+// API, so that bots don't have to poll, amend and cancel orders by hand.
+//
+// Example:
+//
+//	t := trader.New(cl)
+//	o, err := t.PlaceLimit(ctx, &luno.PostLimitOrderRequest{
+//		Pair:   "XBTZAR",
+//		Type:   luno.OrderTypeAsk,
+//		Price:  decimal.NewFromInt64(1200000),
+//		Volume: decimal.NewFromFloat64(0.01, 2),
+//	}, trader.WithStopLoss(decimal.NewFromInt64(1000000), decimal.NewFromInt64(990000)))
+//	...
+//	state, err := o.Await(ctx)
+//
+// The order is stopped automatically if the context passed to PlaceLimit is
+// done before it completes.
+package trader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/streaming"
+)
+
+const (
+	// defaultPollInterval is how often orders are polled by default.
+	defaultPollInterval = 2 * time.Second
+
+	// stopTimeout bounds the calls made to stop an order once the context
+	// it was placed with is done.
+	stopTimeout = 10 * time.Second
+)
+
+// Trader places limit orders and watches them until they complete. It is
+// safe for concurrent use.
+type Trader struct {
+	api          luno.API
+	pollInterval time.Duration
+
+	mu     sync.Mutex
+	orders map[string]*Order
+}
+
+// Option configures a Trader.
+type Option func(*Trader)
+
+// WithPollInterval returns an option which polls each open order every d.
+// The default is 2 seconds.
+func WithPollInterval(d time.Duration) Option {
+	return func(t *Trader) {
+		t.pollInterval = d
+	}
+}
+
+// New returns a Trader which makes requests with api, usually a
+// *luno.Client.
+func New(api luno.API, opts ...Option) *Trader {
+	t := &Trader{
+		api:          api,
+		pollInterval: defaultPollInterval,
+		orders:       make(map[string]*Order),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// HandleUpdate refreshes the open orders affected by u, which have traded or
+// been removed from the order book, without waiting for the next poll. Pass
+// it to streaming.WithUpdateCallback when dialling the markets of the
+// orders:
+//
+//	c, err := streaming.Dial(keyID, keySecret, "XBTZAR",
+//		streaming.WithUpdateCallback(t.HandleUpdate))
+func (t *Trader) HandleUpdate(u streaming.Update) {
+	for _, tu := range u.TradeUpdates {
+		if tu != nil {
+			t.wake(tu.OrderID)
+		}
+	}
+	if u.DeleteUpdate != nil {
+		t.wake(u.DeleteUpdate.OrderID)
+	}
+}
+
+func (t *Trader) wake(id string) {
+	t.mu.Lock()
+	o := t.orders[id]
+	t.mu.Unlock()
+	if o == nil {
+		return
+	}
+	select {
+	case o.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// track registers o under its current order ID, replacing oldID if set.
+func (t *Trader) track(o *Order, oldID, id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if oldID != "" {
+		delete(t.orders, oldID)
+	}
+	if id != "" {
+		t.orders[id] = o
+	}
+}
+
+// stopContext returns a context for stopping an order after ctx is done.
+func stopContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), stopTimeout)
+}
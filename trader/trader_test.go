@@ -0,0 +1,316 @@
+package trader_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-go/lunotest"
+	"github.com/luno/luno-go/streaming"
+	"github.com/luno/luno-go/trader"
+)
+
+// exchange is a fake exchange which keeps the state of the orders placed
+// with it.
+type exchange struct {
+	*lunotest.Fake
+
+	mu        sync.Mutex
+	orders    map[string]*luno.GetOrderResponse
+	ids       []string
+	lastTrade decimal.Decimal
+}
+
+func newExchange() *exchange {
+	e := &exchange{
+		Fake:      lunotest.NewFake(),
+		orders:    make(map[string]*luno.GetOrderResponse),
+		lastTrade: decimal.NewFromInt64(100),
+	}
+	e.Handle("PostLimitOrder", func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(*luno.PostLimitOrderRequest)
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		id := fmt.Sprintf("O%d", len(e.ids)+1)
+		e.ids = append(e.ids, id)
+		e.orders[id] = &luno.GetOrderResponse{
+			OrderId:     id,
+			Pair:        r.Pair,
+			Type:        r.Type,
+			State:       luno.OrderStatePending,
+			LimitPrice:  r.Price,
+			LimitVolume: r.Volume,
+			Base:        decimal.Zero(),
+			Counter:     decimal.Zero(),
+		}
+		return &luno.PostLimitOrderResponse{OrderId: id}, nil
+	})
+	e.Handle("GetOrder", func(ctx context.Context, req interface{}) (interface{}, error) {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		o, ok := e.orders[req.(*luno.GetOrderRequest).Id]
+		if !ok {
+			return nil, luno.ErrOrderNotFound
+		}
+		res := *o
+		return &res, nil
+	})
+	e.Handle("StopOrder", func(ctx context.Context, req interface{}) (interface{}, error) {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.orders[req.(*luno.StopOrderRequest).OrderId].State = luno.OrderStateComplete
+		return &luno.StopOrderResponse{Success: true}, nil
+	})
+	e.Handle("GetTicker", func(ctx context.Context, req interface{}) (interface{}, error) {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		return &luno.GetTickerResponse{LastTrade: e.lastTrade}, nil
+	})
+	return e
+}
+
+// fill trades base of the order with the given ID at its limit price.
+func (e *exchange) fill(id string, base float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	o := e.orders[id]
+	b := decimal.NewFromFloat64(base, 2)
+	o.Base = o.Base.Add(b)
+	o.Counter = o.Counter.Add(b.Mul(o.LimitPrice))
+	if o.Base.Cmp(o.LimitVolume) >= 0 {
+		o.State = luno.OrderStateComplete
+	}
+}
+
+func (e *exchange) order(id string) luno.GetOrderResponse {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return *e.orders[id]
+}
+
+func (e *exchange) setLastTrade(price int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastTrade = decimal.NewFromInt64(price)
+}
+
+func ask(price int64, volume float64) *luno.PostLimitOrderRequest {
+	return &luno.PostLimitOrderRequest{
+		Pair:   "XBTZAR",
+		Type:   luno.OrderTypeAsk,
+		Price:  decimal.NewFromInt64(price),
+		Volume: decimal.NewFromFloat64(volume, 2),
+	}
+}
+
+func await(t *testing.T, o *trader.Order, exp trader.State) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	state, err := o.Await(ctx)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if state != exp {
+		t.Errorf("Expected order %s, got %s", exp, state)
+	}
+}
+
+func TestPlaceLimit(t *testing.T) {
+	e := newExchange()
+	tr := trader.New(e, trader.WithPollInterval(time.Millisecond))
+
+	o, err := tr.PlaceLimit(context.Background(), ask(110, 1))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if o.ID() != "O1" || o.State() != trader.StateOpen {
+		t.Errorf("Expected open order O1, got %s %s", o.ID(), o.State())
+	}
+	e.fill("O1", 0.5)
+	e.fill("O1", 0.5)
+	await(t, o, trader.StateFilled)
+
+	base, counter := o.Filled()
+	if base.String() != "1.00" || counter.String() != "110.00" {
+		t.Errorf("Expected 1.00 filled for 110.00, got %s for %s", base, counter)
+	}
+	if err := o.Cancel(context.Background()); err != trader.ErrOrderDone {
+		t.Errorf("Expected ErrOrderDone, got %v", err)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	e := newExchange()
+	tr := trader.New(e, trader.WithPollInterval(time.Millisecond))
+
+	o, err := tr.PlaceLimit(context.Background(), ask(110, 1))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	e.fill("O1", 0.25)
+	if err := o.Cancel(context.Background()); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	await(t, o, trader.StateCancelled)
+	if base, _ := o.Filled(); base.String() != "0.25" {
+		t.Errorf("Expected 0.25 filled, got %s", base)
+	}
+}
+
+func TestCancelOnContextDone(t *testing.T) {
+	e := newExchange()
+	tr := trader.New(e, trader.WithPollInterval(time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	o, err := tr.PlaceLimit(ctx, ask(110, 1))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	cancel()
+	await(t, o, trader.StateCancelled)
+	if n := len(e.CallsTo("StopOrder")); n != 1 {
+		t.Errorf("Expected the order to be stopped once, got %d", n)
+	}
+}
+
+func TestAmend(t *testing.T) {
+	e := newExchange()
+	tr := trader.New(e, trader.WithPollInterval(time.Millisecond))
+
+	o, err := tr.PlaceLimit(context.Background(), ask(110, 1))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	e.fill("O1", 0.4)
+	if err := o.Amend(context.Background(), decimal.NewFromInt64(105)); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if o.ID() != "O2" {
+		t.Fatalf("Expected order to be replaced by O2, got %s", o.ID())
+	}
+	if n := e.order("O2"); n.LimitVolume.String() != "0.60" || n.LimitPrice.String() != "105" {
+		t.Errorf("Expected 0.60 at 105, got %s at %s", n.LimitVolume, n.LimitPrice)
+	}
+
+	e.fill("O2", 0.6)
+	await(t, o, trader.StateFilled)
+	base, counter := o.Filled()
+	if base.String() != "1.00" || counter.String() != "107.00" {
+		t.Errorf("Expected 1.00 filled for 107.00, got %s for %s", base, counter)
+	}
+}
+
+func TestAmendRejected(t *testing.T) {
+	e := newExchange()
+	tr := trader.New(e, trader.WithPollInterval(time.Millisecond))
+
+	o, err := tr.PlaceLimit(context.Background(), ask(110, 1))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	e.FailNext("PostLimitOrder", luno.ErrInsufficientBalance)
+	err = o.Amend(context.Background(), decimal.NewFromInt64(105))
+	var replaceErr *luno.ReplaceOrderError
+	if !errors.As(err, &replaceErr) || replaceErr.OldOrderID != "O1" {
+		t.Fatalf("Expected ReplaceOrderError for O1, got %v", err)
+	}
+	state, err := o.Await(context.Background())
+	if state != trader.StateFailed || !errors.Is(err, luno.ErrInsufficientBalance) {
+		t.Errorf("Expected failed order, got %s: %v", state, err)
+	}
+}
+
+func TestStopLoss(t *testing.T) {
+	e := newExchange()
+	tr := trader.New(e, trader.WithPollInterval(time.Millisecond))
+
+	o, err := tr.PlaceLimit(context.Background(), ask(110, 1),
+		trader.WithStopLoss(decimal.NewFromInt64(95), decimal.NewFromInt64(94)))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	e.setLastTrade(95)
+
+	deadline := time.Now().Add(time.Second)
+	for o.ID() != "O2" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected stop loss to replace the order, got %s", o.ID())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if n := e.order("O2"); n.LimitPrice.String() != "94" || n.LimitVolume.String() != "1.00" {
+		t.Errorf("Expected 1.00 at 94, got %s at %s", n.LimitVolume, n.LimitPrice)
+	}
+	e.fill("O2", 1)
+	await(t, o, trader.StateFilled)
+	if n := len(e.CallsTo("PostLimitOrder")); n != 2 {
+		t.Errorf("Expected stop loss to trigger once, got %d orders", n)
+	}
+}
+
+func TestTransientErrors(t *testing.T) {
+	e := newExchange()
+	tr := trader.New(e, trader.WithPollInterval(time.Millisecond))
+
+	timeout := errors.New("connection reset")
+	e.FailNext("GetOrder", timeout, luno.Error{Code: "ErrInternal", StatusCode: 500})
+	e.FailNext("GetTicker", timeout, luno.ErrRateLimited)
+	o, err := tr.PlaceLimit(context.Background(), ask(110, 1),
+		trader.WithStopLoss(decimal.NewFromInt64(95), decimal.NewFromInt64(94)))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(e.CallsTo("GetTicker")) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected polling to continue after transient errors")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if state := o.State(); state != trader.StateOpen {
+		t.Fatalf("Expected order open, got %s", state)
+	}
+	e.fill("O1", 1)
+	await(t, o, trader.StateFilled)
+}
+
+func TestFatalError(t *testing.T) {
+	e := newExchange()
+	tr := trader.New(e, trader.WithPollInterval(time.Millisecond))
+
+	e.FailNext("GetOrder", luno.Error{Code: "ErrUnauthorised", StatusCode: 401})
+	o, err := tr.PlaceLimit(context.Background(), ask(110, 1))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	state, err := o.Await(context.Background())
+	if state != trader.StateFailed || !errors.Is(err, luno.ErrAuth) {
+		t.Errorf("Expected failed order, got %s: %v", state, err)
+	}
+	if n := len(e.CallsTo("StopOrder")); n != 1 {
+		t.Errorf("Expected the order to be stopped, got %d calls", n)
+	}
+	if s := e.order("O1").State; s != luno.OrderStateComplete {
+		t.Errorf("Expected order complete on the exchange, got %s", s)
+	}
+}
+
+func TestHandleUpdate(t *testing.T) {
+	e := newExchange()
+	tr := trader.New(e, trader.WithPollInterval(time.Hour))
+
+	o, err := tr.PlaceLimit(context.Background(), ask(110, 1))
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	e.fill("O1", 1)
+	tr.HandleUpdate(streaming.Update{DeleteUpdate: &streaming.DeleteUpdate{OrderID: "O1"}})
+	await(t, o, trader.StateFilled)
+}
@@ -0,0 +1,127 @@
+package luno
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithTransport returns an option which sets the transport of the client's
+// current HTTP client, e.g. to share a tuned transport between clients.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(cl *Client) {
+		cl.httpClient.Transport = rt
+	}
+}
+
+// SetConnectionPool configures how many idle connections to each host the
+// HTTP client keeps open for reuse, and for how long. The default transport
+// keeps only 2, so callers polling at high frequency from many goroutines
+// should raise it to about the number of concurrent requests. A zero
+// idleTimeout keeps the transport's timeout.
+//
+// Like SetProxy, this configures the transport of the HTTP client, so call it
+// after SetHTTPClient. An error is returned if the HTTP client uses a
+// transport other than *http.Transport.
+func (cl *Client) SetConnectionPool(maxIdleConnsPerHost int,
+	idleTimeout time.Duration) error {
+
+	if maxIdleConnsPerHost < 0 || idleTimeout < 0 {
+		return fmt.Errorf("luno: invalid connection pool size %d or idle timeout %v",
+			maxIdleConnsPerHost, idleTimeout)
+	}
+	t, err := cl.transport()
+	if err != nil {
+		return fmt.Errorf("luno: can't configure connection pool: %w", err)
+	}
+	t.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	if t.MaxIdleConns != 0 && t.MaxIdleConns < maxIdleConnsPerHost {
+		t.MaxIdleConns = maxIdleConnsPerHost
+	}
+	if idleTimeout > 0 {
+		t.IdleConnTimeout = idleTimeout
+	}
+	return nil
+}
+
+// SetTLSSessionCache enables TLS session resumption with a cache of the given
+// capacity, so that new connections, e.g. after idle ones were closed, skip
+// most of the TLS handshake. A capacity of zero uses the default capacity of
+// crypto/tls.
+//
+// Like SetProxy, this configures the transport of the HTTP client, so call it
+// after SetHTTPClient. An error is returned if the HTTP client uses a
+// transport other than *http.Transport.
+func (cl *Client) SetTLSSessionCache(capacity int) error {
+	t, err := cl.transport()
+	if err != nil {
+		return fmt.Errorf("luno: can't configure TLS session cache: %w", err)
+	}
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	} else {
+		t.TLSClientConfig = t.TLSClientConfig.Clone()
+	}
+	t.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(capacity)
+	return nil
+}
+
+// Response bodies are read into pooled buffers, and gzip readers are reused,
+// so that frequent polling of large responses such as order books doesn't
+// allocate for every request.
+var (
+	bufferPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+	gzipPool   sync.Pool
+)
+
+// maxPooledBufferBytes is the largest buffer returned to bufferPool, so that
+// one unusually large response doesn't stay in memory.
+const maxPooledBufferBytes = 1 << 20
+
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferBytes {
+		bufferPool.Put(buf)
+	}
+}
+
+// readBody reads at most limit+1 bytes of the body of httpRes into buf,
+// decompressing it if it's gzip encoded. Requests are sent with
+// Accept-Encoding: gzip by the client itself, so the transport doesn't
+// decompress responses and whether they are compressed doesn't depend on the
+// transport used.
+func readBody(buf *bytes.Buffer, httpRes *http.Response, limit int64) error {
+	var r io.Reader = httpRes.Body
+	if strings.EqualFold(httpRes.Header.Get("Content-Encoding"), "gzip") {
+		zr, err := getGzipReader(httpRes.Body)
+		if err != nil {
+			return err
+		}
+		defer gzipPool.Put(zr)
+		r = zr
+	}
+	_, err := buf.ReadFrom(io.LimitReader(r, limit+1))
+	return err
+}
+
+func getGzipReader(r io.Reader) (*gzip.Reader, error) {
+	if zr, ok := gzipPool.Get().(*gzip.Reader); ok {
+		if err := zr.Reset(r); err != nil {
+			gzipPool.Put(zr)
+			return nil, err
+		}
+		return zr, nil
+	}
+	return gzip.NewReader(r)
+}
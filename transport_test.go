@@ -0,0 +1,152 @@
+package luno_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+)
+
+// newOrderBookServer returns a server which answers every request with an
+// order book of n levels a side, gzip encoded if compress is true and the
+// request accepts it.
+func newOrderBookServer(t testing.TB, n int, compress bool) *httptest.Server {
+	var sb strings.Builder
+	sb.WriteString(`{"timestamp":1,"bids":[`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, `{"price":"%d.00","volume":"0.01"}`, 1000000-i)
+	}
+	sb.WriteString(`],"asks":[`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, `{"price":"%d.00","volume":"0.01"}`, 1000001+i)
+	}
+	sb.WriteString(`]}`)
+	plain := []byte(sb.String())
+
+	var zipped bytes.Buffer
+	zw := gzip.NewWriter(&zipped)
+	zw.Write(plain)
+	zw.Close()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if compress && strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(zipped.Bytes())
+			return
+		}
+		w.Write(plain)
+	}))
+}
+
+func TestGzipResponse(t *testing.T) {
+	srv := newOrderBookServer(t, 10, true)
+	defer srv.Close()
+
+	// The custom transport doesn't decompress responses itself.
+	tr := new(countingTransport)
+	cl := luno.NewClient(
+		luno.WithHTTPClient(&http.Client{}),
+		luno.WithTransport(tr),
+		luno.WithBaseURL(srv.URL))
+
+	res, err := cl.GetOrderBookFull(context.Background(),
+		&luno.GetOrderBookFullRequest{Pair: "XBTZAR"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(res.Bids) != 10 || len(res.Asks) != 10 {
+		t.Errorf("Expected 10 bids and asks, got %d and %d", len(res.Bids), len(res.Asks))
+	}
+	if atomic.LoadInt32(&tr.n) != 1 {
+		t.Errorf("Expected request to use the transport")
+	}
+}
+
+func TestGzipResponseLimit(t *testing.T) {
+	srv := newOrderBookServer(t, 100, true)
+	defer srv.Close()
+
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL))
+	cl.SetMaxResponseBytes(1024)
+
+	// The limit applies to the decompressed body.
+	_, err := cl.GetOrderBookFull(context.Background(),
+		&luno.GetOrderBookFullRequest{Pair: "XBTZAR"})
+	if err == nil || !strings.Contains(err.Error(), "exceeds 1024 bytes") {
+		t.Errorf("Expected error for too large response, got %v", err)
+	}
+}
+
+func TestSetConnectionPool(t *testing.T) {
+	tr := &http.Transport{MaxIdleConns: 10}
+	cl := luno.NewClient(luno.WithHTTPClient(&http.Client{Transport: tr}))
+	if err := cl.SetConnectionPool(32, time.Minute); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if tr.MaxIdleConnsPerHost != 32 {
+		t.Errorf("Expected 32 idle connections per host, got %d", tr.MaxIdleConnsPerHost)
+	}
+	if tr.MaxIdleConns != 32 {
+		t.Errorf("Expected 32 idle connections, got %d", tr.MaxIdleConns)
+	}
+	if tr.IdleConnTimeout != time.Minute {
+		t.Errorf("Expected idle timeout 1m, got %v", tr.IdleConnTimeout)
+	}
+
+	if err := cl.SetConnectionPool(-1, 0); err == nil {
+		t.Errorf("Expected error for negative pool size, got nil")
+	}
+
+	cl = luno.NewClient(luno.WithHTTPClient(&http.Client{}), luno.WithTransport(new(countingTransport)))
+	if err := cl.SetConnectionPool(32, 0); err == nil {
+		t.Errorf("Expected error for custom transport, got nil")
+	}
+}
+
+func TestSetTLSSessionCache(t *testing.T) {
+	tr := new(http.Transport)
+	cl := luno.NewClient(luno.WithHTTPClient(&http.Client{Transport: tr}))
+	if err := cl.SetTLSSessionCache(16); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if tr.TLSClientConfig == nil || tr.TLSClientConfig.ClientSessionCache == nil {
+		t.Errorf("Expected TLS session cache to be set")
+	}
+}
+
+func BenchmarkGetOrderBookFull(b *testing.B) {
+	for _, compress := range []bool{false, true} {
+		b.Run(fmt.Sprintf("gzip=%v", compress), func(b *testing.B) {
+			srv := newOrderBookServer(b, 500, compress)
+			defer srv.Close()
+
+			cl := luno.NewClient(luno.WithBaseURL(srv.URL))
+			if err := cl.SetConnectionPool(8, 0); err != nil {
+				b.Fatal(err)
+			}
+			req := &luno.GetOrderBookFullRequest{Pair: "XBTZAR"}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := cl.GetOrderBookFull(context.Background(), req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
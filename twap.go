@@ -0,0 +1,145 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// TWAPRequest describes a market order to be split into equal slices which
+// are submitted at a fixed interval.
+type TWAPRequest struct {
+	Pair string
+	Side Side
+
+	// Total volume of all slices. For a buy order this is the amount of
+	// counter currency to spend and for a sell order it is the amount of base
+	// currency to sell, as for OrderBuilder.Market.
+	Volume decimal.Decimal
+
+	// Number of market orders to submit. Volume is split at the scale of the
+	// market, see MarketInfo: VolumeScale for a sell order and PriceScale
+	// for a buy order. Any remainder is added to the last slice.
+	Slices int
+
+	// Time to wait between submitting slices.
+	Interval time.Duration
+
+	// Accounts to use. Zero IDs select the default accounts.
+	BaseAccountId    int64
+	CounterAccountId int64
+}
+
+// TWAPSlice is the outcome of a single TWAP slice.
+type TWAPSlice struct {
+	OrderId string
+	Volume  decimal.Decimal
+
+	// Amounts filled, excluding fees, as reported by GetOrder.
+	Base    decimal.Decimal
+	Counter decimal.Decimal
+}
+
+// TWAPResult is the outcome of a TWAP execution.
+type TWAPResult struct {
+	// Slices which were submitted, in order.
+	Slices []TWAPSlice
+
+	// Total amounts filled by all slices.
+	Base    decimal.Decimal
+	Counter decimal.Decimal
+
+	// Volume weighted average price of all fills, or zero if nothing was
+	// filled.
+	AveragePrice decimal.Decimal
+}
+
+// TWAP executes a market order as Slices market orders submitted Interval
+// apart. Requests are subject to the client's rate limit.
+//
+// If a slice fails or ctx is cancelled, no further slices are submitted and
+// the slices completed so far are returned along with the error.
+func (cl *Client) TWAP(ctx context.Context, req TWAPRequest) (*TWAPResult, error) {
+	if req.Slices <= 0 {
+		return nil, errors.New("luno: TWAP requires at least one slice")
+	}
+	volume := req.Volume
+	m, err := cl.MarketInfo(ctx, req.Pair)
+	if err == nil {
+		scale := m.PriceScale
+		if req.Side == SideSell {
+			scale = m.VolumeScale
+		}
+		volume = volume.ToScale(int(scale))
+	} else if err := cl.metadataUnavailable(ctx, "TWAP slice scaling", req.Pair, err); err != nil {
+		return nil, err
+	}
+	sliceVolume := volume.DivInt64(int64(req.Slices))
+	if sliceVolume.Sign() <= 0 {
+		return nil, fmt.Errorf("luno: TWAP volume %s is too small for %d slices",
+			req.Volume, req.Slices)
+	}
+	lastVolume := req.Volume.Sub(sliceVolume.MulInt64(int64(req.Slices - 1)))
+
+	res := &TWAPResult{
+		Base:         decimal.Zero(),
+		Counter:      decimal.Zero(),
+		AveragePrice: decimal.Zero(),
+	}
+	for i := 0; i < req.Slices; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return res, ctx.Err()
+			case <-cl.clock.After(req.Interval):
+			}
+		}
+
+		vol := sliceVolume
+		if i == req.Slices-1 {
+			vol = lastVolume
+		}
+		s, err := cl.twapSlice(ctx, req, vol)
+		if s.OrderId != "" {
+			// Report the order even if its fill couldn't be fetched.
+			res.Slices = append(res.Slices, s)
+			res.Base = res.Base.Add(s.Base)
+			res.Counter = res.Counter.Add(s.Counter)
+			if res.Base.Sign() > 0 {
+				res.AveragePrice = res.Counter.Div(res.Base, averagePriceScale)
+			}
+		}
+		if err != nil {
+			return res, fmt.Errorf("luno: TWAP slice %d of %d: %w",
+				i+1, req.Slices, err)
+		}
+	}
+	return res, nil
+}
+
+func (cl *Client) twapSlice(ctx context.Context, req TWAPRequest,
+	vol decimal.Decimal) (TWAPSlice, error) {
+
+	order, err := NewOrderBuilder().
+		Market(req.Pair, req.Side, vol).
+		OnAccount(req.BaseAccountId, req.CounterAccountId).
+		Build()
+	if err != nil {
+		return TWAPSlice{}, err
+	}
+	placed, err := cl.PostMarketOrder(ctx, order.Market)
+	if err != nil {
+		return TWAPSlice{}, err
+	}
+	s := TWAPSlice{OrderId: placed.OrderId, Volume: vol}
+
+	filled, err := cl.GetOrder(ctx, &GetOrderRequest{Id: placed.OrderId})
+	if err != nil {
+		return s, err
+	}
+	s.Base, s.Counter = filled.Base, filled.Counter
+	return s, nil
+}
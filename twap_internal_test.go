@@ -0,0 +1,195 @@
+package luno
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// newTWAPServer fills each market order at a price which increases by 10 per
+// order, starting at 100.
+func newTWAPServer(t *testing.T, failOrder int) (*httptest.Server, func() []string) {
+	var (
+		mu      sync.Mutex
+		volumes []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == "/api/exchange/1/markets":
+			w.Write([]byte(`{"markets":[{"market_id":"XBTZAR","price_scale":0,"volume_scale":4}]}`))
+		case r.URL.Path == "/api/1/marketorder":
+			if len(volumes)+1 == failOrder {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"Rejected","error_code":"ErrRejected"}`))
+				return
+			}
+			vol := r.FormValue("counter_volume")
+			if r.FormValue("type") == "SELL" {
+				vol = r.FormValue("base_volume")
+			}
+			volumes = append(volumes, vol)
+			fmt.Fprintf(w, `{"order_id":"BXO%d"}`, len(volumes))
+		case strings.HasPrefix(r.URL.Path, "/api/1/orders/BXO"):
+			n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/1/orders/BXO"))
+			counter, _ := decimal.NewFromString(volumes[n-1])
+			price := decimal.NewFromInt64(int64(90 + 10*n))
+			fmt.Fprintf(w, `{"order_id":"BXO%d","counter":"%s","base":"%s"}`,
+				n, counter, counter.Div(price, 4))
+		default:
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+	}))
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), volumes...)
+	}
+}
+
+func TestTWAP(t *testing.T) {
+	clk := newFakeClock()
+	srv, volumes := newTWAPServer(t, 0)
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.clock = clk
+	cl.SetBaseURL(srv.URL)
+
+	go func() {
+		for i := 0; i < 2; i++ {
+			clk.BlockUntil(1)
+			clk.Advance(time.Hour)
+		}
+	}()
+	res, err := cl.TWAP(context.Background(), TWAPRequest{
+		Pair:     "XBTZAR",
+		Side:     SideBuy,
+		Volume:   decimal.NewFromInt64(1000),
+		Slices:   3,
+		Interval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	exp := []string{"333", "333", "334"}
+	if got := volumes(); strings.Join(got, ",") != strings.Join(exp, ",") {
+		t.Errorf("Expected slice volumes %v, got %v", exp, got)
+	}
+	if len(res.Slices) != 3 {
+		t.Fatalf("Expected 3 slices, got %d", len(res.Slices))
+	}
+	if res.Counter.String() != "1000" {
+		t.Errorf("Expected counter 1000, got %s", res.Counter)
+	}
+	// 3.33 + 3.0272 + 2.7833 = 9.1405 base for 1000 counter.
+	if res.Base.String() != "9.1405" {
+		t.Errorf("Expected base 9.1405, got %s", res.Base)
+	}
+	if res.AveragePrice.String() != "109.40320551" {
+		t.Errorf("Expected average price 109.40320551, got %s", res.AveragePrice)
+	}
+}
+
+func TestTWAPMarketScale(t *testing.T) {
+	clk := newFakeClock()
+	srv, volumes := newTWAPServer(t, 0)
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.clock = clk
+	cl.SetBaseURL(srv.URL)
+
+	go func() {
+		for i := 0; i < 2; i++ {
+			clk.BlockUntil(1)
+			clk.Advance(time.Hour)
+		}
+	}()
+	// 1 XBT can't be split at its own scale of 0, but can at the market's
+	// volume scale of 4.
+	_, err := cl.TWAP(context.Background(), TWAPRequest{
+		Pair:     "XBTZAR",
+		Side:     SideSell,
+		Volume:   decimal.NewFromInt64(1),
+		Slices:   3,
+		Interval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	exp := []string{"0.3333", "0.3333", "0.3334"}
+	if got := volumes(); strings.Join(got, ",") != strings.Join(exp, ",") {
+		t.Errorf("Expected slice volumes %v, got %v", exp, got)
+	}
+}
+
+func TestTWAPCancelled(t *testing.T) {
+	clk := newFakeClock()
+	srv, volumes := newTWAPServer(t, 0)
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.clock = clk
+	cl.SetBaseURL(srv.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		clk.BlockUntil(1)
+		clk.Advance(time.Hour)
+		// Cancel while waiting for the third slice.
+		clk.BlockUntil(1)
+		cancel()
+	}()
+	res, err := cl.TWAP(ctx, TWAPRequest{
+		Pair:     "XBTZAR",
+		Side:     SideBuy,
+		Volume:   decimal.NewFromInt64(1000),
+		Slices:   5,
+		Interval: time.Hour,
+	})
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if len(res.Slices) != 2 || len(volumes()) != 2 {
+		t.Errorf("Expected 2 slices, got %d", len(res.Slices))
+	}
+}
+
+func TestTWAPSliceFails(t *testing.T) {
+	clk := newFakeClock()
+	srv, _ := newTWAPServer(t, 2)
+	defer srv.Close()
+
+	cl := NewClient()
+	cl.clock = clk
+	cl.SetBaseURL(srv.URL)
+
+	go func() {
+		clk.BlockUntil(1)
+		clk.Advance(time.Hour)
+	}()
+	res, err := cl.TWAP(context.Background(), TWAPRequest{
+		Pair:     "XBTZAR",
+		Side:     SideBuy,
+		Volume:   decimal.NewFromInt64(1000),
+		Slices:   3,
+		Interval: time.Hour,
+	})
+	if !IsErrorCode(err, "ErrRejected") {
+		t.Errorf("Expected ErrRejected, got %v", err)
+	}
+	if len(res.Slices) != 1 || res.Slices[0].OrderId != "BXO1" {
+		t.Errorf("Expected only the first slice, got %+v", res.Slices)
+	}
+}
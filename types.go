@@ -16,6 +16,26 @@ type AddressMeta struct {
 	Value string `json:"value"`
 }
 
+type Candle struct {
+	// Closing price
+	Close decimal.Decimal `json:"close"`
+
+	// High price
+	High decimal.Decimal `json:"high"`
+
+	// Low price
+	Low decimal.Decimal `json:"low"`
+
+	// Opening price
+	Open decimal.Decimal `json:"open"`
+
+	// Candle start time
+	Timestamp Time `json:"timestamp"`
+
+	// Volume traded
+	Volume decimal.Decimal `json:"volume"`
+}
+
 type CryptoDetails struct {
 	Address string `json:"address"`
 	Txid    string `json:"txid"`
@@ -26,6 +46,36 @@ type DetailFields struct {
 	TradeDetails  TradeDetails  `json:"trade_details"`
 }
 
+type FundingAddress struct {
+	AccountId string `json:"account_id"`
+
+	// Receive address
+	Address string `json:"address"`
+
+	// Extra details needed to send to the address, e.g. a destination tag
+	AddressMeta []AddressMeta `json:"address_meta"`
+
+	// Currency code of the asset
+	Asset string `json:"asset"`
+
+	// Time the address was assigned to the account
+	AssignedAt Time `json:"assigned_at"`
+
+	// Name of the address
+	Name string `json:"name"`
+
+	// URI to encode in a QR code for sending to the address
+	QrCodeUri string `json:"qr_code_uri"`
+
+	ReceiveFee decimal.Decimal `json:"receive_fee"`
+
+	// Total confirmed amount received, excluding unconfirmed transactions
+	TotalReceived decimal.Decimal `json:"total_received"`
+
+	// Total amount of unconfirmed receive transactions
+	TotalUnconfirmed decimal.Decimal `json:"total_unconfirmed"`
+}
+
 type Kind string
 
 const (
@@ -78,6 +128,41 @@ type MarketInfo struct {
 	VolumeScale int64 `json:"volume_scale"`
 }
 
+type Move struct {
+	// Amount moved
+	Amount decimal.Decimal `json:"amount"`
+
+	// User defined ID for the move, if one was given
+	ClientMoveId string `json:"client_move_id"`
+
+	// Time the move was created
+	CreatedAt Time `json:"created_at"`
+
+	// Account which was credited
+	CreditAccountId int64 `json:"credit_account_id"`
+
+	// Account which was debited
+	DebitAccountId int64 `json:"debit_account_id"`
+
+	// Move ID
+	Id string `json:"id"`
+
+	// Current status of the move
+	Status MoveStatus `json:"status"`
+
+	// Time the move was last updated
+	UpdatedAt Time `json:"updated_at"`
+}
+
+type MoveStatus string
+
+const (
+	MoveStatusCreated    MoveStatus = "CREATED"
+	MoveStatusMoving     MoveStatus = "MOVING"
+	MoveStatusSuccessful MoveStatus = "SUCCESSFUL"
+	MoveStatusFailed     MoveStatus = "FAILED"
+)
+
 type Order struct {
 	Base                decimal.Decimal `json:"base"`
 	CompletedTimestamp  Time            `json:"completed_timestamp"`
@@ -185,6 +270,35 @@ type OrderV2 struct {
 	Type Type `json:"type"`
 }
 
+type Quote struct {
+	// Amount of the pair base currency
+	BaseAmount decimal.Decimal `json:"base_amount"`
+
+	// Amount of the pair counter currency
+	CounterAmount decimal.Decimal `json:"counter_amount"`
+
+	// Time the quote was created
+	CreatedAt Time `json:"created_at"`
+
+	// Whether the quote has been discarded
+	Discarded bool `json:"discarded"`
+
+	// Whether the quote has been exercised
+	Exercised bool `json:"exercised"`
+
+	// Time after which the quote can no longer be exercised
+	ExpiresAt Time `json:"expires_at"`
+
+	// Quote ID
+	Id string `json:"id"`
+
+	// Currency pair, possibly flipped, e.g. ZARXBT
+	Pair string `json:"pair"`
+
+	// <code>BUY</code> or <code>SELL</code>
+	Type OrderType `json:"type"`
+}
+
 type Side string
 
 const (
@@ -242,6 +356,14 @@ type Trade struct {
 	Volume     decimal.Decimal `json:"volume"`
 }
 
+type TimeInForce string
+
+const (
+	TimeInForceFok TimeInForce = "FOK"
+	TimeInForceGtc TimeInForce = "GTC"
+	TimeInForceIoc TimeInForce = "IOC"
+)
+
 type TradeDetails struct {
 	// Pair of the market
 	Pair string `json:"pair"`
@@ -307,11 +429,11 @@ type Withdrawal struct {
 	ExternalId string          `json:"external_id"`
 	Fee        decimal.Decimal `json:"fee"`
 	Id         string          `json:"id"`
-	Status     string          `json:"status"`
+	Status     SendStatus      `json:"status"`
 	Type       string          `json:"type"`
 }
 
-type beneficiary struct {
+type Beneficiary struct {
 	BankAccountBranch string `json:"bank_account_branch"`
 	BankAccountNumber string `json:"bank_account_number"`
 	BankAccountType   string `json:"bank_account_type"`
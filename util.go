@@ -4,24 +4,56 @@ import (
 	"fmt"
 	"net/url"
 	"reflect"
+	"regexp"
 	"strconv"
+	"strings"
 )
 
-// makeURLValues converts a request struct into a url.Values map.
-func makeURLValues(v interface{}) url.Values {
+var pathParamRe = regexp.MustCompile(`\{(\w+)\}`)
+
+// expandPath replaces each "{param}" in path with the escaped value of the
+// parameter of that name in values, and removes those parameters from values.
+// It returns the expanded path and the names of the parameters replaced.
+func expandPath(path string, values url.Values) (string, []string) {
+	var params []string
+	path = pathParamRe.ReplaceAllStringFunc(path, func(m string) string {
+		name := m[1 : len(m)-1]
+		params = append(params, name)
+		return url.PathEscape(values.Get(name))
+	})
+	for _, name := range params {
+		values.Del(name)
+	}
+	return path, params
+}
+
+// makeURLValues converts a request struct, or a map of parameters, into a
+// url.Values map. An error is returned if a tagged field has a type which
+// can't be encoded.
+func makeURLValues(v interface{}) (url.Values, error) {
 	values := make(url.Values)
 
+	if m, ok := v.(map[string]string); ok {
+		for k, s := range m {
+			values.Set(k, s)
+		}
+		return values, nil
+	}
+
 	valElem := reflect.ValueOf(v).Elem()
 	typElem := reflect.TypeOf(v).Elem()
 
 	for i := 0; i < typElem.NumField(); i++ {
 		field := typElem.Field(i)
-		urlTag := field.Tag.Get("url")
+		urlTag, opts := parseURLTag(field.Tag.Get("url"))
 		if urlTag == "" || urlTag == "-" {
 			continue
 		}
 
 		fieldValue := valElem.Field(i)
+		if opts == "omitempty" && isEmptyValue(fieldValue) {
+			continue
+		}
 
 		stringer, ok := fieldValue.Interface().(QueryValuer)
 		if ok {
@@ -43,22 +75,53 @@ func makeURLValues(v interface{}) url.Values {
 		case reflect.Float64:
 			ss = append(ss, strconv.FormatFloat(fieldValue.Float(), 'f', 4, 64))
 		case reflect.Slice:
-			 if field.Type.Elem().Kind() == reflect.String {
-				for i := 0; i < fieldValue.Len(); i++ {
-					ss = append(ss, fieldValue.Index(i).String())
-				}
+			if field.Type.Elem().Kind() != reflect.String {
+				return nil, fmt.Errorf("luno: unsupported type %s for field %s",
+					field.Type, field.Name)
+			}
+			for i := 0; i < fieldValue.Len(); i++ {
+				ss = append(ss, fieldValue.Index(i).String())
 			}
 		case reflect.String:
 			ss = append(ss, fieldValue.String())
 		case reflect.Bool:
 			ss = append(ss, fmt.Sprintf("%v", fieldValue.Bool()))
+		default:
+			return nil, fmt.Errorf("luno: unsupported type %s for field %s",
+				field.Type, field.Name)
 		}
 		for _, str := range ss {
 			values.Add(urlTag, str)
 		}
 	}
 
-	return values
+	return values, nil
+}
+
+// parseURLTag splits a url struct tag into the parameter name and options,
+// e.g. "time_in_force,omitempty".
+func parseURLTag(tag string) (string, string) {
+	if i := strings.IndexByte(tag, ','); i >= 0 {
+		return tag[:i], tag[i+1:]
+	}
+	return tag, ""
+}
+
+// isEmptyValue reports whether v is the zero value of a basic type.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	}
+	return false
 }
 
 type QueryValuer interface {
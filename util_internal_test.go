@@ -1,6 +1,7 @@
 package luno
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -81,7 +82,12 @@ func TestMakeURLValues(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			act := makeURLValues(&(tt.r)).Encode()
+			values, err := makeURLValues(&(tt.r))
+			if err != nil {
+				t.Errorf("Expected success, got %v", err)
+				return
+			}
+			act := values.Encode()
 			if act != tt.expected {
 				t.Errorf("Expected %q, got %q", tt.expected, act)
 				return
@@ -89,3 +95,171 @@ func TestMakeURLValues(t *testing.T) {
 		})
 	}
 }
+
+func TestMakeURLValuesUnsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		r    interface{}
+	}{
+		{
+			name: "map",
+			r: &struct {
+				M map[string]string `url:"m"`
+			}{},
+		},
+		{
+			name: "pointer",
+			r: &struct {
+				P *string `url:"p"`
+			}{},
+		},
+		{
+			name: "int slice",
+			r: &struct {
+				AI []int `url:"ai"`
+			}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := makeURLValues(tt.r)
+			if err == nil {
+				t.Errorf("Expected error, got nil")
+			}
+		})
+	}
+}
+
+// fillRequest sets every url tagged field of the struct pointed to by v to a
+// non-zero value.
+func fillRequest(t *testing.T, v interface{}) {
+	val := reflect.ValueOf(v).Elem()
+	for i := 0; i < val.NumField(); i++ {
+		if val.Type().Field(i).Tag.Get("url") == "-" {
+			continue
+		}
+		f := val.Field(i)
+		switch f.Interface().(type) {
+		case decimal.Decimal:
+			f.Set(reflect.ValueOf(decimal.NewFromInt64(1)))
+			continue
+		case Time:
+			f.Set(reflect.ValueOf(Time(time.Unix(1, 0))))
+			continue
+		}
+		switch f.Kind() {
+		case reflect.String:
+			f.SetString("x")
+		case reflect.Int, reflect.Int64:
+			f.SetInt(1)
+		case reflect.Bool:
+			f.SetBool(true)
+		case reflect.Slice:
+			f.Set(reflect.ValueOf([]string{"x"}).Convert(f.Type()))
+		default:
+			t.Fatalf("Unable to fill field %s of %T", val.Type().Field(i).Name, v)
+		}
+	}
+}
+
+func TestMakeURLValuesRequests(t *testing.T) {
+	reqs := []interface{}{
+		&CancelWithdrawalRequest{},
+		&CreateAccountRequest{},
+		&CreateBeneficiaryRequest{},
+		&CreateFundingAddressRequest{},
+		&CreateQuoteRequest{},
+		&CreateWithdrawalRequest{},
+		&DeleteBeneficiaryRequest{},
+		&DiscardQuoteRequest{},
+		&ExerciseQuoteRequest{},
+		&GetBalancesRequest{},
+		&GetCandlesRequest{},
+		&GetFeeInfoRequest{},
+		&GetFundingAddressRequest{},
+		&GetMoveRequest{},
+		&GetOrderRequest{},
+		&GetOrderBookRequest{},
+		&GetOrderBookFullRequest{},
+		&GetOrderV2Request{},
+		&GetQuoteRequest{},
+		&GetServerTimeRequest{},
+		&GetTickerRequest{},
+		&GetTickersRequest{},
+		&GetWithdrawalRequest{},
+		&ListBeneficiariesRequest{},
+		&ListBeneficiariesResponseRequest{},
+		&ListMovesRequest{},
+		&ListOrdersRequest{},
+		&ListOrdersV2Request{},
+		&ListPendingTransactionsRequest{},
+		&ListTradesRequest{},
+		&ListTransactionsRequest{},
+		&ListUserTradesRequest{},
+		&ListWithdrawalsRequest{},
+		&MarketsRequest{},
+		&MoveRequest{},
+		&PostLimitOrderRequest{},
+		&PostMarketOrderRequest{},
+		&SendRequest{},
+		&SendFeeRequest{},
+		&StopOrderRequest{},
+		&UpdateAccountNameRequest{},
+		&ValidateRequest{},
+	}
+
+	for _, req := range reqs {
+		typ := reflect.TypeOf(req).Elem()
+		t.Run(typ.Name(), func(t *testing.T) {
+			fillRequest(t, req)
+			values, err := makeURLValues(req)
+			if err != nil {
+				t.Fatalf("Expected success, got %v", err)
+			}
+			for i := 0; i < typ.NumField(); i++ {
+				field := typ.Field(i)
+				tag, _ := parseURLTag(field.Tag.Get("url"))
+				if tag == "-" {
+					// Client-side only.
+					continue
+				}
+				if tag == "" {
+					t.Errorf("Field %s has no url tag", field.Name)
+					continue
+				}
+				if tag != field.Tag.Get("json") {
+					t.Errorf("Field %s has url tag %q but json tag %q",
+						field.Name, tag, field.Tag.Get("json"))
+				}
+				if values.Get(tag) == "" {
+					t.Errorf("Expected parameter %q to be set", tag)
+				}
+			}
+		})
+	}
+}
+
+func TestMakeURLValuesOmitEmpty(t *testing.T) {
+	type req struct {
+		A string          `url:"a"`
+		B string          `url:"b,omitempty"`
+		C int64           `url:"c,omitempty"`
+		D decimal.Decimal `url:"d,omitempty"`
+	}
+	values, err := makeURLValues(&req{})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if exp := "a=&d=0"; values.Encode() != exp {
+		t.Errorf("Expected %q, got %q", exp, values.Encode())
+	}
+
+	values, err = makeURLValues(&req{B: "x", C: 1})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if exp := "a=&b=x&c=1&d=0"; values.Encode() != exp {
+		t.Errorf("Expected %q, got %q", exp, values.Encode())
+	}
+}
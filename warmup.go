@@ -0,0 +1,38 @@
+package luno
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Warmup primes the connection pool of the HTTP client for the host which the
+// next request will use, so that a latency sensitive first request doesn't
+// pay for DNS resolution and the TLS handshake. It makes a HEAD request for
+// the root of the host, whose status is ignored, and doesn't use the API key,
+// rate limiter, hooks or metrics. It is safe to call at any time, e.g. again
+// after an idle period, but Warmup is never called automatically.
+//
+// An error is only returned if no connection could be made.
+func (cl *Client) Warmup(ctx context.Context) error {
+	host := cl.baseURL
+	if cl.hosts != nil {
+		host = cl.hosts.pick()
+	}
+
+	req, err := http.NewRequest(http.MethodHead, host+"/", nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("User-Agent", cl.userAgent())
+
+	res, err := cl.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	// The body must be drained for the connection to be reused.
+	io.Copy(ioutil.Discard, res.Body)
+	return res.Body.Close()
+}
@@ -0,0 +1,64 @@
+package luno_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	luno "github.com/luno/luno-go"
+)
+
+func TestWarmup(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var dials int32
+	dialer := &net.Dialer{}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			atomic.AddInt32(&dials, 1)
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}
+	defer transport.CloseIdleConnections()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	cl.SetHTTPClient(&http.Client{Transport: transport})
+
+	ctx := context.Background()
+	if err := cl.Warmup(ctx); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if n := atomic.LoadInt32(&dials); n != 1 {
+		t.Fatalf("Expected Warmup to dial once, got %d", n)
+	}
+
+	if _, err := cl.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if n := atomic.LoadInt32(&dials); n != 1 {
+		t.Errorf("Expected the warmed connection to be reused, got %d dials", n)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("Expected 2 requests, got %d", n)
+	}
+}
+
+func TestWarmupUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	cl := luno.NewClient()
+	cl.SetBaseURL(srv.URL)
+	if err := cl.Warmup(context.Background()); err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
@@ -0,0 +1,145 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WarningKind is the kind of problem reported by a Warning.
+type WarningKind string
+
+const (
+	// WarningStale means that the response has a timestamp older than the
+	// threshold set with SetStaleThreshold, which may indicate a feed issue.
+	WarningStale WarningKind = "stale"
+
+	// WarningMissingField means that the response lacks a field which is
+	// expected to be set.
+	WarningMissingField WarningKind = "missing_field"
+
+	// WarningValidationSkipped means that a local check of a request was
+	// skipped because metadata it depends on was unavailable, see
+	// SetMetadataPolicy. The request was still sent. It is also reported
+	// when FormatAmount, TickSize or LotSize fall back without the metadata.
+	// It is only reported to the Warning hook, not to call stats.
+	WarningValidationSkipped WarningKind = "validation_skipped"
+
+	// WarningClockSkew means that the local clock is further from the server
+	// clock than the threshold set with SetClockSkewThreshold. It is only
+	// reported to the Warning hook, not to call stats.
+	WarningClockSkew WarningKind = "clock_skew"
+)
+
+// Warning describes a successful response whose data may be stale or
+// partial, or a request which was sent without being fully checked. Warnings
+// are reported to the Warning hook and the call stats of the request context,
+// see WithCallStats, and the response is still returned.
+type Warning struct {
+	RequestInfo
+
+	Kind    WarningKind
+	Message string
+}
+
+// SetStaleThreshold sets how old the timestamp of a ticker may be before a
+// WarningStale is reported for it. Zero disables staleness warnings, which is
+// the default.
+func (cl *Client) SetStaleThreshold(d time.Duration) {
+	cl.staleThreshold = d
+}
+
+// responseWarner is implemented by responses which can detect degraded data.
+// now is the current time and staleAfter the threshold set with
+// SetStaleThreshold.
+type responseWarner interface {
+	responseWarnings(now time.Time, staleAfter time.Duration) []Warning
+}
+
+// reportWarnings reports any warnings for res, which was returned by the
+// request described by info.
+func (cl *Client) reportWarnings(ctx context.Context, info RequestInfo, res interface{}) {
+	rw, ok := res.(responseWarner)
+	if !ok {
+		return
+	}
+	stats := callStatsFromContext(ctx)
+	for _, w := range rw.responseWarnings(cl.ServerNow(), cl.staleThreshold) {
+		w.RequestInfo = info
+		cl.hooks.warning(ctx, w)
+		if stats != nil {
+			stats.Warnings = append(stats.Warnings, w)
+		}
+	}
+}
+
+func (r *GetTickerResponse) responseWarnings(now time.Time, staleAfter time.Duration) []Warning {
+	return tickerWarnings(Ticker(*r), now, staleAfter)
+}
+
+func (r *GetTickersResponse) responseWarnings(now time.Time, staleAfter time.Duration) []Warning {
+	var ws []Warning
+	for _, t := range r.Tickers {
+		ws = append(ws, tickerWarnings(t, now, staleAfter)...)
+	}
+	return ws
+}
+
+func tickerWarnings(t Ticker, now time.Time, staleAfter time.Duration) []Warning {
+	ts := time.Time(t.Timestamp)
+	if ts.IsZero() {
+		return []Warning{{
+			Kind:    WarningMissingField,
+			Message: fmt.Sprintf("ticker for %s has no timestamp", t.Pair),
+		}}
+	}
+	if age := now.Sub(ts); staleAfter > 0 && age > staleAfter {
+		return []Warning{{
+			Kind:    WarningStale,
+			Message: fmt.Sprintf("ticker for %s is %s old", t.Pair, age.Round(time.Millisecond)),
+		}}
+	}
+	return nil
+}
+
+// MetadataPolicy determines what happens to a request which is checked
+// locally, e.g. against the notional cap set with SetMaxOrderNotional, when
+// the market metadata needed for the check can't be fetched. It also applies
+// to FormatAmount, TickSize and LotSize. An unknown market, see
+// ErrUnknownMarket, is always an error.
+type MetadataPolicy int
+
+const (
+	// MetadataFailClosed fails the request with the error from fetching the
+	// metadata. This is the default.
+	MetadataFailClosed MetadataPolicy = iota
+
+	// MetadataFailOpen sends the request without the check, or falls back
+	// to a default, and reports a WarningValidationSkipped instead. Use it when the checks are advisory
+	// and a transient metadata failure shouldn't block trading.
+	MetadataFailOpen
+)
+
+// SetMetadataPolicy sets what happens to requests whose local checks need
+// market metadata which can't be fetched.
+func (cl *Client) SetMetadataPolicy(p MetadataPolicy) {
+	cl.metadataPolicy = p
+}
+
+// metadataUnavailable returns err, which occurred fetching the metadata
+// needed for check on pair, unless the metadata policy is to fail open and
+// err isn't ErrUnknownMarket, in which case a warning is reported and nil
+// returned.
+func (cl *Client) metadataUnavailable(ctx context.Context, check, pair string, err error) error {
+	if cl.metadataPolicy != MetadataFailOpen || errors.Is(err, ErrUnknownMarket) {
+		return err
+	}
+	w := Warning{
+		RequestInfo: RequestInfo{CorrelationID: CorrelationIDFromContext(ctx)},
+		Kind:        WarningValidationSkipped,
+		Message:     fmt.Sprintf("%s on %s skipped: %v", check, pair, err),
+	}
+	cl.hooks.warning(ctx, w)
+	return nil
+}
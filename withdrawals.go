@@ -0,0 +1,110 @@
+package luno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SendStatus is the status of a withdrawal, which is how Luno processes
+// sends.
+type SendStatus string
+
+const (
+	// SendStatusPending is the only status in which a withdrawal may be
+	// cancelled.
+	SendStatusPending    SendStatus = "PENDING"
+	SendStatusProcessing SendStatus = "PROCESSING"
+	SendStatusCompleted  SendStatus = "COMPLETED"
+	SendStatusCancelled  SendStatus = "CANCELLED"
+)
+
+// Final returns whether s is a status which a withdrawal doesn't leave.
+func (s SendStatus) Final() bool {
+	return s == SendStatusCompleted || s == SendStatusCancelled
+}
+
+// NotCancelableError is returned when a withdrawal or send can no longer be
+// cancelled, e.g. because it has already been processed.
+type NotCancelableError struct {
+	// ID of the withdrawal.
+	ID string
+
+	// Status of the withdrawal at the time of the cancellation attempt.
+	Status string
+}
+
+func (e NotCancelableError) Error() string {
+	return fmt.Sprintf("luno: withdrawal %s can't be cancelled in status %s",
+		e.ID, e.Status)
+}
+
+// CancelSend cancels a send which hasn't been processed yet. Sends are
+// processed as withdrawals, so id is the WithdrawalId returned by Send.
+//
+// If Luno rejects the cancellation because the send has already been
+// processed, a NotCancelableError is returned.
+func (cl *Client) CancelSend(ctx context.Context, id string) (*CancelWithdrawalResponse, error) {
+	wid, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("luno: invalid withdrawal id %q: %w", id, err)
+	}
+
+	res, err := cl.CancelWithdrawal(ctx, &CancelWithdrawalRequest{Id: wid})
+	if err == nil {
+		return res, nil
+	}
+
+	var apiErr Error
+	if !errors.As(err, &apiErr) {
+		return nil, err
+	}
+
+	// Luno doesn't identify this case with a specific error code, so check
+	// the status of the withdrawal to tell it apart from other rejections.
+	w, getErr := cl.GetWithdrawal(ctx, &GetWithdrawalRequest{Id: wid})
+	if getErr != nil || w.Status == SendStatusPending {
+		return nil, err
+	}
+	return nil, NotCancelableError{ID: id, Status: string(w.Status)}
+}
+
+// WatchSend polls the withdrawal with the given ID, as returned by Send, every
+// interval until its status is final or ctx is done, calling fn whenever the
+// status has changed since the previous poll. The last state of the
+// withdrawal is returned; a send which was cancelled isn't an error. An
+// error is returned if interval is not positive.
+func (cl *Client) WatchSend(ctx context.Context, id string, interval time.Duration,
+	fn func(SendStatus, *GetWithdrawalResponse)) (*GetWithdrawalResponse, error) {
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("luno: invalid watch interval %s", interval)
+	}
+	wid, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("luno: invalid withdrawal id %q: %w", id, err)
+	}
+
+	var status SendStatus
+	for {
+		w, err := cl.GetWithdrawal(ctx, &GetWithdrawalRequest{Id: wid})
+		if err != nil {
+			return nil, err
+		}
+		if w.Status != status {
+			status = w.Status
+			fn(status, w)
+		}
+		if status.Final() {
+			return w, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return w, ctx.Err()
+		case <-cl.clock.After(interval):
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package luno_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	luno "github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+func TestCancelSend(t *testing.T) {
+	testCases := []struct {
+		name       string
+		status     string
+		cancelled  bool
+		expErr     bool
+		expTooLate bool
+	}{
+		{
+			name:      "pending",
+			status:    "PENDING",
+			cancelled: true,
+		},
+		{
+			name:       "too late",
+			status:     "COMPLETED",
+			expErr:     true,
+			expTooLate: true,
+		},
+		{
+			name:   "rejected while pending",
+			status: "PENDING",
+			expErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/1/withdrawals/123" {
+					t.Errorf("Unexpected path %q", r.URL.Path)
+				}
+				switch {
+				case r.Method == http.MethodDelete && tc.cancelled:
+					w.Write([]byte(`{"id":"123","status":"CANCELLED"}`))
+				case r.Method == http.MethodDelete:
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"error":"Cannot cancel","error_code":"ErrInvalidArguments"}`))
+				default:
+					w.Write([]byte(`{"id":"123","status":"` + tc.status + `"}`))
+				}
+			}))
+			defer srv.Close()
+
+			cl := luno.NewClient()
+			cl.SetBaseURL(srv.URL)
+
+			res, err := cl.CancelSend(context.Background(), "123")
+			if tc.expErr != (err != nil) {
+				t.Fatalf("Expected error %t, got %v", tc.expErr, err)
+			}
+			var tooLate luno.NotCancelableError
+			if errors.As(err, &tooLate) != tc.expTooLate {
+				t.Errorf("Expected NotCancelableError %t, got %v", tc.expTooLate, err)
+			}
+			if tc.expTooLate && tooLate.Status != tc.status {
+				t.Errorf("Expected status %q, got %q", tc.status, tooLate.Status)
+			}
+			if !tc.expErr && res.Status != "CANCELLED" {
+				t.Errorf("Expected cancelled withdrawal, got %q", res.Status)
+			}
+		})
+	}
+}
+
+func TestBeneficiaries(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			b, _ := ioutil.ReadAll(r.Body)
+			got = append(got, r.Method+" "+r.URL.Path+" "+r.Header.Get("Content-Type")+" "+string(b))
+		} else {
+			r.ParseForm()
+			got = append(got, r.Method+" "+r.URL.Path+" "+r.Form.Encode())
+		}
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"beneficiaries":[{"id":"7","bank_name":"FNB","bank_recipient":"A Person"}]}`))
+		case http.MethodPost:
+			w.Write([]byte(`{"id":"8","bank_name":"ABSA"}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL), luno.WithAuth("key", "secret"))
+	ctx := context.Background()
+
+	list, err := cl.ListBeneficiaries(ctx, &luno.ListBeneficiariesRequest{})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if len(list.Beneficiaries) != 1 || list.Beneficiaries[0].Id != "7" {
+		t.Errorf("Expected beneficiary 7, got %+v", list.Beneficiaries)
+	}
+
+	created, err := cl.CreateBeneficiary(ctx, &luno.CreateBeneficiaryRequest{
+		AccountType:       "Current/Cheque",
+		BankAccountNumber: "12345",
+		BankName:          "ABSA",
+		BankRecipient:     "A Person",
+	})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if created.Id != "8" {
+		t.Errorf("Expected beneficiary 8, got %q", created.Id)
+	}
+
+	if _, err := cl.DeleteBeneficiary(ctx, &luno.DeleteBeneficiaryRequest{Id: 8}); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+
+	exp := []string{
+		"GET /api/1/beneficiaries ",
+		`POST /api/1/beneficiaries application/json {"account_type":"Current/Cheque",` +
+			`"bank_account_number":"12345","bank_name":"ABSA","bank_recipient":"A Person"}`,
+		"DELETE /api/1/beneficiaries/8 ",
+	}
+	for i := range exp {
+		if i >= len(got) || got[i] != exp[i] {
+			t.Errorf("Expected requests %q, got %q", exp, got)
+			break
+		}
+	}
+}
+
+func TestSendFee(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/1/send_fee" || r.FormValue("currency") != "XBT" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"currency":"XBT","fee":"0.0002"}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL), luno.WithAuth("key", "secret"))
+	res, err := cl.SendFee(context.Background(), &luno.SendFeeRequest{
+		Address:  "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+		Amount:   decimal.NewFromFloat64(0.1, 1),
+		Currency: "XBT",
+	})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if res.Fee.String() != "0.0002" {
+		t.Errorf("Expected fee 0.0002, got %s", res.Fee)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/1/address/validate" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.FormValue("memo") != "1234" || r.FormValue("network") != "XLM" {
+			t.Errorf("Expected memo and network, got %v", r.Form)
+		}
+		if _, ok := r.Form["destination_tag"]; ok {
+			t.Errorf("Expected no destination tag, got %q", r.FormValue("destination_tag"))
+		}
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL), luno.WithAuth("key", "secret"))
+	res, err := cl.Validate(context.Background(), &luno.ValidateRequest{
+		Address:  "GAHK7EEG2WWHVKDNT4CEQFZGKF2LGDSW2IVM4S5DP42RBW3K6BTODB4A",
+		Currency: "XLM",
+		Memo:     "1234",
+		Network:  "XLM",
+	})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if !res.Success {
+		t.Errorf("Expected valid address, got %+v", res)
+	}
+}
+
+func TestWatchSend(t *testing.T) {
+	states := []string{"PENDING", "PENDING", "PROCESSING", "COMPLETED"}
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/1/withdrawals/123" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		w.Write([]byte(`{"id":"123","status":"` + states[calls] + `"}`))
+		calls++
+	}))
+	defer srv.Close()
+
+	cl := luno.NewClient(luno.WithBaseURL(srv.URL))
+	var got []luno.SendStatus
+	w, err := cl.WatchSend(context.Background(), "123", time.Millisecond,
+		func(s luno.SendStatus, w *luno.GetWithdrawalResponse) {
+			got = append(got, s)
+		})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if w.Status != "COMPLETED" {
+		t.Errorf("Expected completed withdrawal, got %q", w.Status)
+	}
+	exp := []luno.SendStatus{luno.SendStatusPending, luno.SendStatusProcessing, luno.SendStatusCompleted}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected statuses %v, got %v", exp, got)
+	}
+	if calls != len(states) {
+		t.Errorf("Expected %d polls, got %d", len(states), calls)
+	}
+
+	if _, err := cl.WatchSend(context.Background(), "abc", time.Millisecond, nil); err == nil {
+		t.Errorf("Expected error for invalid id, got nil")
+	}
+}
+
+func TestWatchSendInterval(t *testing.T) {
+	cl := luno.NewClient(luno.WithBaseURL("http://127.0.0.1:0"))
+	for _, interval := range []time.Duration{0, -time.Second} {
+		_, err := cl.WatchSend(context.Background(), "123", interval,
+			func(luno.SendStatus, *luno.GetWithdrawalResponse) {})
+		if err == nil || !strings.Contains(err.Error(), "invalid watch interval") {
+			t.Errorf("Expected invalid interval error for %s, got %v", interval, err)
+		}
+	}
+}